@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"context"
+	goerrors "errors"
+	"ewallet-transaction/helpers"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"gorm.io/gorm"
+)
+
+type worker interface {
+	Start()
+	Stop(ctx context.Context) error
+}
+
+type app struct {
+	container       *Container
+	httpServer      *http.Server
+	grpcServer      *grpc.Server
+	workers         []worker
+	grpcPort        string
+	shutdownTimeout time.Duration
+}
+
+// Run starts the http and grpc servers and blocks until SIGINT/SIGTERM,
+// then shuts every component down in order.
+func Run(cfg *helpers.Config) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logrus.Info("config loaded: ", cfg)
+
+	db, err := helpers.SetupDB(cfg.DB)
+	if err != nil {
+		return err
+	}
+
+	if cfg.DB.AutoMigrate {
+		if err := autoMigrate(ctx, db, cfg.DB.Driver); err != nil {
+			return err
+		}
+	}
+
+	replica, err := helpers.SetupDBReplica(cfg.DB)
+	if err != nil {
+		return err
+	}
+
+	container, err := NewContainer(cfg, db, replica)
+	if err != nil {
+		return err
+	}
+
+	return newApp(container).run(ctx)
+}
+
+func newApp(c *Container) *app {
+	return &app{
+		container:       c,
+		httpServer:      newHttpServer(c),
+		grpcServer:      newGRPCServer(c),
+		workers:         c.Workers(),
+		grpcPort:        c.Config.GRPCPort,
+		shutdownTimeout: c.Config.ShutdownTimeout,
+	}
+}
+
+func (a *app) run(ctx context.Context) error {
+	for _, w := range a.workers {
+		w.Start()
+	}
+
+	errCh := make(chan error, 2)
+
+	go func() {
+		logrus.Info("start listening http on " + a.httpServer.Addr)
+		if err := a.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- errors.Wrap(err, "failed to serve http")
+		}
+	}()
+
+	go func() {
+		lis, err := net.Listen("tcp", ":"+a.grpcPort)
+		if err != nil {
+			errCh <- errors.Wrap(err, "failed to listen grpc port")
+			return
+		}
+
+		logrus.Info("start listening grpc on port:" + a.grpcPort)
+		if err := a.grpcServer.Serve(lis); err != nil {
+			errCh <- errors.Wrap(err, "failed to serve grpc")
+		}
+	}()
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		logrus.Info("shutdown signal received")
+	case runErr = <-errCh:
+		logrus.Error("server stopped unexpectedly: ", runErr)
+	}
+
+	return goerrors.Join(runErr, a.shutdown())
+}
+
+// shutdown drains the servers first so in-flight requests can finish their wallet calls,
+// then stops the background workers and finally closes the outgoing connections and the db.
+func (a *app) shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), a.shutdownTimeout)
+	defer cancel()
+
+	var errs []error
+
+	if err := a.httpServer.Shutdown(ctx); err != nil {
+		errs = append(errs, errors.Wrap(err, "failed to shutdown http server"))
+	}
+
+	a.stopGRPC(ctx)
+
+	for i := len(a.workers) - 1; i >= 0; i-- {
+		if err := a.workers[i].Stop(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := a.container.Publisher.Close(); err != nil {
+		errs = append(errs, errors.Wrap(err, "failed to close event publisher"))
+	}
+
+	if err := a.container.External.Close(); err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, db := range []*gorm.DB{a.container.DB, a.container.ReplicaDB} {
+		if db == nil {
+			continue
+		}
+
+		sqlDB, err := db.DB()
+		if err == nil {
+			err = sqlDB.Close()
+		}
+		if err != nil {
+			errs = append(errs, errors.Wrap(err, "failed to close database"))
+		}
+	}
+
+	logrus.Info("shutdown completed")
+
+	return goerrors.Join(errs...)
+}
+
+func (a *app) stopGRPC(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		a.grpcServer.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		logrus.Warn("grpc graceful stop timed out, forcing stop")
+		a.grpcServer.Stop()
+	}
+}
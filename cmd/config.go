@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"ewallet-transaction/helpers"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+func newConfigCmd(opts *rootOptions) *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the service configuration",
+	}
+
+	configCmd.AddCommand(&cobra.Command{
+		Use:   "check",
+		Short: "Print the effective configuration and validate it",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := opts.loadConfig()
+			if cfg == nil {
+				return err
+			}
+
+			printConfig(cmd.OutOrStdout(), cfg)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "config is valid")
+			return nil
+		},
+	})
+
+	return configCmd
+}
+
+func printConfig(out io.Writer, cfg *helpers.Config) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+	for _, entry := range cfg.Entries() {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", entry.Key, entry.Value, entry.Source)
+	}
+	w.Flush()
+}
@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"ewallet-transaction/constants"
+	"ewallet-transaction/external"
+	"ewallet-transaction/external/ratelimit"
+	"ewallet-transaction/helpers"
+	adminHandler "ewallet-transaction/internal/handler/admin"
+	exportHandler "ewallet-transaction/internal/handler/export"
+	healthcheckHandler "ewallet-transaction/internal/handler/healthcheck"
+	settlementHandler "ewallet-transaction/internal/handler/settlement"
+	summaryHandler "ewallet-transaction/internal/handler/summary"
+	transactionHandler "ewallet-transaction/internal/handler/transaction"
+	webhookHandler "ewallet-transaction/internal/handler/webhook"
+	"ewallet-transaction/internal/interfaces"
+	"ewallet-transaction/internal/models"
+	auditRepo "ewallet-transaction/internal/repository/audit"
+	contactRepo "ewallet-transaction/internal/repository/contact"
+	healthcheckRepo "ewallet-transaction/internal/repository/healthcheck"
+	ledgerRepo "ewallet-transaction/internal/repository/ledger"
+	limitRepo "ewallet-transaction/internal/repository/limit"
+	transactionRepo "ewallet-transaction/internal/repository/transaction"
+	webhookRepo "ewallet-transaction/internal/repository/webhook"
+	adminSvc "ewallet-transaction/internal/services/admin"
+	exportSvc "ewallet-transaction/internal/services/export"
+	fraudSvc "ewallet-transaction/internal/services/fraud"
+	healthcheckSvc "ewallet-transaction/internal/services/healthcheck"
+	ledgerSvc "ewallet-transaction/internal/services/ledger"
+	limitSvc "ewallet-transaction/internal/services/limit"
+	notificationSvc "ewallet-transaction/internal/services/notification"
+	reconciliationSvc "ewallet-transaction/internal/services/reconciliation"
+	searchSvc "ewallet-transaction/internal/services/search"
+	settlementSvc "ewallet-transaction/internal/services/settlement"
+	summarySvc "ewallet-transaction/internal/services/summary"
+	transactionSvc "ewallet-transaction/internal/services/transaction"
+	webhookSvc "ewallet-transaction/internal/services/webhook"
+	"ewallet-transaction/middleware"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Container holds every component of the application, wired once through their constructors
+// so the http server, the grpc server and tests share the same construction path.
+type Container struct {
+	Config     *helpers.Config
+	DB         *gorm.DB
+	ReplicaDB  *gorm.DB
+	JWT        *helpers.JWT
+	External   *external.External
+	Publisher  interfaces.IEventPublisher
+	Middleware *middleware.ExternalDependency
+
+	HealthcheckRepo interfaces.IHealthcheckRepo
+	TransactionRepo interfaces.ITransactionRepo
+	WebhookRepo     interfaces.IWebhookRepo
+	LedgerRepo      interfaces.ILedgerRepo
+	AuditRepo       interfaces.IAuditRepo
+	LimitRepo       interfaces.ILimitRepo
+	ContactRepo     interfaces.IContactRepo
+
+	HealthcheckService  interfaces.IHealthcheckServices
+	NotificationService interfaces.INotificationService
+	WebhookService      interfaces.IWebhookService
+	LedgerService       interfaces.ILedgerService
+	LimitService        interfaces.ILimitService
+	FraudService        interfaces.IFraudService
+	ReconcileService    interfaces.IReconciliationService
+	TransactionService  interfaces.ITransactionService
+	SettlementService   interfaces.ISettlementService
+	ExportService       interfaces.IExportService
+	SummaryService      interfaces.ISummaryService
+	SearchService       interfaces.ISearchService
+	AdminService        interfaces.IAdminService
+}
+
+// NewContainer wires every component, replica is optional and only serves history reads.
+func NewContainer(cfg *helpers.Config, db *gorm.DB, replica *gorm.DB) (*Container, error) {
+	settlementMappings, err := settlementSvc.LoadMappings(cfg.Settlement.MappingsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas, err := transactionSvc.LoadSchemas(cfg.Transaction.SchemaDir)
+	if err != nil {
+		return nil, err
+	}
+
+	limitLocation, err := time.LoadLocation(cfg.Limit.Timezone)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load limit timezone")
+	}
+
+	ext, err := external.NewExternal(cfg.External)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to setup external clients")
+	}
+
+	publisher, err := setupPublisher(cfg.Event)
+	if err != nil {
+		ext.Close()
+		return nil, errors.Wrap(err, "failed to setup event publisher")
+	}
+
+	c := &Container{
+		Config:    cfg,
+		DB:        db,
+		ReplicaDB: replica,
+		JWT:       helpers.NewJWT(cfg.AppSecret, cfg.AppName),
+		External:  ext,
+		Publisher: publisher,
+		Middleware: &middleware.ExternalDependency{
+			External:         ext,
+			SettlementAPIKey: cfg.Settlement.APIKey,
+			AdminUserIDs:     userIDSet(cfg.Admin.UserIDs),
+			MerchantUserIDs:  userIDSet(cfg.Webhook.MerchantUserIDs),
+			RateLimitStore:   ratelimit.NewMemoryStore(),
+			RateLimits:       rateLimits(cfg.RateLimit.Groups),
+		},
+	}
+
+	c.HealthcheckRepo = healthcheckRepo.NewRepository()
+	c.TransactionRepo = transactionRepo.NewRepository(db, replica, cfg.DB.QueryTimeouts())
+	c.WebhookRepo = webhookRepo.NewRepository(db, cfg.DB.QueryTimeouts())
+	c.LedgerRepo = ledgerRepo.NewRepository(db, cfg.DB.QueryTimeouts())
+	c.AuditRepo = auditRepo.NewRepository(db, cfg.DB.QueryTimeouts())
+	c.LimitRepo = limitRepo.NewRepository(db, cfg.DB.QueryTimeouts())
+	c.ContactRepo = contactRepo.NewRepository(db, cfg.DB.QueryTimeouts())
+
+	c.HealthcheckService = healthcheckSvc.NewService(c.HealthcheckRepo)
+	c.NotificationService = notificationSvc.NewService(ext, c.ContactRepo, notificationSvc.Config{
+		Templates:     cfg.Notification.Templates,
+		Workers:       cfg.Notification.Workers,
+		QueueSize:     cfg.Notification.QueueSize,
+		MaxRetry:      cfg.Notification.MaxRetry,
+		RetryInterval: cfg.Notification.RetryInterval,
+		SendTimeout:   cfg.Notification.SendTimeout,
+	})
+	c.Middleware.Contacts = c.NotificationService
+	c.WebhookService = webhookSvc.NewService(c.WebhookRepo, webhookSvc.NewHTTPClient(), webhookSvc.Config{
+		MaxAttempts:  cfg.Webhook.MaxAttempts,
+		BaseBackoff:  cfg.Webhook.BaseBackoff,
+		PollInterval: cfg.Webhook.PollInterval,
+		BatchSize:    cfg.Webhook.BatchSize,
+		Timeout:      cfg.Webhook.Timeout,
+	})
+	c.LedgerService = ledgerSvc.NewService(c.LedgerRepo, ledgerSvc.Config{
+		PurchaseFeeBps: cfg.Ledger.PurchaseFeeBps,
+	})
+	c.LimitService = limitSvc.NewService(c.LimitRepo, c.TransactionRepo, limitSvc.Config{
+		Location: limitLocation,
+	})
+	c.FraudService = fraudSvc.NewService(c.TransactionRepo, fraudSvc.Config{
+		VelocityWindow: cfg.Fraud.VelocityWindow,
+		VelocityReview: cfg.Fraud.VelocityReview,
+		VelocityDeny:   cfg.Fraud.VelocityDeny,
+		Lookback:       cfg.Fraud.Lookback,
+		MinHistory:     cfg.Fraud.MinHistory,
+		AmountFactor:   cfg.Fraud.AmountFactor,
+		NewDevice:      cfg.Fraud.NewDevice,
+		RefundWindow:   cfg.Fraud.RefundWindow,
+		RefundReview:   cfg.Fraud.RefundReview,
+		RefundDeny:     cfg.Fraud.RefundDeny,
+	})
+	c.TransactionService = transactionSvc.NewService(c.TransactionRepo, ext, c.NotificationService, c.Publisher, c.WebhookService, c.LedgerService, schemas, c.LimitService, c.FraudService, transactionSvc.Config{
+		MerchantUserIDs: c.Middleware.MerchantUserIDs,
+	})
+	c.ReconcileService = reconciliationSvc.NewService(c.TransactionRepo, ext, c.TransactionService, reconciliationSvc.Config{
+		Interval:   cfg.Reconcile.Interval,
+		Window:     cfg.Reconcile.Window,
+		Grace:      cfg.Reconcile.Grace,
+		AutoRepair: cfg.Reconcile.AutoRepair,
+	})
+	c.SettlementService = settlementSvc.NewService(c.TransactionRepo, c.TransactionService, settlementSvc.Config{
+		Mappings:     settlementMappings,
+		ServiceToken: cfg.External.WalletServiceToken,
+	})
+	c.ExportService = exportSvc.NewService(c.TransactionRepo, exportSvc.Config{
+		BrandName: cfg.Export.BrandName,
+		MaxRange:  cfg.Export.MaxRange,
+	})
+	c.SummaryService = summarySvc.NewService(c.TransactionRepo, summarySvc.Config{
+		DefaultTimezone: cfg.Summary.DefaultTimezone,
+		CacheTTL:        cfg.Summary.CacheTTL,
+		CacheSize:       cfg.Summary.CacheSize,
+	})
+	c.SearchService = searchSvc.NewService(c.TransactionRepo)
+	c.AdminService = adminSvc.NewService(c.SearchService, c.TransactionService, c.TransactionRepo, c.AuditRepo, c.LimitService, adminSvc.Config{
+		ServiceToken: cfg.External.WalletServiceToken,
+	})
+
+	return c, nil
+}
+
+func userIDSet(ids []uint64) map[uint64]bool {
+	users := make(map[uint64]bool, len(ids))
+	for _, id := range ids {
+		users[id] = true
+	}
+
+	return users
+}
+
+func rateLimits(groups map[string]helpers.RateLimitGroupConfig) map[string]models.RateLimit {
+	limits := make(map[string]models.RateLimit, len(groups))
+	for group, limit := range groups {
+		limits[group] = models.RateLimit{
+			Requests: limit.Requests,
+			Period:   limit.Period,
+		}
+	}
+
+	return limits
+}
+
+// Workers returns the background workers that must be started and stopped with the servers,
+// the reconciliation job only runs when RECONCILE_INTERVAL is set.
+func (c *Container) Workers() []worker {
+	workers := []worker{c.NotificationService, c.WebhookService}
+	if c.Config.Reconcile.Interval > 0 {
+		workers = append(workers, c.ReconcileService)
+	}
+
+	return workers
+}
+
+// Router builds the gin engine and registers the routes of every handler.
+func (c *Container) Router() *gin.Engine {
+	r := gin.Default()
+	// the client ip, rate limited and screened for fraud, is only read from X-Forwarded-For
+	// when the request comes through a trusted proxy, an invalid list trusts none
+	if err := r.SetTrustedProxies(c.Config.TrustedProxies); err != nil {
+		logrus.Error("failed to set trusted proxies: ", err)
+		_ = r.SetTrustedProxies(nil)
+	}
+	// every client ip is limited before the routes validate the user token with ums
+	r.Use(c.Middleware.MiddlewareRateLimit(constants.RateLimitGroupClient))
+
+	transactionHandler := transactionHandler.NewHandler(r, c.TransactionService, c.External, c.Middleware)
+	transactionHandler.RegisterRoute()
+
+	exportHandler := exportHandler.NewHandler(r, c.ExportService, c.Middleware)
+	exportHandler.RegisterRoute()
+
+	summaryHandler := summaryHandler.NewHandler(r, c.SummaryService, c.Middleware)
+	summaryHandler.RegisterRoute()
+
+	webhookHandler := webhookHandler.NewHandler(r, c.WebhookService, c.Middleware)
+	webhookHandler.RegisterRoute()
+
+	adminHandler := adminHandler.NewHandler(r, c.AdminService, c.Middleware)
+	adminHandler.RegisterRoute()
+
+	// the settlement upload has no user token, it is only served once an api key guards it
+	if c.Config.Settlement.APIKey != "" {
+		settlementHandler := settlementHandler.NewHandler(r, c.SettlementService, c.Middleware)
+		settlementHandler.RegisterRoute()
+	}
+
+	healthcheckHandler := healthcheckHandler.NewHandler(r, c.HealthcheckService)
+	healthcheckHandler.RegisterRoute()
+
+	return r
+}
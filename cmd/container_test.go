@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func TestNewContainer(t *testing.T) {
+	db, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{})
+	assert.NoError(t, err)
+
+	cfg := helpers.NewConfig(map[string]string{
+		"PORT":            "8080",
+		"EVENT_PUBLISHER": "memory",
+	})
+
+	c, err := NewContainer(cfg, gormDB, nil)
+	assert.NoError(t, err)
+	defer c.External.Close()
+
+	assert.Len(t, c.Workers(), 2)
+	assert.Equal(t, constants.MapRateLimitRequests[constants.RateLimitGroupTransaction], c.Middleware.RateLimits[constants.RateLimitGroupTransaction].Requests)
+
+	routes := map[string]bool{}
+	for _, route := range c.Router().Routes() {
+		routes[route.Method+" "+route.Path] = true
+	}
+	assert.True(t, routes[http.MethodGet+" /health"])
+	assert.True(t, routes[http.MethodPost+" /transaction/v1/create"])
+	assert.True(t, routes[http.MethodPost+" /webhook/v1/register"])
+	assert.True(t, routes[http.MethodGet+" /transaction/v1/export"])
+	assert.True(t, routes[http.MethodGet+" /transaction/v1/summary"])
+	assert.True(t, routes[http.MethodGet+" /transaction/v1/categories"])
+	assert.True(t, routes[http.MethodGet+" /admin/v1/transactions/search"])
+	assert.True(t, routes[http.MethodGet+" /admin/v1/transactions"])
+	assert.True(t, routes[http.MethodPost+" /admin/v1/transactions/:reference/status"])
+	assert.True(t, routes[http.MethodPost+" /admin/v1/transactions/:reference/refund"])
+	assert.True(t, routes[http.MethodGet+" /admin/v1/transactions/:reference/history"])
+	assert.True(t, routes[http.MethodGet+" /admin/v1/limits"])
+	assert.True(t, routes[http.MethodPut+" /admin/v1/limits"])
+	assert.True(t, routes[http.MethodPut+" /admin/v1/users/:user_id/kyc-tier"])
+	assert.True(t, routes[http.MethodPost+" /admin/v1/transactions/:reference/approve"])
+	assert.True(t, routes[http.MethodPost+" /admin/v1/transactions/:reference/reject"])
+	assert.True(t, routes[http.MethodGet+" /transaction/v1/:reference"])
+	assert.False(t, routes[http.MethodPost+" /settlement/v1/import"])
+
+	assert.Equal(t, ":8080", newHttpServer(c).Addr)
+
+	cfg.Settlement.APIKey = "KEY"
+	routes = map[string]bool{}
+	for _, route := range c.Router().Routes() {
+		routes[route.Method+" "+route.Path] = true
+	}
+	assert.True(t, routes[http.MethodPost+" /settlement/v1/import"])
+
+	clientIP := func() string {
+		r := c.Router()
+		r.GET("/ip", func(ctx *gin.Context) {
+			ctx.String(http.StatusOK, ctx.ClientIP())
+		})
+		req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+		req.RemoteAddr = "10.0.0.2:4321"
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+	assert.Equal(t, "10.0.0.2", clientIP(), "no trusted proxy")
+	cfg.TrustedProxies = []string{"10.0.0.0/8"}
+	assert.Equal(t, "203.0.113.7", clientIP(), "trusted proxy")
+	cfg.TrustedProxies = []string{"proxy.internal"}
+	assert.Equal(t, "10.0.0.2", clientIP(), "invalid trusted proxy")
+
+	// a throttled client ip is turned away before its token is validated
+	c.Middleware.RateLimits[constants.RateLimitGroupClient] = models.RateLimit{Requests: 1, Period: time.Minute}
+	r := c.Router()
+	for _, expectedStatusCode := range []int{http.StatusUnauthorized, http.StatusTooManyRequests} {
+		req := httptest.NewRequest(http.MethodGet, "/transaction/v1/categories", nil)
+		req.RemoteAddr = "10.0.0.3:4321"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, expectedStatusCode, w.Code)
+	}
+
+	cfg.Settlement.MappingsFile = "missing.json"
+	_, err = NewContainer(cfg, gormDB, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/migration"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+func newMigrateCmd(opts *rootOptions) *cobra.Command {
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Manage the database schema",
+	}
+
+	migrateCmd.AddCommand(&cobra.Command{
+		Use:   "up",
+		Short: "Apply every pending migration",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withMigrator(opts, func(m *migration.Migrator) error {
+				applied, err := m.Up(cmd.Context())
+				for _, mig := range applied {
+					fmt.Fprintf(cmd.OutOrStdout(), "applied %d_%s\n", mig.Version, mig.Name)
+				}
+				if err == nil && len(applied) == 0 {
+					fmt.Fprintln(cmd.OutOrStdout(), "no pending migration")
+				}
+				return err
+			})
+		},
+	})
+
+	var steps int
+	downCmd := &cobra.Command{
+		Use:   "down",
+		Short: "Roll back the last applied migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withMigrator(opts, func(m *migration.Migrator) error {
+				rolledBack, err := m.Down(cmd.Context(), steps)
+				for _, mig := range rolledBack {
+					fmt.Fprintf(cmd.OutOrStdout(), "rolled back %d_%s\n", mig.Version, mig.Name)
+				}
+				return err
+			})
+		},
+	}
+	downCmd.Flags().IntVar(&steps, "steps", 1, "number of migrations to roll back")
+	migrateCmd.AddCommand(downCmd)
+
+	migrateCmd.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "Print every migration and whether it has been applied",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withMigrator(opts, func(m *migration.Migrator) error {
+				status, err := m.Status(cmd.Context())
+				if err != nil {
+					return err
+				}
+
+				w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+				for _, s := range status {
+					appliedAt := "pending"
+					if s.Applied {
+						appliedAt = s.AppliedAt.Format(time.RFC3339)
+					}
+					fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+				}
+				return w.Flush()
+			})
+		},
+	})
+
+	return migrateCmd
+}
+
+func withMigrator(opts *rootOptions, fn func(m *migration.Migrator) error) error {
+	cfg, err := helpers.ReadConfig(opts.envFile, opts.overrides)
+	if err != nil {
+		return err
+	}
+
+	if err := cfg.ValidateDB(); err != nil {
+		return err
+	}
+
+	db, err := helpers.SetupDB(cfg.DB)
+	if err != nil {
+		return err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return errors.Wrap(err, "failed to get database connection")
+	}
+	defer sqlDB.Close()
+
+	m, err := migration.NewMigrator(sqlDB, cfg.DB.Driver)
+	if err != nil {
+		return err
+	}
+
+	return fn(m)
+}
+
+// autoMigrate applies pending migrations on boot when DB_AUTO_MIGRATE is enabled.
+func autoMigrate(ctx context.Context, db *gorm.DB, driver string) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return errors.Wrap(err, "failed to get database connection")
+	}
+
+	m, err := migration.NewMigrator(sqlDB, driver)
+	if err != nil {
+		return err
+	}
+
+	applied, err := m.Up(ctx)
+	for _, mig := range applied {
+		logrus.Infof("applied migration %d_%s", mig.Version, mig.Name)
+	}
+
+	return err
+}
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"ewallet-transaction/external/publisher"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/interfaces"
+
+	"github.com/sirupsen/logrus"
+)
+
+func setupPublisher(cfg helpers.EventConfig) (interfaces.IEventPublisher, error) {
+	switch cfg.Publisher {
+	case "nats":
+		natsPublisher, err := publisher.NewNatsPublisher(cfg.NatsURL, cfg.SubjectPrefix)
+		if err != nil {
+			return nil, err
+		}
+		logrus.Info("publishing transaction events to nats")
+		return natsPublisher, nil
+	case "memory":
+		return publisher.NewMemoryPublisher(cfg.SubjectPrefix), nil
+	default:
+		return &publisher.NopPublisher{}, nil
+	}
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"encoding/json"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type reconcileOptions struct {
+	from           string
+	to             string
+	repair         bool
+	json           bool
+	failOnMismatch bool
+}
+
+func newReconcileCmd(opts *rootOptions) *cobra.Command {
+	reconcileOpts := &reconcileOptions{}
+
+	reconcileCmd := &cobra.Command{
+		Use:   "reconcile",
+		Short: "Compare transactions with the wallet movements and report mismatches",
+		Long: "Compare the transactions created in [--from, --to) with the wallet movements of their references.\n" +
+			"Without flags the window is RECONCILE_WINDOW ending RECONCILE_GRACE ago.\n" +
+			"--repair only fixes the local status when the wallet moved exactly the expected amount.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runReconcile(cmd, opts, reconcileOpts)
+		},
+	}
+
+	reconcileCmd.Flags().StringVar(&reconcileOpts.from, "from", "", "start of the window, RFC3339")
+	reconcileCmd.Flags().StringVar(&reconcileOpts.to, "to", "", "end of the window, RFC3339")
+	reconcileCmd.Flags().BoolVar(&reconcileOpts.repair, "repair", false, "repair the safe mismatches, defaults to RECONCILE_AUTO_REPAIR")
+	reconcileCmd.Flags().BoolVar(&reconcileOpts.json, "json", false, "print the report as json")
+	reconcileCmd.Flags().BoolVar(&reconcileOpts.failOnMismatch, "fail-on-mismatch", false, "exit with an error when a mismatch is left unrepaired")
+
+	return reconcileCmd
+}
+
+func runReconcile(cmd *cobra.Command, opts *rootOptions, reconcileOpts *reconcileOptions) error {
+	// repairs go through the transaction service, the container is needed for its side effects
+	return withContainer(opts, func(c *Container) error {
+		from, to, err := reconcileOpts.window(c.Config.Reconcile, time.Now())
+		if err != nil {
+			return err
+		}
+
+		repair := c.Config.Reconcile.AutoRepair
+		if cmd.Flags().Changed("repair") {
+			repair = reconcileOpts.repair
+		}
+
+		report, err := c.ReconcileService.Reconcile(cmd.Context(), from, to, repair)
+		if err != nil {
+			return err
+		}
+
+		if reconcileOpts.json {
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			err = enc.Encode(report)
+		} else {
+			err = printReconcileReport(cmd.OutOrStdout(), report)
+		}
+		if err != nil {
+			return err
+		}
+
+		if unrepaired := len(report.Mismatches) - report.Repaired; reconcileOpts.failOnMismatch && unrepaired > 0 {
+			return fmt.Errorf("%d mismatches left unrepaired", unrepaired)
+		}
+
+		return nil
+	})
+}
+
+// window resolves the reconciled window, each bound falls back to the configured window ending grace ago.
+func (o *reconcileOptions) window(cfg helpers.ReconcileConfig, now time.Time) (time.Time, time.Time, error) {
+	to := now.Add(-cfg.Grace)
+	if o.to != "" {
+		parsed, err := time.Parse(time.RFC3339, o.to)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.Wrap(err, "invalid --to")
+		}
+		to = parsed
+	}
+
+	from := to.Add(-cfg.Window)
+	if o.from != "" {
+		parsed, err := time.Parse(time.RFC3339, o.from)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.Wrap(err, "invalid --from")
+		}
+		from = parsed
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, errors.New("--from must be before --to")
+	}
+
+	return from, to, nil
+}
+
+func printReconcileReport(out io.Writer, report models.ReconciliationReport) error {
+	fmt.Fprintf(out, "window %s - %s, checked %d, mismatches %d, repaired %d\n",
+		report.From.Format(time.RFC3339), report.To.Format(time.RFC3339), report.Checked, len(report.Mismatches), report.Repaired)
+	if len(report.Mismatches) == 0 {
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "REFERENCE\tTYPE\tSTATUS\tKIND\tEXPECTED\tWALLET\tREPAIR\tDETAIL")
+	for _, m := range report.Mismatches {
+		repair := "-"
+		if m.RepairStatus != "" {
+			repair = m.RepairStatus
+			if m.Repaired {
+				repair += " (done)"
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.2f\t%.2f\t%s\t%s\n", m.Reference, m.TransactionType, m.TransactionStatus, m.Kind, m.ExpectedAmount, m.WalletAmount, repair, m.Detail)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"ewallet-transaction/helpers"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_reconcileOptions_window(t *testing.T) {
+	now := time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC)
+	cfg := helpers.ReconcileConfig{Window: 24 * time.Hour, Grace: 5 * time.Minute}
+
+	tests := []struct {
+		name     string
+		opts     reconcileOptions
+		wantFrom time.Time
+		wantTo   time.Time
+		wantErr  bool
+	}{
+		{
+			name:     "success default window ends grace ago",
+			wantFrom: now.Add(-5*time.Minute - 24*time.Hour),
+			wantTo:   now.Add(-5 * time.Minute),
+		},
+		{
+			name:     "success only to",
+			opts:     reconcileOptions{to: "2024-05-01T00:00:00Z"},
+			wantFrom: time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC),
+			wantTo:   time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "success from and to",
+			opts:     reconcileOptions{from: "2024-05-01T00:00:00Z", to: "2024-05-01T06:00:00Z"},
+			wantFrom: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			wantTo:   time.Date(2024, 5, 1, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "error invalid from",
+			opts:    reconcileOptions{from: "yesterday"},
+			wantErr: true,
+		},
+		{
+			name:    "error from after to",
+			opts:    reconcileOptions{from: "2024-05-02T00:00:00Z", to: "2024-05-01T00:00:00Z"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := tt.opts.window(cfg, now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("reconcileOptions.window() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				assert.True(t, tt.wantFrom.Equal(from), "from = %v", from)
+				assert.True(t, tt.wantTo.Equal(to), "to = %v", to)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"ewallet-transaction/helpers"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+type rootOptions struct {
+	envFile   string
+	overrides map[string]string
+}
+
+// Execute runs the command line, serving http and grpc when no subcommand is given.
+func Execute() {
+	if err := newRootCmd().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func newRootCmd() *cobra.Command {
+	opts := &rootOptions{}
+
+	root := &cobra.Command{
+		Use:          "ewallet-transaction",
+		Short:        "E-wallet transaction service",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runServe(opts)
+		},
+	}
+
+	root.PersistentFlags().StringVar(&opts.envFile, "env-file", ".env", "optional env file, missing file is ignored")
+	root.PersistentFlags().StringToStringVar(&opts.overrides, "set", nil, "override a config key, e.g. --set PORT=8081 (repeatable)")
+
+	root.AddCommand(
+		newServeCmd(opts),
+		newConfigCmd(opts),
+		newMigrateCmd(opts),
+		newReconcileCmd(opts),
+		newSettlementCmd(opts),
+		newTrxCmd(opts),
+	)
+
+	return root
+}
+
+func newServeCmd(opts *rootOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the http and grpc api until a shutdown signal",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runServe(opts)
+		},
+	}
+}
+
+func runServe(opts *rootOptions) error {
+	cfg, err := opts.loadConfig()
+	if err != nil {
+		return err
+	}
+
+	helpers.SetupLogger()
+
+	return Run(cfg)
+}
+
+func (o *rootOptions) loadConfig() (*helpers.Config, error) {
+	return helpers.LoadConfig(o.envFile, o.overrides)
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type settlementOptions struct {
+	provider        string
+	json            bool
+	failOnUnmatched bool
+}
+
+func newSettlementCmd(opts *rootOptions) *cobra.Command {
+	settlementCmd := &cobra.Command{
+		Use:   "settlement",
+		Short: "Import payment provider settlement files",
+	}
+
+	settlementOpts := &settlementOptions{}
+
+	importCmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "Match a settlement file to the topups and mark the settled pending ones success",
+		Long: "Read the csv settlement file of a provider with its column mapping (SETTLEMENT_MAPPINGS_FILE),\n" +
+			"match every line to a PENDING or SUCCESS topup by reference and amount and mark the matched\n" +
+			"PENDING topups SUCCESS, which credits the wallet. Lines that don't match are reported.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSettlementImport(cmd, opts, settlementOpts, args[0])
+		},
+	}
+
+	importCmd.Flags().StringVar(&settlementOpts.provider, "provider", constants.SettlementDefaultProvider, "provider of the file, selects its column mapping")
+	importCmd.Flags().BoolVar(&settlementOpts.json, "json", false, "print the report as json")
+	importCmd.Flags().BoolVar(&settlementOpts.failOnUnmatched, "fail-on-unmatched", false, "exit with an error when a line is unmatched")
+
+	settlementCmd.AddCommand(importCmd)
+
+	return settlementCmd
+}
+
+func runSettlementImport(cmd *cobra.Command, opts *rootOptions, settlementOpts *settlementOptions, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to open settlement file")
+	}
+	defer file.Close()
+
+	return withContainer(opts, func(c *Container) error {
+		report, err := c.SettlementService.Import(cmd.Context(), settlementOpts.provider, file)
+		if err != nil {
+			return err
+		}
+
+		if settlementOpts.json {
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			err = enc.Encode(report)
+		} else {
+			err = printSettlementReport(cmd.OutOrStdout(), report)
+		}
+		if err != nil {
+			return err
+		}
+
+		if settlementOpts.failOnUnmatched && len(report.Unmatched) > 0 {
+			return fmt.Errorf("%d settlement lines unmatched", len(report.Unmatched))
+		}
+
+		return nil
+	})
+}
+
+// withContainer runs fn with the whole container for commands that go through the services.
+// The notifications queued by fn are sent before the outgoing connections and the database are
+// closed once fn returns.
+func withContainer(opts *rootOptions, fn func(c *Container) error) error {
+	cfg, err := opts.loadConfig()
+	if err != nil {
+		return err
+	}
+
+	db, err := helpers.SetupDB(cfg.DB)
+	if err != nil {
+		return err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return errors.Wrap(err, "failed to get database connection")
+	}
+	defer sqlDB.Close()
+
+	c, err := NewContainer(cfg, db, nil)
+	if err != nil {
+		return err
+	}
+
+	c.NotificationService.Start()
+
+	runErr := fn(c)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	if err := c.NotificationService.Stop(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.Publisher.Close(); err != nil {
+		errs = append(errs, errors.Wrap(err, "failed to close event publisher"))
+	}
+	if err := c.External.Close(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return goerrors.Join(append([]error{runErr}, errs...)...)
+}
+
+func printSettlementReport(out io.Writer, report models.SettlementReport) error {
+	fmt.Fprintf(out, "provider %s, lines %d, matched %d, marked success %d, unmatched %d\n",
+		report.Provider, report.Lines, report.Matched, report.MarkedSuccess, len(report.Unmatched))
+	if len(report.Unmatched) == 0 {
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LINE\tREFERENCE\tAMOUNT\tREASON\tDETAIL")
+	for _, u := range report.Unmatched {
+		fmt.Fprintf(w, "%d\t%s\t%.2f\t%s\t%s\n", u.Line, u.Reference, u.Amount, u.Reason, u.Detail)
+	}
+
+	return w.Flush()
+}
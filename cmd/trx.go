@@ -0,0 +1,322 @@
+package cmd
+
+import (
+	"encoding/json"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type trxOptions struct {
+	json bool
+
+	// list
+	userID   uint64
+	trxType  string
+	status   string
+	page     int
+	pageSize int
+
+	// set-status and refund
+	adminID        uint64
+	reason         string
+	description    string
+	additionalInfo string
+
+	// expire-pending
+	olderThan time.Duration
+	limit     int
+}
+
+func newTrxCmd(opts *rootOptions) *cobra.Command {
+	trxOpts := &trxOptions{}
+
+	trxCmd := &cobra.Command{
+		Use:   "trx",
+		Short: "Inspect and fix transactions through the service layer",
+		Long: "Inspect and fix transactions without the http api. set-status and refund go through\n" +
+			"the admin service, they are recorded in the status history and the admin audit log\n" +
+			"under the --admin user id.",
+	}
+
+	trxCmd.PersistentFlags().BoolVar(&trxOpts.json, "json", false, "print the result as json")
+
+	trxCmd.AddCommand(
+		newTrxGetCmd(opts, trxOpts),
+		newTrxListCmd(opts, trxOpts),
+		newTrxSetStatusCmd(opts, trxOpts),
+		newTrxRefundCmd(opts, trxOpts),
+		newTrxExpirePendingCmd(opts, trxOpts),
+	)
+
+	return trxCmd
+}
+
+func newTrxGetCmd(opts *rootOptions, trxOpts *trxOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get REFERENCE",
+		Short: "Print a transaction and its status history",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withContainer(opts, func(c *Container) error {
+				trx, err := c.TransactionService.GetTransactionDetail(cmd.Context(), args[0])
+				if err != nil {
+					return errors.Wrap(err, "failed to get transaction")
+				}
+
+				history, err := c.TransactionRepo.GetStatusHistory(cmd.Context(), args[0])
+				if err != nil {
+					return errors.Wrap(err, "failed to get status history")
+				}
+
+				if trxOpts.json {
+					return printJSON(cmd.OutOrStdout(), struct {
+						models.Transaction
+						History []models.TransactionStatusHistory `json:"history"`
+					}{trx, history})
+				}
+
+				return printTransactionDetail(cmd.OutOrStdout(), trx, history)
+			})
+		},
+	}
+}
+
+func newTrxListCmd(opts *rootOptions, trxOpts *trxOptions) *cobra.Command {
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the transactions of a user, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withContainer(opts, func(c *Container) error {
+				resp, err := c.SearchService.Search(cmd.Context(), models.SearchRequest{
+					UserID:            trxOpts.userID,
+					TransactionType:   strings.ToUpper(trxOpts.trxType),
+					TransactionStatus: strings.ToUpper(trxOpts.status),
+					Page:              trxOpts.page,
+					PageSize:          trxOpts.pageSize,
+				})
+				if err != nil {
+					return err
+				}
+
+				if trxOpts.json {
+					return printJSON(cmd.OutOrStdout(), resp)
+				}
+
+				return printTransactionList(cmd.OutOrStdout(), resp)
+			})
+		},
+	}
+
+	listCmd.Flags().Uint64Var(&trxOpts.userID, "user", 0, "user id")
+	listCmd.Flags().StringVar(&trxOpts.trxType, "type", "", "only transactions of this type")
+	listCmd.Flags().StringVar(&trxOpts.status, "status", "", "only transactions in this status")
+	listCmd.Flags().IntVar(&trxOpts.page, "page", 1, "page to print")
+	listCmd.Flags().IntVar(&trxOpts.pageSize, "page-size", constants.DefaultSearchPageSize, "transactions per page")
+	_ = listCmd.MarkFlagRequired("user")
+
+	return listCmd
+}
+
+func newTrxSetStatusCmd(opts *rootOptions, trxOpts *trxOptions) *cobra.Command {
+	setStatusCmd := &cobra.Command{
+		Use:   "set-status REFERENCE STATUS",
+		Short: "Move a transaction to a status on behalf of its user",
+		Long: "Move a transaction to a status on behalf of its user, moving the wallet like the user would.\n" +
+			"The status flow still applies but a SUCCESS transaction can be reversed past the reversal window.",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withContainer(opts, func(c *Container) error {
+				status := strings.ToUpper(args[1])
+				err := c.AdminService.ForceStatus(cmd.Context(), models.TokenData{UserID: trxOpts.adminID}, args[0], models.AdminStatusRequest{
+					TransactionStatus: status,
+					Reason:            trxOpts.reason,
+					AdditionalInfo:    trxOpts.additionalInfo,
+				})
+				if err != nil {
+					return err
+				}
+
+				return printTrxResult(cmd.OutOrStdout(), trxOpts.json, models.CreateTransactionResponse{
+					Reference:         args[0],
+					TransactionStatus: status,
+				})
+			})
+		},
+	}
+
+	addAdminFlags(setStatusCmd, trxOpts)
+	setStatusCmd.Flags().StringVar(&trxOpts.additionalInfo, "additional-info", "", "json object merged into the additional info")
+
+	return setStatusCmd
+}
+
+func newTrxRefundCmd(opts *rootOptions, trxOpts *trxOptions) *cobra.Command {
+	refundCmd := &cobra.Command{
+		Use:   "refund REFERENCE",
+		Short: "Refund a SUCCESS purchase on behalf of its user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withContainer(opts, func(c *Container) error {
+				resp, err := c.AdminService.Refund(cmd.Context(), models.TokenData{UserID: trxOpts.adminID}, args[0], models.AdminRefundRequest{
+					Description:    trxOpts.description,
+					Reason:         trxOpts.reason,
+					AdditionalInfo: trxOpts.additionalInfo,
+				})
+				if err != nil {
+					return err
+				}
+
+				return printTrxResult(cmd.OutOrStdout(), trxOpts.json, resp)
+			})
+		},
+	}
+
+	addAdminFlags(refundCmd, trxOpts)
+	refundCmd.Flags().StringVar(&trxOpts.description, "description", "", "description of the refund, defaults to the reason")
+	refundCmd.Flags().StringVar(&trxOpts.additionalInfo, "additional-info", "", "additional info of the refund, a json object")
+
+	return refundCmd
+}
+
+func newTrxExpirePendingCmd(opts *rootOptions, trxOpts *trxOptions) *cobra.Command {
+	expireCmd := &cobra.Command{
+		Use:   "expire-pending",
+		Short: "Fail the transactions left PENDING for too long",
+		Long: "Mark FAILED the transactions still PENDING after --older-than, TRANSACTION_PENDING_TTL by default.\n" +
+			"At most --limit transactions are expired per run, oldest first.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withContainer(opts, func(c *Container) error {
+				olderThan := c.Config.Transaction.PendingTTL
+				if cmd.Flags().Changed("older-than") {
+					olderThan = trxOpts.olderThan
+				}
+				if olderThan <= 0 {
+					return errors.New("--older-than must be positive")
+				}
+
+				report, err := c.TransactionService.ExpirePending(cmd.Context(), time.Now().Add(-olderThan), trxOpts.limit)
+				if err != nil {
+					return err
+				}
+
+				if trxOpts.json {
+					return printJSON(cmd.OutOrStdout(), report)
+				}
+
+				return printExpirePendingReport(cmd.OutOrStdout(), report)
+			})
+		},
+	}
+
+	expireCmd.Flags().DurationVar(&trxOpts.olderThan, "older-than", 0, "minimum age of an expired transaction, defaults to TRANSACTION_PENDING_TTL")
+	expireCmd.Flags().IntVar(&trxOpts.limit, "limit", constants.DefaultExpirePendingLimit, "maximum number of transactions expired")
+
+	return expireCmd
+}
+
+func addAdminFlags(cmd *cobra.Command, trxOpts *trxOptions) {
+	cmd.Flags().Uint64Var(&trxOpts.adminID, "admin", 0, "user id of the operator, recorded in the audit log")
+	cmd.Flags().StringVar(&trxOpts.reason, "reason", "", "why the transaction is changed, recorded in the status history")
+	_ = cmd.MarkFlagRequired("admin")
+	_ = cmd.MarkFlagRequired("reason")
+}
+
+func printJSON(out io.Writer, v interface{}) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func printTrxResult(out io.Writer, asJSON bool, resp models.CreateTransactionResponse) error {
+	if asJSON {
+		return printJSON(out, resp)
+	}
+
+	_, err := fmt.Fprintf(out, "%s is %s\n", resp.Reference, resp.TransactionStatus)
+	return err
+}
+
+func printTransactionDetail(out io.Writer, trx models.Transaction, history []models.TransactionStatusHistory) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "REFERENCE\t%s\n", trx.Reference)
+	fmt.Fprintf(w, "USER\t%d\n", trx.UserID)
+	fmt.Fprintf(w, "TYPE\t%s\n", trx.TransactionType)
+	fmt.Fprintf(w, "STATUS\t%s\n", trx.TransactionStatus)
+	fmt.Fprintf(w, "AMOUNT\t%.2f\n", trx.Amount)
+	fmt.Fprintf(w, "DESCRIPTION\t%s\n", trx.Description)
+	if trx.MerchantID != 0 {
+		fmt.Fprintf(w, "MERCHANT\t%d %s\n", trx.MerchantID, trx.MerchantName)
+	}
+	if trx.Category != "" {
+		fmt.Fprintf(w, "CATEGORY\t%s\n", trx.Category)
+	}
+	if len(trx.Tags) > 0 {
+		fmt.Fprintf(w, "TAGS\t%s\n", strings.Join(trx.Tags, ", "))
+	}
+	if trx.AdditionalInfo != "" {
+		fmt.Fprintf(w, "ADDITIONAL INFO\t%s\n", trx.AdditionalInfo)
+	}
+	fmt.Fprintf(w, "CREATED AT\t%s\n", trx.CreatedAt.Format(time.RFC3339))
+	fmt.Fprintf(w, "UPDATED AT\t%s\n", trx.UpdatedAt.Format(time.RFC3339))
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(history) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(out)
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "AT\tFROM\tTO\tACTOR\tREASON")
+	for _, h := range history {
+		from := h.FromStatus
+		if from == "" {
+			from = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", h.CreatedAt.Format(time.RFC3339), from, h.ToStatus, h.Actor, h.Reason)
+	}
+
+	return w.Flush()
+}
+
+func printTransactionList(out io.Writer, resp models.SearchResponse) error {
+	fmt.Fprintf(out, "page %d, page size %d, total %d\n", resp.Page, resp.PageSize, resp.Total)
+	if len(resp.Items) == 0 {
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "REFERENCE\tTYPE\tSTATUS\tAMOUNT\tCREATED AT\tDESCRIPTION")
+	for _, item := range resp.Items {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%.2f\t%s\t%s\n", item.Reference, item.TransactionType, item.TransactionStatus, item.Amount, item.CreatedAt.Format(time.RFC3339), item.Description)
+	}
+
+	return w.Flush()
+}
+
+func printExpirePendingReport(out io.Writer, report models.ExpirePendingReport) error {
+	fmt.Fprintf(out, "pending before %s, checked %d, expired %d, failed %d\n",
+		report.Before.Format(time.RFC3339), report.Checked, len(report.Expired), len(report.Failed))
+	if len(report.Failed) == 0 {
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "REFERENCE\tERROR")
+	for _, f := range report.Failed {
+		fmt.Fprintf(w, "%s\t%s\n", f.Reference, f.Error)
+	}
+
+	return w.Flush()
+}
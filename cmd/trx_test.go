@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"bytes"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_trxCmd_requiredFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{
+			name:    "set-status without reason",
+			args:    []string{"trx", "set-status", "REFERENCE", "REVERSED", "--admin", "7"},
+			wantErr: `required flag(s) "reason" not set`,
+		},
+		{
+			name:    "refund without admin",
+			args:    []string{"trx", "refund", "REFERENCE", "--reason", "chargeback"},
+			wantErr: `required flag(s) "admin" not set`,
+		},
+		{
+			name:    "list without user",
+			args:    []string{"trx", "list"},
+			wantErr: `required flag(s) "user" not set`,
+		},
+		{
+			name:    "get without reference",
+			args:    []string{"trx", "get"},
+			wantErr: "accepts 1 arg(s), received 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := newRootCmd()
+			root.SetArgs(tt.args)
+			root.SetOut(&bytes.Buffer{})
+			root.SetErr(&bytes.Buffer{})
+
+			err := root.Execute()
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_printTransactionDetail(t *testing.T) {
+	createdAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	trx := models.Transaction{
+		UserID:            3,
+		Amount:            15000,
+		TransactionType:   constants.TransactionTypePurchase,
+		TransactionStatus: constants.TransactionStatusReversed,
+		Reference:         "REFERENCE",
+		Description:       "kopi susu",
+		CreatedAt:         createdAt,
+		UpdatedAt:         createdAt,
+	}
+	history := []models.TransactionStatusHistory{
+		{ToStatus: constants.TransactionStatusPending, Actor: "user:3", CreatedAt: createdAt},
+		{FromStatus: constants.TransactionStatusPending, ToStatus: constants.TransactionStatusReversed, Actor: "admin:7", Reason: "chargeback", CreatedAt: createdAt},
+	}
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, printTransactionDetail(out, trx, history))
+	assert.Equal(t, "REFERENCE    REFERENCE\n"+
+		"USER         3\n"+
+		"TYPE         PURCHASE\n"+
+		"STATUS       REVERSED\n"+
+		"AMOUNT       15000.00\n"+
+		"DESCRIPTION  kopi susu\n"+
+		"CREATED AT   2025-01-01T10:00:00Z\n"+
+		"UPDATED AT   2025-01-01T10:00:00Z\n"+
+		"\n"+
+		"AT                    FROM     TO        ACTOR    REASON\n"+
+		"2025-01-01T10:00:00Z  -        PENDING   user:3   \n"+
+		"2025-01-01T10:00:00Z  PENDING  REVERSED  admin:7  chargeback\n", out.String())
+}
+
+func Test_printExpirePendingReport(t *testing.T) {
+	out := &bytes.Buffer{}
+	err := printExpirePendingReport(out, models.ExpirePendingReport{
+		Before:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Checked: 2,
+		Expired: []string{"EXPIRED"},
+		Failed:  []models.ExpirePendingFailure{{Reference: "SETTLED", Error: "transaction status flow invalid"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "pending before 2025-01-01T00:00:00Z, checked 2, expired 1, failed 1\n"+
+		"REFERENCE  ERROR\n"+
+		"SETTLED    transaction status flow invalid\n", out.String())
+}
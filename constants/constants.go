@@ -3,9 +3,16 @@ package constants
 import "time"
 
 const (
-	SuccessMessage      = "success"
-	ErrFailedBadRequest = "Data tidak sesuai"
-	ErrServerError      = "Terjadi kesalahan pada server"
+	SuccessMessage       = "success"
+	ErrFailedBadRequest  = "Data tidak sesuai"
+	ErrServerError       = "Terjadi kesalahan pada server"
+	ErrRequestCanceled   = "Permintaan dibatalkan"
+	ErrRequestTimeout    = "Permintaan melebihi batas waktu"
+	ErrLimitExceeded     = "Transaksi melebihi batas limit"
+	ErrTransactionOnHold = "Transaksi ditahan untuk ditinjau"
+	ErrTransactionDenied = "Transaksi ditolak"
+	ErrTooManyRequests   = "Terlalu banyak permintaan, coba lagi nanti"
+	ErrStatusConflict    = "Status transaksi telah berubah"
 )
 
 const (
@@ -13,8 +20,18 @@ const (
 	TransactionStatusSuccess  = "SUCCESS"
 	TransactionStatusFailed   = "FAILED"
 	TransactionStatusReversed = "REVERSED"
+	// ON_HOLD is only set by the fraud screening, an admin approves or rejects it
+	TransactionStatusOnHold = "ON_HOLD"
 )
 
+var MapTransactionStatus = map[string]bool{
+	TransactionStatusPending:  true,
+	TransactionStatusSuccess:  true,
+	TransactionStatusFailed:   true,
+	TransactionStatusReversed: true,
+	TransactionStatusOnHold:   true,
+}
+
 const (
 	TransactionTypeTopup    = "TOPUP"
 	TransactionTypePurchase = "PURCHASE"
@@ -32,12 +49,361 @@ var MapTransactionType = map[string]bool{
 
 //SUCCESS -> REVERSED
 
+// ON_HOLD -> SUCCESS
+// ON_HOLD -> FAILED
+
 var MapTransactionStatusFlow = map[string][]string{
 	TransactionStatusPending: {TransactionStatusSuccess, TransactionStatusFailed},
 	TransactionStatusSuccess: {TransactionStatusReversed},
 	TransactionStatusFailed:  {TransactionStatusSuccess},
+	TransactionStatusOnHold:  {TransactionStatusSuccess, TransactionStatusFailed},
 }
 
 const (
 	MaximumReversalDuration = time.Hour * 24
+	// MaxReferenceAttempts is how many generated references a create tries before giving up on duplicates
+	MaxReferenceAttempts = 3
+)
+
+const (
+	DefaultShutdownTimeout = time.Second * 30
+)
+
+const (
+	DBDriverMySQL    = "mysql"
+	DBDriverPostgres = "postgres"
+	DBDriverSQLite   = "sqlite"
+)
+
+const (
+	DefaultDBMaxOpenConns    = 25
+	DefaultDBMaxIdleConns    = 10
+	DefaultDBConnMaxLifetime = time.Minute * 30
+	DefaultDBReadTimeout     = time.Second * 5
+	DefaultDBWriteTimeout    = time.Second * 10
+)
+
+const (
+	NotificationEventPurchaseSuccess  = "purchase_success"
+	NotificationEventTopupSuccess     = "topup_success"
+	NotificationEventTopupFailed      = "topup_failed"
+	NotificationEventReversal         = "reversal"
+	NotificationEventRefundIssued     = "refund_issued"
+	NotificationEventTransferReceived = "transfer_received"
+	NotificationEventPendingExpired   = "pending_expired"
+)
+
+// default template for each notification event,
+// can be overridden with NOTIFICATION_TEMPLATE_<EVENT> env
+var MapNotificationTemplate = map[string]string{
+	NotificationEventPurchaseSuccess:  "purchase_success",
+	NotificationEventTopupSuccess:     "topup_success",
+	NotificationEventTopupFailed:      "topup_failed",
+	NotificationEventReversal:         "reversal",
+	NotificationEventRefundIssued:     "refund_issued",
+	NotificationEventTransferReceived: "transfer_received",
+	NotificationEventPendingExpired:   "pending_expired",
+}
+
+// transaction type -> transaction status -> notification event
+var MapNotificationEvent = map[string]map[string]string{
+	TransactionTypeTopup: {
+		TransactionStatusSuccess:  NotificationEventTopupSuccess,
+		TransactionStatusFailed:   NotificationEventTopupFailed,
+		TransactionStatusReversed: NotificationEventReversal,
+	},
+	TransactionTypePurchase: {
+		TransactionStatusSuccess:  NotificationEventPurchaseSuccess,
+		TransactionStatusReversed: NotificationEventReversal,
+	},
+}
+
+const (
+	DefaultNotificationWorkers       = 4
+	DefaultNotificationQueueSize     = 100
+	DefaultNotificationMaxRetry      = 3
+	DefaultNotificationRetryInterval = time.Second
+	DefaultNotificationSendTimeout   = time.Second * 5
+)
+
+const (
+	EventTransactionCreated       = "transaction.created"
+	EventTransactionStatusChanged = "transaction.status_changed"
+	EventTransactionRefunded      = "transaction.refunded"
+)
+
+// schema version of each event payload, bump when the payload changes incompatibly
+var MapEventVersion = map[string]int{
+	EventTransactionCreated:       1,
+	EventTransactionStatusChanged: 1,
+	EventTransactionRefunded:      1,
+}
+
+const (
+	DefaultEventSubjectPrefix = "ewallet"
+)
+
+const (
+	WebhookDeliveryStatusPending = "PENDING"
+	WebhookDeliveryStatusSuccess = "SUCCESS"
+	WebhookDeliveryStatusFailed  = "FAILED"
+)
+
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+const (
+	DefaultWebhookMaxAttempts  = 8
+	DefaultWebhookBaseBackoff  = time.Second * 10
+	DefaultWebhookPollInterval = time.Second * 5
+	DefaultWebhookBatchSize    = 50
+	DefaultWebhookTimeout      = time.Second * 10
+)
+
+const (
+	LedgerAccountTypeWallet     = "WALLET"
+	LedgerAccountTypeSettlement = "SETTLEMENT"
+	LedgerAccountTypeFee        = "FEE"
+)
+
+// codes of the shared ledger accounts, every user wallet gets its own WALLET-<user id> account
+const (
+	LedgerAccountSettlement   = "SETTLEMENT"
+	LedgerAccountFee          = "FEE"
+	LedgerAccountWalletPrefix = "WALLET-"
+)
+
+const (
+	JournalDirectionDebit  = "DEBIT"
+	JournalDirectionCredit = "CREDIT"
+)
+
+const (
+	JournalEntryTypeSuccess  = "SUCCESS"
+	JournalEntryTypeReversed = "REVERSED"
+	JournalEntryTypeRefund   = "REFUND"
+)
+
+const (
+	DefaultLedgerPurchaseFeeBps = 0
+)
+
+const (
+	WalletMovementCredit = "CREDIT"
+	WalletMovementDebit  = "DEBIT"
+)
+
+// WalletReferenceReversedPrefix marks the wallet movement that undoes a reversed transaction.
+const (
+	WalletReferenceReversedPrefix = "REVERSED-"
+)
+
+const (
+	ReconciliationMismatchMissing = "MISSING"
+	ReconciliationMismatchAmount  = "AMOUNT_MISMATCH"
+	ReconciliationMismatchStatus  = "STATUS_MISMATCH"
+)
+
+const (
+	DefaultReconcileWindow = time.Hour * 24
+	DefaultReconcileGrace  = time.Minute * 5
+)
+
+const (
+	SettlementUnmatchedInvalidLine    = "INVALID_LINE"
+	SettlementUnmatchedDuplicate      = "DUPLICATE"
+	SettlementUnmatchedNotFound       = "NOT_FOUND"
+	SettlementUnmatchedAmountMismatch = "AMOUNT_MISMATCH"
+	SettlementUnmatchedInvalidStatus  = "INVALID_STATUS"
+	SettlementUnmatchedUpdateFailed   = "UPDATE_FAILED"
+)
+
+const (
+	SettlementAPIKeyHeader    = "X-Settlement-Key"
+	SettlementDefaultProvider = "default"
+	SettlementMaxUploadSize   = 10 << 20
+)
+
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+	ExportFormatPDF  = "pdf"
+
+	ExportDateLayout      = "2006-01-02"
+	DefaultExportMaxRange = time.Hour * 24 * 366
+
+	ExportRowOpening = "OPENING_BALANCE"
+	ExportRowClosing = "CLOSING_BALANCE"
+)
+
+var MapExportContentType = map[string]string{
+	ExportFormatCSV:  "text/csv",
+	ExportFormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	ExportFormatPDF:  "application/pdf",
+}
+
+const (
+	SummaryPeriodDay   = "day"
+	SummaryPeriodWeek  = "week"
+	SummaryPeriodMonth = "month"
+
+	SummaryDateLayout       = "2006-01-02"
+	DefaultSummaryTimezone  = "Asia/Jakarta"
+	DefaultSummaryCacheTTL  = time.Second * 30
+	DefaultSummaryCacheSize = 10000
+)
+
+var MapSummaryPeriod = map[string]bool{
+	SummaryPeriodDay:   true,
+	SummaryPeriodWeek:  true,
+	SummaryPeriodMonth: true,
+}
+
+const (
+	SummaryGroupByCategory = "category"
+	SummaryGroupByMerchant = "merchant"
+	SummaryGroupByTag      = "tag"
+)
+
+var MapSummaryGroupBy = map[string]bool{
+	SummaryGroupByCategory: true,
+	SummaryGroupByMerchant: true,
+	SummaryGroupByTag:      true,
+}
+
+const (
+	SearchDateLayout      = "2006-01-02"
+	MinSearchQueryLength  = 2
+	DefaultSearchPageSize = 20
+	MaxSearchPageSize     = 100
+)
+
+// actors recorded in the status history, users and admins are recorded with their id
+const (
+	StatusActorUserPrefix     = "user:"
+	StatusActorAdminPrefix    = "admin:"
+	StatusActorSettlement     = "settlement"
+	StatusActorReconciliation = "reconciliation"
+	StatusActorExpiry         = "expiry"
+	StatusActorFraud          = "fraud"
+)
+
+const (
+	AdminActionListTransactions   = "LIST_TRANSACTIONS"
+	AdminActionSearchTransactions = "SEARCH_TRANSACTIONS"
+	AdminActionForceStatus        = "FORCE_STATUS"
+	AdminActionRefund             = "REFUND"
+	AdminActionStatusHistory      = "VIEW_STATUS_HISTORY"
+	AdminActionListLimits         = "LIST_LIMITS"
+	AdminActionSetLimit           = "SET_LIMIT"
+	AdminActionGetKYCTier         = "VIEW_KYC_TIER"
+	AdminActionSetKYCTier         = "SET_KYC_TIER"
+	AdminActionApprove            = "APPROVE_TRANSACTION"
+	AdminActionReject             = "REJECT_TRANSACTION"
+
+	AdminAuditOutcomeSuccess = "SUCCESS"
+	AdminAuditOutcomeFailed  = "FAILED"
+)
+
+const (
+	// DefaultPendingTTL is how long a transaction may stay PENDING before expire-pending fails it.
+	DefaultPendingTTL         = time.Hour * 24
+	DefaultExpirePendingLimit = 500
+)
+
+// KYC tiers of the transaction limits, a user without a recorded tier is BASIC
+const (
+	KYCTierBasic    = "BASIC"
+	KYCTierVerified = "VERIFIED"
+
+	DefaultKYCTier       = KYCTierBasic
+	DefaultLimitTimezone = "Asia/Jakarta"
+)
+
+var MapKYCTier = map[string]bool{
+	KYCTierBasic:    true,
+	KYCTierVerified: true,
+}
+
+// LimitExceededCode identifies a rejected transaction in the response,
+// the Limit* constants tell which limit rejected it.
+const (
+	LimitExceededCode = "LIMIT_EXCEEDED"
+
+	LimitMaxAmount     = "MAX_AMOUNT"
+	LimitDailyAmount   = "DAILY_AMOUNT"
+	LimitDailyCount    = "DAILY_COUNT"
+	LimitMonthlyAmount = "MONTHLY_AMOUNT"
+	LimitMonthlyCount  = "MONTHLY_COUNT"
+)
+
+// DeviceIDHeader identifies the device a transaction is created from, for the fraud screening.
+const DeviceIDHeader = "X-Device-ID"
+
+// decisions of the fraud screening, the most severe decision of the rules wins
+const (
+	FraudDecisionAllow  = "ALLOW"
+	FraudDecisionReview = "REVIEW"
+	FraudDecisionDeny   = "DENY"
+)
+
+var MapFraudDecisionSeverity = map[string]int{
+	FraudDecisionAllow:  0,
+	FraudDecisionReview: 1,
+	FraudDecisionDeny:   2,
+}
+
+const (
+	FraudRuleVelocity        = "VELOCITY"
+	FraudRuleAmountDeviation = "AMOUNT_DEVIATION"
+	FraudRuleNewDevice       = "NEW_DEVICE"
+	FraudRuleNewIP           = "NEW_IP"
+	FraudRuleRefundCycle     = "REFUND_CYCLE"
+)
+
+const (
+	DefaultFraudVelocityWindow = time.Minute * 10
+	DefaultFraudVelocityReview = 5
+	DefaultFraudVelocityDeny   = 10
+	DefaultFraudLookback       = time.Hour * 24 * 90
+	DefaultFraudMinHistory     = 3
+	DefaultFraudAmountFactor   = 5
+	DefaultFraudRefundWindow   = time.Hour * 24
+	DefaultFraudRefundReview   = 3
+	DefaultFraudRefundDeny     = 5
+)
+
+// route groups of the rate limiter, each is limited to RATE_LIMIT_<GROUP>_REQUESTS per RATE_LIMIT_<GROUP>_PERIOD
+// for every user, or every client ip on the routes without a user token.
+// RateLimitGroupClient limits every client ip on all the routes before the user token is validated.
+const (
+	RateLimitGroupClient      = "client"
+	RateLimitGroupTransaction = "transaction"
+	RateLimitGroupExport      = "export"
+	RateLimitGroupSummary     = "summary"
+	RateLimitGroupWebhook     = "webhook"
+	RateLimitGroupAdmin       = "admin"
+	RateLimitGroupSettlement  = "settlement"
+)
+
+// default requests of each route group per DefaultRateLimitPeriod, zero disables the limit of a group
+var MapRateLimitRequests = map[string]int{
+	RateLimitGroupClient:      300,
+	RateLimitGroupTransaction: 60,
+	RateLimitGroupExport:      5,
+	RateLimitGroupSummary:     30,
+	RateLimitGroupWebhook:     30,
+	RateLimitGroupAdmin:       120,
+	RateLimitGroupSettlement:  10,
+}
+
+const DefaultRateLimitPeriod = time.Minute
+
+const (
+	RateLimitLimitHeader     = "X-RateLimit-Limit"
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	RetryAfterHeader         = "Retry-After"
 )
@@ -4,24 +4,12 @@ import (
 	"context"
 	"ewallet-transaction/constants"
 	"ewallet-transaction/external/proto/notification"
-	"ewallet-transaction/helpers"
 	"fmt"
-
-	"github.com/pkg/errors"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
-func (*External) SendNotification(ctx context.Context, recipient string, templateName string, placeHolder map[string]string) error {
-
-	conn, err := grpc.NewClient(helpers.GetEnv("NOTIFICATION_GRPC_HOST", "7003"), grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		return errors.Wrap(err, "failed to dial grpc")
-	}
-
-	defer conn.Close()
+func (e *External) SendNotification(ctx context.Context, recipient string, templateName string, placeHolder map[string]string) error {
 
-	client := notification.NewNotificationServiceClient(conn)
+	client := notification.NewNotificationServiceClient(e.notificationConn)
 	request := &notification.SendNotificationRequest{
 		Recipient:    recipient,
 		TemplateName: templateName,
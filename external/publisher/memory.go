@@ -0,0 +1,48 @@
+package publisher
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+	"sync"
+)
+
+// MemoryPublisher keeps every published message in memory, used by tests and local runs.
+type MemoryPublisher struct {
+	mu            sync.Mutex
+	subjectPrefix string
+	messages      []Message
+}
+
+func NewMemoryPublisher(subjectPrefix string) *MemoryPublisher {
+	return &MemoryPublisher{
+		subjectPrefix: subjectPrefix,
+	}
+}
+
+func (p *MemoryPublisher) Publish(ctx context.Context, evt models.TransactionEvent) error {
+	msg, err := Encode(p.subjectPrefix, evt)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.messages = append(p.messages, msg)
+
+	return nil
+}
+
+func (p *MemoryPublisher) Messages() []Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	result := make([]Message, len(p.messages))
+	copy(result, p.messages)
+
+	return result
+}
+
+func (p *MemoryPublisher) Close() error {
+	return nil
+}
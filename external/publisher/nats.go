@@ -0,0 +1,79 @@
+package publisher
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/pkg/errors"
+)
+
+// NatsPublisher publishes the events to a JetStream stream. The stream drops a message whose
+// Nats-Msg-Id it already stored within its duplicate window, a retried publish is stored once.
+type NatsPublisher struct {
+	conn          *nats.Conn
+	js            jetstream.JetStream
+	subjectPrefix string
+}
+
+// NewNatsPublisher connects to nats and checks a stream captures the <subjectPrefix>.> subjects,
+// without it every publish would fail.
+func NewNatsPublisher(url string, subjectPrefix string) (*NatsPublisher, error) {
+	conn, err := nats.Connect(url, nats.Name("ewallet-transaction"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to nats")
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "failed to create jetstream context")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = js.StreamNameBySubject(ctx, subjectPrefix+".>")
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrapf(err, "failed to find the jetstream stream of %s.>", subjectPrefix)
+	}
+
+	return &NatsPublisher{
+		conn:          conn,
+		js:            js,
+		subjectPrefix: subjectPrefix,
+	}, nil
+}
+
+func (p *NatsPublisher) Publish(ctx context.Context, evt models.TransactionEvent) error {
+	msg, err := Encode(p.subjectPrefix, evt)
+	if err != nil {
+		return err
+	}
+
+	natsMsg := nats.NewMsg(msg.Subject)
+	natsMsg.Data = msg.Data
+	for key, val := range msg.Headers {
+		natsMsg.Header.Set(key, val)
+	}
+	natsMsg.Header.Set("Key", msg.Key)
+
+	_, err = p.js.PublishMsg(ctx, natsMsg)
+	if err != nil {
+		return errors.Wrap(err, "failed to publish event to nats")
+	}
+
+	return nil
+}
+
+// Close flushes the pending messages and closes the connection.
+func (p *NatsPublisher) Close() error {
+	err := p.conn.Drain()
+	if err != nil {
+		return errors.Wrap(err, "failed to drain nats connection")
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package publisher
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+)
+
+// NopPublisher drops every event, used when no broker is configured.
+type NopPublisher struct {
+}
+
+func (*NopPublisher) Publish(ctx context.Context, evt models.TransactionEvent) error {
+	return nil
+}
+
+func (*NopPublisher) Close() error {
+	return nil
+}
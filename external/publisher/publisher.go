@@ -0,0 +1,85 @@
+package publisher
+
+import (
+	"ewallet-transaction/constants"
+	"ewallet-transaction/external/proto/event"
+	"ewallet-transaction/internal/models"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type Message struct {
+	Subject string
+	Key     string
+	Headers map[string]string
+	Data    []byte
+}
+
+// Encode turns a transaction event into a protobuf encoded broker message.
+// The subject is <prefix>.<key>.<event type> so brokers partitioning by subject
+// token keep every event of one user in order.
+func Encode(prefix string, evt models.TransactionEvent) (Message, error) {
+	var (
+		msg = Message{}
+	)
+
+	envelope := &event.TransactionEvent{
+		EventId:    evt.ID,
+		EventType:  evt.Type,
+		Version:    int32(evt.Version),
+		Key:        evt.Key(),
+		OccurredAt: timestamppb.New(evt.OccurredAt),
+	}
+
+	trx := &event.Transaction{
+		UserId:            evt.Transaction.UserID,
+		Reference:         evt.Transaction.Reference,
+		TransactionType:   evt.Transaction.TransactionType,
+		TransactionStatus: evt.Transaction.TransactionStatus,
+		Amount:            evt.Transaction.Amount,
+		Description:       evt.Transaction.Description,
+		AdditionalInfo:    evt.Transaction.AdditionalInfo,
+		CreatedAt:         timestamppb.New(evt.Transaction.CreatedAt),
+		MerchantId:        evt.Transaction.MerchantID,
+		MerchantName:      evt.Transaction.MerchantName,
+		Category:          evt.Transaction.Category,
+		Tags:              evt.Transaction.Tags,
+	}
+
+	switch evt.Type {
+	case constants.EventTransactionCreated:
+		envelope.Payload = &event.TransactionEvent_Created{
+			Created: &event.TransactionCreated{Transaction: trx},
+		}
+	case constants.EventTransactionStatusChanged:
+		envelope.Payload = &event.TransactionEvent_StatusChanged{
+			StatusChanged: &event.TransactionStatusChanged{Transaction: trx, PreviousStatus: evt.PreviousStatus},
+		}
+	case constants.EventTransactionRefunded:
+		envelope.Payload = &event.TransactionEvent_Refunded{
+			Refunded: &event.TransactionRefunded{Transaction: trx, OriginalReference: evt.OriginalReference},
+		}
+	default:
+		return msg, fmt.Errorf("unknown event type %s", evt.Type)
+	}
+
+	data, err := proto.Marshal(envelope)
+	if err != nil {
+		return msg, errors.Wrap(err, "failed to marshal event")
+	}
+
+	msg.Subject = fmt.Sprintf("%s.%s.%s", prefix, envelope.Key, evt.Type)
+	msg.Key = envelope.Key
+	msg.Headers = map[string]string{
+		"Nats-Msg-Id":   evt.ID,
+		"Event-Type":    evt.Type,
+		"Event-Version": fmt.Sprint(evt.Version),
+		"Content-Type":  "application/x-protobuf",
+	}
+	msg.Data = data
+
+	return msg, nil
+}
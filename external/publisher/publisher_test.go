@@ -0,0 +1,126 @@
+package publisher
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/external/proto/event"
+	"ewallet-transaction/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMemoryPublisher_Publish(t *testing.T) {
+	now := time.Now()
+
+	trx := models.Transaction{
+		UserID:            7,
+		Amount:            100000,
+		TransactionType:   constants.TransactionTypePurchase,
+		TransactionStatus: constants.TransactionStatusSuccess,
+		Reference:         "REFERENCE",
+		Description:       "DESCRIPTION",
+		MerchantID:        9,
+		MerchantName:      "MERCHANT",
+		Category:          "FOOD",
+		Tags:              []string{"lunch", "office"},
+		CreatedAt:         now,
+	}
+
+	tests := []struct {
+		name        string
+		event       models.TransactionEvent
+		wantSubject string
+		wantErr     bool
+		check       func(t *testing.T, envelope *event.TransactionEvent)
+	}{
+		{
+			name: "success created",
+			event: models.TransactionEvent{
+				ID:          "ID-1",
+				Type:        constants.EventTransactionCreated,
+				Version:     1,
+				OccurredAt:  now,
+				Transaction: trx,
+			},
+			wantSubject: "ewallet.7.transaction.created",
+			check: func(t *testing.T, envelope *event.TransactionEvent) {
+				assert.Equal(t, "REFERENCE", envelope.GetCreated().GetTransaction().GetReference())
+				assert.Equal(t, uint64(9), envelope.GetCreated().GetTransaction().GetMerchantId())
+				assert.Equal(t, "MERCHANT", envelope.GetCreated().GetTransaction().GetMerchantName())
+				assert.Equal(t, "FOOD", envelope.GetCreated().GetTransaction().GetCategory())
+				assert.Equal(t, []string{"lunch", "office"}, envelope.GetCreated().GetTransaction().GetTags())
+			},
+		},
+		{
+			name: "success status changed",
+			event: models.TransactionEvent{
+				ID:             "ID-2",
+				Type:           constants.EventTransactionStatusChanged,
+				Version:        1,
+				OccurredAt:     now,
+				PreviousStatus: constants.TransactionStatusPending,
+				Transaction:    trx,
+			},
+			wantSubject: "ewallet.7.transaction.status_changed",
+			check: func(t *testing.T, envelope *event.TransactionEvent) {
+				assert.Equal(t, constants.TransactionStatusPending, envelope.GetStatusChanged().GetPreviousStatus())
+				assert.Equal(t, constants.TransactionStatusSuccess, envelope.GetStatusChanged().GetTransaction().GetTransactionStatus())
+				assert.Equal(t, []string{"lunch", "office"}, envelope.GetStatusChanged().GetTransaction().GetTags())
+			},
+		},
+		{
+			name: "success refunded",
+			event: models.TransactionEvent{
+				ID:                "ID-3",
+				Type:              constants.EventTransactionRefunded,
+				Version:           1,
+				OccurredAt:        now,
+				OriginalReference: "REFERENCE",
+				Transaction:       trx,
+			},
+			wantSubject: "ewallet.7.transaction.refunded",
+			check: func(t *testing.T, envelope *event.TransactionEvent) {
+				assert.Equal(t, "REFERENCE", envelope.GetRefunded().GetOriginalReference())
+			},
+		},
+		{
+			name: "error unknown event",
+			event: models.TransactionEvent{
+				ID:          "ID-4",
+				Type:        "UNKNOWN",
+				Transaction: trx,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewMemoryPublisher(constants.DefaultEventSubjectPrefix)
+			err := p.Publish(context.Background(), tt.event)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MemoryPublisher.Publish() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				assert.Empty(t, p.Messages())
+				return
+			}
+
+			messages := p.Messages()
+			assert.Len(t, messages, 1)
+			assert.Equal(t, tt.wantSubject, messages[0].Subject)
+			assert.Equal(t, "7", messages[0].Key)
+			assert.Equal(t, tt.event.ID, messages[0].Headers["Nats-Msg-Id"])
+
+			envelope := &event.TransactionEvent{}
+			assert.NoError(t, proto.Unmarshal(messages[0].Data, envelope))
+			assert.Equal(t, tt.event.Type, envelope.EventType)
+			assert.Equal(t, int32(1), envelope.Version)
+			assert.Equal(t, "7", envelope.Key)
+			tt.check(t, envelope)
+		})
+	}
+}
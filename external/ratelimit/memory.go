@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the idle buckets are dropped.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	period  time.Duration
+}
+
+// MemoryStore keeps the token buckets in memory, each instance of the service limits on its own.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: map[string]*bucket{},
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit models.RateLimit) (models.RateLimitResult, error) {
+	now := s.now()
+	capacity := float64(limit.Requests)
+	// tokens refilled per nanosecond
+	rate := capacity / float64(limit.Period)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		s.buckets[key] = b
+	}
+	b.period = limit.Period
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+float64(elapsed)*rate)
+		b.updated = now
+	}
+
+	if b.tokens < 1 {
+		return models.RateLimitResult{
+			RetryAfter: time.Duration(math.Ceil((1 - b.tokens) / rate)),
+		}, nil
+	}
+
+	b.tokens--
+	return models.RateLimitResult{
+		Allowed:   true,
+		Remaining: int(b.tokens),
+	}, nil
+}
+
+// sweep drops the buckets idle for a whole period, they are full again and a new bucket is the same.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if now.Sub(b.updated) >= b.period {
+			delete(s.buckets, key)
+		}
+	}
+}
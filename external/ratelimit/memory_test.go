@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore_Take(t *testing.T) {
+	ctx := context.Background()
+	limit := models.RateLimit{Requests: 3, Period: time.Minute}
+
+	now := time.Now()
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+
+	for remaining := 2; remaining >= 0; remaining-- {
+		result, err := s.Take(ctx, "user:1", limit)
+		assert.NoError(t, err)
+		assert.Equal(t, models.RateLimitResult{Allowed: true, Remaining: remaining}, result)
+	}
+
+	result, err := s.Take(ctx, "user:1", limit)
+	assert.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 20*time.Second, result.RetryAfter)
+
+	// another key has its own bucket
+	result, err = s.Take(ctx, "user:2", limit)
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	// a token is refilled every period / requests
+	now = now.Add(5 * time.Second)
+	result, err = s.Take(ctx, "user:1", limit)
+	assert.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 15*time.Second, result.RetryAfter)
+
+	now = now.Add(15 * time.Second)
+	result, err = s.Take(ctx, "user:1", limit)
+	assert.NoError(t, err)
+	assert.Equal(t, models.RateLimitResult{Allowed: true, Remaining: 0}, result)
+
+	// the bucket never holds more than its requests
+	now = now.Add(time.Hour)
+	result, err = s.Take(ctx, "user:1", limit)
+	assert.NoError(t, err)
+	assert.Equal(t, models.RateLimitResult{Allowed: true, Remaining: 2}, result)
+}
+
+func TestMemoryStore_sweep(t *testing.T) {
+	ctx := context.Background()
+
+	now := time.Now()
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+
+	_, err := s.Take(ctx, "idle", models.RateLimit{Requests: 1, Period: time.Second})
+	assert.NoError(t, err)
+	_, err = s.Take(ctx, "slow", models.RateLimit{Requests: 1, Period: time.Hour})
+	assert.NoError(t, err)
+
+	now = now.Add(sweepInterval)
+	_, err = s.Take(ctx, "new", models.RateLimit{Requests: 1, Period: time.Second})
+	assert.NoError(t, err)
+
+	assert.NotContains(t, s.buckets, "idle")
+	assert.Contains(t, s.buckets, "slow")
+	assert.Contains(t, s.buckets, "new")
+}
@@ -2,11 +2,13 @@ package external
 
 import (
 	"context"
+	goerrors "errors"
 	"ewallet-transaction/constants"
 	"ewallet-transaction/external/proto/tokenvalidation"
 	"ewallet-transaction/helpers"
 	"ewallet-transaction/internal/models"
 	"fmt"
+	"net/http"
 
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
@@ -14,21 +16,53 @@ import (
 )
 
 type External struct {
+	config           helpers.ExternalConfig
+	httpClient       *http.Client
+	umsConn          *grpc.ClientConn
+	notificationConn *grpc.ClientConn
 }
 
-func (*External) ValidateToken(ctx context.Context, token string) (models.TokenData, error) {
-	var (
-		resp models.TokenData
-	)
+// NewExternal creates the grpc clients shared by every request,
+// the connections are established lazily on first use.
+func NewExternal(config helpers.ExternalConfig) (*External, error) {
+	umsConn, err := grpc.NewClient(config.UMSGRPCHost, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create ums grpc client")
+	}
 
-	conn, err := grpc.NewClient(helpers.GetEnv("UMS_GRPC_HOST", "7000"), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	notificationConn, err := grpc.NewClient(config.NotificationGRPCHost, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
-		return resp, errors.Wrap(err, "failed to dial grpc")
+		umsConn.Close()
+		return nil, errors.Wrap(err, "failed to create notification grpc client")
 	}
 
-	defer conn.Close()
+	return &External{
+		config:           config,
+		httpClient:       &http.Client{},
+		umsConn:          umsConn,
+		notificationConn: notificationConn,
+	}, nil
+}
+
+// Close closes the grpc client connections.
+func (e *External) Close() error {
+	var errs []error
+	if err := e.umsConn.Close(); err != nil {
+		errs = append(errs, errors.Wrap(err, "failed to close ums grpc client"))
+	}
+	if err := e.notificationConn.Close(); err != nil {
+		errs = append(errs, errors.Wrap(err, "failed to close notification grpc client"))
+	}
+
+	return goerrors.Join(errs...)
+}
+
+func (e *External) ValidateToken(ctx context.Context, token string) (models.TokenData, error) {
+	var (
+		resp models.TokenData
+	)
 
-	client := tokenvalidation.NewTokenValidationClient(conn)
+	client := tokenvalidation.NewTokenValidationClient(e.umsConn)
 	req := &tokenvalidation.TokenRequest{
 		Token: token,
 	}
@@ -4,14 +4,18 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"ewallet-transaction/helpers"
 	"fmt"
 	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/pkg/errors"
 )
 
 type UpdateBalance struct {
+	// UserID is the owner of the moved wallet, a service token moves the wallet of this user
+	// instead of its own.
+	UserID    uint64  `json:"user_id"`
 	Reference string  `json:"reference"`
 	Amount    float64 `json:"amount"`
 }
@@ -24,13 +28,26 @@ type UpdateBalanceResponse struct {
 type Wallet struct {
 }
 
+// WalletMovement is one balance change the wallet service applied for a reference.
+type WalletMovement struct {
+	Reference             string    `json:"reference"`
+	Amount                float64   `json:"amount"`
+	WalletTransactionType string    `json:"wallet_transaction_type"`
+	CreatedAt             time.Time `json:"created_at"`
+}
+
+type WalletMovementsResponse struct {
+	Message string           `json:"message"`
+	Data    []WalletMovement `json:"data"`
+}
+
 func (e *External) CreditBalance(ctx context.Context, token string, req UpdateBalance) (*UpdateBalanceResponse, error) {
 	payload, err := json.Marshal(req)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal json")
 	}
 
-	url := helpers.GetEnv("WALLET_HOST", "") + helpers.GetEnv("WALLET_ENDPOINT_CREDIT", "")
+	url := e.config.WalletHost + e.config.WalletEndpointCredit
 
 	httpReq, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(payload))
 	if err != nil {
@@ -39,8 +56,7 @@ func (e *External) CreditBalance(ctx context.Context, token string, req UpdateBa
 
 	httpReq.Header.Set("Authorization", token)
 
-	client := &http.Client{}
-	resp, err := client.Do(httpReq)
+	resp, err := e.httpClient.Do(httpReq)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to connect wallet service")
 	}
@@ -65,7 +81,7 @@ func (e *External) DebitBalance(ctx context.Context, token string, req UpdateBal
 		return nil, errors.Wrap(err, "failed to marshal json")
 	}
 
-	url := helpers.GetEnv("WALLET_HOST", "") + helpers.GetEnv("WALLET_ENDPOINT_DEBIT", "")
+	url := e.config.WalletHost + e.config.WalletEndpointDebit
 
 	httpReq, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(payload))
 	if err != nil {
@@ -74,8 +90,7 @@ func (e *External) DebitBalance(ctx context.Context, token string, req UpdateBal
 
 	httpReq.Header.Set("Authorization", token)
 
-	client := &http.Client{}
-	resp, err := client.Do(httpReq)
+	resp, err := e.httpClient.Do(httpReq)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to connect wallet service")
 	}
@@ -93,3 +108,42 @@ func (e *External) DebitBalance(ctx context.Context, token string, req UpdateBal
 
 	return result, nil
 }
+
+// GetWalletMovements lists the movements the wallet service applied for reference,
+// a reference the wallet never saw has no movements.
+func (e *External) GetWalletMovements(ctx context.Context, reference string) ([]WalletMovement, error) {
+	if e.config.WalletEndpointMovements == "" {
+		return nil, errors.New("wallet movements endpoint is not configured")
+	}
+
+	endpoint := e.config.WalletHost + e.config.WalletEndpointMovements + "?reference=" + url.QueryEscape(reference)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create wallet http request")
+	}
+
+	httpReq.Header.Set("Authorization", e.config.WalletServiceToken)
+
+	resp, err := e.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect wallet service")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got error response from wallet service : %d", resp.StatusCode)
+	}
+
+	result := WalletMovementsResponse{}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response body")
+	}
+
+	return result.Data, nil
+}
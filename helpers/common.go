@@ -1,15 +1,50 @@
 package helpers
 
 import (
+	crand "crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"math/big"
 	"math/rand"
 	"time"
 )
 
+// referenceRandomDigits follow the creation second of a reference, the references of one second
+// collide once in 10^12 pairs instead of once in 100.
+const referenceRandomDigits = 1_000_000_000_000
+
+// GenerateReference is the creation time to the second followed by 12 random digits,
+// still all digits for the providers matching settlement lines by reference.
 func GenerateReference() string {
-	now := time.Now()
-	nowFormat := now.Format("20060102150405")
-	randomNumber := rand.Intn(100)
-	reference := fmt.Sprintf("%s%d", nowFormat, randomNumber)
-	return reference
+	nowFormat := time.Now().Format("20060102150405")
+
+	var randomNumber int64
+	n, err := crand.Int(crand.Reader, big.NewInt(referenceRandomDigits))
+	if err == nil {
+		randomNumber = n.Int64()
+	} else {
+		randomNumber = rand.Int63n(referenceRandomDigits)
+	}
+
+	return fmt.Sprintf("%s%012d", nowFormat, randomNumber)
+}
+
+func GenerateEventID() string {
+	b := make([]byte, 16)
+	_, err := crand.Read(b)
+	if err != nil {
+		return GenerateReference()
+	}
+
+	return hex.EncodeToString(b)
+}
+
+func GenerateSecret() string {
+	b := make([]byte, 32)
+	_, err := crand.Read(b)
+	if err != nil {
+		panic(err)
+	}
+
+	return hex.EncodeToString(b)
 }
@@ -0,0 +1,18 @@
+package helpers
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateReference(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 1000; i++ {
+		reference := GenerateReference()
+		assert.Regexp(t, regexp.MustCompile(`^\d{26}$`), reference)
+		assert.False(t, seen[reference], "references of the same second must not collide")
+		seen[reference] = true
+	}
+}
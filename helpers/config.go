@@ -1,25 +1,717 @@
 package helpers
 
 import (
-	"log"
+	"ewallet-transaction/constants"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	// timezones of the summaries must resolve in images without a zoneinfo database
+	_ "time/tzdata"
 
 	"github.com/joho/godotenv"
+	"github.com/pkg/errors"
 )
 
-var Env = map[string]string{}
+// config sources, in the order they are applied. A later source overrides an earlier one.
+const (
+	ConfigSourceDefault = "default"
+	ConfigSourceFile    = "file"
+	ConfigSourceEnv     = "env"
+	ConfigSourceFlag    = "flag"
+)
+
+const redactedValue = "******"
+
+// dsnPasswordPatterns match the password of a `user:password@tcp(host)/db` dsn
+// and of a `host=host password=password` keyword dsn.
+var dsnPasswordPatterns = map[*regexp.Regexp]string{
+	regexp.MustCompile(`:[^:@/]*@`):    ":" + redactedValue + "@",
+	regexp.MustCompile(`password=\S*`): "password=" + redactedValue,
+}
+
+type Config struct {
+	AppName         string
+	AppSecret       string
+	Port            string
+	GRPCPort        string
+	ShutdownTimeout time.Duration
+	// TrustedProxies are the ips and cidrs allowed to set the client ip in X-Forwarded-For,
+	// with none the client ip is the remote address of the connection.
+	TrustedProxies []string
+
+	DB           DBConfig
+	External     ExternalConfig
+	Notification NotificationConfig
+	Webhook      WebhookConfig
+	Event        EventConfig
+	Ledger       LedgerConfig
+	Reconcile    ReconcileConfig
+	Settlement   SettlementConfig
+	Export       ExportConfig
+	Summary      SummaryConfig
+	Transaction  TransactionConfig
+	Limit        LimitConfig
+	Fraud        FraudConfig
+	RateLimit    RateLimitConfig
+	Admin        AdminConfig
+
+	entries map[string]ConfigEntry
+	errs    []string
+}
+
+type DBConfig struct {
+	Driver      string
+	Host        string
+	Port        string
+	User        string
+	Password    string
+	Name        string
+	SSLMode     string
+	AutoMigrate bool
+
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+
+	// ReadTimeout and WriteTimeout bound a single repository query, zero disables the limit.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+
+	// ReplicaDSN is an optional read replica for history reads, ForcePrimary ignores it for strict consistency.
+	ReplicaDSN   string
+	ForcePrimary bool
+}
+
+type ExternalConfig struct {
+	UMSGRPCHost          string
+	NotificationGRPCHost string
+	WalletHost           string
+	WalletEndpointCredit string
+	WalletEndpointDebit  string
+	// WalletEndpointMovements lists the wallet movements of a reference for reconciliation,
+	// it is called with WalletServiceToken instead of a user token.
+	WalletEndpointMovements string
+	WalletServiceToken      string
+}
+
+type NotificationConfig struct {
+	Templates     map[string]string
+	Workers       int
+	QueueSize     int
+	MaxRetry      int
+	RetryInterval time.Duration
+	SendTimeout   time.Duration
+}
+
+type WebhookConfig struct {
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	PollInterval time.Duration
+	BatchSize    int
+	Timeout      time.Duration
+	// MerchantUserIDs are the merchants, the users allowed to register a webhook and to be paid
+	// by a purchase, none means nobody can.
+	MerchantUserIDs []uint64
+}
+
+type LedgerConfig struct {
+	// PurchaseFeeBps is the share of a purchase posted to the fee account, in basis points.
+	PurchaseFeeBps int
+}
+
+type ReconcileConfig struct {
+	// Interval of the scheduled reconciliation, zero disables the job.
+	Interval time.Duration
+	// Window is how far back each run looks, Grace skips the newest transactions that may still be in flight.
+	Window     time.Duration
+	Grace      time.Duration
+	AutoRepair bool
+}
+
+type SettlementConfig struct {
+	// MappingsFile is an optional json file of provider column mappings added to the built-in ones.
+	MappingsFile string
+	// APIKey guards the upload endpoint, the endpoint is not registered when it is empty.
+	APIKey string
+}
+
+type ExportConfig struct {
+	// BrandName is printed on the pdf statements.
+	BrandName string
+	// MaxRange caps the period of one export.
+	MaxRange time.Duration
+}
+
+type SummaryConfig struct {
+	// DefaultTimezone is used when the request has no tz.
+	DefaultTimezone string
+	// CacheTTL keeps a computed summary for a short time, zero disables the cache.
+	CacheTTL  time.Duration
+	CacheSize int
+}
+
+type TransactionConfig struct {
+	// SchemaDir holds additional_info json schemas named TYPE.json or TYPE.STATUS.json,
+	// they override the built-in ones.
+	SchemaDir string
+	// PendingTTL is how long a transaction may stay PENDING before it is expired to FAILED.
+	PendingTTL time.Duration
+}
+
+type LimitConfig struct {
+	// Timezone of the days and months the cumulative limits are counted over.
+	Timezone string
+}
+
+// FraudConfig sets the thresholds of the fraud rules, a zero threshold disables its rule.
+type FraudConfig struct {
+	VelocityWindow time.Duration
+	VelocityReview int
+	VelocityDeny   int
+	// Lookback is the history the average amount and the known devices are taken from.
+	Lookback     time.Duration
+	MinHistory   int
+	AmountFactor int
+	NewDevice    bool
+	RefundWindow time.Duration
+	RefundReview int
+	RefundDeny   int
+}
+
+type RateLimitConfig struct {
+	// Groups holds the limit of each route group in constants.MapRateLimitRequests.
+	Groups map[string]RateLimitGroupConfig
+}
+
+// RateLimitGroupConfig lets a client burst Requests requests and then make Requests per Period,
+// zero requests disables the limit.
+type RateLimitGroupConfig struct {
+	Requests int
+	Period   time.Duration
+}
+
+type AdminConfig struct {
+	// UserIDs are the users allowed on the /admin routes, none means the routes reject everyone.
+	UserIDs []uint64
+}
+
+type EventConfig struct {
+	Publisher     string
+	NatsURL       string
+	SubjectPrefix string
+}
+
+// ConfigEntry is the effective value of one config key and the source it was taken from.
+type ConfigEntry struct {
+	Key    string
+	Value  string
+	Source string
+}
+
+// LoadConfig reads the config with ReadConfig and validates the result.
+func LoadConfig(file string, overrides map[string]string) (*Config, error) {
+	cfg, err := ReadConfig(file, overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, cfg.Validate()
+}
+
+// ReadConfig reads the config in layers without validating it: defaults, then the optional env file,
+// then the OS environment and finally the flag overrides.
+func ReadConfig(file string, overrides map[string]string) (*Config, error) {
+	values := map[string]configValue{}
+
+	if file != "" {
+		fileEnv, err := godotenv.Read(file)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, errors.Wrap(err, "failed to read env file")
+		}
+		mergeConfigValues(values, fileEnv, ConfigSourceFile)
+	}
+
+	osEnv := map[string]string{}
+	for _, kv := range os.Environ() {
+		key, val, _ := strings.Cut(kv, "=")
+		osEnv[key] = val
+	}
+	mergeConfigValues(values, osEnv, ConfigSourceEnv)
+	mergeConfigValues(values, overrides, ConfigSourceFlag)
+
+	return newConfig(values), nil
+}
+
+// NewConfig builds the config from env key values, falling back to defaults for missing keys.
+func NewConfig(env map[string]string) *Config {
+	values := map[string]configValue{}
+	mergeConfigValues(values, env, ConfigSourceEnv)
+
+	return newConfig(values)
+}
+
+func newConfig(values map[string]configValue) *Config {
+	l := &configLoader{
+		values:  values,
+		entries: map[string]ConfigEntry{},
+	}
+
+	templates := map[string]string{}
+	for event, template := range constants.MapNotificationTemplate {
+		templates[event] = l.get("NOTIFICATION_TEMPLATE_"+strings.ToUpper(event), template)
+	}
+
+	rateLimits := map[string]RateLimitGroupConfig{}
+	for group, requests := range constants.MapRateLimitRequests {
+		prefix := "RATE_LIMIT_" + strings.ToUpper(group)
+		rateLimits[group] = RateLimitGroupConfig{
+			Requests: l.getInt(prefix+"_REQUESTS", requests),
+			Period:   l.getDuration(prefix+"_PERIOD", constants.DefaultRateLimitPeriod),
+		}
+	}
+
+	dbDriver := l.get("DB_DRIVER", constants.DBDriverMySQL)
+	dbPort := "3306"
+	if dbDriver == constants.DBDriverPostgres {
+		dbPort = "5432"
+	}
+
+	cfg := &Config{
+		AppName:         l.get("APP_NAME", "ewallet-transaction"),
+		AppSecret:       l.get("APP_SECRET", ""),
+		Port:            l.get("PORT", "8080"),
+		GRPCPort:        l.get("GRPC_PORT", "7000"),
+		ShutdownTimeout: l.getDuration("SHUTDOWN_TIMEOUT", constants.DefaultShutdownTimeout),
+		TrustedProxies:  l.getList("TRUSTED_PROXIES"),
+		DB: DBConfig{
+			Driver:      dbDriver,
+			Host:        l.get("DB_HOST", "127.0.0.1"),
+			Port:        l.get("DB_PORT", dbPort),
+			User:        l.get("DB_USER", ""),
+			Password:    l.get("DB_PASSWORD", ""),
+			Name:        l.get("DB_NAME", ""),
+			SSLMode:     l.get("DB_SSLMODE", "disable"),
+			AutoMigrate: l.getBool("DB_AUTO_MIGRATE", false),
+
+			MaxOpenConns:    l.getInt("DB_MAX_OPEN_CONNS", constants.DefaultDBMaxOpenConns),
+			MaxIdleConns:    l.getInt("DB_MAX_IDLE_CONNS", constants.DefaultDBMaxIdleConns),
+			ConnMaxLifetime: l.getDuration("DB_CONN_MAX_LIFETIME", constants.DefaultDBConnMaxLifetime),
+			ReadTimeout:     l.getDuration("DB_READ_TIMEOUT", constants.DefaultDBReadTimeout),
+			WriteTimeout:    l.getDuration("DB_WRITE_TIMEOUT", constants.DefaultDBWriteTimeout),
+			ReplicaDSN:      l.get("DB_REPLICA_DSN", ""),
+			ForcePrimary:    l.getBool("DB_FORCE_PRIMARY", false),
+		},
+		External: ExternalConfig{
+			UMSGRPCHost:             l.get("UMS_GRPC_HOST", "127.0.0.1:7000"),
+			NotificationGRPCHost:    l.get("NOTIFICATION_GRPC_HOST", "127.0.0.1:7003"),
+			WalletHost:              l.get("WALLET_HOST", ""),
+			WalletEndpointCredit:    l.get("WALLET_ENDPOINT_CREDIT", ""),
+			WalletEndpointDebit:     l.get("WALLET_ENDPOINT_DEBIT", ""),
+			WalletEndpointMovements: l.get("WALLET_ENDPOINT_MOVEMENTS", ""),
+			WalletServiceToken:      l.get("WALLET_SERVICE_TOKEN", ""),
+		},
+		Notification: NotificationConfig{
+			Templates:     templates,
+			Workers:       l.getInt("NOTIFICATION_WORKERS", constants.DefaultNotificationWorkers),
+			QueueSize:     l.getInt("NOTIFICATION_QUEUE_SIZE", constants.DefaultNotificationQueueSize),
+			MaxRetry:      l.getInt("NOTIFICATION_MAX_RETRY", constants.DefaultNotificationMaxRetry),
+			RetryInterval: l.getDuration("NOTIFICATION_RETRY_INTERVAL", constants.DefaultNotificationRetryInterval),
+			SendTimeout:   l.getDuration("NOTIFICATION_SEND_TIMEOUT", constants.DefaultNotificationSendTimeout),
+		},
+		Webhook: WebhookConfig{
+			MaxAttempts:  l.getInt("WEBHOOK_MAX_ATTEMPTS", constants.DefaultWebhookMaxAttempts),
+			BaseBackoff:  l.getDuration("WEBHOOK_BASE_BACKOFF", constants.DefaultWebhookBaseBackoff),
+			PollInterval: l.getDuration("WEBHOOK_POLL_INTERVAL", constants.DefaultWebhookPollInterval),
+			BatchSize:    l.getInt("WEBHOOK_BATCH_SIZE", constants.DefaultWebhookBatchSize),
+			Timeout:      l.getDuration("WEBHOOK_TIMEOUT", constants.DefaultWebhookTimeout),
+
+			MerchantUserIDs: l.getUint64List("MERCHANT_USER_IDS"),
+		},
+		Event: EventConfig{
+			Publisher:     l.get("EVENT_PUBLISHER", ""),
+			NatsURL:       l.get("EVENT_NATS_URL", "nats://127.0.0.1:4222"),
+			SubjectPrefix: l.get("EVENT_SUBJECT_PREFIX", constants.DefaultEventSubjectPrefix),
+		},
+		Ledger: LedgerConfig{
+			PurchaseFeeBps: l.getInt("LEDGER_PURCHASE_FEE_BPS", constants.DefaultLedgerPurchaseFeeBps),
+		},
+		Reconcile: ReconcileConfig{
+			Interval:   l.getDuration("RECONCILE_INTERVAL", 0),
+			Window:     l.getDuration("RECONCILE_WINDOW", constants.DefaultReconcileWindow),
+			Grace:      l.getDuration("RECONCILE_GRACE", constants.DefaultReconcileGrace),
+			AutoRepair: l.getBool("RECONCILE_AUTO_REPAIR", false),
+		},
+		Settlement: SettlementConfig{
+			MappingsFile: l.get("SETTLEMENT_MAPPINGS_FILE", ""),
+			APIKey:       l.get("SETTLEMENT_API_KEY", ""),
+		},
+		Export: ExportConfig{
+			BrandName: l.get("EXPORT_BRAND_NAME", "E-Wallet"),
+			MaxRange:  l.getDuration("EXPORT_MAX_RANGE", constants.DefaultExportMaxRange),
+		},
+		Summary: SummaryConfig{
+			DefaultTimezone: l.get("SUMMARY_DEFAULT_TIMEZONE", constants.DefaultSummaryTimezone),
+			CacheTTL:        l.getDuration("SUMMARY_CACHE_TTL", constants.DefaultSummaryCacheTTL),
+			CacheSize:       l.getInt("SUMMARY_CACHE_SIZE", constants.DefaultSummaryCacheSize),
+		},
+		Transaction: TransactionConfig{
+			SchemaDir:  l.get("ADDITIONAL_INFO_SCHEMA_DIR", ""),
+			PendingTTL: l.getDuration("TRANSACTION_PENDING_TTL", constants.DefaultPendingTTL),
+		},
+		Limit: LimitConfig{
+			Timezone: l.get("LIMIT_TIMEZONE", constants.DefaultLimitTimezone),
+		},
+		Fraud: FraudConfig{
+			VelocityWindow: l.getDuration("FRAUD_VELOCITY_WINDOW", constants.DefaultFraudVelocityWindow),
+			VelocityReview: l.getInt("FRAUD_VELOCITY_REVIEW", constants.DefaultFraudVelocityReview),
+			VelocityDeny:   l.getInt("FRAUD_VELOCITY_DENY", constants.DefaultFraudVelocityDeny),
+			Lookback:       l.getDuration("FRAUD_LOOKBACK", constants.DefaultFraudLookback),
+			MinHistory:     l.getInt("FRAUD_MIN_HISTORY", constants.DefaultFraudMinHistory),
+			AmountFactor:   l.getInt("FRAUD_AMOUNT_FACTOR", constants.DefaultFraudAmountFactor),
+			NewDevice:      l.getBool("FRAUD_NEW_DEVICE", true),
+			RefundWindow:   l.getDuration("FRAUD_REFUND_WINDOW", constants.DefaultFraudRefundWindow),
+			RefundReview:   l.getInt("FRAUD_REFUND_REVIEW", constants.DefaultFraudRefundReview),
+			RefundDeny:     l.getInt("FRAUD_REFUND_DENY", constants.DefaultFraudRefundDeny),
+		},
+		RateLimit: RateLimitConfig{
+			Groups: rateLimits,
+		},
+		Admin: AdminConfig{
+			UserIDs: l.getUint64List("ADMIN_USER_IDS"),
+		},
+		entries: l.entries,
+		errs:    l.errs,
+	}
+
+	return cfg
+}
+
+// Validate checks that required keys are set and every value is usable.
+func (c *Config) Validate() error {
+	return validationError(append(c.validateDB(), c.validateApp()...))
+}
+
+// ValidateDB only checks the database keys, for commands that don't serve the api.
+func (c *Config) ValidateDB() error {
+	return validationError(c.validateDB())
+}
+
+func (c *Config) validateDB() []string {
+	var errs []string
+	for _, err := range c.errs {
+		if strings.HasPrefix(err, "DB_") {
+			errs = append(errs, err)
+		}
+	}
+
+	required := map[string]string{
+		"DB_NAME": c.DB.Name,
+	}
+	// sqlite only needs the file path in DB_NAME
+	if c.DB.Driver != constants.DBDriverSQLite {
+		required["DB_HOST"] = c.DB.Host
+		required["DB_PORT"] = c.DB.Port
+		required["DB_USER"] = c.DB.User
+	}
+	errs = append(errs, requiredErrors(required)...)
+
+	switch c.DB.Driver {
+	case constants.DBDriverMySQL, constants.DBDriverPostgres, constants.DBDriverSQLite:
+	default:
+		errs = append(errs, "DB_DRIVER must be one of mysql, postgres or sqlite")
+	}
+
+	if c.DB.MaxOpenConns <= 0 {
+		errs = append(errs, "DB_MAX_OPEN_CONNS must be greater than 0")
+	}
+
+	if c.DB.MaxIdleConns < 0 || c.DB.MaxIdleConns > c.DB.MaxOpenConns {
+		errs = append(errs, "DB_MAX_IDLE_CONNS must be between 0 and DB_MAX_OPEN_CONNS")
+	}
+
+	if c.DB.ReadTimeout < 0 || c.DB.WriteTimeout < 0 {
+		errs = append(errs, "DB_READ_TIMEOUT and DB_WRITE_TIMEOUT must not be negative")
+	}
+
+	return errs
+}
+
+func (c *Config) validateApp() []string {
+	var errs []string
+	for _, err := range c.errs {
+		if !strings.HasPrefix(err, "DB_") {
+			errs = append(errs, err)
+		}
+	}
+
+	errs = append(errs, requiredErrors(map[string]string{
+		"PORT":                   c.Port,
+		"GRPC_PORT":              c.GRPCPort,
+		"UMS_GRPC_HOST":          c.External.UMSGRPCHost,
+		"NOTIFICATION_GRPC_HOST": c.External.NotificationGRPCHost,
+		"WALLET_HOST":            c.External.WalletHost,
+		"WALLET_ENDPOINT_CREDIT": c.External.WalletEndpointCredit,
+		"WALLET_ENDPOINT_DEBIT":  c.External.WalletEndpointDebit,
+	})...)
+
+	for _, proxy := range c.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			errs = append(errs, fmt.Sprintf("TRUSTED_PROXIES must be a comma separated list of ips or cidrs, got %q", proxy))
+		}
+	}
+
+	positive := map[string]int{
+		"NOTIFICATION_WORKERS":    c.Notification.Workers,
+		"NOTIFICATION_QUEUE_SIZE": c.Notification.QueueSize,
+		"WEBHOOK_MAX_ATTEMPTS":    c.Webhook.MaxAttempts,
+		"WEBHOOK_BATCH_SIZE":      c.Webhook.BatchSize,
+		"SUMMARY_CACHE_SIZE":      c.Summary.CacheSize,
+	}
+	for key, val := range positive {
+		if val <= 0 {
+			errs = append(errs, key+" must be greater than 0")
+		}
+	}
+
+	if c.Ledger.PurchaseFeeBps < 0 || c.Ledger.PurchaseFeeBps > 10000 {
+		errs = append(errs, "LEDGER_PURCHASE_FEE_BPS must be between 0 and 10000")
+	}
+
+	if c.Reconcile.Interval > 0 && c.External.WalletEndpointMovements == "" {
+		errs = append(errs, "WALLET_ENDPOINT_MOVEMENTS is required when RECONCILE_INTERVAL is set")
+	}
+
+	if c.Reconcile.Interval < 0 || c.Reconcile.Window <= 0 || c.Reconcile.Grace < 0 {
+		errs = append(errs, "RECONCILE_WINDOW must be greater than 0, RECONCILE_INTERVAL and RECONCILE_GRACE must not be negative")
+	}
+
+	if _, err := time.LoadLocation(c.Summary.DefaultTimezone); err != nil {
+		errs = append(errs, "SUMMARY_DEFAULT_TIMEZONE must be a valid timezone")
+	}
+
+	if _, err := time.LoadLocation(c.Limit.Timezone); err != nil {
+		errs = append(errs, "LIMIT_TIMEZONE must be a valid timezone")
+	}
+
+	if c.Fraud.VelocityWindow < 0 || c.Fraud.Lookback < 0 || c.Fraud.RefundWindow < 0 ||
+		c.Fraud.VelocityReview < 0 || c.Fraud.VelocityDeny < 0 || c.Fraud.MinHistory < 0 ||
+		c.Fraud.AmountFactor < 0 || c.Fraud.RefundReview < 0 || c.Fraud.RefundDeny < 0 {
+		errs = append(errs, "FRAUD_* thresholds must not be negative")
+	}
+
+	for group, limit := range c.RateLimit.Groups {
+		if limit.Requests < 0 || limit.Period <= 0 {
+			prefix := "RATE_LIMIT_" + strings.ToUpper(group)
+			errs = append(errs, fmt.Sprintf("%s_REQUESTS must not be negative and %s_PERIOD must be greater than 0", prefix, prefix))
+		}
+	}
+
+	if c.Summary.CacheTTL < 0 {
+		errs = append(errs, "SUMMARY_CACHE_TTL must not be negative")
+	}
+
+	if c.Export.MaxRange <= 0 {
+		errs = append(errs, "EXPORT_MAX_RANGE must be greater than 0")
+	}
+
+	switch c.Event.Publisher {
+	case "", "nop", "memory":
+	case "nats":
+		if c.Event.NatsURL == "" {
+			errs = append(errs, "EVENT_NATS_URL is required when EVENT_PUBLISHER is nats")
+		}
+	default:
+		errs = append(errs, "EVENT_PUBLISHER must be one of nats, memory or nop")
+	}
+
+	return errs
+}
+
+func requiredErrors(required map[string]string) []string {
+	var errs []string
+	for key, val := range required {
+		if val == "" {
+			errs = append(errs, key+" is required")
+		}
+	}
+
+	return errs
+}
+
+func validationError(errs []string) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	sort.Strings(errs)
+	return errors.Errorf("invalid config: %s", strings.Join(errs, "; "))
+}
+
+// Entries returns the effective config sorted by key, with secret values redacted.
+func (c *Config) Entries() []ConfigEntry {
+	entries := make([]ConfigEntry, 0, len(c.entries))
+	for _, entry := range c.entries {
+		if isSecretConfigKey(entry.Key) && entry.Value != "" {
+			entry.Value = redactedValue
+		}
+		if strings.HasSuffix(entry.Key, "_DSN") {
+			for pattern, replacement := range dsnPasswordPatterns {
+				entry.Value = pattern.ReplaceAllString(entry.Value, replacement)
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+
+	return entries
+}
+
+// String returns the effective config with secret values redacted, so the config is safe to log.
+func (c *Config) String() string {
+	entries := c.Entries()
+
+	parts := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		parts = append(parts, fmt.Sprintf("%s=%s", entry.Key, entry.Value))
+	}
+
+	return strings.Join(parts, " ")
+}
 
-func SetupConfig() {
-	var err error
-	Env, err = godotenv.Read(".env")
+func isSecretConfigKey(key string) bool {
+	for _, marker := range []string{"SECRET", "PASSWORD", "TOKEN", "API_KEY"} {
+		if strings.Contains(key, marker) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type configValue struct {
+	value  string
+	source string
+}
+
+func mergeConfigValues(values map[string]configValue, env map[string]string, source string) {
+	for key, val := range env {
+		values[key] = configValue{value: val, source: source}
+	}
+}
+
+type configLoader struct {
+	values  map[string]configValue
+	entries map[string]ConfigEntry
+	errs    []string
+}
+
+func (l *configLoader) lookup(key string, val string) (string, bool) {
+	result, ok := l.values[key]
+	if !ok || result.value == "" {
+		l.entries[key] = ConfigEntry{Key: key, Value: val, Source: ConfigSourceDefault}
+		return val, false
+	}
+
+	l.entries[key] = ConfigEntry{Key: key, Value: result.value, Source: result.source}
+	return result.value, true
+}
+
+func (l *configLoader) get(key string, val string) string {
+	result, _ := l.lookup(key, val)
+	return result
+}
+
+func (l *configLoader) getInt(key string, val int) int {
+	raw, ok := l.lookup(key, strconv.Itoa(val))
+	if !ok {
+		return val
+	}
+
+	result, err := strconv.Atoi(raw)
 	if err != nil {
-		log.Fatal("failed to read env file: ", err)
+		l.errs = append(l.errs, fmt.Sprintf("%s must be an integer, got %q", key, raw))
+		return val
 	}
+
+	return result
 }
 
-func GetEnv(key string, val string) string {
-	result := Env[key]
-	if result == "" {
-		result = val
+func (l *configLoader) getDuration(key string, val time.Duration) time.Duration {
+	raw, ok := l.lookup(key, val.String())
+	if !ok {
+		return val
+	}
+
+	result, err := time.ParseDuration(raw)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Sprintf("%s must be a duration, got %q", key, raw))
+		return val
+	}
+
+	return result
+}
+
+func (l *configLoader) getBool(key string, val bool) bool {
+	raw, ok := l.lookup(key, strconv.FormatBool(val))
+	if !ok {
+		return val
+	}
+
+	result, err := strconv.ParseBool(raw)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Sprintf("%s must be a boolean, got %q", key, raw))
+		return val
+	}
+
+	return result
+}
+
+// getList reads a comma separated list, blank items are skipped.
+func (l *configLoader) getList(key string) []string {
+	raw, ok := l.lookup(key, "")
+	if !ok {
+		return nil
+	}
+
+	var result []string
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// getUint64List reads a comma separated list, blank items are skipped.
+func (l *configLoader) getUint64List(key string) []uint64 {
+	raw, ok := l.lookup(key, "")
+	if !ok {
+		return nil
+	}
+
+	var result []uint64
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		val, err := strconv.ParseUint(item, 10, 64)
+		if err != nil {
+			l.errs = append(l.errs, fmt.Sprintf("%s must be a comma separated list of ids, got %q", key, raw))
+			return nil
+		}
+		result = append(result, val)
 	}
 
 	return result
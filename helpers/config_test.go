@@ -0,0 +1,164 @@
+package helpers
+
+import (
+	"ewallet-transaction/constants"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, ".env")
+	err := os.WriteFile(envFile, []byte("PORT=8081\nDB_USER=file_user\nDB_PASSWORD=file_password\nDB_NAME=ewallet\nWALLET_HOST=http://wallet\nWALLET_ENDPOINT_CREDIT=/credit\nWALLET_ENDPOINT_DEBIT=/debit\n"), 0o600)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		file      string
+		env       map[string]string
+		overrides map[string]string
+		wantErr   bool
+		check     func(t *testing.T, cfg *Config)
+	}{
+		{
+			name: "success file only",
+			file: envFile,
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "8081", cfg.Port)
+				assert.Equal(t, "3306", cfg.DB.Port)
+				assert.Equal(t, "file_user", cfg.DB.User)
+			},
+		},
+		{
+			name:      "success env overrides file and flag overrides env",
+			file:      envFile,
+			env:       map[string]string{"DB_USER": "env_user", "PORT": "8082"},
+			overrides: map[string]string{"PORT": "8083", "WEBHOOK_TIMEOUT": "3s", "ADMIN_USER_IDS": "7, 9,", "MERCHANT_USER_IDS": "11", "TRUSTED_PROXIES": "10.0.0.0/8, 192.168.1.1"},
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "8083", cfg.Port)
+				assert.Equal(t, "env_user", cfg.DB.User)
+				assert.Equal(t, 3*time.Second, cfg.Webhook.Timeout)
+				assert.Equal(t, []uint64{7, 9}, cfg.Admin.UserIDs)
+				assert.Equal(t, []uint64{11}, cfg.Webhook.MerchantUserIDs)
+				assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.1"}, cfg.TrustedProxies)
+			},
+		},
+		{
+			name: "success missing file",
+			file: filepath.Join(dir, "missing.env"),
+			env: map[string]string{
+				"DB_USER":                "env_user",
+				"DB_NAME":                "ewallet",
+				"WALLET_HOST":            "http://wallet",
+				"WALLET_ENDPOINT_CREDIT": "/credit",
+				"WALLET_ENDPOINT_DEBIT":  "/debit",
+			},
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "env_user", cfg.DB.User)
+				assert.Equal(t, constants.DefaultPendingTTL, cfg.Transaction.PendingTTL)
+				assert.Equal(t, constants.DefaultLimitTimezone, cfg.Limit.Timezone)
+			},
+		},
+		{
+			name:    "error missing required keys",
+			file:    filepath.Join(dir, "missing.env"),
+			wantErr: true,
+		},
+		{
+			name:      "error invalid values",
+			file:      envFile,
+			overrides: map[string]string{"WEBHOOK_BATCH_SIZE": "abc", "EVENT_PUBLISHER": "kafka", "ADMIN_USER_IDS": "7,admin"},
+			wantErr:   true,
+		},
+		{
+			name:      "error invalid summary timezone",
+			file:      envFile,
+			overrides: map[string]string{"SUMMARY_DEFAULT_TIMEZONE": "Mars/Olympus"},
+			wantErr:   true,
+		},
+		{
+			name:      "error invalid limit timezone",
+			file:      envFile,
+			overrides: map[string]string{"LIMIT_TIMEZONE": "Mars/Olympus"},
+			wantErr:   true,
+		},
+		{
+			name:      "error negative fraud threshold",
+			file:      envFile,
+			overrides: map[string]string{"FRAUD_VELOCITY_DENY": "-1"},
+			wantErr:   true,
+		},
+		{
+			name:      "error invalid rate limit period",
+			file:      envFile,
+			overrides: map[string]string{"RATE_LIMIT_EXPORT_PERIOD": "0s"},
+			wantErr:   true,
+		},
+		{
+			name:      "error invalid trusted proxy",
+			file:      envFile,
+			overrides: map[string]string{"TRUSTED_PROXIES": "10.0.0.0/8,proxy.internal"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, val := range tt.env {
+				t.Setenv(key, val)
+			}
+
+			cfg, err := LoadConfig(tt.file, tt.overrides)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.check != nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
+
+func TestConfig_ValidateDB(t *testing.T) {
+	cfg := NewConfig(map[string]string{"DB_DRIVER": "sqlite", "DB_NAME": "ewallet.db"})
+	assert.NoError(t, cfg.ValidateDB())
+	assert.Error(t, cfg.Validate())
+
+	cfg = NewConfig(map[string]string{"DB_MAX_OPEN_CONNS": "abc", "DB_NAME": "ewallet"})
+	assert.Error(t, cfg.ValidateDB())
+}
+
+func TestConfig_Entries(t *testing.T) {
+	cfg := NewConfig(map[string]string{
+		"APP_SECRET":     "super-secret",
+		"DB_PASSWORD":    "password",
+		"DB_USER":        "root",
+		"DB_REPLICA_DSN": "reader:replica-password@tcp(replica:3306)/ewallet",
+	})
+
+	entries := map[string]ConfigEntry{}
+	for _, entry := range cfg.Entries() {
+		entries[entry.Key] = entry
+	}
+
+	assert.Equal(t, ConfigEntry{Key: "APP_SECRET", Value: redactedValue, Source: ConfigSourceEnv}, entries["APP_SECRET"])
+	assert.Equal(t, ConfigEntry{Key: "DB_PASSWORD", Value: redactedValue, Source: ConfigSourceEnv}, entries["DB_PASSWORD"])
+	assert.Equal(t, ConfigEntry{Key: "DB_USER", Value: "root", Source: ConfigSourceEnv}, entries["DB_USER"])
+	assert.Equal(t, ConfigEntry{Key: "DB_PORT", Value: "3306", Source: ConfigSourceDefault}, entries["DB_PORT"])
+	assert.Equal(t, "reader:******@tcp(replica:3306)/ewallet", entries["DB_REPLICA_DSN"].Value)
+	assert.NotContains(t, cfg.String(), "super-secret")
+	assert.NotContains(t, cfg.String(), "replica-password")
+	assert.NotContains(t, cfg.String(), "=password")
+
+	cfg = NewConfig(map[string]string{
+		"DB_DRIVER":      "postgres",
+		"DB_REPLICA_DSN": "host=replica user=reader password=replica-password dbname=ewallet",
+	})
+	assert.Equal(t, "5432", cfg.DB.Port)
+	assert.Contains(t, cfg.String(), "DB_REPLICA_DSN=host=replica user=reader password=****** dbname=ewallet")
+	assert.NotContains(t, cfg.String(), "replica-password")
+}
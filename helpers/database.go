@@ -1,49 +1,172 @@
 package helpers
 
 import (
-	"ewallet-transaction/internal/models"
+	"context"
+	"ewallet-transaction/constants"
 	"fmt"
+	"time"
 
+	"github.com/glebarez/sqlite"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-var DB *gorm.DB
+var (
+	// ErrQueryCanceled is returned when the caller cancelled the request while its query was running.
+	ErrQueryCanceled = errors.New("query canceled")
+	// ErrQueryTimeout is returned when a query ran past the request deadline or its own timeout.
+	ErrQueryTimeout = errors.New("query timeout")
+)
+
+// QueryTimeouts bound a single repository query, a zero value disables the limit.
+type QueryTimeouts struct {
+	Read  time.Duration
+	Write time.Duration
+}
+
+// QueryTimeouts returns the per query limits of the config.
+func (c DBConfig) QueryTimeouts() QueryTimeouts {
+	return QueryTimeouts{Read: c.ReadTimeout, Write: c.WriteTimeout}
+}
+
+// QueryContext derives the context of a single query from the request context.
+func QueryContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// QueryError tells cancelled and timed out queries apart from database failures,
+// so the handler can answer 499/504 instead of 500.
+func QueryError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
 
-func SetupMySQL() {
-	dbuser := GetEnv("DB_USER", "")
-	dbpass := GetEnv("DB_PASSWORD", "")
-	dbhost := GetEnv("DB_HOST", "127.0.0.1")
-	dbport := GetEnv("DB_PORT", "3036")
-	dbname := GetEnv("DB_NAME", "")
+	// the cause tells a deadline apart from a cancel, also when the deadline cancels the context itself
+	switch {
+	case errors.Is(context.Cause(ctx), context.DeadlineExceeded), errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("%w: %w", ErrQueryTimeout, err)
+	case errors.Is(context.Cause(ctx), context.Canceled), errors.Is(err, context.Canceled):
+		return fmt.Errorf("%w: %w", ErrQueryCanceled, err)
+	}
+
+	return err
+}
+
+type txKey struct{}
+
+// WithTx returns a context carrying the db transaction tx, the queries of the repositories given it
+// run in tx.
+func WithTx(ctx context.Context, tx *gorm.DB) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
 
-	createDBDsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/", dbuser, dbpass, dbhost, dbport)
-	database, err := gorm.Open(mysql.Open(createDBDsn), &gorm.Config{})
+// Conn returns the db transaction carried by ctx or else db, bound to ctx.
+func Conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+
+	return db.WithContext(ctx)
+}
+
+// SetupDB opens the primary connection pool of the configured driver,
+// the schema is managed by the migrate command.
+func SetupDB(cfg DBConfig) (*gorm.DB, error) {
+	db, err := openDB(cfg, DSN(cfg))
 	if err != nil {
-		logrus.Fatal("failed to create database", err)
+		return nil, errors.Wrap(err, "failed to connect to database")
 	}
 
-	result := database.Exec("CREATE DATABASE IF NOT EXISTS " + dbname + ";")
+	logrus.Info("successfully connect to database")
 
-	if result.Error != nil {
-		logrus.Fatal("failed create database")
+	return db, nil
+}
+
+// SetupDBReplica opens the read replica pool, it returns nil when no replica is configured
+// or primary reads are forced.
+func SetupDBReplica(cfg DBConfig) (*gorm.DB, error) {
+	if cfg.ReplicaDSN == "" || cfg.ForcePrimary {
+		return nil, nil
+	}
+
+	db, err := openDB(cfg, cfg.ReplicaDSN)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to read replica")
 	}
 
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=true&loc=Local",
-		dbuser,
-		dbpass,
-		dbhost,
-		dbport,
-		dbname,
+	logrus.Info("successfully connect to read replica")
+
+	return db, nil
+}
+
+// DSN builds the primary dsn of the configured driver, for sqlite DB_NAME is the database file.
+func DSN(cfg DBConfig) string {
+	if cfg.Driver == constants.DBDriverSQLite {
+		return cfg.Name
+	}
+
+	if cfg.Driver == constants.DBDriverPostgres {
+		return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+			cfg.Host,
+			cfg.Port,
+			cfg.User,
+			cfg.Password,
+			cfg.Name,
+			cfg.SSLMode,
+		)
+	}
+
+	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=true&loc=Local",
+		cfg.User,
+		cfg.Password,
+		cfg.Host,
+		cfg.Port,
+		cfg.Name,
 	)
+}
 
-	DB, err = gorm.Open(mysql.Open(dsn), &gorm.Config{})
+func openDB(cfg DBConfig, dsn string) (*gorm.DB, error) {
+	var dialector gorm.Dialector
+	switch cfg.Driver {
+	case constants.DBDriverMySQL:
+		dialector = mysql.Open(dsn)
+	case constants.DBDriverPostgres:
+		dialector = postgres.Open(dsn)
+	case constants.DBDriverSQLite:
+		dialector = sqlite.Open(dsn)
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", cfg.Driver)
+	}
+
+	// duplicate keys are reported as gorm.ErrDuplicatedKey whatever the driver
+	db, err := gorm.Open(dialector, &gorm.Config{TranslateError: true})
 	if err != nil {
-		logrus.Fatal("failed to connect to database", err)
+		return nil, err
 	}
 
-	logrus.Info("successfully connect to database")
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
+	// sqlite allows a single writer and gives every connection to :memory: its own database,
+	// so share one connection that never expires.
+	if cfg.Driver == constants.DBDriverSQLite {
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetMaxIdleConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+	}
 
-	DB.AutoMigrate(&models.Transaction{})
+	return db, nil
 }
@@ -0,0 +1,47 @@
+package helpers
+
+import (
+	"ewallet-transaction/constants"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type referenceRow struct {
+	ID        uint64 `gorm:"primaryKey"`
+	Reference string `gorm:"uniqueIndex"`
+}
+
+func TestSetupDB_SQLite(t *testing.T) {
+	db, err := SetupDB(DBConfig{Driver: constants.DBDriverSQLite, Name: ":memory:"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, db.AutoMigrate(&referenceRow{}))
+	assert.NoError(t, db.Create(&referenceRow{Reference: "REFERENCE"}).Error)
+	assert.ErrorIs(t, db.Create(&referenceRow{Reference: "REFERENCE"}).Error, gorm.ErrDuplicatedKey)
+
+	var count int64
+	assert.NoError(t, db.Model(&referenceRow{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+// the sqlite driver is pure go, the service builds and opens its database without a C toolchain
+func TestSetupDB_SQLiteWithoutCgo(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the package again")
+	}
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not found")
+	}
+
+	cmd := exec.Command(gobin, "test", "-count=1", "-run", "^TestSetupDB_SQLite$", ".")
+	cmd.Env = append(os.Environ(), "CGO_ENABLED=0")
+	out, err := cmd.CombinedOutput()
+	assert.NoError(t, err, string(out))
+}
@@ -21,9 +21,19 @@ var MapTypeToken = map[string]time.Duration{
 	"refresh_token": time.Hour * 72,
 }
 
-var jwtSecret = []byte(GetEnv("APP_SECRET", ""))
+type JWT struct {
+	secret []byte
+	issuer string
+}
+
+func NewJWT(secret string, issuer string) *JWT {
+	return &JWT{
+		secret: []byte(secret),
+		issuer: issuer,
+	}
+}
 
-func GenerateToken(ctx context.Context, userID uint64, username string, fullname string, email string, now time.Time, tokenType string) (string, error) {
+func (j *JWT) GenerateToken(ctx context.Context, userID uint64, username string, fullname string, email string, now time.Time, tokenType string) (string, error) {
 
 	claimToken := ClaimToken{
 		UserID:   userID,
@@ -31,7 +41,7 @@ func GenerateToken(ctx context.Context, userID uint64, username string, fullname
 		Fullname: fullname,
 		Email:    email,
 		RegisteredClaims: jwt.RegisteredClaims{
-			Issuer:    GetEnv("APP_NAME", ""),
+			Issuer:    j.issuer,
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(now.Add(MapTypeToken[tokenType])),
 		},
@@ -39,14 +49,14 @@ func GenerateToken(ctx context.Context, userID uint64, username string, fullname
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claimToken)
 
-	resultToken, err := token.SignedString(jwtSecret)
+	resultToken, err := token.SignedString(j.secret)
 	if err != nil {
 		return resultToken, fmt.Errorf("failed to generate token: %v", err)
 	}
 	return resultToken, nil
 }
 
-func ValidateToken(ctx context.Context, token string) (*ClaimToken, error) {
+func (j *JWT) ValidateToken(ctx context.Context, token string) (*ClaimToken, error) {
 
 	var (
 		claimToken *ClaimToken
@@ -57,7 +67,7 @@ func ValidateToken(ctx context.Context, token string) (*ClaimToken, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("failed to validate method jwt: %v", t.Header["alg"])
 		}
-		return jwtSecret, nil
+		return j.secret, nil
 	})
 
 	if err != nil {
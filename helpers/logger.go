@@ -2,10 +2,9 @@ package helpers
 
 import "github.com/sirupsen/logrus"
 
-var Logger *logrus.Logger
-
-func SetupLogger() {
-	log := logrus.New()
+// SetupLogger configures the standard logrus logger used across the service.
+func SetupLogger() *logrus.Logger {
+	log := logrus.StandardLogger()
 
 	log.SetFormatter(&logrus.JSONFormatter{
 		PrettyPrint: true,
@@ -13,5 +12,5 @@ func SetupLogger() {
 
 	log.Info("logger initiated using logrus")
 
-	Logger = log
+	return log
 }
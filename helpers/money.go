@@ -0,0 +1,13 @@
+package helpers
+
+import "math"
+
+// Cents turns an amount into whole cents so amounts are summed and compared without float rounding.
+func Cents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// Amount turns whole cents back into an amount.
+func Amount(cents int64) float64 {
+	return float64(cents) / 100
+}
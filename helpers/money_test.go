@@ -0,0 +1,15 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCents(t *testing.T) {
+	assert.Equal(t, int64(30), Cents(0.1+0.2))
+	assert.Equal(t, int64(1025), Cents(10.245))
+	assert.Equal(t, int64(-1999), Cents(-19.99))
+	assert.Equal(t, 0.3, Amount(Cents(0.1+0.2)))
+	assert.Equal(t, -19.99, Amount(-1999))
+}
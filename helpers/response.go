@@ -1,9 +1,16 @@
 package helpers
 
 import (
+	"ewallet-transaction/constants"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
 )
 
+// StatusClientClosedRequest is the non standard status of a request the client cancelled.
+const StatusClientClosedRequest = 499
+
 type Response struct {
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
@@ -17,3 +24,16 @@ func SendResponseHTTP(c *gin.Context, code int, message string, data interface{}
 
 	c.JSON(code, resp)
 }
+
+// SendErrorResponseHTTP answers a failed service call, cancelled and timed out queries
+// get 499 and 504, anything else is a server error.
+func SendErrorResponseHTTP(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrQueryCanceled):
+		SendResponseHTTP(c, StatusClientClosedRequest, constants.ErrRequestCanceled, nil)
+	case errors.Is(err, ErrQueryTimeout):
+		SendResponseHTTP(c, http.StatusGatewayTimeout, constants.ErrRequestTimeout, nil)
+	default:
+		SendResponseHTTP(c, http.StatusInternalServerError, constants.ErrServerError, nil)
+	}
+}
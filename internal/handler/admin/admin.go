@@ -0,0 +1,243 @@
+package admin
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// ListTransactions lists the transactions of every user, newest first. It takes the filters
+// and paging of SearchTransactions without q.
+func (h *Handler) ListTransactions(c *gin.Context) {
+	h.search(c, h.Service.ListTransactions)
+}
+
+// SearchTransactions finds the transactions of every user matching q, a description fragment, a reference prefix
+// or an amount. It can be narrowed with user_id, type, status and the inclusive dates from and to,
+// and paged with page and page_size.
+func (h *Handler) SearchTransactions(c *gin.Context) {
+	h.search(c, h.Service.SearchTransactions)
+}
+
+func (h *Handler) search(c *gin.Context, fn func(context.Context, models.TokenData, models.SearchRequest) (models.SearchResponse, error)) {
+	req, err := parseSearchRequest(c)
+	if err != nil {
+		fmt.Println("failed to parse search request, ", err)
+		helpers.SendResponseHTTP(c, http.StatusBadRequest, constants.ErrFailedBadRequest, nil)
+		return
+	}
+
+	tokenData, ok := getTokenData(c)
+	if !ok {
+		helpers.SendResponseHTTP(c, http.StatusInternalServerError, constants.ErrServerError, nil)
+		return
+	}
+
+	resp, err := fn(c.Request.Context(), tokenData, req)
+	if err != nil {
+		fmt.Println("failed to search transactions, ", err)
+		sendError(c, err)
+		return
+	}
+
+	helpers.SendResponseHTTP(c, http.StatusOK, constants.SuccessMessage, resp)
+}
+
+// ForceStatus moves a transaction to transaction_status on behalf of its user, reason is mandatory.
+func (h *Handler) ForceStatus(c *gin.Context) {
+	var (
+		req models.AdminStatusRequest
+	)
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		fmt.Println("failed to parse request, ", err)
+		helpers.SendResponseHTTP(c, http.StatusBadRequest, constants.ErrFailedBadRequest, nil)
+		return
+	}
+
+	tokenData, ok := getTokenData(c)
+	if !ok {
+		helpers.SendResponseHTTP(c, http.StatusInternalServerError, constants.ErrServerError, nil)
+		return
+	}
+
+	err := h.Service.ForceStatus(c.Request.Context(), tokenData, c.Param("reference"), req)
+	if err != nil {
+		fmt.Println("failed to force transaction status, ", err)
+		sendError(c, err)
+		return
+	}
+
+	helpers.SendResponseHTTP(c, http.StatusOK, constants.SuccessMessage, nil)
+}
+
+// Refund refunds a purchase on behalf of its user, reason is mandatory.
+func (h *Handler) Refund(c *gin.Context) {
+	var (
+		req models.AdminRefundRequest
+	)
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		fmt.Println("failed to parse request, ", err)
+		helpers.SendResponseHTTP(c, http.StatusBadRequest, constants.ErrFailedBadRequest, nil)
+		return
+	}
+
+	tokenData, ok := getTokenData(c)
+	if !ok {
+		helpers.SendResponseHTTP(c, http.StatusInternalServerError, constants.ErrServerError, nil)
+		return
+	}
+
+	resp, err := h.Service.Refund(c.Request.Context(), tokenData, c.Param("reference"), req)
+	if err != nil {
+		fmt.Println("failed to refund transaction, ", err)
+		sendError(c, err)
+		return
+	}
+
+	helpers.SendResponseHTTP(c, http.StatusOK, constants.SuccessMessage, resp)
+}
+
+// Approve debits a transaction on hold, reason is mandatory.
+func (h *Handler) Approve(c *gin.Context) {
+	h.review(c, h.Service.Approve)
+}
+
+// Reject fails a transaction on hold, reason is mandatory.
+func (h *Handler) Reject(c *gin.Context) {
+	h.review(c, h.Service.Reject)
+}
+
+func (h *Handler) review(c *gin.Context, fn func(context.Context, models.TokenData, string, models.AdminReviewRequest) error) {
+	var (
+		req models.AdminReviewRequest
+	)
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		fmt.Println("failed to parse request, ", err)
+		helpers.SendResponseHTTP(c, http.StatusBadRequest, constants.ErrFailedBadRequest, nil)
+		return
+	}
+
+	tokenData, ok := getTokenData(c)
+	if !ok {
+		helpers.SendResponseHTTP(c, http.StatusInternalServerError, constants.ErrServerError, nil)
+		return
+	}
+
+	err := fn(c.Request.Context(), tokenData, c.Param("reference"), req)
+	if err != nil {
+		fmt.Println("failed to review transaction, ", err)
+		sendError(c, err)
+		return
+	}
+
+	helpers.SendResponseHTTP(c, http.StatusOK, constants.SuccessMessage, nil)
+}
+
+// StatusHistory returns the statuses a transaction went through, oldest first.
+func (h *Handler) StatusHistory(c *gin.Context) {
+	tokenData, ok := getTokenData(c)
+	if !ok {
+		helpers.SendResponseHTTP(c, http.StatusInternalServerError, constants.ErrServerError, nil)
+		return
+	}
+
+	resp, err := h.Service.StatusHistory(c.Request.Context(), tokenData, c.Param("reference"))
+	if err != nil {
+		fmt.Println("failed to get status history, ", err)
+		helpers.SendErrorResponseHTTP(c, err)
+		return
+	}
+
+	helpers.SendResponseHTTP(c, http.StatusOK, constants.SuccessMessage, resp)
+}
+
+// sendError answers the invalid requests with 400, the fields of an invalid additional_info included,
+// a transaction over a limit of its user with 422 and a status changed by another update with 409.
+func sendError(c *gin.Context, err error) {
+	var (
+		infoErr  *models.AdditionalInfoError
+		limitErr *models.LimitExceededError
+	)
+	switch {
+	case errors.As(err, &infoErr):
+		helpers.SendResponseHTTP(c, http.StatusBadRequest, constants.ErrFailedBadRequest, infoErr.Fields)
+	case errors.As(err, &limitErr):
+		helpers.SendResponseHTTP(c, http.StatusUnprocessableEntity, constants.ErrLimitExceeded, limitErr)
+	case errors.Is(err, models.ErrInvalidAdminRequest), errors.Is(err, models.ErrInvalidSearchRequest), errors.Is(err, models.ErrInvalidLimit):
+		helpers.SendResponseHTTP(c, http.StatusBadRequest, constants.ErrFailedBadRequest, nil)
+	case errors.Is(err, models.ErrStatusConflict):
+		helpers.SendResponseHTTP(c, http.StatusConflict, constants.ErrStatusConflict, nil)
+	default:
+		helpers.SendErrorResponseHTTP(c, err)
+	}
+}
+
+func getTokenData(c *gin.Context) (models.TokenData, bool) {
+	token, ok := c.Get("token")
+	if !ok {
+		fmt.Println("failed to get token data")
+		return models.TokenData{}, false
+	}
+
+	tokenData, ok := token.(models.TokenData)
+	if !ok {
+		fmt.Println("failed to parse token data")
+		return models.TokenData{}, false
+	}
+
+	return tokenData, true
+}
+
+func parseSearchRequest(c *gin.Context) (models.SearchRequest, error) {
+	var err error
+	req := models.SearchRequest{
+		Query:             c.Query("q"),
+		TransactionType:   c.Query("type"),
+		TransactionStatus: c.Query("status"),
+	}
+
+	if userID := c.Query("user_id"); userID != "" {
+		if req.UserID, err = strconv.ParseUint(userID, 10, 64); err != nil {
+			return req, errors.Wrap(err, "invalid user_id")
+		}
+	}
+
+	if page := c.Query("page"); page != "" {
+		if req.Page, err = strconv.Atoi(page); err != nil {
+			return req, errors.Wrap(err, "invalid page")
+		}
+	}
+
+	if pageSize := c.Query("page_size"); pageSize != "" {
+		if req.PageSize, err = strconv.Atoi(pageSize); err != nil {
+			return req, errors.Wrap(err, "invalid page_size")
+		}
+	}
+
+	if from := c.Query("from"); from != "" {
+		if req.From, err = time.ParseInLocation(constants.SearchDateLayout, from, time.Local); err != nil {
+			return req, errors.Wrap(err, "invalid from")
+		}
+	}
+
+	if to := c.Query("to"); to != "" {
+		parsed, err := time.ParseInLocation(constants.SearchDateLayout, to, time.Local)
+		if err != nil {
+			return req, errors.Wrap(err, "invalid to")
+		}
+		req.To = parsed.AddDate(0, 0, 1)
+	}
+
+	return req, nil
+}
@@ -0,0 +1,541 @@
+package admin
+
+import (
+	"encoding/json"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+)
+
+var tokenData = models.TokenData{
+	UserID:   7,
+	Username: "ADMIN",
+	Fullname: "FULLNAME",
+	Token:    "TOKEN",
+	Email:    "EMAIL",
+}
+
+func TestHandler_SearchTransactions(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockSvc := NewMockService(ctrlMock)
+	mockMdw := NewMockMiddleware(ctrlMock)
+	mockMdw.EXPECT().MiddlewareRateLimit(gomock.Any()).Return(func(c *gin.Context) { c.Next() }).AnyTimes()
+
+	validAdmin := func() {
+		mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+			c.Set("token", tokenData)
+			c.Next()
+		})
+		mockMdw.EXPECT().MiddlewareValidateAdmin(gomock.Any()).Do(func(c *gin.Context) {
+			c.Next()
+		})
+	}
+
+	tests := []struct {
+		name               string
+		endpoint           string
+		expectedStatusCode int
+		expectedBody       helpers.Response
+		wantErr            bool
+		mockFn             func()
+	}{
+		{
+			name:               "success",
+			endpoint:           "/admin/v1/transactions/search?q=kopi",
+			expectedStatusCode: http.StatusOK,
+			expectedBody: helpers.Response{
+				Message: constants.SuccessMessage,
+				Data: map[string]interface{}{
+					"items":     []interface{}{},
+					"page":      float64(1),
+					"page_size": float64(20),
+					"total":     float64(0),
+				},
+			},
+			mockFn: func() {
+				validAdmin()
+				mockSvc.EXPECT().SearchTransactions(gomock.Any(), tokenData, models.SearchRequest{Query: "kopi"}).Return(models.SearchResponse{
+					Items:    []models.SearchResult{},
+					Page:     1,
+					PageSize: 20,
+				}, nil)
+			},
+		},
+		{
+			name:               "success with filters",
+			endpoint:           "/admin/v1/transactions/search?q=REF-&user_id=1&type=PURCHASE&status=SUCCESS&from=2025-01-01&to=2025-01-31&page=2&page_size=50",
+			expectedStatusCode: http.StatusOK,
+			expectedBody: helpers.Response{
+				Message: constants.SuccessMessage,
+				Data: map[string]interface{}{
+					"items":     []interface{}{},
+					"page":      float64(2),
+					"page_size": float64(50),
+					"total":     float64(51),
+				},
+			},
+			mockFn: func() {
+				validAdmin()
+				mockSvc.EXPECT().SearchTransactions(gomock.Any(), tokenData, models.SearchRequest{
+					Query:             "REF-",
+					UserID:            1,
+					TransactionType:   constants.TransactionTypePurchase,
+					TransactionStatus: constants.TransactionStatusSuccess,
+					From:              time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local),
+					To:                time.Date(2025, 2, 1, 0, 0, 0, 0, time.Local),
+					Page:              2,
+					PageSize:          50,
+				}).Return(models.SearchResponse{
+					Items:    []models.SearchResult{},
+					Page:     2,
+					PageSize: 50,
+					Total:    51,
+				}, nil)
+			},
+		},
+		{
+			name:               "error invalid date",
+			endpoint:           "/admin/v1/transactions/search?q=kopi&from=01-01-2025",
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+			mockFn: func() {
+				validAdmin()
+			},
+		},
+		{
+			name:               "error invalid request",
+			endpoint:           "/admin/v1/transactions/search?q=k",
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+			mockFn: func() {
+				validAdmin()
+				mockSvc.EXPECT().SearchTransactions(gomock.Any(), tokenData, models.SearchRequest{Query: "k"}).Return(models.SearchResponse{}, errors.Wrap(models.ErrInvalidSearchRequest, "query too short"))
+			},
+		},
+		{
+			name:               "error",
+			endpoint:           "/admin/v1/transactions/search?q=kopi",
+			expectedStatusCode: http.StatusInternalServerError,
+			wantErr:            true,
+			mockFn: func() {
+				validAdmin()
+				mockSvc.EXPECT().SearchTransactions(gomock.Any(), tokenData, models.SearchRequest{Query: "kopi"}).Return(models.SearchResponse{}, assert.AnError)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			api := gin.New()
+			h := &Handler{
+				Engine:     api,
+				Service:    mockSvc,
+				Middleware: mockMdw,
+			}
+			h.RegisterRoute()
+			w := httptest.NewRecorder()
+
+			req, err := http.NewRequest(http.MethodGet, tt.endpoint, nil)
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", "authorization")
+
+			h.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if !tt.wantErr {
+				response := helpers.Response{}
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+
+				assert.Equal(t, tt.expectedBody, response)
+			}
+		})
+	}
+}
+
+func TestHandler_Actions(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockSvc := NewMockService(ctrlMock)
+	mockMdw := NewMockMiddleware(ctrlMock)
+	mockMdw.EXPECT().MiddlewareRateLimit(gomock.Any()).Return(func(c *gin.Context) { c.Next() }).AnyTimes()
+
+	validAdmin := func() {
+		mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+			c.Set("token", tokenData)
+			c.Next()
+		})
+		mockMdw.EXPECT().MiddlewareValidateAdmin(gomock.Any()).Do(func(c *gin.Context) {
+			c.Next()
+		})
+	}
+
+	tests := []struct {
+		name               string
+		method             string
+		endpoint           string
+		body               string
+		expectedStatusCode int
+		expectedBody       helpers.Response
+		wantErr            bool
+		mockFn             func()
+	}{
+		{
+			name:               "success list",
+			method:             http.MethodGet,
+			endpoint:           "/admin/v1/transactions?user_id=3&status=PENDING",
+			expectedStatusCode: http.StatusOK,
+			expectedBody: helpers.Response{
+				Message: constants.SuccessMessage,
+				Data: map[string]interface{}{
+					"items":     []interface{}{},
+					"page":      float64(1),
+					"page_size": float64(20),
+					"total":     float64(0),
+				},
+			},
+			mockFn: func() {
+				validAdmin()
+				mockSvc.EXPECT().ListTransactions(gomock.Any(), tokenData, models.SearchRequest{UserID: 3, TransactionStatus: constants.TransactionStatusPending}).Return(models.SearchResponse{
+					Items:    []models.SearchResult{},
+					Page:     1,
+					PageSize: 20,
+				}, nil)
+			},
+		},
+		{
+			name:               "success force status",
+			method:             http.MethodPost,
+			endpoint:           "/admin/v1/transactions/REFERENCE/status",
+			body:               `{"transaction_status":"REVERSED","reason":"chargeback"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedBody: helpers.Response{
+				Message: constants.SuccessMessage,
+			},
+			mockFn: func() {
+				validAdmin()
+				mockSvc.EXPECT().ForceStatus(gomock.Any(), tokenData, "REFERENCE", models.AdminStatusRequest{
+					TransactionStatus: constants.TransactionStatusReversed,
+					Reason:            "chargeback",
+				}).Return(nil)
+			},
+		},
+		{
+			name:               "error force status without reason",
+			method:             http.MethodPost,
+			endpoint:           "/admin/v1/transactions/REFERENCE/status",
+			body:               `{"transaction_status":"REVERSED"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+			mockFn: func() {
+				validAdmin()
+				mockSvc.EXPECT().ForceStatus(gomock.Any(), tokenData, "REFERENCE", gomock.Any()).Return(errors.Wrap(models.ErrInvalidAdminRequest, "reason is required"))
+			},
+		},
+		{
+			name:               "error force status invalid additional info",
+			method:             http.MethodPost,
+			endpoint:           "/admin/v1/transactions/REFERENCE/status",
+			body:               `{"transaction_status":"SUCCESS","reason":"paid","additional_info":"[]"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody: helpers.Response{
+				Message: constants.ErrFailedBadRequest,
+				Data: []interface{}{
+					map[string]interface{}{"field": "additional_info", "message": "must be a json object"},
+				},
+			},
+			mockFn: func() {
+				validAdmin()
+				mockSvc.EXPECT().ForceStatus(gomock.Any(), tokenData, "REFERENCE", gomock.Any()).Return(errors.Wrap(&models.AdditionalInfoError{
+					Fields: []models.FieldError{{Field: "additional_info", Message: "must be a json object"}},
+				}, "failed to validate additional info"))
+			},
+		},
+		{
+			name:               "error force status invalid body",
+			method:             http.MethodPost,
+			endpoint:           "/admin/v1/transactions/REFERENCE/status",
+			body:               `{`,
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+			mockFn: func() {
+				validAdmin()
+			},
+		},
+		{
+			name:               "success refund",
+			method:             http.MethodPost,
+			endpoint:           "/admin/v1/transactions/REFERENCE/refund",
+			body:               `{"reason":"item out of stock"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedBody: helpers.Response{
+				Message: constants.SuccessMessage,
+				Data: map[string]interface{}{
+					"reference":          "REFUND-REFERENCE",
+					"transaction_status": constants.TransactionStatusSuccess,
+				},
+			},
+			mockFn: func() {
+				validAdmin()
+				mockSvc.EXPECT().Refund(gomock.Any(), tokenData, "REFERENCE", models.AdminRefundRequest{Reason: "item out of stock"}).Return(models.CreateTransactionResponse{
+					Reference:         "REFUND-REFERENCE",
+					TransactionStatus: constants.TransactionStatusSuccess,
+				}, nil)
+			},
+		},
+		{
+			name:               "error refund",
+			method:             http.MethodPost,
+			endpoint:           "/admin/v1/transactions/REFERENCE/refund",
+			body:               `{"reason":"item out of stock"}`,
+			expectedStatusCode: http.StatusInternalServerError,
+			wantErr:            true,
+			mockFn: func() {
+				validAdmin()
+				mockSvc.EXPECT().Refund(gomock.Any(), tokenData, "REFERENCE", gomock.Any()).Return(models.CreateTransactionResponse{}, assert.AnError)
+			},
+		},
+		{
+			name:               "success history",
+			method:             http.MethodGet,
+			endpoint:           "/admin/v1/transactions/REFERENCE/history",
+			expectedStatusCode: http.StatusOK,
+			expectedBody: helpers.Response{
+				Message: constants.SuccessMessage,
+				Data: []interface{}{
+					map[string]interface{}{
+						"id":             float64(1),
+						"transaction_id": float64(1),
+						"reference":      "REFERENCE",
+						"from_status":    "",
+						"to_status":      constants.TransactionStatusPending,
+						"actor":          "user:3",
+						"created_at":     "0001-01-01T00:00:00Z",
+					},
+				},
+			},
+			mockFn: func() {
+				validAdmin()
+				mockSvc.EXPECT().StatusHistory(gomock.Any(), tokenData, "REFERENCE").Return([]models.TransactionStatusHistory{
+					{ID: 1, TransactionID: 1, Reference: "REFERENCE", ToStatus: constants.TransactionStatusPending, Actor: "user:3"},
+				}, nil)
+			},
+		},
+		{
+			name:               "error history",
+			method:             http.MethodGet,
+			endpoint:           "/admin/v1/transactions/REFERENCE/history",
+			expectedStatusCode: http.StatusInternalServerError,
+			wantErr:            true,
+			mockFn: func() {
+				validAdmin()
+				mockSvc.EXPECT().StatusHistory(gomock.Any(), tokenData, "REFERENCE").Return(nil, assert.AnError)
+			},
+		},
+		{
+			name:               "error force status limit exceeded",
+			method:             http.MethodPost,
+			endpoint:           "/admin/v1/transactions/REFERENCE/status",
+			body:               `{"transaction_status":"SUCCESS","reason":"paid at the counter"}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			wantErr:            true,
+			mockFn: func() {
+				validAdmin()
+				mockSvc.EXPECT().ForceStatus(gomock.Any(), tokenData, "REFERENCE", gomock.Any()).
+					Return(errors.Wrap(&models.LimitExceededError{Code: constants.LimitExceededCode}, "failed to check limits"))
+			},
+		},
+		{
+			name:               "success approve",
+			method:             http.MethodPost,
+			endpoint:           "/admin/v1/transactions/REFERENCE/approve",
+			body:               `{"reason":"customer confirmed"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       helpers.Response{Message: constants.SuccessMessage},
+			mockFn: func() {
+				validAdmin()
+				mockSvc.EXPECT().Approve(gomock.Any(), tokenData, "REFERENCE", models.AdminReviewRequest{Reason: "customer confirmed"}).Return(nil)
+			},
+		},
+		{
+			name:               "error reject not on hold",
+			method:             http.MethodPost,
+			endpoint:           "/admin/v1/transactions/REFERENCE/reject",
+			body:               `{"reason":"stolen card"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+			mockFn: func() {
+				validAdmin()
+				mockSvc.EXPECT().Reject(gomock.Any(), tokenData, "REFERENCE", models.AdminReviewRequest{Reason: "stolen card"}).
+					Return(errors.Wrap(models.ErrInvalidAdminRequest, "transaction is PENDING, not on hold"))
+			},
+		},
+		{
+			name:               "error reject invalid body",
+			method:             http.MethodPost,
+			endpoint:           "/admin/v1/transactions/REFERENCE/reject",
+			body:               `{"reason":`,
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+			mockFn: func() {
+				validAdmin()
+			},
+		},
+		{
+			name:               "success list limits",
+			method:             http.MethodGet,
+			endpoint:           "/admin/v1/limits",
+			expectedStatusCode: http.StatusOK,
+			expectedBody: helpers.Response{
+				Message: constants.SuccessMessage,
+				Data: []interface{}{
+					map[string]interface{}{
+						"kyc_tier":         constants.KYCTierBasic,
+						"transaction_type": constants.TransactionTypeTopup,
+						"max_amount":       float64(2000000),
+						"daily_amount":     float64(0),
+						"daily_count":      float64(20),
+						"monthly_amount":   float64(0),
+						"monthly_count":    float64(0),
+						"updated_at":       "0001-01-01T00:00:00Z",
+					},
+				},
+			},
+			mockFn: func() {
+				validAdmin()
+				mockSvc.EXPECT().ListLimits(gomock.Any(), tokenData).Return([]models.TransactionLimit{
+					{KYCTier: constants.KYCTierBasic, TransactionType: constants.TransactionTypeTopup, MaxAmount: 2000000, DailyCount: 20},
+				}, nil)
+			},
+		},
+		{
+			name:               "success set limit",
+			method:             http.MethodPut,
+			endpoint:           "/admin/v1/limits",
+			body:               `{"kyc_tier":"VERIFIED","transaction_type":"PURCHASE","max_amount":10000000,"daily_count":100,"reason":"raised"}`,
+			expectedStatusCode: http.StatusOK,
+			wantErr:            true,
+			mockFn: func() {
+				validAdmin()
+				mockSvc.EXPECT().SetLimit(gomock.Any(), tokenData, models.AdminLimitRequest{
+					TransactionLimit: models.TransactionLimit{
+						KYCTier:         constants.KYCTierVerified,
+						TransactionType: constants.TransactionTypePurchase,
+						MaxAmount:       10000000,
+						DailyCount:      100,
+					},
+					Reason: "raised",
+				}).Return(models.TransactionLimit{}, nil)
+			},
+		},
+		{
+			name:               "error set limit invalid",
+			method:             http.MethodPut,
+			endpoint:           "/admin/v1/limits",
+			body:               `{"kyc_tier":"GOLD","transaction_type":"PURCHASE","reason":"raised"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+			mockFn: func() {
+				validAdmin()
+				mockSvc.EXPECT().SetLimit(gomock.Any(), tokenData, gomock.Any()).Return(models.TransactionLimit{}, errors.Wrap(models.ErrInvalidLimit, "unknown kyc tier"))
+			},
+		},
+		{
+			name:               "success get kyc tier",
+			method:             http.MethodGet,
+			endpoint:           "/admin/v1/users/3/kyc-tier",
+			expectedStatusCode: http.StatusOK,
+			expectedBody: helpers.Response{
+				Message: constants.SuccessMessage,
+				Data: map[string]interface{}{
+					"user_id":    float64(3),
+					"kyc_tier":   constants.KYCTierBasic,
+					"updated_at": "0001-01-01T00:00:00Z",
+				},
+			},
+			mockFn: func() {
+				validAdmin()
+				mockSvc.EXPECT().GetUserKYCTier(gomock.Any(), tokenData, uint64(3)).Return(models.UserKYCTier{UserID: 3, KYCTier: constants.KYCTierBasic}, nil)
+			},
+		},
+		{
+			name:               "error get kyc tier invalid user id",
+			method:             http.MethodGet,
+			endpoint:           "/admin/v1/users/abc/kyc-tier",
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+			mockFn: func() {
+				validAdmin()
+			},
+		},
+		{
+			name:               "success set kyc tier",
+			method:             http.MethodPut,
+			endpoint:           "/admin/v1/users/3/kyc-tier",
+			body:               `{"kyc_tier":"VERIFIED","reason":"identity checked"}`,
+			expectedStatusCode: http.StatusOK,
+			wantErr:            true,
+			mockFn: func() {
+				validAdmin()
+				mockSvc.EXPECT().SetUserKYCTier(gomock.Any(), tokenData, uint64(3), models.AdminKYCTierRequest{KYCTier: constants.KYCTierVerified, Reason: "identity checked"}).
+					Return(models.UserKYCTier{UserID: 3, KYCTier: constants.KYCTierVerified}, nil)
+			},
+		},
+		{
+			name:               "error set kyc tier without reason",
+			method:             http.MethodPut,
+			endpoint:           "/admin/v1/users/3/kyc-tier",
+			body:               `{"kyc_tier":"VERIFIED"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+			mockFn: func() {
+				validAdmin()
+				mockSvc.EXPECT().SetUserKYCTier(gomock.Any(), tokenData, uint64(3), gomock.Any()).
+					Return(models.UserKYCTier{}, errors.Wrap(models.ErrInvalidAdminRequest, "reason is required"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			api := gin.New()
+			h := &Handler{
+				Engine:     api,
+				Service:    mockSvc,
+				Middleware: mockMdw,
+			}
+			h.RegisterRoute()
+			w := httptest.NewRecorder()
+
+			req, err := http.NewRequest(tt.method, tt.endpoint, strings.NewReader(tt.body))
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", "authorization")
+
+			h.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if !tt.wantErr {
+				response := helpers.Response{}
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+
+				assert.Equal(t, tt.expectedBody, response)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package admin
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:generate mockgen -source=handler.go -destination=handler_mock_test.go -package=admin
+type Service interface {
+	ListTransactions(ctx context.Context, admin models.TokenData, req models.SearchRequest) (models.SearchResponse, error)
+	SearchTransactions(ctx context.Context, admin models.TokenData, req models.SearchRequest) (models.SearchResponse, error)
+	ForceStatus(ctx context.Context, admin models.TokenData, reference string, req models.AdminStatusRequest) error
+	Refund(ctx context.Context, admin models.TokenData, reference string, req models.AdminRefundRequest) (models.CreateTransactionResponse, error)
+	StatusHistory(ctx context.Context, admin models.TokenData, reference string) ([]models.TransactionStatusHistory, error)
+	Approve(ctx context.Context, admin models.TokenData, reference string, req models.AdminReviewRequest) error
+	Reject(ctx context.Context, admin models.TokenData, reference string, req models.AdminReviewRequest) error
+	ListLimits(ctx context.Context, admin models.TokenData) ([]models.TransactionLimit, error)
+	SetLimit(ctx context.Context, admin models.TokenData, req models.AdminLimitRequest) (models.TransactionLimit, error)
+	GetUserKYCTier(ctx context.Context, admin models.TokenData, userID uint64) (models.UserKYCTier, error)
+	SetUserKYCTier(ctx context.Context, admin models.TokenData, userID uint64, req models.AdminKYCTierRequest) (models.UserKYCTier, error)
+}
+
+type Handler struct {
+	*gin.Engine
+	Service    Service
+	Middleware Middleware
+}
+
+func NewHandler(api *gin.Engine, service Service, mdw Middleware) *Handler {
+	return &Handler{
+		api,
+		service,
+		mdw,
+	}
+}
+
+func (h *Handler) RegisterRoute() {
+	adminV1 := h.Group("/admin/v1", h.Middleware.MiddlewareValidateToken, h.Middleware.MiddlewareValidateAdmin,
+		h.Middleware.MiddlewareRateLimit(constants.RateLimitGroupAdmin))
+	adminV1.GET("/transactions", h.ListTransactions)
+	adminV1.GET("/transactions/search", h.SearchTransactions)
+	adminV1.POST("/transactions/:reference/status", h.ForceStatus)
+	adminV1.POST("/transactions/:reference/refund", h.Refund)
+	adminV1.GET("/transactions/:reference/history", h.StatusHistory)
+	adminV1.POST("/transactions/:reference/approve", h.Approve)
+	adminV1.POST("/transactions/:reference/reject", h.Reject)
+	adminV1.GET("/limits", h.ListLimits)
+	adminV1.PUT("/limits", h.SetLimit)
+	adminV1.GET("/users/:user_id/kyc-tier", h.GetUserKYCTier)
+	adminV1.PUT("/users/:user_id/kyc-tier", h.SetUserKYCTier)
+}
@@ -0,0 +1,204 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: handler.go
+//
+// Generated by this command:
+//
+//	mockgen -source=handler.go -destination=handler_mock_test.go -package=admin
+//
+
+// Package admin is a generated GoMock package.
+package admin
+
+import (
+	context "context"
+	models "ewallet-transaction/internal/models"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockService is a mock of Service interface.
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService.
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance.
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// Approve mocks base method.
+func (m *MockService) Approve(ctx context.Context, admin models.TokenData, reference string, req models.AdminReviewRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Approve", ctx, admin, reference, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Approve indicates an expected call of Approve.
+func (mr *MockServiceMockRecorder) Approve(ctx, admin, reference, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Approve", reflect.TypeOf((*MockService)(nil).Approve), ctx, admin, reference, req)
+}
+
+// ForceStatus mocks base method.
+func (m *MockService) ForceStatus(ctx context.Context, admin models.TokenData, reference string, req models.AdminStatusRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForceStatus", ctx, admin, reference, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForceStatus indicates an expected call of ForceStatus.
+func (mr *MockServiceMockRecorder) ForceStatus(ctx, admin, reference, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForceStatus", reflect.TypeOf((*MockService)(nil).ForceStatus), ctx, admin, reference, req)
+}
+
+// GetUserKYCTier mocks base method.
+func (m *MockService) GetUserKYCTier(ctx context.Context, admin models.TokenData, userID uint64) (models.UserKYCTier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserKYCTier", ctx, admin, userID)
+	ret0, _ := ret[0].(models.UserKYCTier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserKYCTier indicates an expected call of GetUserKYCTier.
+func (mr *MockServiceMockRecorder) GetUserKYCTier(ctx, admin, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserKYCTier", reflect.TypeOf((*MockService)(nil).GetUserKYCTier), ctx, admin, userID)
+}
+
+// ListLimits mocks base method.
+func (m *MockService) ListLimits(ctx context.Context, admin models.TokenData) ([]models.TransactionLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLimits", ctx, admin)
+	ret0, _ := ret[0].([]models.TransactionLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLimits indicates an expected call of ListLimits.
+func (mr *MockServiceMockRecorder) ListLimits(ctx, admin any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLimits", reflect.TypeOf((*MockService)(nil).ListLimits), ctx, admin)
+}
+
+// ListTransactions mocks base method.
+func (m *MockService) ListTransactions(ctx context.Context, admin models.TokenData, req models.SearchRequest) (models.SearchResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransactions", ctx, admin, req)
+	ret0, _ := ret[0].(models.SearchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransactions indicates an expected call of ListTransactions.
+func (mr *MockServiceMockRecorder) ListTransactions(ctx, admin, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactions", reflect.TypeOf((*MockService)(nil).ListTransactions), ctx, admin, req)
+}
+
+// Refund mocks base method.
+func (m *MockService) Refund(ctx context.Context, admin models.TokenData, reference string, req models.AdminRefundRequest) (models.CreateTransactionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refund", ctx, admin, reference, req)
+	ret0, _ := ret[0].(models.CreateTransactionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockServiceMockRecorder) Refund(ctx, admin, reference, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockService)(nil).Refund), ctx, admin, reference, req)
+}
+
+// Reject mocks base method.
+func (m *MockService) Reject(ctx context.Context, admin models.TokenData, reference string, req models.AdminReviewRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reject", ctx, admin, reference, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reject indicates an expected call of Reject.
+func (mr *MockServiceMockRecorder) Reject(ctx, admin, reference, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reject", reflect.TypeOf((*MockService)(nil).Reject), ctx, admin, reference, req)
+}
+
+// SearchTransactions mocks base method.
+func (m *MockService) SearchTransactions(ctx context.Context, admin models.TokenData, req models.SearchRequest) (models.SearchResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTransactions", ctx, admin, req)
+	ret0, _ := ret[0].(models.SearchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTransactions indicates an expected call of SearchTransactions.
+func (mr *MockServiceMockRecorder) SearchTransactions(ctx, admin, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTransactions", reflect.TypeOf((*MockService)(nil).SearchTransactions), ctx, admin, req)
+}
+
+// SetLimit mocks base method.
+func (m *MockService) SetLimit(ctx context.Context, admin models.TokenData, req models.AdminLimitRequest) (models.TransactionLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLimit", ctx, admin, req)
+	ret0, _ := ret[0].(models.TransactionLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetLimit indicates an expected call of SetLimit.
+func (mr *MockServiceMockRecorder) SetLimit(ctx, admin, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLimit", reflect.TypeOf((*MockService)(nil).SetLimit), ctx, admin, req)
+}
+
+// SetUserKYCTier mocks base method.
+func (m *MockService) SetUserKYCTier(ctx context.Context, admin models.TokenData, userID uint64, req models.AdminKYCTierRequest) (models.UserKYCTier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserKYCTier", ctx, admin, userID, req)
+	ret0, _ := ret[0].(models.UserKYCTier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUserKYCTier indicates an expected call of SetUserKYCTier.
+func (mr *MockServiceMockRecorder) SetUserKYCTier(ctx, admin, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserKYCTier", reflect.TypeOf((*MockService)(nil).SetUserKYCTier), ctx, admin, userID, req)
+}
+
+// StatusHistory mocks base method.
+func (m *MockService) StatusHistory(ctx context.Context, admin models.TokenData, reference string) ([]models.TransactionStatusHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StatusHistory", ctx, admin, reference)
+	ret0, _ := ret[0].([]models.TransactionStatusHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StatusHistory indicates an expected call of StatusHistory.
+func (mr *MockServiceMockRecorder) StatusHistory(ctx, admin, reference any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatusHistory", reflect.TypeOf((*MockService)(nil).StatusHistory), ctx, admin, reference)
+}
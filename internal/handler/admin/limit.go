@@ -0,0 +1,118 @@
+package admin
+
+import (
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListLimits lists the limits of every KYC tier and transaction type.
+func (h *Handler) ListLimits(c *gin.Context) {
+	tokenData, ok := getTokenData(c)
+	if !ok {
+		helpers.SendResponseHTTP(c, http.StatusInternalServerError, constants.ErrServerError, nil)
+		return
+	}
+
+	resp, err := h.Service.ListLimits(c.Request.Context(), tokenData)
+	if err != nil {
+		fmt.Println("failed to list limits, ", err)
+		helpers.SendErrorResponseHTTP(c, err)
+		return
+	}
+
+	helpers.SendResponseHTTP(c, http.StatusOK, constants.SuccessMessage, resp)
+}
+
+// SetLimit creates or replaces the limit of kyc_tier and transaction_type, reason is mandatory.
+func (h *Handler) SetLimit(c *gin.Context) {
+	var (
+		req models.AdminLimitRequest
+	)
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		fmt.Println("failed to parse request, ", err)
+		helpers.SendResponseHTTP(c, http.StatusBadRequest, constants.ErrFailedBadRequest, nil)
+		return
+	}
+
+	tokenData, ok := getTokenData(c)
+	if !ok {
+		helpers.SendResponseHTTP(c, http.StatusInternalServerError, constants.ErrServerError, nil)
+		return
+	}
+
+	resp, err := h.Service.SetLimit(c.Request.Context(), tokenData, req)
+	if err != nil {
+		fmt.Println("failed to set limit, ", err)
+		sendError(c, err)
+		return
+	}
+
+	helpers.SendResponseHTTP(c, http.StatusOK, constants.SuccessMessage, resp)
+}
+
+// GetUserKYCTier returns the KYC tier the limits of a user are taken from.
+func (h *Handler) GetUserKYCTier(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		fmt.Println("invalid user id, ", err)
+		helpers.SendResponseHTTP(c, http.StatusBadRequest, constants.ErrFailedBadRequest, nil)
+		return
+	}
+
+	tokenData, ok := getTokenData(c)
+	if !ok {
+		helpers.SendResponseHTTP(c, http.StatusInternalServerError, constants.ErrServerError, nil)
+		return
+	}
+
+	resp, err := h.Service.GetUserKYCTier(c.Request.Context(), tokenData, userID)
+	if err != nil {
+		fmt.Println("failed to get kyc tier, ", err)
+		helpers.SendErrorResponseHTTP(c, err)
+		return
+	}
+
+	helpers.SendResponseHTTP(c, http.StatusOK, constants.SuccessMessage, resp)
+}
+
+// SetUserKYCTier moves a user to kyc_tier, reason is mandatory.
+func (h *Handler) SetUserKYCTier(c *gin.Context) {
+	var (
+		req models.AdminKYCTierRequest
+	)
+
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		fmt.Println("invalid user id, ", err)
+		helpers.SendResponseHTTP(c, http.StatusBadRequest, constants.ErrFailedBadRequest, nil)
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		fmt.Println("failed to parse request, ", err)
+		helpers.SendResponseHTTP(c, http.StatusBadRequest, constants.ErrFailedBadRequest, nil)
+		return
+	}
+
+	tokenData, ok := getTokenData(c)
+	if !ok {
+		helpers.SendResponseHTTP(c, http.StatusInternalServerError, constants.ErrServerError, nil)
+		return
+	}
+
+	resp, err := h.Service.SetUserKYCTier(c.Request.Context(), tokenData, userID, req)
+	if err != nil {
+		fmt.Println("failed to set kyc tier, ", err)
+		sendError(c, err)
+		return
+	}
+
+	helpers.SendResponseHTTP(c, http.StatusOK, constants.SuccessMessage, resp)
+}
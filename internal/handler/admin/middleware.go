@@ -0,0 +1,10 @@
+package admin
+
+import "github.com/gin-gonic/gin"
+
+//go:generate mockgen -source=middleware.go -destination=middleware_mock_test.go -package=admin
+type Middleware interface {
+	MiddlewareValidateToken(c *gin.Context)
+	MiddlewareValidateAdmin(c *gin.Context)
+	MiddlewareRateLimit(group string) gin.HandlerFunc
+}
@@ -0,0 +1,91 @@
+package export
+
+import (
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Export streams the statement of the user. from and to are dates, both inclusive,
+// they default to the current month up to today.
+func (h *Handler) Export(c *gin.Context) {
+	token, ok := c.Get("token")
+	if !ok {
+		fmt.Println("failed to get token data")
+		helpers.SendResponseHTTP(c, http.StatusInternalServerError, constants.ErrServerError, nil)
+		return
+	}
+
+	tokenData, ok := token.(models.TokenData)
+	if !ok {
+		fmt.Println("failed to parse token data")
+		helpers.SendResponseHTTP(c, http.StatusInternalServerError, constants.ErrServerError, nil)
+		return
+	}
+
+	req, err := parseExportRequest(c, time.Now())
+	if err != nil {
+		fmt.Println("failed to parse export request, ", err)
+		helpers.SendResponseHTTP(c, http.StatusBadRequest, constants.ErrFailedBadRequest, nil)
+		return
+	}
+	req.UserID = tokenData.UserID
+
+	if err := h.Service.Validate(req); err != nil {
+		fmt.Println("failed to validate export request, ", err)
+		helpers.SendResponseHTTP(c, http.StatusBadRequest, constants.ErrFailedBadRequest, nil)
+		return
+	}
+
+	filename := fmt.Sprintf("statement-%s-%s.%s", req.From.Format(constants.ExportDateLayout), req.To.AddDate(0, 0, -1).Format(constants.ExportDateLayout), req.Format)
+	c.Header("Content-Type", constants.MapExportContentType[req.Format])
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	err = h.Service.Export(c.Request.Context(), req, c.Writer)
+	if err == nil {
+		return
+	}
+
+	fmt.Println("failed to export transactions, ", err)
+	if c.Writer.Written() {
+		// only csv flushes before the end, the cut file has no closing balance for its last period
+		c.Abort()
+		return
+	}
+
+	c.Writer.Header().Del("Content-Disposition")
+	c.Writer.Header().Del("Content-Type")
+	helpers.SendErrorResponseHTTP(c, err)
+}
+
+func parseExportRequest(c *gin.Context, now time.Time) (models.ExportRequest, error) {
+	req := models.ExportRequest{
+		Format: c.DefaultQuery("format", constants.ExportFormatCSV),
+		From:   time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()),
+		To:     time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1),
+	}
+
+	if from := c.Query("from"); from != "" {
+		parsed, err := time.ParseInLocation(constants.ExportDateLayout, from, now.Location())
+		if err != nil {
+			return req, err
+		}
+		req.From = parsed
+	}
+
+	if to := c.Query("to"); to != "" {
+		parsed, err := time.ParseInLocation(constants.ExportDateLayout, to, now.Location())
+		if err != nil {
+			return req, err
+		}
+		req.To = parsed.AddDate(0, 0, 1)
+	}
+
+	return req, nil
+}
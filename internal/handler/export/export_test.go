@@ -0,0 +1,164 @@
+package export
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+)
+
+var tokenData = models.TokenData{
+	UserID:   1,
+	Username: "USERNAME",
+	Fullname: "FULLNAME",
+	Token:    "TOKEN",
+	Email:    "EMAIL",
+}
+
+func TestHandler_Export(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockSvc := NewMockService(ctrlMock)
+	mockMdw := NewMockMiddleware(ctrlMock)
+	mockMdw.EXPECT().MiddlewareRateLimit(gomock.Any()).Return(func(c *gin.Context) { c.Next() }).AnyTimes()
+
+	wantReq := models.ExportRequest{
+		UserID: 1,
+		Format: constants.ExportFormatPDF,
+		From:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local),
+		To:     time.Date(2025, 2, 1, 0, 0, 0, 0, time.Local),
+	}
+
+	validToken := func() {
+		mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+			c.Set("token", tokenData)
+			c.Next()
+		})
+	}
+
+	tests := []struct {
+		name               string
+		endpoint           string
+		expectedStatusCode int
+		expectedBody       string
+		expectedHeader     string
+		expectedType       string
+		mockFn             func()
+	}{
+		{
+			name:               "success",
+			endpoint:           "/transaction/v1/export?format=pdf&from=2025-01-01&to=2025-01-31",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "%PDF-",
+			expectedHeader:     `attachment; filename="statement-2025-01-01-2025-01-31.pdf"`,
+			mockFn: func() {
+				validToken()
+				mockSvc.EXPECT().Validate(wantReq).Return(nil)
+				mockSvc.EXPECT().Export(gomock.Any(), wantReq, gomock.Any()).DoAndReturn(func(ctx context.Context, req models.ExportRequest, w io.Writer) error {
+					_, err := w.Write([]byte("%PDF-"))
+					return err
+				})
+			},
+		},
+		{
+			name:               "error invalid date",
+			endpoint:           "/transaction/v1/export?from=01-01-2025",
+			expectedStatusCode: http.StatusBadRequest,
+			mockFn:             validToken,
+		},
+		{
+			name:               "error invalid request",
+			endpoint:           "/transaction/v1/export?format=doc",
+			expectedStatusCode: http.StatusBadRequest,
+			mockFn: func() {
+				validToken()
+				mockSvc.EXPECT().Validate(gomock.Any()).Return(assert.AnError)
+			},
+		},
+		{
+			name:               "error before the first write",
+			endpoint:           "/transaction/v1/export",
+			expectedStatusCode: http.StatusGatewayTimeout,
+			expectedType:       "application/json; charset=utf-8",
+			mockFn: func() {
+				validToken()
+				mockSvc.EXPECT().Validate(gomock.Any()).Return(nil)
+				mockSvc.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).Return(helpers.ErrQueryTimeout)
+			},
+		},
+		{
+			name:               "error after the first write",
+			endpoint:           "/transaction/v1/export",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "date,reference",
+			mockFn: func() {
+				validToken()
+				mockSvc.EXPECT().Validate(gomock.Any()).Return(nil)
+				mockSvc.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req models.ExportRequest, w io.Writer) error {
+					_, err := w.Write([]byte("date,reference"))
+					assert.NoError(t, err)
+					return assert.AnError
+				})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			api := gin.New()
+			h := &Handler{
+				Engine:     api,
+				Service:    mockSvc,
+				Middleware: mockMdw,
+			}
+			h.RegisterRoute()
+			w := httptest.NewRecorder()
+
+			req, err := http.NewRequest(http.MethodGet, tt.endpoint, nil)
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", "authorization")
+
+			h.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+			}
+			if tt.expectedHeader != "" {
+				assert.Equal(t, tt.expectedHeader, w.Header().Get("Content-Disposition"))
+				assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+			}
+			if tt.expectedType != "" {
+				assert.Equal(t, tt.expectedType, w.Header().Get("Content-Type"))
+				assert.Empty(t, w.Header().Get("Content-Disposition"))
+			}
+		})
+	}
+}
+
+func Test_parseExportRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	now := time.Date(2025, 5, 20, 15, 0, 0, 0, time.UTC)
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/transaction/v1/export", nil)
+
+	req, err := parseExportRequest(c, now)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ExportRequest{
+		Format: constants.ExportFormatCSV,
+		From:   time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+		To:     time.Date(2025, 5, 21, 0, 0, 0, 0, time.UTC),
+	}, req)
+}
@@ -0,0 +1,35 @@
+package export
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+	"io"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:generate mockgen -source=handler.go -destination=handler_mock_test.go -package=export
+type Service interface {
+	Validate(req models.ExportRequest) error
+	Export(ctx context.Context, req models.ExportRequest, w io.Writer) error
+}
+
+type Handler struct {
+	*gin.Engine
+	Service    Service
+	Middleware Middleware
+}
+
+func NewHandler(api *gin.Engine, service Service, mdw Middleware) *Handler {
+	return &Handler{
+		api,
+		service,
+		mdw,
+	}
+}
+
+func (h *Handler) RegisterRoute() {
+	transactionV1 := h.Group("/transaction/v1")
+	transactionV1.GET("/export", h.Middleware.MiddlewareValidateToken, h.Middleware.MiddlewareRateLimit(constants.RateLimitGroupExport), h.Export)
+}
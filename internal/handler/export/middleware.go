@@ -0,0 +1,9 @@
+package export
+
+import "github.com/gin-gonic/gin"
+
+//go:generate mockgen -source=middleware.go -destination=middleware_mock_test.go -package=export
+type Middleware interface {
+	MiddlewareValidateToken(c *gin.Context)
+	MiddlewareRateLimit(group string) gin.HandlerFunc
+}
@@ -0,0 +1,34 @@
+package settlement
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+	"io"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:generate mockgen -source=handler.go -destination=handler_mock_test.go -package=settlement
+type Service interface {
+	Import(ctx context.Context, provider string, r io.Reader) (models.SettlementReport, error)
+}
+
+type Handler struct {
+	*gin.Engine
+	Service    Service
+	Middleware Middleware
+}
+
+func NewHandler(api *gin.Engine, service Service, mdw Middleware) *Handler {
+	return &Handler{
+		api,
+		service,
+		mdw,
+	}
+}
+
+func (h *Handler) RegisterRoute() {
+	settlementV1 := h.Group("/settlement/v1")
+	settlementV1.POST("/import", h.Middleware.MiddlewareValidateSettlementKey, h.Middleware.MiddlewareRateLimit(constants.RateLimitGroupSettlement), h.Import)
+}
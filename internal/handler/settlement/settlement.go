@@ -0,0 +1,47 @@
+package settlement
+
+import (
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// Import reads the settlement file of the multipart field "file", the provider query selects its
+// column mapping. Unmatched lines are part of the report, they don't fail the request.
+func (h *Handler) Import(c *gin.Context) {
+	provider := c.DefaultQuery("provider", constants.SettlementDefaultProvider)
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, constants.SettlementMaxUploadSize)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		fmt.Println("failed to get settlement file, ", err)
+		helpers.SendResponseHTTP(c, http.StatusBadRequest, constants.ErrFailedBadRequest, nil)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		fmt.Println("failed to open settlement file, ", err)
+		helpers.SendResponseHTTP(c, http.StatusBadRequest, constants.ErrFailedBadRequest, nil)
+		return
+	}
+	defer file.Close()
+
+	report, err := h.Service.Import(c.Request.Context(), provider, file)
+	if err != nil {
+		fmt.Println("failed to import settlement file, ", err)
+		if errors.Is(err, models.ErrInvalidSettlementFile) {
+			helpers.SendResponseHTTP(c, http.StatusBadRequest, constants.ErrFailedBadRequest, nil)
+			return
+		}
+		helpers.SendErrorResponseHTTP(c, err)
+		return
+	}
+
+	helpers.SendResponseHTTP(c, http.StatusOK, constants.SuccessMessage, report)
+}
@@ -0,0 +1,160 @@
+package settlement
+
+import (
+	"bytes"
+	"encoding/json"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestHandler_Import(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockSvc := NewMockService(ctrlMock)
+	mockMdw := NewMockMiddleware(ctrlMock)
+	mockMdw.EXPECT().MiddlewareRateLimit(gomock.Any()).Return(func(c *gin.Context) { c.Next() }).AnyTimes()
+
+	file := "reference,amount\nREFERENCE,100000\n"
+
+	tests := []struct {
+		name               string
+		endpoint           string
+		withFile           bool
+		expectedStatusCode int
+		expectedBody       helpers.Response
+		wantErr            bool
+		mockFn             func()
+	}{
+		{
+			name:               "success",
+			endpoint:           "/settlement/v1/import?provider=bank",
+			withFile:           true,
+			expectedStatusCode: http.StatusOK,
+			expectedBody: helpers.Response{
+				Message: constants.SuccessMessage,
+				Data: map[string]interface{}{
+					"provider":       "bank",
+					"lines":          float64(1),
+					"matched":        float64(1),
+					"marked_success": float64(1),
+					"unmatched":      []interface{}{},
+				},
+			},
+			mockFn: func() {
+				mockMdw.EXPECT().MiddlewareValidateSettlementKey(gomock.Any()).Do(func(c *gin.Context) {
+					c.Next()
+				})
+
+				mockSvc.EXPECT().Import(gomock.Any(), "bank", gomock.Any()).DoAndReturn(func(_ interface{}, provider string, r io.Reader) (models.SettlementReport, error) {
+					content, err := io.ReadAll(r)
+					assert.NoError(t, err)
+					assert.Equal(t, file, string(content))
+					return models.SettlementReport{Provider: provider, Lines: 1, Matched: 1, MarkedSuccess: 1, Unmatched: []models.SettlementUnmatched{}}, nil
+				})
+			},
+		},
+		{
+			name:               "error without file",
+			endpoint:           "/settlement/v1/import",
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+			mockFn: func() {
+				mockMdw.EXPECT().MiddlewareValidateSettlementKey(gomock.Any()).Do(func(c *gin.Context) {
+					c.Next()
+				})
+			},
+		},
+		{
+			name:               "error invalid file",
+			endpoint:           "/settlement/v1/import",
+			withFile:           true,
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+			mockFn: func() {
+				mockMdw.EXPECT().MiddlewareValidateSettlementKey(gomock.Any()).Do(func(c *gin.Context) {
+					c.Next()
+				})
+
+				mockSvc.EXPECT().Import(gomock.Any(), constants.SettlementDefaultProvider, gomock.Any()).Return(models.SettlementReport{}, errors.Wrap(models.ErrInvalidSettlementFile, "unknown provider"))
+			},
+		},
+		{
+			name:               "error timeout",
+			endpoint:           "/settlement/v1/import",
+			withFile:           true,
+			expectedStatusCode: http.StatusGatewayTimeout,
+			wantErr:            true,
+			mockFn: func() {
+				mockMdw.EXPECT().MiddlewareValidateSettlementKey(gomock.Any()).Do(func(c *gin.Context) {
+					c.Next()
+				})
+
+				mockSvc.EXPECT().Import(gomock.Any(), constants.SettlementDefaultProvider, gomock.Any()).Return(models.SettlementReport{}, helpers.ErrQueryTimeout)
+			},
+		},
+		{
+			name:               "error unauthorized",
+			endpoint:           "/settlement/v1/import",
+			withFile:           true,
+			expectedStatusCode: http.StatusUnauthorized,
+			wantErr:            true,
+			mockFn: func() {
+				mockMdw.EXPECT().MiddlewareValidateSettlementKey(gomock.Any()).Do(func(c *gin.Context) {
+					c.AbortWithStatus(http.StatusUnauthorized)
+				})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			api := gin.New()
+			h := &Handler{
+				Engine:     api,
+				Service:    mockSvc,
+				Middleware: mockMdw,
+			}
+			h.RegisterRoute()
+			w := httptest.NewRecorder()
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			if tt.withFile {
+				part, err := writer.CreateFormFile("file", "settlement.csv")
+				assert.NoError(t, err)
+				_, err = part.Write([]byte(file))
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, writer.Close())
+
+			req, err := http.NewRequest(http.MethodPost, tt.endpoint, body)
+			assert.NoError(t, err)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			req.Header.Set(constants.SettlementAPIKeyHeader, "KEY")
+
+			h.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if !tt.wantErr {
+				response := helpers.Response{}
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+
+				assert.Equal(t, tt.expectedBody, response)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package summary
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:generate mockgen -source=handler.go -destination=handler_mock_test.go -package=summary
+type Service interface {
+	GetSummary(ctx context.Context, userID uint64, req models.SummaryRequest) (models.TransactionSummary, error)
+}
+
+type Handler struct {
+	*gin.Engine
+	Service    Service
+	Middleware Middleware
+}
+
+func NewHandler(api *gin.Engine, service Service, mdw Middleware) *Handler {
+	return &Handler{
+		api,
+		service,
+		mdw,
+	}
+}
+
+func (h *Handler) RegisterRoute() {
+	transactionV1 := h.Group("/transaction/v1")
+	transactionV1.GET("/summary", h.Middleware.MiddlewareValidateToken, h.Middleware.MiddlewareRateLimit(constants.RateLimitGroupSummary), h.GetSummary)
+}
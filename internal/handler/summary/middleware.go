@@ -0,0 +1,9 @@
+package summary
+
+import "github.com/gin-gonic/gin"
+
+//go:generate mockgen -source=middleware.go -destination=middleware_mock_test.go -package=summary
+type Middleware interface {
+	MiddlewareValidateToken(c *gin.Context)
+	MiddlewareRateLimit(group string) gin.HandlerFunc
+}
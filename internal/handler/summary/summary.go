@@ -0,0 +1,49 @@
+package summary
+
+import (
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// GetSummary returns the spending summary of the user, period is day, week or month
+// and tz an IANA timezone like Asia/Jakarta, group_by optionally is category, merchant or tag.
+func (h *Handler) GetSummary(c *gin.Context) {
+	token, ok := c.Get("token")
+	if !ok {
+		fmt.Println("failed to get token data")
+		helpers.SendResponseHTTP(c, http.StatusInternalServerError, constants.ErrServerError, nil)
+		return
+	}
+
+	tokenData, ok := token.(models.TokenData)
+	if !ok {
+		fmt.Println("failed to parse token data")
+		helpers.SendResponseHTTP(c, http.StatusInternalServerError, constants.ErrServerError, nil)
+		return
+	}
+
+	req := models.SummaryRequest{
+		Period:   c.DefaultQuery("period", constants.SummaryPeriodMonth),
+		Timezone: c.Query("tz"),
+		GroupBy:  c.Query("group_by"),
+	}
+
+	resp, err := h.Service.GetSummary(c.Request.Context(), tokenData.UserID, req)
+	if err != nil {
+		fmt.Println("failed to get summary, ", err)
+		if errors.Is(err, models.ErrInvalidSummaryRequest) {
+			helpers.SendResponseHTTP(c, http.StatusBadRequest, constants.ErrFailedBadRequest, nil)
+			return
+		}
+		helpers.SendErrorResponseHTTP(c, err)
+		return
+	}
+
+	helpers.SendResponseHTTP(c, http.StatusOK, constants.SuccessMessage, resp)
+}
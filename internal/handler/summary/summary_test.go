@@ -0,0 +1,157 @@
+package summary
+
+import (
+	"encoding/json"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+)
+
+var tokenData = models.TokenData{
+	UserID:   1,
+	Username: "USERNAME",
+	Fullname: "FULLNAME",
+	Token:    "TOKEN",
+	Email:    "EMAIL",
+}
+
+func TestHandler_GetSummary(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockSvc := NewMockService(ctrlMock)
+	mockMdw := NewMockMiddleware(ctrlMock)
+	mockMdw.EXPECT().MiddlewareRateLimit(gomock.Any()).Return(func(c *gin.Context) { c.Next() }).AnyTimes()
+
+	validToken := func() {
+		mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+			c.Set("token", tokenData)
+			c.Next()
+		})
+	}
+
+	tests := []struct {
+		name               string
+		endpoint           string
+		expectedStatusCode int
+		expectedBody       helpers.Response
+		wantErr            bool
+		mockFn             func()
+	}{
+		{
+			name:               "success",
+			endpoint:           "/transaction/v1/summary?period=week&tz=Asia/Makassar",
+			expectedStatusCode: http.StatusOK,
+			expectedBody: helpers.Response{
+				Message: constants.SuccessMessage,
+				Data: map[string]interface{}{
+					"period":   "week",
+					"timezone": "Asia/Makassar",
+					"from":     "0001-01-01T00:00:00Z",
+					"to":       "0001-01-01T00:00:00Z",
+					"totals": []interface{}{
+						map[string]interface{}{"transaction_type": "TOPUP", "count": float64(1), "amount": float64(50000)},
+					},
+					"net_flow": float64(50000),
+					"series":   nil,
+				},
+			},
+			mockFn: func() {
+				validToken()
+				mockSvc.EXPECT().GetSummary(gomock.Any(), uint64(1), models.SummaryRequest{Period: "week", Timezone: "Asia/Makassar"}).Return(models.TransactionSummary{
+					Period:   "week",
+					Timezone: "Asia/Makassar",
+					Totals:   []models.SummaryTotal{{TransactionType: "TOPUP", Count: 1, Amount: 50000}},
+					NetFlow:  50000,
+				}, nil)
+			},
+		},
+		{
+			name:               "success group by",
+			endpoint:           "/transaction/v1/summary?group_by=category",
+			expectedStatusCode: http.StatusOK,
+			expectedBody: helpers.Response{
+				Message: constants.SuccessMessage,
+				Data: map[string]interface{}{
+					"period":   "month",
+					"timezone": "Asia/Jakarta",
+					"from":     "0001-01-01T00:00:00Z",
+					"to":       "0001-01-01T00:00:00Z",
+					"totals":   nil,
+					"net_flow": float64(0),
+					"series":   nil,
+					"group_by": "category",
+					"groups": []interface{}{
+						map[string]interface{}{"key": "BILLS", "name": "Tagihan", "count": float64(1), "amount": float64(150000)},
+					},
+				},
+			},
+			mockFn: func() {
+				validToken()
+				mockSvc.EXPECT().GetSummary(gomock.Any(), uint64(1), models.SummaryRequest{Period: "month", GroupBy: "category"}).Return(models.TransactionSummary{
+					Period:   "month",
+					Timezone: "Asia/Jakarta",
+					GroupBy:  "category",
+					Groups:   []models.SummaryGroup{{Key: "BILLS", Name: "Tagihan", Count: 1, Amount: 150000}},
+				}, nil)
+			},
+		},
+		{
+			name:               "error invalid period",
+			endpoint:           "/transaction/v1/summary?period=year",
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+			mockFn: func() {
+				validToken()
+				mockSvc.EXPECT().GetSummary(gomock.Any(), uint64(1), models.SummaryRequest{Period: "year"}).Return(models.TransactionSummary{}, errors.Wrap(models.ErrInvalidSummaryRequest, "unknown period"))
+			},
+		},
+		{
+			name:               "error",
+			endpoint:           "/transaction/v1/summary",
+			expectedStatusCode: http.StatusInternalServerError,
+			wantErr:            true,
+			mockFn: func() {
+				validToken()
+				mockSvc.EXPECT().GetSummary(gomock.Any(), uint64(1), models.SummaryRequest{Period: constants.SummaryPeriodMonth}).Return(models.TransactionSummary{}, assert.AnError)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			api := gin.New()
+			h := &Handler{
+				Engine:     api,
+				Service:    mockSvc,
+				Middleware: mockMdw,
+			}
+			h.RegisterRoute()
+			w := httptest.NewRecorder()
+
+			req, err := http.NewRequest(http.MethodGet, tt.endpoint, nil)
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", "authorization")
+
+			h.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if !tt.wantErr {
+				response := helpers.Response{}
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+
+				assert.Equal(t, tt.expectedBody, response)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package transaction
 
 import (
 	"context"
+	"ewallet-transaction/constants"
 	"ewallet-transaction/internal/models"
 
 	"github.com/gin-gonic/gin"
@@ -12,8 +13,9 @@ type Service interface {
 	CreateTransaction(ctx context.Context, req *models.Transaction) (models.CreateTransactionResponse, error)
 	UpdateStatusTransaction(ctx context.Context, tokenData models.TokenData, req *models.UpdateStatusTransaction) error
 	GetTransactionDetail(ctx context.Context, reference string) (models.Transaction, error)
-	GetTransaction(ctx context.Context, userID uint64) ([]models.Transaction, error)
+	GetTransaction(ctx context.Context, userID uint64, filter models.TransactionFilter) ([]models.Transaction, error)
 	RefundTransaction(ctx context.Context, tokenData models.TokenData, req *models.RefundTransaction) (models.CreateTransactionResponse, error)
+	ListCategories(ctx context.Context) ([]models.TransactionCategory, error)
 }
 
 type Handler struct {
@@ -34,9 +36,11 @@ func NewHandler(api *gin.Engine, service Service, ext External, mdw Middleware)
 
 func (h *Handler) RegisterRoute() {
 	transactionV1 := h.Group("/transaction/v1")
-	transactionV1.POST("/create", h.Middleware.MiddlewareValidateToken, h.CreateTransaction)
-	transactionV1.PUT("/update-status/:reference", h.Middleware.MiddlewareValidateToken, h.UpdateStatusTransaction)
-	transactionV1.GET("/", h.Middleware.MiddlewareValidateToken, h.GetTransaction)
-	transactionV1.GET("/:reference", h.Middleware.MiddlewareValidateToken, h.GetTransactionDetail)
-	transactionV1.POST("/refund", h.Middleware.MiddlewareValidateToken, h.RefundTransaction)
+	limit := h.Middleware.MiddlewareRateLimit(constants.RateLimitGroupTransaction)
+	transactionV1.POST("/create", h.Middleware.MiddlewareValidateToken, limit, h.CreateTransaction)
+	transactionV1.PUT("/update-status/:reference", h.Middleware.MiddlewareValidateToken, limit, h.UpdateStatusTransaction)
+	transactionV1.GET("/", h.Middleware.MiddlewareValidateToken, limit, h.GetTransaction)
+	transactionV1.GET("/categories", h.Middleware.MiddlewareValidateToken, limit, h.ListCategories)
+	transactionV1.GET("/:reference", h.Middleware.MiddlewareValidateToken, limit, h.GetTransactionDetail)
+	transactionV1.POST("/refund", h.Middleware.MiddlewareValidateToken, limit, h.RefundTransaction)
 }
@@ -57,18 +57,18 @@ func (mr *MockServiceMockRecorder) CreateTransaction(ctx, req any) *gomock.Call
 }
 
 // GetTransaction mocks base method.
-func (m *MockService) GetTransaction(ctx context.Context, userID uint64) ([]models.Transaction, error) {
+func (m *MockService) GetTransaction(ctx context.Context, userID uint64, filter models.TransactionFilter) ([]models.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransaction", ctx, userID)
+	ret := m.ctrl.Call(m, "GetTransaction", ctx, userID, filter)
 	ret0, _ := ret[0].([]models.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTransaction indicates an expected call of GetTransaction.
-func (mr *MockServiceMockRecorder) GetTransaction(ctx, userID any) *gomock.Call {
+func (mr *MockServiceMockRecorder) GetTransaction(ctx, userID, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransaction", reflect.TypeOf((*MockService)(nil).GetTransaction), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransaction", reflect.TypeOf((*MockService)(nil).GetTransaction), ctx, userID, filter)
 }
 
 // GetTransactionDetail mocks base method.
@@ -86,6 +86,21 @@ func (mr *MockServiceMockRecorder) GetTransactionDetail(ctx, reference any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionDetail", reflect.TypeOf((*MockService)(nil).GetTransactionDetail), ctx, reference)
 }
 
+// ListCategories mocks base method.
+func (m *MockService) ListCategories(ctx context.Context) ([]models.TransactionCategory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCategories", ctx)
+	ret0, _ := ret[0].([]models.TransactionCategory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCategories indicates an expected call of ListCategories.
+func (mr *MockServiceMockRecorder) ListCategories(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCategories", reflect.TypeOf((*MockService)(nil).ListCategories), ctx)
+}
+
 // RefundTransaction mocks base method.
 func (m *MockService) RefundTransaction(ctx context.Context, tokenData models.TokenData, req *models.RefundTransaction) (models.CreateTransactionResponse, error) {
 	m.ctrl.T.Helper()
@@ -40,6 +40,20 @@ func (m *MockMiddleware) EXPECT() *MockMiddlewareMockRecorder {
 	return m.recorder
 }
 
+// MiddlewareRateLimit mocks base method.
+func (m *MockMiddleware) MiddlewareRateLimit(group string) gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MiddlewareRateLimit", group)
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// MiddlewareRateLimit indicates an expected call of MiddlewareRateLimit.
+func (mr *MockMiddlewareMockRecorder) MiddlewareRateLimit(group any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MiddlewareRateLimit", reflect.TypeOf((*MockMiddleware)(nil).MiddlewareRateLimit), group)
+}
+
 // MiddlewareValidateToken mocks base method.
 func (m *MockMiddleware) MiddlewareValidateToken(c *gin.Context) {
 	m.ctrl.T.Helper()
@@ -6,8 +6,11 @@ import (
 	"ewallet-transaction/internal/models"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
 )
 
 func (h *Handler) CreateTransaction(c *gin.Context) {
@@ -47,12 +50,35 @@ func (h *Handler) CreateTransaction(c *gin.Context) {
 		return
 	}
 
+	tags, err := models.NormalizeTags(req.Tags)
+	if err != nil {
+		fmt.Println("invalid tags, ", err)
+		helpers.SendResponseHTTP(c, http.StatusBadRequest, constants.ErrFailedBadRequest, nil)
+		return
+	}
+
 	req.UserID = tokenData.UserID
+	req.Tags = tags
+	// the screening compares them with the history of the user, they can't come from the body
+	req.DeviceID = c.GetHeader(constants.DeviceIDHeader)
+	req.ClientIP = c.ClientIP()
+	if len(req.DeviceID) > models.MaxDeviceIDLength {
+		fmt.Println("invalid device id")
+		helpers.SendResponseHTTP(c, http.StatusBadRequest, constants.ErrFailedBadRequest, nil)
+		return
+	}
 
 	resp, err := h.Service.CreateTransaction(c.Request.Context(), &req)
 	if err != nil {
 		fmt.Println("failed to create transaction, ", err)
-		helpers.SendResponseHTTP(c, http.StatusInternalServerError, constants.ErrServerError, nil)
+		if errors.Is(err, models.ErrInvalidCategory) || errors.Is(err, models.ErrInvalidMerchant) {
+			helpers.SendResponseHTTP(c, http.StatusBadRequest, constants.ErrFailedBadRequest, nil)
+			return
+		}
+		if sendAdditionalInfoError(c, err) || sendLimitExceededError(c, err) {
+			return
+		}
+		helpers.SendErrorResponseHTTP(c, err)
 		return
 	}
 
@@ -95,7 +121,25 @@ func (h *Handler) UpdateStatusTransaction(c *gin.Context) {
 
 	if err != nil {
 		fmt.Println("failed to update transaction, ", err)
-		helpers.SendResponseHTTP(c, http.StatusInternalServerError, constants.ErrServerError, nil)
+		if sendAdditionalInfoError(c, err) || sendLimitExceededError(c, err) {
+			return
+		}
+		if errors.Is(err, models.ErrTransactionOnHold) {
+			helpers.SendResponseHTTP(c, http.StatusAccepted, constants.ErrTransactionOnHold, models.CreateTransactionResponse{
+				Reference:         req.Reference,
+				TransactionStatus: constants.TransactionStatusOnHold,
+			})
+			return
+		}
+		if errors.Is(err, models.ErrTransactionDenied) {
+			helpers.SendResponseHTTP(c, http.StatusForbidden, constants.ErrTransactionDenied, nil)
+			return
+		}
+		if errors.Is(err, models.ErrStatusConflict) {
+			helpers.SendResponseHTTP(c, http.StatusConflict, constants.ErrStatusConflict, nil)
+			return
+		}
+		helpers.SendErrorResponseHTTP(c, err)
 		return
 	}
 
@@ -118,11 +162,25 @@ func (h *Handler) GetTransaction(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.Service.GetTransaction(c.Request.Context(), uint64(tokenData.UserID))
+	filter := models.TransactionFilter{
+		Category: strings.ToUpper(c.Query("category")),
+		Tag:      strings.ToLower(c.Query("tag")),
+	}
+	if merchantID := c.Query("merchant_id"); merchantID != "" {
+		id, err := strconv.ParseUint(merchantID, 10, 64)
+		if err != nil {
+			fmt.Println("invalid merchant id, ", err)
+			helpers.SendResponseHTTP(c, http.StatusBadRequest, constants.ErrFailedBadRequest, nil)
+			return
+		}
+		filter.MerchantID = id
+	}
+
+	resp, err := h.Service.GetTransaction(c.Request.Context(), uint64(tokenData.UserID), filter)
 
 	if err != nil {
 		fmt.Println("failed to update transaction, ", err)
-		helpers.SendResponseHTTP(c, http.StatusInternalServerError, constants.ErrServerError, nil)
+		helpers.SendErrorResponseHTTP(c, err)
 		return
 	}
 
@@ -155,7 +213,7 @@ func (h *Handler) GetTransactionDetail(c *gin.Context) {
 
 	if err != nil {
 		fmt.Println("failed to update transaction, ", err)
-		helpers.SendResponseHTTP(c, http.StatusInternalServerError, constants.ErrServerError, nil)
+		helpers.SendErrorResponseHTTP(c, err)
 		return
 	}
 
@@ -196,9 +254,45 @@ func (h *Handler) RefundTransaction(c *gin.Context) {
 	resp, err := h.Service.RefundTransaction(c.Request.Context(), tokenData, &req)
 	if err != nil {
 		fmt.Println("failed to refund transaction, ", err)
-		helpers.SendResponseHTTP(c, http.StatusInternalServerError, constants.ErrServerError, nil)
+		if sendAdditionalInfoError(c, err) {
+			return
+		}
+		helpers.SendErrorResponseHTTP(c, err)
 		return
 	}
 
 	helpers.SendResponseHTTP(c, http.StatusOK, constants.SuccessMessage, resp)
 }
+
+func (h *Handler) ListCategories(c *gin.Context) {
+	resp, err := h.Service.ListCategories(c.Request.Context())
+	if err != nil {
+		fmt.Println("failed to list categories, ", err)
+		helpers.SendErrorResponseHTTP(c, err)
+		return
+	}
+
+	helpers.SendResponseHTTP(c, http.StatusOK, constants.SuccessMessage, resp)
+}
+
+// sendAdditionalInfoError answers 400 with the invalid fields when additional_info failed its schema.
+func sendAdditionalInfoError(c *gin.Context, err error) bool {
+	var infoErr *models.AdditionalInfoError
+	if !errors.As(err, &infoErr) {
+		return false
+	}
+
+	helpers.SendResponseHTTP(c, http.StatusBadRequest, constants.ErrFailedBadRequest, infoErr.Fields)
+	return true
+}
+
+// sendLimitExceededError answers 422 with the exceeded limit when the transaction is over a limit of the user.
+func sendLimitExceededError(c *gin.Context, err error) bool {
+	var limitErr *models.LimitExceededError
+	if !errors.As(err, &limitErr) {
+		return false
+	}
+
+	helpers.SendResponseHTTP(c, http.StatusUnprocessableEntity, constants.ErrLimitExceeded, limitErr)
+	return true
+}
@@ -2,6 +2,7 @@ package transaction
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"ewallet-transaction/constants"
 	"ewallet-transaction/helpers"
@@ -9,10 +10,12 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -24,6 +27,7 @@ func TestHandler_CreateTransaction(t *testing.T) {
 	mockSvc := NewMockService(ctrlMock)
 	mockExt := NewMockExternal(ctrlMock)
 	mockMdw := NewMockMiddleware(ctrlMock)
+	mockMdw.EXPECT().MiddlewareRateLimit(gomock.Any()).Return(func(c *gin.Context) { c.Next() }).AnyTimes()
 
 	trx := models.Transaction{
 		UserID:            1,
@@ -97,6 +101,82 @@ func TestHandler_CreateTransaction(t *testing.T) {
 				mockSvc.EXPECT().CreateTransaction(gomock.Any(), &trx).Return(models.CreateTransactionResponse{}, assert.AnError)
 			},
 		},
+		{
+			name:               "error invalid category",
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+			mockFn: func() {
+				mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+					c.Set("token", models.TokenData{UserID: 1})
+					c.Next()
+				})
+
+				mockSvc.EXPECT().CreateTransaction(gomock.Any(), &trx).Return(models.CreateTransactionResponse{}, errors.Wrap(models.ErrInvalidCategory, "unknown category"))
+			},
+		},
+		{
+			name:               "error unknown merchant",
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+			mockFn: func() {
+				mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+					c.Set("token", models.TokenData{UserID: 1})
+					c.Next()
+				})
+
+				mockSvc.EXPECT().CreateTransaction(gomock.Any(), &trx).Return(models.CreateTransactionResponse{}, errors.Wrap(models.ErrInvalidMerchant, "unknown merchant 10"))
+			},
+		},
+		{
+			name:               "error invalid additional info",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody: helpers.Response{
+				Message: constants.ErrFailedBadRequest,
+				Data: []interface{}{
+					map[string]interface{}{"field": "additional_info.foo", "message": "unknown field"},
+				},
+			},
+			mockFn: func() {
+				mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+					c.Set("token", models.TokenData{UserID: 1})
+					c.Next()
+				})
+
+				mockSvc.EXPECT().CreateTransaction(gomock.Any(), &trx).Return(models.CreateTransactionResponse{}, errors.Wrap(&models.AdditionalInfoError{
+					Fields: []models.FieldError{{Field: "additional_info.foo", Message: "unknown field"}},
+				}, "failed to validate additional info"))
+			},
+		},
+		{
+			name:               "error limit exceeded",
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody: helpers.Response{
+				Message: constants.ErrLimitExceeded,
+				Data: map[string]interface{}{
+					"code":             constants.LimitExceededCode,
+					"limit":            constants.LimitMaxAmount,
+					"kyc_tier":         constants.KYCTierBasic,
+					"transaction_type": constants.TransactionTypeTopup,
+					"max":              float64(2000000),
+					"current":          float64(3000000),
+				},
+			},
+			mockFn: func() {
+				mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+					c.Set("token", models.TokenData{UserID: 1})
+					c.Next()
+				})
+
+				mockSvc.EXPECT().CreateTransaction(gomock.Any(), &trx).Return(models.CreateTransactionResponse{}, errors.Wrap(&models.LimitExceededError{
+					Code:            constants.LimitExceededCode,
+					Limit:           constants.LimitMaxAmount,
+					KYCTier:         constants.KYCTierBasic,
+					TransactionType: constants.TransactionTypeTopup,
+					Max:             2000000,
+					Current:         3000000,
+				}, "failed to check limits"))
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -138,6 +218,50 @@ func TestHandler_CreateTransaction(t *testing.T) {
 	}
 }
 
+func TestHandler_CreateTransaction_device(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockSvc := NewMockService(ctrlMock)
+	mockMdw := NewMockMiddleware(ctrlMock)
+	mockMdw.EXPECT().MiddlewareRateLimit(gomock.Any()).Return(func(c *gin.Context) { c.Next() }).AnyTimes()
+	mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+		c.Set("token", models.TokenData{UserID: 1})
+		c.Next()
+	}).AnyTimes()
+
+	h := &Handler{
+		Engine:     gin.New(),
+		Service:    mockSvc,
+		Middleware: mockMdw,
+	}
+	h.RegisterRoute()
+
+	newRequest := func(deviceID string) *http.Request {
+		body := `{"amount":200000,"transaction_type":"PURCHASE","description":"DESC","device_id":"SPOOFED","client_ip":"1.1.1.1"}`
+		req, err := http.NewRequest(http.MethodPost, "/transaction/v1/create", strings.NewReader(body))
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "authorization")
+		req.Header.Set(constants.DeviceIDHeader, deviceID)
+		req.RemoteAddr = "10.0.0.1:52000"
+		return req
+	}
+
+	// the device and the address come from the request, never from the body
+	mockSvc.EXPECT().CreateTransaction(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, trx *models.Transaction) {
+		assert.Equal(t, "DEVICE", trx.DeviceID)
+		assert.Equal(t, "10.0.0.1", trx.ClientIP)
+	}).Return(models.CreateTransactionResponse{Reference: "REFERENCE"}, nil)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newRequest("DEVICE"))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newRequest(strings.Repeat("x", models.MaxDeviceIDLength+1)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestHandler_UpdateStatusTransaction(t *testing.T) {
 	ctrlMock := gomock.NewController(t)
 	defer ctrlMock.Finish()
@@ -145,6 +269,7 @@ func TestHandler_UpdateStatusTransaction(t *testing.T) {
 	mockSvc := NewMockService(ctrlMock)
 	mockExt := NewMockExternal(ctrlMock)
 	mockMdw := NewMockMiddleware(ctrlMock)
+	mockMdw.EXPECT().MiddlewareRateLimit(gomock.Any()).Return(func(c *gin.Context) { c.Next() }).AnyTimes()
 
 	tokenData := models.TokenData{
 		UserID:   1,
@@ -203,6 +328,91 @@ func TestHandler_UpdateStatusTransaction(t *testing.T) {
 				mockSvc.EXPECT().UpdateStatusTransaction(gomock.Any(), tokenData, &req).Return(assert.AnError)
 			},
 		},
+		{
+			name:               "error invalid additional info",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody: helpers.Response{
+				Message: constants.ErrFailedBadRequest,
+				Data: []interface{}{
+					map[string]interface{}{"field": "additional_info", "message": "must be a json object"},
+				},
+			},
+			mockFn: func() {
+				mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+					c.Set("token", tokenData)
+					c.Next()
+				})
+
+				mockSvc.EXPECT().UpdateStatusTransaction(gomock.Any(), tokenData, &req).Return(errors.Wrap(&models.AdditionalInfoError{
+					Fields: []models.FieldError{{Field: "additional_info", Message: "must be a json object"}},
+				}, "failed to validate additional info"))
+			},
+		},
+		{
+			name:               "error held by the fraud screening",
+			expectedStatusCode: http.StatusAccepted,
+			expectedBody: helpers.Response{
+				Message: constants.ErrTransactionOnHold,
+				Data: map[string]interface{}{
+					"reference":          req.Reference,
+					"transaction_status": constants.TransactionStatusOnHold,
+				},
+			},
+			mockFn: func() {
+				mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+					c.Set("token", tokenData)
+					c.Next()
+				})
+
+				mockSvc.EXPECT().UpdateStatusTransaction(gomock.Any(), tokenData, &req).Return(errors.Wrap(models.ErrTransactionOnHold, "NEW_DEVICE: device DEVICE is new to the user"))
+			},
+		},
+		{
+			name:               "error denied by the fraud screening",
+			expectedStatusCode: http.StatusForbidden,
+			expectedBody: helpers.Response{
+				Message: constants.ErrTransactionDenied,
+			},
+			mockFn: func() {
+				mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+					c.Set("token", tokenData)
+					c.Next()
+				})
+
+				mockSvc.EXPECT().UpdateStatusTransaction(gomock.Any(), tokenData, &req).Return(errors.Wrap(models.ErrTransactionDenied, "VELOCITY: 10 transactions in the last 10m0s"))
+			},
+		},
+		{
+			name:               "error status changed by another update",
+			expectedStatusCode: http.StatusConflict,
+			expectedBody: helpers.Response{
+				Message: constants.ErrStatusConflict,
+			},
+			mockFn: func() {
+				mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+					c.Set("token", tokenData)
+					c.Next()
+				})
+
+				mockSvc.EXPECT().UpdateStatusTransaction(gomock.Any(), tokenData, &req).Return(errors.Wrap(models.ErrStatusConflict, "failed to update status transaction"))
+			},
+		},
+		{
+			name:               "error limit exceeded",
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			wantErr:            true,
+			mockFn: func() {
+				mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+					c.Set("token", tokenData)
+					c.Next()
+				})
+
+				mockSvc.EXPECT().UpdateStatusTransaction(gomock.Any(), tokenData, &req).Return(errors.Wrap(&models.LimitExceededError{
+					Code:  constants.LimitExceededCode,
+					Limit: constants.LimitDailyCount,
+				}, "failed to check limits"))
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -251,6 +461,7 @@ func TestHandler_GetTransaction(t *testing.T) {
 	mockSvc := NewMockService(ctrlMock)
 	mockExt := NewMockExternal(ctrlMock)
 	mockMdw := NewMockMiddleware(ctrlMock)
+	mockMdw.EXPECT().MiddlewareRateLimit(gomock.Any()).Return(func(c *gin.Context) { c.Next() }).AnyTimes()
 
 	now := time.Now()
 
@@ -265,6 +476,7 @@ func TestHandler_GetTransaction(t *testing.T) {
 	tests := []struct {
 		name               string
 		expectedStatusCode int
+		query              string
 		expectedBody       helpers.Response
 		wantErr            bool
 		mockFn             func()
@@ -310,7 +522,7 @@ func TestHandler_GetTransaction(t *testing.T) {
 					c.Next()
 				})
 
-				mockSvc.EXPECT().GetTransaction(gomock.Any(), tokenData.UserID).Return([]models.Transaction{
+				mockSvc.EXPECT().GetTransaction(gomock.Any(), tokenData.UserID, models.TransactionFilter{}).Return([]models.Transaction{
 					{
 						ID:                1,
 						UserID:            1,
@@ -353,7 +565,71 @@ func TestHandler_GetTransaction(t *testing.T) {
 					c.Next()
 				})
 
-				mockSvc.EXPECT().GetTransaction(gomock.Any(), tokenData.UserID).Return(nil, assert.AnError)
+				mockSvc.EXPECT().GetTransaction(gomock.Any(), tokenData.UserID, models.TransactionFilter{}).Return(nil, assert.AnError)
+			},
+		},
+		{
+			name:               "success with filter",
+			expectedStatusCode: http.StatusOK,
+			query:              "?category=food_and_drink&merchant_id=9&tag=Trip",
+			expectedBody: helpers.Response{
+				Message: constants.SuccessMessage,
+				Data:    []interface{}{},
+			},
+			wantErr: false,
+			mockFn: func() {
+				mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+					c.Set("token", tokenData)
+					c.Next()
+				})
+
+				mockSvc.EXPECT().GetTransaction(gomock.Any(), tokenData.UserID, models.TransactionFilter{
+					Category:   "FOOD_AND_DRINK",
+					MerchantID: 9,
+					Tag:        "trip",
+				}).Return([]models.Transaction{}, nil)
+			},
+		},
+		{
+			name:               "error invalid merchant id",
+			expectedStatusCode: http.StatusBadRequest,
+			query:              "?merchant_id=abc",
+			wantErr:            true,
+			mockFn: func() {
+				mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+					c.Set("token", tokenData)
+					c.Next()
+				})
+			},
+		},
+		{
+			name:               "error request canceled",
+			expectedStatusCode: helpers.StatusClientClosedRequest,
+			wantErr:            true,
+			mockFn: func() {
+				mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+					tokenData := tokenData
+
+					c.Set("token", tokenData)
+					c.Next()
+				})
+
+				mockSvc.EXPECT().GetTransaction(gomock.Any(), tokenData.UserID, models.TransactionFilter{}).Return(nil, errors.Wrap(helpers.ErrQueryCanceled, "failed to get transaction"))
+			},
+		},
+		{
+			name:               "error query timeout",
+			expectedStatusCode: http.StatusGatewayTimeout,
+			wantErr:            true,
+			mockFn: func() {
+				mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+					tokenData := tokenData
+
+					c.Set("token", tokenData)
+					c.Next()
+				})
+
+				mockSvc.EXPECT().GetTransaction(gomock.Any(), tokenData.UserID, models.TransactionFilter{}).Return(nil, errors.Wrap(helpers.ErrQueryTimeout, "failed to get transaction"))
 			},
 		},
 	}
@@ -370,7 +646,7 @@ func TestHandler_GetTransaction(t *testing.T) {
 			}
 			h.RegisterRoute()
 			w := httptest.NewRecorder()
-			endpoint := "/transaction/v1/"
+			endpoint := "/transaction/v1/" + tt.query
 
 			req, err := http.NewRequest(http.MethodGet, endpoint, nil)
 			assert.NoError(t, err)
@@ -400,6 +676,7 @@ func TestHandler_GetTransactionDetail(t *testing.T) {
 	mockSvc := NewMockService(ctrlMock)
 	mockExt := NewMockExternal(ctrlMock)
 	mockMdw := NewMockMiddleware(ctrlMock)
+	mockMdw.EXPECT().MiddlewareRateLimit(gomock.Any()).Return(func(c *gin.Context) { c.Next() }).AnyTimes()
 
 	now := time.Now()
 
@@ -522,6 +799,7 @@ func TestHandler_RefundTransaction(t *testing.T) {
 	mockSvc := NewMockService(ctrlMock)
 	mockExt := NewMockExternal(ctrlMock)
 	mockMdw := NewMockMiddleware(ctrlMock)
+	mockMdw.EXPECT().MiddlewareRateLimit(gomock.Any()).Return(func(c *gin.Context) { c.Next() }).AnyTimes()
 
 	tokenData := models.TokenData{
 		UserID:   1,
@@ -626,3 +904,103 @@ func TestHandler_RefundTransaction(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_CreateTransactionTags(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockSvc := NewMockService(ctrlMock)
+	mockMdw := NewMockMiddleware(ctrlMock)
+	mockMdw.EXPECT().MiddlewareRateLimit(gomock.Any()).Return(func(c *gin.Context) { c.Next() }).AnyTimes()
+
+	h := &Handler{
+		Engine:     gin.New(),
+		Service:    mockSvc,
+		Middleware: mockMdw,
+	}
+	h.RegisterRoute()
+
+	mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+		c.Set("token", models.TokenData{UserID: 1})
+		c.Next()
+	}).Times(2)
+
+	mockSvc.EXPECT().CreateTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, req *models.Transaction) (models.CreateTransactionResponse, error) {
+		assert.Equal(t, []string{"trip", "bali-2025"}, req.Tags)
+		assert.Equal(t, "Kopi Kenangan", req.MerchantName)
+		assert.Equal(t, "food_and_drink", req.Category, "the service validates the category")
+		return models.CreateTransactionResponse{Reference: "REFERENCE"}, nil
+	})
+
+	tests := []struct {
+		name               string
+		body               string
+		expectedStatusCode int
+	}{
+		{
+			name:               "success",
+			body:               `{"amount":50000,"transaction_type":"PURCHASE","description":"DESC","merchant_id":9,"merchant_name":"Kopi Kenangan","category":"food_and_drink","tags":[" Trip","bali-2025","trip"]}`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "error invalid tag",
+			body:               `{"amount":50000,"transaction_type":"PURCHASE","description":"DESC","tags":["not a tag"]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, err := http.NewRequest(http.MethodPost, "/transaction/v1/create", bytes.NewBufferString(tt.body))
+			assert.NoError(t, err)
+
+			h.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+		})
+	}
+}
+
+func TestHandler_ListCategories(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockSvc := NewMockService(ctrlMock)
+	mockMdw := NewMockMiddleware(ctrlMock)
+	mockMdw.EXPECT().MiddlewareRateLimit(gomock.Any()).Return(func(c *gin.Context) { c.Next() }).AnyTimes()
+
+	h := &Handler{
+		Engine:     gin.New(),
+		Service:    mockSvc,
+		Middleware: mockMdw,
+	}
+	h.RegisterRoute()
+
+	mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+		c.Set("token", models.TokenData{UserID: 1})
+		c.Next()
+	}).Times(2)
+
+	mockSvc.EXPECT().ListCategories(gomock.Any()).Return([]models.TransactionCategory{
+		{Code: "FOOD_AND_DRINK", Name: "Makanan & Minuman"},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodGet, "/transaction/v1/categories", nil)
+	assert.NoError(t, err)
+	h.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	response := helpers.Response{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"code": "FOOD_AND_DRINK", "name": "Makanan & Minuman"},
+	}, response.Data)
+
+	mockSvc.EXPECT().ListCategories(gomock.Any()).Return(nil, assert.AnError)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
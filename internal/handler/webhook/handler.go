@@ -0,0 +1,38 @@
+package webhook
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:generate mockgen -source=handler.go -destination=handler_mock_test.go -package=webhook
+type Service interface {
+	RegisterWebhook(ctx context.Context, merchantID uint64, req *models.RegisterWebhook) (models.RegisterWebhookResponse, error)
+	GetDeliveries(ctx context.Context, merchantID uint64, reference string) ([]models.WebhookDelivery, error)
+	Redeliver(ctx context.Context, merchantID uint64, deliveryID int) (models.WebhookDelivery, error)
+}
+
+type Handler struct {
+	*gin.Engine
+	Service    Service
+	Middleware Middleware
+}
+
+func NewHandler(api *gin.Engine, service Service, mdw Middleware) *Handler {
+	return &Handler{
+		api,
+		service,
+		mdw,
+	}
+}
+
+func (h *Handler) RegisterRoute() {
+	webhookV1 := h.Group("/webhook/v1", h.Middleware.MiddlewareValidateToken, h.Middleware.MiddlewareValidateMerchant,
+		h.Middleware.MiddlewareRateLimit(constants.RateLimitGroupWebhook))
+	webhookV1.POST("/register", h.RegisterWebhook)
+	webhookV1.GET("/deliveries/:reference", h.GetDeliveries)
+	webhookV1.POST("/redeliver/:id", h.Redeliver)
+}
@@ -0,0 +1,10 @@
+package webhook
+
+import "github.com/gin-gonic/gin"
+
+//go:generate mockgen -source=middleware.go -destination=middleware_mock_test.go -package=webhook
+type Middleware interface {
+	MiddlewareValidateToken(c *gin.Context)
+	MiddlewareValidateMerchant(c *gin.Context)
+	MiddlewareRateLimit(group string) gin.HandlerFunc
+}
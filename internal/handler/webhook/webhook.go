@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) RegisterWebhook(c *gin.Context) {
+	var (
+		req models.RegisterWebhook
+	)
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		fmt.Println("failed to parse request, ", err)
+		helpers.SendResponseHTTP(c, http.StatusBadRequest, constants.ErrFailedBadRequest, nil)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		fmt.Println("failed to validate request, ", err)
+		helpers.SendResponseHTTP(c, http.StatusBadRequest, constants.ErrFailedBadRequest, nil)
+		return
+	}
+
+	tokenData, ok := getTokenData(c)
+	if !ok {
+		helpers.SendResponseHTTP(c, http.StatusInternalServerError, constants.ErrServerError, nil)
+		return
+	}
+
+	resp, err := h.Service.RegisterWebhook(c.Request.Context(), tokenData.UserID, &req)
+	if err != nil {
+		fmt.Println("failed to register webhook, ", err)
+		helpers.SendResponseHTTP(c, http.StatusBadRequest, constants.ErrFailedBadRequest, nil)
+		return
+	}
+
+	helpers.SendResponseHTTP(c, http.StatusOK, constants.SuccessMessage, resp)
+}
+
+func (h *Handler) GetDeliveries(c *gin.Context) {
+	reference := c.Param("reference")
+	if reference == "" {
+		fmt.Println("failed to get reference")
+		helpers.SendResponseHTTP(c, http.StatusBadRequest, constants.ErrFailedBadRequest, nil)
+		return
+	}
+
+	tokenData, ok := getTokenData(c)
+	if !ok {
+		helpers.SendResponseHTTP(c, http.StatusInternalServerError, constants.ErrServerError, nil)
+		return
+	}
+
+	resp, err := h.Service.GetDeliveries(c.Request.Context(), tokenData.UserID, reference)
+	if err != nil {
+		fmt.Println("failed to get webhook deliveries, ", err)
+		helpers.SendErrorResponseHTTP(c, err)
+		return
+	}
+
+	helpers.SendResponseHTTP(c, http.StatusOK, constants.SuccessMessage, resp)
+}
+
+func (h *Handler) Redeliver(c *gin.Context) {
+	deliveryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		fmt.Println("failed to parse delivery id, ", err)
+		helpers.SendResponseHTTP(c, http.StatusBadRequest, constants.ErrFailedBadRequest, nil)
+		return
+	}
+
+	tokenData, ok := getTokenData(c)
+	if !ok {
+		helpers.SendResponseHTTP(c, http.StatusInternalServerError, constants.ErrServerError, nil)
+		return
+	}
+
+	resp, err := h.Service.Redeliver(c.Request.Context(), tokenData.UserID, deliveryID)
+	if err != nil {
+		fmt.Println("failed to redeliver webhook, ", err)
+		helpers.SendErrorResponseHTTP(c, err)
+		return
+	}
+
+	helpers.SendResponseHTTP(c, http.StatusOK, constants.SuccessMessage, resp)
+}
+
+func getTokenData(c *gin.Context) (models.TokenData, bool) {
+	token, ok := c.Get("token")
+	if !ok {
+		fmt.Println("failed to get token data")
+		return models.TokenData{}, false
+	}
+
+	tokenData, ok := token.(models.TokenData)
+	if !ok {
+		fmt.Println("failed to parse token data")
+		return models.TokenData{}, false
+	}
+
+	return tokenData, true
+}
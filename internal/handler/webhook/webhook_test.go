@@ -0,0 +1,259 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+)
+
+var tokenData = models.TokenData{
+	UserID:   9,
+	Username: "MERCHANT",
+	Fullname: "MERCHANT",
+	Token:    "TOKEN",
+	Email:    "EMAIL",
+}
+
+func TestHandler_RegisterWebhook(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockSvc := NewMockService(ctrlMock)
+	mockMdw := NewMockMiddleware(ctrlMock)
+	mockMdw.EXPECT().MiddlewareRateLimit(gomock.Any()).Return(func(c *gin.Context) { c.Next() }).AnyTimes()
+	mockMdw.EXPECT().MiddlewareValidateMerchant(gomock.Any()).Do(func(c *gin.Context) { c.Next() }).AnyTimes()
+
+	reqBody := models.RegisterWebhook{
+		URL: "https://merchant.test/callback",
+	}
+
+	tests := []struct {
+		name               string
+		expectedStatusCode int
+		expectedBody       helpers.Response
+		wantErr            bool
+		mockFn             func()
+	}{
+		{
+			name:               "success",
+			expectedStatusCode: http.StatusOK,
+			expectedBody: helpers.Response{
+				Message: constants.SuccessMessage,
+				Data: map[string]interface{}{
+					"merchant_id": float64(9),
+					"url":         "https://merchant.test/callback",
+					"secret":      "SECRET",
+				},
+			},
+			wantErr: false,
+			mockFn: func() {
+				mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+					c.Set("token", tokenData)
+					c.Next()
+				})
+
+				mockSvc.EXPECT().RegisterWebhook(gomock.Any(), tokenData.UserID, &reqBody).Return(models.RegisterWebhookResponse{
+					MerchantID: 9,
+					URL:        "https://merchant.test/callback",
+					Secret:     "SECRET",
+				}, nil)
+			},
+		},
+		{
+			name:               "error",
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+			mockFn: func() {
+				mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+					c.Set("token", tokenData)
+					c.Next()
+				})
+
+				mockSvc.EXPECT().RegisterWebhook(gomock.Any(), tokenData.UserID, &reqBody).Return(models.RegisterWebhookResponse{}, assert.AnError)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			api := gin.New()
+			h := &Handler{
+				Engine:     api,
+				Service:    mockSvc,
+				Middleware: mockMdw,
+			}
+			h.RegisterRoute()
+			w := httptest.NewRecorder()
+
+			val, err := json.Marshal(reqBody)
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodPost, "/webhook/v1/register", bytes.NewReader(val))
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", "authorization")
+
+			h.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if !tt.wantErr {
+				response := helpers.Response{}
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+
+				assert.Equal(t, tt.expectedBody, response)
+			}
+		})
+	}
+}
+
+func TestHandler_GetDeliveries(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockSvc := NewMockService(ctrlMock)
+	mockMdw := NewMockMiddleware(ctrlMock)
+	mockMdw.EXPECT().MiddlewareRateLimit(gomock.Any()).Return(func(c *gin.Context) { c.Next() }).AnyTimes()
+	mockMdw.EXPECT().MiddlewareValidateMerchant(gomock.Any()).Do(func(c *gin.Context) { c.Next() }).AnyTimes()
+
+	tests := []struct {
+		name               string
+		expectedStatusCode int
+		mockFn             func()
+	}{
+		{
+			name:               "success",
+			expectedStatusCode: http.StatusOK,
+			mockFn: func() {
+				mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+					c.Set("token", tokenData)
+					c.Next()
+				})
+
+				mockSvc.EXPECT().GetDeliveries(gomock.Any(), tokenData.UserID, "REFERENCE").Return([]models.WebhookDelivery{
+					{ID: 1, MerchantID: 9, Reference: "REFERENCE", Status: constants.WebhookDeliveryStatusSuccess},
+				}, nil)
+			},
+		},
+		{
+			name:               "error",
+			expectedStatusCode: http.StatusInternalServerError,
+			mockFn: func() {
+				mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+					c.Set("token", tokenData)
+					c.Next()
+				})
+
+				mockSvc.EXPECT().GetDeliveries(gomock.Any(), tokenData.UserID, "REFERENCE").Return(nil, assert.AnError)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			api := gin.New()
+			h := &Handler{
+				Engine:     api,
+				Service:    mockSvc,
+				Middleware: mockMdw,
+			}
+			h.RegisterRoute()
+			w := httptest.NewRecorder()
+
+			req, err := http.NewRequest(http.MethodGet, "/webhook/v1/deliveries/REFERENCE", nil)
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", "authorization")
+
+			h.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+		})
+	}
+}
+
+func TestHandler_Redeliver(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockSvc := NewMockService(ctrlMock)
+	mockMdw := NewMockMiddleware(ctrlMock)
+	mockMdw.EXPECT().MiddlewareRateLimit(gomock.Any()).Return(func(c *gin.Context) { c.Next() }).AnyTimes()
+	mockMdw.EXPECT().MiddlewareValidateMerchant(gomock.Any()).Do(func(c *gin.Context) { c.Next() }).AnyTimes()
+
+	tests := []struct {
+		name               string
+		endpoint           string
+		expectedStatusCode int
+		mockFn             func()
+	}{
+		{
+			name:               "success",
+			endpoint:           "/webhook/v1/redeliver/1",
+			expectedStatusCode: http.StatusOK,
+			mockFn: func() {
+				mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+					c.Set("token", tokenData)
+					c.Next()
+				})
+
+				mockSvc.EXPECT().Redeliver(gomock.Any(), tokenData.UserID, 1).Return(models.WebhookDelivery{ID: 2}, nil)
+			},
+		},
+		{
+			name:               "error invalid id",
+			endpoint:           "/webhook/v1/redeliver/abc",
+			expectedStatusCode: http.StatusBadRequest,
+			mockFn: func() {
+				mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+					c.Set("token", tokenData)
+					c.Next()
+				})
+			},
+		},
+		{
+			name:               "error",
+			endpoint:           "/webhook/v1/redeliver/1",
+			expectedStatusCode: http.StatusInternalServerError,
+			mockFn: func() {
+				mockMdw.EXPECT().MiddlewareValidateToken(gomock.Any()).Do(func(c *gin.Context) {
+					c.Set("token", tokenData)
+					c.Next()
+				})
+
+				mockSvc.EXPECT().Redeliver(gomock.Any(), tokenData.UserID, 1).Return(models.WebhookDelivery{}, assert.AnError)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			api := gin.New()
+			h := &Handler{
+				Engine:     api,
+				Service:    mockSvc,
+				Middleware: mockMdw,
+			}
+			h.RegisterRoute()
+			w := httptest.NewRecorder()
+
+			req, err := http.NewRequest(http.MethodPost, tt.endpoint, nil)
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", "authorization")
+
+			h.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package interfaces
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IAuditRepo interface {
+	CreateAuditLog(ctx context.Context, log *models.AdminAuditLog) error
+}
+
+type IAdminService interface {
+	ListTransactions(ctx context.Context, admin models.TokenData, req models.SearchRequest) (models.SearchResponse, error)
+	SearchTransactions(ctx context.Context, admin models.TokenData, req models.SearchRequest) (models.SearchResponse, error)
+	ForceStatus(ctx context.Context, admin models.TokenData, reference string, req models.AdminStatusRequest) error
+	Refund(ctx context.Context, admin models.TokenData, reference string, req models.AdminRefundRequest) (models.CreateTransactionResponse, error)
+	StatusHistory(ctx context.Context, admin models.TokenData, reference string) ([]models.TransactionStatusHistory, error)
+	Approve(ctx context.Context, admin models.TokenData, reference string, req models.AdminReviewRequest) error
+	Reject(ctx context.Context, admin models.TokenData, reference string, req models.AdminReviewRequest) error
+	ListLimits(ctx context.Context, admin models.TokenData) ([]models.TransactionLimit, error)
+	SetLimit(ctx context.Context, admin models.TokenData, req models.AdminLimitRequest) (models.TransactionLimit, error)
+	GetUserKYCTier(ctx context.Context, admin models.TokenData, userID uint64) (models.UserKYCTier, error)
+	SetUserKYCTier(ctx context.Context, admin models.TokenData, userID uint64, req models.AdminKYCTierRequest) (models.UserKYCTier, error)
+}
+
+type IAdminAPI interface {
+	ListTransactions(c *gin.Context)
+	SearchTransactions(c *gin.Context)
+	ForceStatus(c *gin.Context)
+	Refund(c *gin.Context)
+	StatusHistory(c *gin.Context)
+	Approve(c *gin.Context)
+	Reject(c *gin.Context)
+	ListLimits(c *gin.Context)
+	SetLimit(c *gin.Context)
+	GetUserKYCTier(c *gin.Context)
+	SetUserKYCTier(c *gin.Context)
+}
@@ -0,0 +1,11 @@
+package interfaces
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+)
+
+type IEventPublisher interface {
+	Publish(ctx context.Context, event models.TransactionEvent) error
+	Close() error
+}
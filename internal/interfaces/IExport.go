@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+	"io"
+)
+
+type IExportService interface {
+	Validate(req models.ExportRequest) error
+	Export(ctx context.Context, req models.ExportRequest, w io.Writer) error
+}
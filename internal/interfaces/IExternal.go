@@ -11,4 +11,5 @@ type IExternal interface {
 	CreditBalance(ctx context.Context, token string, req external.UpdateBalance) (*external.UpdateBalanceResponse, error)
 	DebitBalance(ctx context.Context, token string, req external.UpdateBalance) (*external.UpdateBalanceResponse, error)
 	SendNotification(ctx context.Context, recipient string, templateName string, placeHolder map[string]string) error
+	GetWalletMovements(ctx context.Context, reference string) ([]external.WalletMovement, error)
 }
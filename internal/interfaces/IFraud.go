@@ -0,0 +1,10 @@
+package interfaces
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+)
+
+type IFraudService interface {
+	Screen(ctx context.Context, trx models.Transaction) (models.FraudScreening, error)
+}
@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+)
+
+type ILedgerRepo interface {
+	GetOrCreateAccount(ctx context.Context, account *models.LedgerAccount) error
+	CreateJournalEntry(ctx context.Context, entry *models.JournalEntry) error
+	GetJournalEntry(ctx context.Context, reference string, entryType string) (models.JournalEntry, error)
+	GetUnbalancedEntries(ctx context.Context) ([]int, error)
+}
+
+type ILedgerService interface {
+	Post(ctx context.Context, entry *models.JournalEntry) error
+	PostSuccess(ctx context.Context, trx models.Transaction) error
+	PostReversal(ctx context.Context, trx models.Transaction) error
+	PostRefund(ctx context.Context, refund models.Transaction, originalReference string) error
+	CheckInvariants(ctx context.Context) error
+}
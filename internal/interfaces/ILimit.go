@@ -0,0 +1,22 @@
+package interfaces
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+)
+
+type ILimitRepo interface {
+	GetLimit(ctx context.Context, kycTier string, transactionType string) (models.TransactionLimit, error)
+	ListLimits(ctx context.Context) ([]models.TransactionLimit, error)
+	UpsertLimit(ctx context.Context, limit *models.TransactionLimit) error
+	GetUserKYCTier(ctx context.Context, userID uint64) (models.UserKYCTier, error)
+	UpsertUserKYCTier(ctx context.Context, tier *models.UserKYCTier) error
+}
+
+type ILimitService interface {
+	Check(ctx context.Context, trx models.Transaction, status string) error
+	ListLimits(ctx context.Context) ([]models.TransactionLimit, error)
+	SetLimit(ctx context.Context, limit models.TransactionLimit) (models.TransactionLimit, error)
+	GetUserKYCTier(ctx context.Context, userID uint64) (models.UserKYCTier, error)
+	SetUserKYCTier(ctx context.Context, userID uint64, kycTier string) (models.UserKYCTier, error)
+}
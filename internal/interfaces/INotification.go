@@ -0,0 +1,23 @@
+package interfaces
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+)
+
+type IContactRepo interface {
+	UpsertContact(ctx context.Context, contact *models.UserContact) error
+	GetContact(ctx context.Context, userID uint64) (models.UserContact, error)
+}
+
+type INotificationService interface {
+	Notify(ctx context.Context, event string, userID uint64, recipient string, placeHolder map[string]string) error
+	RecordContact(ctx context.Context, tokenData models.TokenData) error
+	Start()
+	Stop(ctx context.Context) error
+}
+
+// IContactRecorder keeps the email of the validated tokens for the notifications sent without them.
+type IContactRecorder interface {
+	RecordContact(ctx context.Context, tokenData models.TokenData) error
+}
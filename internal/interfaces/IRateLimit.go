@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+)
+
+// IRateLimitStore keeps the token buckets of the rate limiter. The memory store only limits
+// a single instance, a store shared by every instance (e.g. redis) is plugged in behind this interface.
+type IRateLimitStore interface {
+	Take(ctx context.Context, key string, limit models.RateLimit) (models.RateLimitResult, error)
+}
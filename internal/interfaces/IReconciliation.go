@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+	"time"
+)
+
+type IReconciliationService interface {
+	Reconcile(ctx context.Context, from time.Time, to time.Time, repair bool) (models.ReconciliationReport, error)
+	Start()
+	Stop(ctx context.Context) error
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+)
+
+type ISearchService interface {
+	Search(ctx context.Context, req models.SearchRequest) (models.SearchResponse, error)
+}
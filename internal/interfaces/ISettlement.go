@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+	"io"
+)
+
+type ISettlementService interface {
+	Import(ctx context.Context, provider string, r io.Reader) (models.SettlementReport, error)
+	Providers() []string
+}
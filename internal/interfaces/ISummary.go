@@ -0,0 +1,10 @@
+package interfaces
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+)
+
+type ISummaryService interface {
+	GetSummary(ctx context.Context, userID uint64, req models.SummaryRequest) (models.TransactionSummary, error)
+}
@@ -3,6 +3,7 @@ package interfaces
 import (
 	"context"
 	"ewallet-transaction/internal/models"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -10,16 +11,34 @@ import (
 type ITransactionRepo interface {
 	CreateTransaction(ctx context.Context, trx *models.Transaction) error
 	GetTransactionByReference(context.Context, string, bool) (models.Transaction, error)
-	UpdateStatusTransaction(ctx context.Context, reference string, status string, additionalInfo string) error
-	GetTransaction(ctx context.Context, userID uint64) ([]models.Transaction, error)
+	GetTransactionDetail(ctx context.Context, reference string) (models.Transaction, error)
+	UpdateStatusTransaction(ctx context.Context, reference string, currentStatus string, status string, additionalInfo string) error
+	LockUserLimits(ctx context.Context, userID uint64, fn func(ctx context.Context) error) error
+	GetTransaction(ctx context.Context, userID uint64, filter models.TransactionFilter) ([]models.Transaction, error)
+	GetTransactionsByTimeRange(ctx context.Context, from time.Time, to time.Time) ([]models.Transaction, error)
+	GetPendingTransactions(ctx context.Context, before time.Time, limit int) ([]models.Transaction, error)
+	GetLimitUsage(ctx context.Context, userID uint64, transactionType string, statuses []string, dayStart time.Time, monthStart time.Time) (models.LimitUsage, error)
+	GetFraudHistory(ctx context.Context, trx models.Transaction, query models.FraudHistoryQuery) (models.FraudHistory, error)
+	StreamTransactions(ctx context.Context, userID uint64, from time.Time, to time.Time, fn func(models.Transaction) error) error
+	GetBalanceBefore(ctx context.Context, userID uint64, before time.Time) (float64, error)
+	GetSummaryBuckets(ctx context.Context, userID uint64, bounds []time.Time) ([]models.SummaryBucket, error)
+	GetSummaryGroups(ctx context.Context, userID uint64, from time.Time, to time.Time, groupBy string) ([]models.SummaryGroup, error)
+	ListCategories(ctx context.Context) ([]models.TransactionCategory, error)
+	GetCategory(ctx context.Context, code string) (models.TransactionCategory, error)
+	SearchTransactions(ctx context.Context, req models.SearchRequest) ([]models.SearchResult, int64, error)
+	CreateStatusHistory(ctx context.Context, history *models.TransactionStatusHistory) error
+	GetStatusHistory(ctx context.Context, reference string) ([]models.TransactionStatusHistory, error)
 }
 
 type ITransactionService interface {
 	CreateTransaction(ctx context.Context, req *models.Transaction) (models.CreateTransactionResponse, error)
 	UpdateStatusTransaction(ctx context.Context, tokenData models.TokenData, req *models.UpdateStatusTransaction) error
+	SyncStatus(ctx context.Context, trx models.Transaction, status string, actor string, reason string) error
 	GetTransactionDetail(ctx context.Context, reference string) (models.Transaction, error)
-	GetTransaction(ctx context.Context, userID uint64) ([]models.Transaction, error)
+	GetTransaction(ctx context.Context, userID uint64, filter models.TransactionFilter) ([]models.Transaction, error)
 	RefundTransaction(ctx context.Context, tokenData models.TokenData, req *models.RefundTransaction) (models.CreateTransactionResponse, error)
+	ListCategories(ctx context.Context) ([]models.TransactionCategory, error)
+	ExpirePending(ctx context.Context, before time.Time, limit int) (models.ExpirePendingReport, error)
 }
 
 type ITransactionAPI interface {
@@ -28,4 +47,5 @@ type ITransactionAPI interface {
 	GetTransaction(c *gin.Context)
 	GetTransactionDetail(c *gin.Context)
 	RefundTransaction(c *gin.Context)
+	ListCategories(c *gin.Context)
 }
@@ -0,0 +1,35 @@
+package interfaces
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IWebhookRepo interface {
+	UpsertWebhook(ctx context.Context, webhook *models.MerchantWebhook) error
+	GetWebhookByMerchantID(ctx context.Context, merchantID uint64) (models.MerchantWebhook, error)
+	CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	GetDeliveryByID(ctx context.Context, id int) (models.WebhookDelivery, error)
+	GetDeliveriesByReference(ctx context.Context, merchantID uint64, reference string) ([]models.WebhookDelivery, error)
+	GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error)
+	ClaimDelivery(ctx context.Context, id int, now time.Time, leaseUntil time.Time) (bool, error)
+	UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+}
+
+type IWebhookService interface {
+	RegisterWebhook(ctx context.Context, merchantID uint64, req *models.RegisterWebhook) (models.RegisterWebhookResponse, error)
+	GetDeliveries(ctx context.Context, merchantID uint64, reference string) ([]models.WebhookDelivery, error)
+	Redeliver(ctx context.Context, merchantID uint64, deliveryID int) (models.WebhookDelivery, error)
+	DispatchStatusChanged(ctx context.Context, trx models.Transaction, previousStatus string) error
+	Start()
+	Stop(ctx context.Context) error
+}
+
+type IWebhookAPI interface {
+	RegisterWebhook(c *gin.Context)
+	GetDeliveries(c *gin.Context)
+	Redeliver(c *gin.Context)
+}
@@ -0,0 +1,114 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"ewallet-transaction/constants"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	lockName = "ewallet_transaction_migration"
+	// lockKey is the postgres advisory lock key, advisory locks are keyed by a bigint instead of a name.
+	lockKey int64 = 7319246023
+)
+
+const lockPollInterval = time.Second
+
+// dialect holds the sql that differs between the supported databases.
+type dialect struct {
+	dir         string
+	createTable string
+	bind        func(query string) string
+	lock        func(ctx context.Context, conn *sql.Conn, timeout time.Duration) error
+	unlock      func(conn *sql.Conn)
+}
+
+var dialects = map[string]dialect{
+	constants.DBDriverMySQL: {
+		dir:         "mysql",
+		createTable: "CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at DATETIME(3) NOT NULL)",
+		bind:        func(query string) string { return query },
+		lock:        mysqlLock,
+		unlock: func(conn *sql.Conn) {
+			conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", lockName)
+		},
+	},
+	constants.DBDriverPostgres: {
+		dir:         "postgres",
+		createTable: "CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at TIMESTAMPTZ NOT NULL)",
+		bind:        postgresBind,
+		lock:        postgresLock,
+		unlock: func(conn *sql.Conn) {
+			conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey)
+		},
+	},
+	// sqlite is an embedded file used by a single process, the pool is limited to one connection
+	// so there is no concurrent migrator to lock out.
+	constants.DBDriverSQLite: {
+		dir:         "sqlite",
+		createTable: "CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER NOT NULL PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at DATETIME NOT NULL)",
+		bind:        func(query string) string { return query },
+		lock:        func(ctx context.Context, conn *sql.Conn, timeout time.Duration) error { return nil },
+		unlock:      func(conn *sql.Conn) {},
+	},
+}
+
+func mysqlLock(ctx context.Context, conn *sql.Conn, timeout time.Duration) error {
+	var locked sql.NullInt64
+	err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", lockName, int(timeout.Seconds())).Scan(&locked)
+	if err != nil {
+		return errors.Wrap(err, "failed to acquire migration lock")
+	}
+	if !locked.Valid || locked.Int64 != 1 {
+		return errors.New("timeout waiting for migration lock, another migration is running")
+	}
+
+	return nil
+}
+
+// postgresLock polls pg_try_advisory_lock since pg_advisory_lock has no timeout of its own.
+func postgresLock(ctx context.Context, conn *sql.Conn, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		var locked bool
+		err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", lockKey).Scan(&locked)
+		if err != nil {
+			return errors.Wrap(err, "failed to acquire migration lock")
+		}
+		if locked {
+			return nil
+		}
+		if time.Now().Add(lockPollInterval).After(deadline) {
+			return errors.New("timeout waiting for migration lock, another migration is running")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// postgresBind numbers the ? bind vars as postgres expects.
+func postgresBind(query string) string {
+	var (
+		b strings.Builder
+		n int
+	)
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString(fmt.Sprintf("$%d", n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,65 @@
+package migration
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// baselineTransaction is the transactions table the service auto migrated before the schema was versioned.
+type baselineTransaction struct {
+	ID                int
+	UserID            uint64
+	Amount            float64 `gorm:"column:amount;type:decimal(15,2)"`
+	TransactionType   string  `gorm:"column:transaction_type;type:varchar(20)"`
+	TransactionStatus string  `gorm:"column:transaction_status;type:varchar(20)"`
+	Reference         string  `gorm:"column:reference;type:varchar(255)"`
+	Description       string  `gorm:"column:description;type:varchar(255)"`
+	AdditionalInfo    string  `gorm:"column:additional_info;type:text"`
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+func (*baselineTransaction) TableName() string {
+	return "transactions"
+}
+
+func TestMigrator_Up_BaselineDatabase_E2E(t *testing.T) {
+	ctx := context.Background()
+	db, err := helpers.SetupDB(helpers.DBConfig{Driver: constants.DBDriverSQLite, Name: ":memory:"})
+	assert.NoError(t, err)
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	defer sqlDB.Close()
+
+	// a database created before the migrations keeps its transactions table, 000001 is skipped over it
+	assert.NoError(t, db.AutoMigrate(&baselineTransaction{}))
+	// the references of the same second could repeat before they were unique
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, db.Create(&baselineTransaction{UserID: 1, Amount: 1000, TransactionType: constants.TransactionTypeTopup,
+			TransactionStatus: constants.TransactionStatusSuccess, Reference: "BASELINE", Description: "DESCRIPTION"}).Error)
+	}
+
+	m, err := NewMigrator(sqlDB, constants.DBDriverSQLite)
+	assert.NoError(t, err)
+	_, err = m.Up(ctx)
+	assert.NoError(t, err)
+
+	for _, column := range []string{"merchant_id", "merchant_name", "category", "device_id"} {
+		assert.True(t, db.Migrator().HasColumn(&models.Transaction{}, column), column)
+	}
+	assert.True(t, db.Migrator().HasTable(&models.MerchantWebhook{}))
+
+	var trx models.Transaction
+	assert.NoError(t, db.Where("reference = ?", "BASELINE").Where("merchant_id IS NULL").First(&trx).Error)
+	assert.Equal(t, uint64(1), trx.UserID)
+
+	var references []string
+	assert.NoError(t, db.Model(&models.Transaction{}).Order("id").Pluck("reference", &references).Error)
+	assert.Equal(t, []string{"BASELINE", "BASELINE-DUP2"}, references)
+}
@@ -0,0 +1,243 @@
+package migration
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		want    []Migration
+		wantErr bool
+	}{
+		{
+			name: "success ordered by version",
+			fsys: fstest.MapFS{
+				"mysql/000002_add_index.up.sql":   {Data: []byte("CREATE INDEX a ON t (a);")},
+				"mysql/000002_add_index.down.sql": {Data: []byte("DROP INDEX a ON t;")},
+				"mysql/000001_init.up.sql":        {Data: []byte("CREATE TABLE t (a INT);")},
+				"mysql/000001_init.down.sql":      {Data: []byte("DROP TABLE t;")},
+				"mysql/README.md":                 {Data: []byte("ignored")},
+			},
+			want: []Migration{
+				{Version: 1, Name: "init", Up: "CREATE TABLE t (a INT);", Down: "DROP TABLE t;"},
+				{Version: 2, Name: "add_index", Up: "CREATE INDEX a ON t (a);", Down: "DROP INDEX a ON t;"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error missing down",
+			fsys: fstest.MapFS{
+				"mysql/000001_init.up.sql": {Data: []byte("CREATE TABLE t (a INT);")},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(tt.fsys, "mysql")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoad_Embedded(t *testing.T) {
+	for driver, d := range dialects {
+		t.Run(driver, func(t *testing.T) {
+			migrations, err := Load(migrationFS, d.dir)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, migrations)
+			assert.Equal(t, int64(1), migrations[0].Version)
+		})
+	}
+}
+
+func Test_splitStatements(t *testing.T) {
+	script := `-- comment
+CREATE TABLE a (
+  id INT
+);
+
+DROP TABLE b;
+SELECT 1`
+
+	assert.Equal(t, []string{
+		"CREATE TABLE a (\n  id INT\n)",
+		"DROP TABLE b",
+		"SELECT 1",
+	}, splitStatements(script))
+}
+
+var testMigrations = []Migration{
+	{Version: 1, Name: "init", Up: "CREATE TABLE t (a INT);", Down: "DROP TABLE t;"},
+	{Version: 2, Name: "add_index", Up: "CREATE INDEX a ON t (a);", Down: "DROP INDEX a ON t;"},
+}
+
+func expectLock(mock sqlmock.Sqlmock, locked int) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT GET_LOCK(?, ?)")).WithArgs(lockName, 60).
+		WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(locked))
+}
+
+func expectApplied(mock sqlmock.Sqlmock, versions ...int64) {
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS schema_migrations")).WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows([]string{"version", "applied_at"})
+	for _, version := range versions {
+		rows.AddRow(version, time.Now())
+	}
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT version, applied_at FROM schema_migrations")).WillReturnRows(rows)
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta("SELECT RELEASE_LOCK(?)")).WithArgs(lockName).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestMigrator_Up(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    []int64
+		wantErr bool
+		mockFn  func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:    "success apply pending",
+			want:    []int64{2},
+			wantErr: false,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				expectLock(mock, 1)
+				expectApplied(mock, 1)
+				mock.ExpectExec(regexp.QuoteMeta("CREATE INDEX a ON t (a)")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)")).
+					WithArgs(int64(2), "add_index", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectUnlock(mock)
+			},
+		},
+		{
+			name:    "success nothing pending",
+			wantErr: false,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				expectLock(mock, 1)
+				expectApplied(mock, 1, 2)
+				expectUnlock(mock)
+			},
+		},
+		{
+			name:    "error lock timeout",
+			wantErr: true,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				expectLock(mock, 0)
+			},
+		},
+		{
+			name:    "error apply migration",
+			wantErr: true,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				expectLock(mock, 1)
+				expectApplied(mock)
+				mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE t (a INT)")).WillReturnError(assert.AnError)
+				expectUnlock(mock)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.mockFn(mock)
+			m := &Migrator{db: db, dialect: dialects[constants.DBDriverMySQL], migrations: testMigrations, lockTimeout: time.Minute}
+
+			got, err := m.Up(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Migrator.Up() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			var versions []int64
+			for _, mig := range got {
+				versions = append(versions, mig.Version)
+			}
+			assert.Equal(t, tt.want, versions)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMigrator_Down(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	expectLock(mock, 1)
+	expectApplied(mock, 1, 2)
+	mock.ExpectExec(regexp.QuoteMeta("DROP INDEX a ON t")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM schema_migrations WHERE version = ?")).WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectUnlock(mock)
+
+	m := &Migrator{db: db, dialect: dialects[constants.DBDriverMySQL], migrations: testMigrations, lockTimeout: time.Minute}
+	got, err := m.Down(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, got, 1)
+	assert.Equal(t, int64(2), got[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Status(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	expectApplied(mock, 1)
+
+	m := &Migrator{db: db, dialect: dialects[constants.DBDriverMySQL], migrations: testMigrations, lockTimeout: time.Minute}
+	got, err := m.Status(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+	assert.True(t, got[0].Applied)
+	assert.False(t, got[1].Applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Up_Postgres(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT pg_try_advisory_lock($1)")).WithArgs(lockKey).
+		WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(true))
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS schema_migrations")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT version, applied_at FROM schema_migrations")).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()))
+	mock.ExpectExec(regexp.QuoteMeta("CREATE INDEX a ON t (a)")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)")).
+		WithArgs(int64(2), "add_index", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	m := &Migrator{db: db, dialect: dialects[constants.DBDriverPostgres], migrations: testMigrations, lockTimeout: time.Minute}
+	got, err := m.Up(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, got, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNewMigrator(t *testing.T) {
+	_, err := NewMigrator(nil, "oracle")
+	assert.Error(t, err)
+
+	m, err := NewMigrator(nil, constants.DBDriverPostgres)
+	assert.NoError(t, err)
+	assert.Equal(t, "postgres", m.dialect.dir)
+}
@@ -0,0 +1,44 @@
+// Package migrationtest opens migrated databases for the end to end repository tests.
+package migrationtest
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/migration"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// NewSQLiteDB opens an in-memory sqlite database with every migration applied,
+// so a repository can be tested end to end without a database server.
+func NewSQLiteDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := helpers.SetupDB(helpers.DBConfig{
+		Driver: constants.DBDriverSQLite,
+		Name:   ":memory:",
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	sqlDB, err := db.DB()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	m, err := migration.NewMigrator(sqlDB, constants.DBDriverSQLite)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	_, err = m.Up(context.Background())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	return db
+}
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS `transactions` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `user_id` bigint unsigned DEFAULT NULL,
+  `amount` decimal(15,2) DEFAULT NULL,
+  `transaction_type` enum('TOPUP','PURCHASE','REFUND') DEFAULT NULL,
+  `transaction_status` enum('PENDING','SUCCESS','FAILED','REVERSED') DEFAULT NULL,
+  `reference` varchar(255) DEFAULT NULL,
+  `description` varchar(255) DEFAULT NULL,
+  `additional_info` text,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,5 @@
+DROP INDEX `idx_transactions_status_created` ON `transactions`;
+
+DROP INDEX `idx_transactions_user_created` ON `transactions`;
+
+DROP INDEX `idx_transactions_reference_type` ON `transactions`;
@@ -0,0 +1,19 @@
+-- references generated in the same second could repeat before this index. Every duplicate but the oldest
+-- row of a reference and type is renamed <reference>-DUP<id> so the index can be built. The wallet still
+-- knows them by the old reference, the reconciliation reports the renamed rows that moved money.
+UPDATE `transactions` t
+  JOIN (
+    SELECT `reference`, `transaction_type`, MIN(`id`) AS `keep_id` FROM `transactions`
+    GROUP BY `reference`, `transaction_type` HAVING COUNT(*) > 1
+  ) d ON t.`reference` = d.`reference` AND t.`transaction_type` = d.`transaction_type`
+  SET t.`reference` = CONCAT(t.`reference`, '-DUP', t.`id`)
+  WHERE t.`id` <> d.`keep_id`;
+
+-- lookup by reference, a reference is shared by at most one row per transaction type
+CREATE UNIQUE INDEX `idx_transactions_reference_type` ON `transactions` (`reference`, `transaction_type`);
+
+-- transaction history of a user, newest first
+CREATE INDEX `idx_transactions_user_created` ON `transactions` (`user_id`, `created_at`, `id`);
+
+-- expiry jobs scanning old transactions of a status
+CREATE INDEX `idx_transactions_status_created` ON `transactions` (`transaction_status`, `created_at`);
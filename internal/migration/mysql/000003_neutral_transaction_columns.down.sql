@@ -0,0 +1,3 @@
+ALTER TABLE `transactions`
+  MODIFY `transaction_type` enum('TOPUP','PURCHASE','REFUND') DEFAULT NULL,
+  MODIFY `transaction_status` enum('PENDING','SUCCESS','FAILED','REVERSED') DEFAULT NULL;
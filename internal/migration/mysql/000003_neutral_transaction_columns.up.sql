@@ -0,0 +1,4 @@
+-- plain varchar like the other dialects, allowed values are validated by the service
+ALTER TABLE `transactions`
+  MODIFY `transaction_type` varchar(20) DEFAULT NULL,
+  MODIFY `transaction_status` varchar(20) DEFAULT NULL;
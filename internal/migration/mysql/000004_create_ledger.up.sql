@@ -0,0 +1,34 @@
+-- append-only double-entry ledger, rows are never updated or deleted
+CREATE TABLE IF NOT EXISTS `ledger_accounts` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `code` varchar(64) NOT NULL,
+  `account_type` varchar(20) NOT NULL,
+  `user_id` bigint unsigned DEFAULT NULL,
+  `created_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_ledger_accounts_code` (`code`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `journal_entries` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `reference` varchar(255) NOT NULL,
+  `entry_type` varchar(20) NOT NULL,
+  `description` varchar(255) DEFAULT NULL,
+  `created_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_journal_entries_reference` (`reference`,`entry_type`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `journal_lines` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `journal_entry_id` bigint NOT NULL,
+  `account_id` bigint NOT NULL,
+  `direction` varchar(10) NOT NULL,
+  `amount` decimal(15,2) NOT NULL,
+  `created_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_journal_lines_entry` (`journal_entry_id`),
+  KEY `idx_journal_lines_account` (`account_id`),
+  CONSTRAINT `fk_journal_lines_entry` FOREIGN KEY (`journal_entry_id`) REFERENCES `journal_entries` (`id`),
+  CONSTRAINT `fk_journal_lines_account` FOREIGN KEY (`account_id`) REFERENCES `ledger_accounts` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
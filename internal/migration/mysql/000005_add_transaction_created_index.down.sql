@@ -0,0 +1 @@
+DROP INDEX `idx_transactions_created` ON `transactions`;
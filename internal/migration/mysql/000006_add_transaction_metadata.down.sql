@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS `transaction_tags`;
+
+ALTER TABLE `transactions`
+  DROP KEY `idx_transactions_user_category`,
+  DROP COLUMN `category`,
+  DROP COLUMN `merchant_name`;
+
+DROP TABLE IF EXISTS `transaction_categories`;
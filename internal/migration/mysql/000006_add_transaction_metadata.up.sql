@@ -0,0 +1,29 @@
+-- managed categories, a transaction category must be one of these codes
+CREATE TABLE IF NOT EXISTS `transaction_categories` (
+  `code` varchar(50) NOT NULL,
+  `name` varchar(100) NOT NULL,
+  `created_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`code`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT IGNORE INTO `transaction_categories` (`code`, `name`, `created_at`) VALUES
+  ('FOOD_AND_DRINK', 'Makanan & Minuman', NOW(3)),
+  ('TRANSPORT', 'Transportasi', NOW(3)),
+  ('SHOPPING', 'Belanja', NOW(3)),
+  ('BILLS', 'Tagihan', NOW(3)),
+  ('ENTERTAINMENT', 'Hiburan', NOW(3)),
+  ('HEALTH', 'Kesehatan', NOW(3)),
+  ('EDUCATION', 'Pendidikan', NOW(3)),
+  ('OTHERS', 'Lainnya', NOW(3));
+
+ALTER TABLE `transactions`
+  ADD COLUMN `merchant_name` varchar(255) DEFAULT NULL,
+  ADD COLUMN `category` varchar(50) DEFAULT NULL,
+  ADD KEY `idx_transactions_user_category` (`user_id`,`category`,`created_at`);
+
+CREATE TABLE IF NOT EXISTS `transaction_tags` (
+  `transaction_id` bigint NOT NULL,
+  `tag` varchar(32) NOT NULL,
+  PRIMARY KEY (`transaction_id`,`tag`),
+  KEY `idx_transaction_tags_tag` (`tag`,`transaction_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1 @@
+ALTER TABLE `transactions` MODIFY `additional_info` text;
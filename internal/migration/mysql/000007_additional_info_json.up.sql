@@ -0,0 +1,8 @@
+-- additional_info becomes a native json column so its fields can be queried,
+-- empty values become NULL and legacy text that isn't json is kept under "legacy"
+UPDATE `transactions` SET `additional_info` = NULL WHERE `additional_info` = '';
+
+UPDATE `transactions` SET `additional_info` = JSON_OBJECT('legacy', `additional_info`)
+WHERE `additional_info` IS NOT NULL AND JSON_VALID(`additional_info`) = 0;
+
+ALTER TABLE `transactions` MODIFY `additional_info` json DEFAULT NULL;
@@ -0,0 +1 @@
+DROP INDEX `idx_transactions_description_ft` ON `transactions`;
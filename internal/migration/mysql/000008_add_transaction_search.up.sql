@@ -0,0 +1,2 @@
+-- the admin search matches words of the description, references are matched by prefix on their btree index
+CREATE FULLTEXT INDEX `idx_transactions_description_ft` ON `transactions` (`description`);
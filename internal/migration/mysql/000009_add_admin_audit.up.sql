@@ -0,0 +1,29 @@
+-- every status a transaction went through, who moved it and why
+CREATE TABLE IF NOT EXISTS `transaction_status_history` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `transaction_id` bigint NOT NULL,
+  `reference` varchar(255) NOT NULL,
+  `from_status` varchar(20) DEFAULT NULL,
+  `to_status` varchar(20) NOT NULL,
+  `actor` varchar(100) NOT NULL,
+  `reason` varchar(500) DEFAULT NULL,
+  `created_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_transaction_status_history_reference` (`reference`,`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- append-only trail of the admin api, rows are never updated or deleted
+CREATE TABLE IF NOT EXISTS `admin_audit_logs` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `admin_user_id` bigint unsigned NOT NULL,
+  `action` varchar(50) NOT NULL,
+  `reference` varchar(255) DEFAULT NULL,
+  `reason` varchar(500) DEFAULT NULL,
+  `payload` text,
+  `outcome` varchar(20) NOT NULL,
+  `error` text,
+  `created_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_admin_audit_logs_admin` (`admin_user_id`,`created_at`),
+  KEY `idx_admin_audit_logs_reference` (`reference`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,26 @@
+-- caps of the transactions per KYC tier and type, a zero value doesn't limit
+CREATE TABLE IF NOT EXISTS `transaction_limits` (
+  `kyc_tier` varchar(20) NOT NULL,
+  `transaction_type` varchar(20) NOT NULL,
+  `max_amount` decimal(15,2) NOT NULL DEFAULT 0,
+  `daily_amount` decimal(15,2) NOT NULL DEFAULT 0,
+  `daily_count` bigint NOT NULL DEFAULT 0,
+  `monthly_amount` decimal(15,2) NOT NULL DEFAULT 0,
+  `monthly_count` bigint NOT NULL DEFAULT 0,
+  `updated_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`kyc_tier`,`transaction_type`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT IGNORE INTO `transaction_limits` (`kyc_tier`, `transaction_type`, `max_amount`, `daily_amount`, `daily_count`, `monthly_amount`, `monthly_count`, `updated_at`) VALUES
+  ('BASIC', 'TOPUP', 2000000, 5000000, 20, 20000000, 200, NOW(3)),
+  ('BASIC', 'PURCHASE', 2000000, 5000000, 50, 20000000, 500, NOW(3)),
+  ('VERIFIED', 'TOPUP', 20000000, 50000000, 50, 100000000, 500, NOW(3)),
+  ('VERIFIED', 'PURCHASE', 20000000, 50000000, 200, 100000000, 2000, NOW(3));
+
+-- tier of the users whose identity was checked, the others are BASIC
+CREATE TABLE IF NOT EXISTS `user_kyc_tiers` (
+  `user_id` bigint unsigned NOT NULL,
+  `kyc_tier` varchar(20) NOT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
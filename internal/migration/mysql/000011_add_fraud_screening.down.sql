@@ -0,0 +1,3 @@
+ALTER TABLE `transactions`
+  DROP COLUMN `client_ip`,
+  DROP COLUMN `device_id`;
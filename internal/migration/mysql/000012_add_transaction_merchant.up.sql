@@ -0,0 +1,2 @@
+-- the merchant paid by a purchase, the baseline schema has no merchant
+ALTER TABLE `transactions` ADD COLUMN `merchant_id` bigint unsigned DEFAULT NULL AFTER `additional_info`;
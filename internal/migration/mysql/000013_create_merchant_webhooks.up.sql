@@ -0,0 +1,32 @@
+-- signed status callbacks of the merchants and their delivery queue
+CREATE TABLE IF NOT EXISTS `merchant_webhooks` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `merchant_id` bigint unsigned DEFAULT NULL,
+  `url` varchar(255) DEFAULT NULL,
+  `secret` varchar(255) DEFAULT NULL,
+  `is_active` tinyint(1) DEFAULT NULL,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_merchant_webhooks_merchant_id` (`merchant_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `webhook_deliveries` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `merchant_id` bigint unsigned DEFAULT NULL,
+  `reference` varchar(255) DEFAULT NULL,
+  `event_id` varchar(64) DEFAULT NULL,
+  `event_type` varchar(64) DEFAULT NULL,
+  `url` varchar(255) DEFAULT NULL,
+  `payload` text,
+  `status` varchar(20) DEFAULT NULL,
+  `attempts` bigint DEFAULT NULL,
+  `last_response_code` bigint DEFAULT NULL,
+  `last_error` text,
+  `next_attempt_at` datetime(3) DEFAULT NULL,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_webhook_deliveries_reference` (`merchant_id`,`reference`),
+  KEY `idx_webhook_deliveries_due` (`status`,`next_attempt_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,8 @@
+-- email of the users from their last validated token, for the notifications sent without it
+CREATE TABLE IF NOT EXISTS `user_contacts` (
+  `user_id` bigint unsigned NOT NULL,
+  `email` varchar(255) NOT NULL,
+  `full_name` varchar(255) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,5 @@
+-- one row per user, locked while the limits of a new or settling transaction are checked
+CREATE TABLE IF NOT EXISTS `user_limit_locks` (
+  `user_id` bigint unsigned NOT NULL,
+  PRIMARY KEY (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS transactions (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT,
+  amount NUMERIC(15,2),
+  transaction_type VARCHAR(20),
+  transaction_status VARCHAR(20),
+  reference VARCHAR(255),
+  description VARCHAR(255),
+  additional_info TEXT,
+  created_at TIMESTAMPTZ,
+  updated_at TIMESTAMPTZ
+);
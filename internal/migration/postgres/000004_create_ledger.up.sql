@@ -0,0 +1,33 @@
+-- append-only double-entry ledger, rows are never updated or deleted
+CREATE TABLE IF NOT EXISTS ledger_accounts (
+  id BIGSERIAL PRIMARY KEY,
+  code VARCHAR(64) NOT NULL,
+  account_type VARCHAR(20) NOT NULL,
+  user_id BIGINT,
+  created_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ledger_accounts_code ON ledger_accounts (code);
+
+CREATE TABLE IF NOT EXISTS journal_entries (
+  id BIGSERIAL PRIMARY KEY,
+  reference VARCHAR(255) NOT NULL,
+  entry_type VARCHAR(20) NOT NULL,
+  description VARCHAR(255),
+  created_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_journal_entries_reference ON journal_entries (reference, entry_type);
+
+CREATE TABLE IF NOT EXISTS journal_lines (
+  id BIGSERIAL PRIMARY KEY,
+  journal_entry_id BIGINT NOT NULL REFERENCES journal_entries (id),
+  account_id BIGINT NOT NULL REFERENCES ledger_accounts (id),
+  direction VARCHAR(10) NOT NULL,
+  amount NUMERIC(15,2) NOT NULL,
+  created_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_journal_lines_entry ON journal_lines (journal_entry_id);
+
+CREATE INDEX IF NOT EXISTS idx_journal_lines_account ON journal_lines (account_id);
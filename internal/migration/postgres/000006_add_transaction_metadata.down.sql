@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS transaction_tags;
+
+DROP INDEX IF EXISTS idx_transactions_user_category;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS category;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS merchant_name;
+
+DROP TABLE IF EXISTS transaction_categories;
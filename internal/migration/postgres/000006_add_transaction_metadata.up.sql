@@ -0,0 +1,31 @@
+-- managed categories, a transaction category must be one of these codes
+CREATE TABLE IF NOT EXISTS transaction_categories (
+  code VARCHAR(50) PRIMARY KEY,
+  name VARCHAR(100) NOT NULL,
+  created_at TIMESTAMPTZ
+);
+
+INSERT INTO transaction_categories (code, name, created_at) VALUES
+  ('FOOD_AND_DRINK', 'Makanan & Minuman', NOW()),
+  ('TRANSPORT', 'Transportasi', NOW()),
+  ('SHOPPING', 'Belanja', NOW()),
+  ('BILLS', 'Tagihan', NOW()),
+  ('ENTERTAINMENT', 'Hiburan', NOW()),
+  ('HEALTH', 'Kesehatan', NOW()),
+  ('EDUCATION', 'Pendidikan', NOW()),
+  ('OTHERS', 'Lainnya', NOW())
+ON CONFLICT (code) DO NOTHING;
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS merchant_name VARCHAR(255);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS category VARCHAR(50);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_user_category ON transactions (user_id, category, created_at);
+
+CREATE TABLE IF NOT EXISTS transaction_tags (
+  transaction_id BIGINT NOT NULL,
+  tag VARCHAR(32) NOT NULL,
+  PRIMARY KEY (transaction_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_tags_tag ON transaction_tags (tag, transaction_id);
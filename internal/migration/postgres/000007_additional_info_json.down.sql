@@ -0,0 +1 @@
+ALTER TABLE transactions ALTER COLUMN additional_info TYPE TEXT USING additional_info::text;
@@ -0,0 +1,8 @@
+-- additional_info becomes jsonb so its fields can be queried,
+-- empty values become NULL and legacy text that isn't a json object or array is kept under "legacy"
+UPDATE transactions SET additional_info = NULL WHERE additional_info = '';
+
+UPDATE transactions SET additional_info = json_build_object('legacy', additional_info)::text
+WHERE additional_info IS NOT NULL AND additional_info !~ '^\s*[\{\[]';
+
+ALTER TABLE transactions ALTER COLUMN additional_info TYPE JSONB USING additional_info::jsonb;
@@ -0,0 +1,2 @@
+-- the admin search matches words of the description, references are matched by prefix on their btree index
+CREATE INDEX IF NOT EXISTS idx_transactions_description_fts ON transactions USING GIN (to_tsvector('simple', COALESCE(description, '')));
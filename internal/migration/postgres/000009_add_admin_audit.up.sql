@@ -0,0 +1,30 @@
+-- every status a transaction went through, who moved it and why
+CREATE TABLE IF NOT EXISTS transaction_status_history (
+  id BIGSERIAL PRIMARY KEY,
+  transaction_id BIGINT NOT NULL,
+  reference VARCHAR(255) NOT NULL,
+  from_status VARCHAR(20),
+  to_status VARCHAR(20) NOT NULL,
+  actor VARCHAR(100) NOT NULL,
+  reason VARCHAR(500),
+  created_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_status_history_reference ON transaction_status_history (reference, created_at);
+
+-- append-only trail of the admin api, rows are never updated or deleted
+CREATE TABLE IF NOT EXISTS admin_audit_logs (
+  id BIGSERIAL PRIMARY KEY,
+  admin_user_id BIGINT NOT NULL,
+  action VARCHAR(50) NOT NULL,
+  reference VARCHAR(255),
+  reason VARCHAR(500),
+  payload TEXT,
+  outcome VARCHAR(20) NOT NULL,
+  error TEXT,
+  created_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_audit_logs_admin ON admin_audit_logs (admin_user_id, created_at);
+
+CREATE INDEX IF NOT EXISTS idx_admin_audit_logs_reference ON admin_audit_logs (reference);
@@ -0,0 +1,26 @@
+-- caps of the transactions per KYC tier and type, a zero value doesn't limit
+CREATE TABLE IF NOT EXISTS transaction_limits (
+  kyc_tier VARCHAR(20) NOT NULL,
+  transaction_type VARCHAR(20) NOT NULL,
+  max_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+  daily_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+  daily_count BIGINT NOT NULL DEFAULT 0,
+  monthly_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+  monthly_count BIGINT NOT NULL DEFAULT 0,
+  updated_at TIMESTAMPTZ,
+  PRIMARY KEY (kyc_tier, transaction_type)
+);
+
+INSERT INTO transaction_limits (kyc_tier, transaction_type, max_amount, daily_amount, daily_count, monthly_amount, monthly_count, updated_at) VALUES
+  ('BASIC', 'TOPUP', 2000000, 5000000, 20, 20000000, 200, NOW()),
+  ('BASIC', 'PURCHASE', 2000000, 5000000, 50, 20000000, 500, NOW()),
+  ('VERIFIED', 'TOPUP', 20000000, 50000000, 50, 100000000, 500, NOW()),
+  ('VERIFIED', 'PURCHASE', 20000000, 50000000, 200, 100000000, 2000, NOW())
+ON CONFLICT (kyc_tier, transaction_type) DO NOTHING;
+
+-- tier of the users whose identity was checked, the others are BASIC
+CREATE TABLE IF NOT EXISTS user_kyc_tiers (
+  user_id BIGINT PRIMARY KEY,
+  kyc_tier VARCHAR(20) NOT NULL,
+  updated_at TIMESTAMPTZ
+);
@@ -0,0 +1,3 @@
+ALTER TABLE transactions DROP COLUMN IF EXISTS client_ip;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS device_id;
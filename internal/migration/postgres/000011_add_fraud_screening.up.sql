@@ -0,0 +1,4 @@
+-- the device and the address a transaction was created from, screened against the history of the user
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS device_id VARCHAR(100);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS client_ip VARCHAR(45);
@@ -0,0 +1,33 @@
+-- signed status callbacks of the merchants and their delivery queue
+CREATE TABLE IF NOT EXISTS merchant_webhooks (
+  id BIGSERIAL PRIMARY KEY,
+  merchant_id BIGINT,
+  url VARCHAR(255),
+  secret VARCHAR(255),
+  is_active BOOLEAN,
+  created_at TIMESTAMPTZ,
+  updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_merchant_webhooks_merchant_id ON merchant_webhooks (merchant_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id BIGSERIAL PRIMARY KEY,
+  merchant_id BIGINT,
+  reference VARCHAR(255),
+  event_id VARCHAR(64),
+  event_type VARCHAR(64),
+  url VARCHAR(255),
+  payload TEXT,
+  status VARCHAR(20),
+  attempts BIGINT,
+  last_response_code BIGINT,
+  last_error TEXT,
+  next_attempt_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ,
+  updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_reference ON webhook_deliveries (merchant_id, reference);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (status, next_attempt_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_transactions_status_created;
+
+DROP INDEX IF EXISTS idx_transactions_user_created;
+
+DROP INDEX IF EXISTS idx_transactions_reference_type;
@@ -0,0 +1,14 @@
+-- references generated in the same second could repeat before this index. Every duplicate but the oldest
+-- row of a reference and type is renamed <reference>-DUP<id> so the index can be built. The wallet still
+-- knows them by the old reference, the reconciliation reports the renamed rows that moved money.
+UPDATE transactions SET reference = reference || '-DUP' || id
+WHERE id NOT IN (SELECT MIN(id) FROM transactions GROUP BY reference, transaction_type);
+
+-- lookup by reference, a reference is shared by at most one row per transaction type
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_reference_type ON transactions (reference, transaction_type);
+
+-- transaction history of a user, newest first
+CREATE INDEX IF NOT EXISTS idx_transactions_user_created ON transactions (user_id, created_at, id);
+
+-- expiry jobs scanning old transactions of a status
+CREATE INDEX IF NOT EXISTS idx_transactions_status_created ON transactions (transaction_status, created_at);
@@ -0,0 +1,2 @@
+-- reconciliation scans a created_at window over every user
+CREATE INDEX IF NOT EXISTS idx_transactions_created ON transactions (created_at, id);
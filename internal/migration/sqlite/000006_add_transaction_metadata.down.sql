@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS transaction_tags;
+
+DROP INDEX IF EXISTS idx_transactions_user_category;
+
+ALTER TABLE transactions DROP COLUMN category;
+
+ALTER TABLE transactions DROP COLUMN merchant_name;
+
+DROP TABLE IF EXISTS transaction_categories;
@@ -0,0 +1,30 @@
+-- managed categories, a transaction category must be one of these codes
+CREATE TABLE IF NOT EXISTS transaction_categories (
+  code VARCHAR(50) PRIMARY KEY,
+  name VARCHAR(100) NOT NULL,
+  created_at DATETIME
+);
+
+INSERT OR IGNORE INTO transaction_categories (code, name, created_at) VALUES
+  ('FOOD_AND_DRINK', 'Makanan & Minuman', CURRENT_TIMESTAMP),
+  ('TRANSPORT', 'Transportasi', CURRENT_TIMESTAMP),
+  ('SHOPPING', 'Belanja', CURRENT_TIMESTAMP),
+  ('BILLS', 'Tagihan', CURRENT_TIMESTAMP),
+  ('ENTERTAINMENT', 'Hiburan', CURRENT_TIMESTAMP),
+  ('HEALTH', 'Kesehatan', CURRENT_TIMESTAMP),
+  ('EDUCATION', 'Pendidikan', CURRENT_TIMESTAMP),
+  ('OTHERS', 'Lainnya', CURRENT_TIMESTAMP);
+
+ALTER TABLE transactions ADD COLUMN merchant_name VARCHAR(255);
+
+ALTER TABLE transactions ADD COLUMN category VARCHAR(50);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_user_category ON transactions (user_id, category, created_at);
+
+CREATE TABLE IF NOT EXISTS transaction_tags (
+  transaction_id INTEGER NOT NULL,
+  tag VARCHAR(32) NOT NULL,
+  PRIMARY KEY (transaction_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_tags_tag ON transaction_tags (tag, transaction_id);
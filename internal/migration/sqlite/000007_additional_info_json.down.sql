@@ -0,0 +1 @@
+-- the column stays text, nothing to revert
@@ -0,0 +1,6 @@
+-- sqlite has no json column type, its json functions query the text column directly.
+-- Like the other dialects empty values become NULL and legacy text that isn't json is kept under "legacy"
+UPDATE transactions SET additional_info = NULL WHERE additional_info = '';
+
+UPDATE transactions SET additional_info = json_object('legacy', additional_info)
+WHERE additional_info IS NOT NULL AND json_valid(additional_info) = 0;
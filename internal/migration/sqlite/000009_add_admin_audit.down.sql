@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS admin_audit_logs;
+
+DROP TABLE IF EXISTS transaction_status_history;
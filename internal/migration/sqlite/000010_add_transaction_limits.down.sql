@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_kyc_tiers;
+
+DROP TABLE IF EXISTS transaction_limits;
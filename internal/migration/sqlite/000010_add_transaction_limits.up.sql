@@ -0,0 +1,25 @@
+-- caps of the transactions per KYC tier and type, a zero value doesn't limit
+CREATE TABLE IF NOT EXISTS transaction_limits (
+  kyc_tier VARCHAR(20) NOT NULL,
+  transaction_type VARCHAR(20) NOT NULL,
+  max_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+  daily_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+  daily_count INTEGER NOT NULL DEFAULT 0,
+  monthly_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+  monthly_count INTEGER NOT NULL DEFAULT 0,
+  updated_at DATETIME,
+  PRIMARY KEY (kyc_tier, transaction_type)
+);
+
+INSERT OR IGNORE INTO transaction_limits (kyc_tier, transaction_type, max_amount, daily_amount, daily_count, monthly_amount, monthly_count, updated_at) VALUES
+  ('BASIC', 'TOPUP', 2000000, 5000000, 20, 20000000, 200, CURRENT_TIMESTAMP),
+  ('BASIC', 'PURCHASE', 2000000, 5000000, 50, 20000000, 500, CURRENT_TIMESTAMP),
+  ('VERIFIED', 'TOPUP', 20000000, 50000000, 50, 100000000, 500, CURRENT_TIMESTAMP),
+  ('VERIFIED', 'PURCHASE', 20000000, 50000000, 200, 100000000, 2000, CURRENT_TIMESTAMP);
+
+-- tier of the users whose identity was checked, the others are BASIC
+CREATE TABLE IF NOT EXISTS user_kyc_tiers (
+  user_id INTEGER PRIMARY KEY,
+  kyc_tier VARCHAR(20) NOT NULL,
+  updated_at DATETIME
+);
@@ -0,0 +1,3 @@
+ALTER TABLE transactions DROP COLUMN client_ip;
+
+ALTER TABLE transactions DROP COLUMN device_id;
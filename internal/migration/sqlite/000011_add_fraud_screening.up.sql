@@ -0,0 +1,4 @@
+-- the device and the address a transaction was created from, screened against the history of the user
+ALTER TABLE transactions ADD COLUMN device_id VARCHAR(100);
+
+ALTER TABLE transactions ADD COLUMN client_ip VARCHAR(45);
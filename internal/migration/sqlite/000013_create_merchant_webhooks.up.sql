@@ -0,0 +1,33 @@
+-- signed status callbacks of the merchants and their delivery queue
+CREATE TABLE IF NOT EXISTS merchant_webhooks (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  merchant_id INTEGER,
+  url VARCHAR(255),
+  secret VARCHAR(255),
+  is_active BOOLEAN,
+  created_at DATETIME,
+  updated_at DATETIME
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_merchant_webhooks_merchant_id ON merchant_webhooks (merchant_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  merchant_id INTEGER,
+  reference VARCHAR(255),
+  event_id VARCHAR(64),
+  event_type VARCHAR(64),
+  url VARCHAR(255),
+  payload TEXT,
+  status VARCHAR(20),
+  attempts INTEGER,
+  last_response_code INTEGER,
+  last_error TEXT,
+  next_attempt_at DATETIME,
+  created_at DATETIME,
+  updated_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_reference ON webhook_deliveries (merchant_id, reference);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (status, next_attempt_at);
@@ -0,0 +1,7 @@
+-- email of the users from their last validated token, for the notifications sent without it
+CREATE TABLE IF NOT EXISTS user_contacts (
+  user_id INTEGER PRIMARY KEY,
+  email VARCHAR(255) NOT NULL,
+  full_name VARCHAR(255),
+  updated_at DATETIME
+);
@@ -0,0 +1,4 @@
+-- one row per user, locked while the limits of a new or settling transaction are checked
+CREATE TABLE IF NOT EXISTS user_limit_locks (
+  user_id INTEGER PRIMARY KEY
+);
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidAdditionalInfo is returned when additional_info is not a json object
+// or doesn't match the schema registered for the transaction type and status.
+var ErrInvalidAdditionalInfo = errors.New("invalid additional info")
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// AdditionalInfoError lists every field of additional_info that failed validation,
+// it matches ErrInvalidAdditionalInfo with errors.Is.
+type AdditionalInfoError struct {
+	Fields []FieldError
+}
+
+func (e *AdditionalInfoError) Error() string {
+	fields := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		fields[i] = field.Field + ": " + field.Message
+	}
+
+	return ErrInvalidAdditionalInfo.Error() + ": " + strings.Join(fields, "; ")
+}
+
+func (e *AdditionalInfoError) Unwrap() error {
+	return ErrInvalidAdditionalInfo
+}
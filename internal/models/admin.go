@@ -0,0 +1,57 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrInvalidAdminRequest is returned when an admin action misses its reason or asks for an unknown status.
+var ErrInvalidAdminRequest = errors.New("invalid admin request")
+
+// TransactionStatusHistory is one status a transaction went through, FromStatus is empty for its creation.
+type TransactionStatusHistory struct {
+	ID            int       `json:"id"`
+	TransactionID int       `json:"transaction_id" gorm:"column:transaction_id"`
+	Reference     string    `json:"reference" gorm:"column:reference;type:varchar(255)"`
+	FromStatus    string    `json:"from_status" gorm:"column:from_status;type:varchar(20)"`
+	ToStatus      string    `json:"to_status" gorm:"column:to_status;type:varchar(20)"`
+	Actor         string    `json:"actor" gorm:"column:actor;type:varchar(100)"`
+	Reason        string    `json:"reason,omitempty" gorm:"column:reason;type:varchar(500)"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (*TransactionStatusHistory) TableName() string {
+	return "transaction_status_history"
+}
+
+// AdminAuditLog is one call of the admin api, Payload is the request as json.
+type AdminAuditLog struct {
+	ID          int       `json:"id"`
+	AdminUserID uint64    `json:"admin_user_id" gorm:"column:admin_user_id"`
+	Action      string    `json:"action" gorm:"column:action;type:varchar(50)"`
+	Reference   string    `json:"reference,omitempty" gorm:"column:reference;type:varchar(255)"`
+	Reason      string    `json:"reason,omitempty" gorm:"column:reason;type:varchar(500)"`
+	Payload     string    `json:"payload,omitempty" gorm:"column:payload;type:text"`
+	Outcome     string    `json:"outcome" gorm:"column:outcome;type:varchar(20)"`
+	Error       string    `json:"error,omitempty" gorm:"column:error;type:text"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (*AdminAuditLog) TableName() string {
+	return "admin_audit_logs"
+}
+
+// AdminStatusRequest moves a transaction on behalf of its user, Reason is mandatory.
+type AdminStatusRequest struct {
+	TransactionStatus string `json:"transaction_status"`
+	Reason            string `json:"reason"`
+	AdditionalInfo    string `json:"additional_info"`
+}
+
+// AdminRefundRequest refunds a purchase on behalf of its user, Reason is mandatory
+// and is also the description of the refund when none is given.
+type AdminRefundRequest struct {
+	Description    string `json:"description"`
+	Reason         string `json:"reason"`
+	AdditionalInfo string `json:"additional_info"`
+}
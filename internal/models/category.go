@@ -0,0 +1,20 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrInvalidCategory is returned when a transaction refers to a category that isn't managed.
+var ErrInvalidCategory = errors.New("invalid transaction category")
+
+// TransactionCategory is a managed category, transactions refer to it by Code.
+type TransactionCategory struct {
+	Code      string    `json:"code" gorm:"column:code;primaryKey;type:varchar(50)"`
+	Name      string    `json:"name" gorm:"column:name;type:varchar(100)"`
+	CreatedAt time.Time `json:"-"`
+}
+
+func (*TransactionCategory) TableName() string {
+	return "transaction_categories"
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"strconv"
+	"time"
+)
+
+type TransactionEvent struct {
+	ID                string
+	Type              string
+	Version           int
+	OccurredAt        time.Time
+	PreviousStatus    string
+	OriginalReference string
+	Transaction       Transaction
+}
+
+// Key is the partition key of the event, all events of one user are kept in order.
+func (e TransactionEvent) Key() string {
+	return strconv.FormatUint(e.Transaction.UserID, 10)
+}
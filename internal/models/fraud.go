@@ -0,0 +1,67 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrTransactionOnHold is returned when the screening parks a transaction for review,
+// and while it waits for an admin.
+var ErrTransactionOnHold = errors.New("transaction on hold")
+
+// ErrTransactionDenied is returned when the screening fails a transaction.
+var ErrTransactionDenied = errors.New("transaction denied")
+
+// FraudHistoryQuery bounds the history of a user the screening reads, Since is the earliest of the windows.
+type FraudHistoryQuery struct {
+	Since         time.Time
+	VelocitySince time.Time
+	RefundSince   time.Time
+}
+
+// FraudHistory describes the transactions of a user since FraudHistoryQuery.Since,
+// the screened transaction excluded.
+type FraudHistory struct {
+	// RecentCount counts the transactions of any type and status since VelocitySince.
+	RecentCount int64 `gorm:"column:recent_count"`
+	// SuccessCount counts the SUCCESS transactions of any type.
+	SuccessCount int64 `gorm:"column:success_count"`
+	// TypeCount and AverageAmount describe the SUCCESS transactions of the screened type.
+	TypeCount     int64   `gorm:"column:type_count"`
+	AverageAmount float64 `gorm:"column:average_amount"`
+	// DeviceCount and IPCount count the SUCCESS transactions made from the same device and address.
+	DeviceCount int64 `gorm:"column:device_count"`
+	IPCount     int64 `gorm:"column:ip_count"`
+	// RefundCount counts the refunds since RefundSince.
+	RefundCount int64 `gorm:"column:refund_count"`
+}
+
+// FraudRuleHit is a rule that flagged a transaction.
+type FraudRuleHit struct {
+	Rule     string `json:"rule"`
+	Decision string `json:"decision"`
+	Detail   string `json:"detail"`
+}
+
+// FraudScreening is the decision on a transaction, ALLOW when no rule flagged it.
+type FraudScreening struct {
+	Decision string         `json:"decision"`
+	Hits     []FraudRuleHit `json:"hits,omitempty"`
+}
+
+// Reason lists the rules that flagged the transaction, it is kept in the status history.
+func (s FraudScreening) Reason() string {
+	reasons := make([]string, 0, len(s.Hits))
+	for _, hit := range s.Hits {
+		reasons = append(reasons, hit.Rule+": "+hit.Detail)
+	}
+
+	return strings.Join(reasons, "; ")
+}
+
+// AdminReviewRequest approves or rejects a transaction on hold.
+type AdminReviewRequest struct {
+	Reason         string `json:"reason"`
+	AdditionalInfo string `json:"additional_info"`
+}
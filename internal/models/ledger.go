@@ -0,0 +1,85 @@
+package models
+
+import (
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"fmt"
+	"time"
+)
+
+type LedgerAccount struct {
+	ID          int       `json:"id"`
+	Code        string    `json:"code" gorm:"column:code;type:varchar(64);uniqueIndex"`
+	AccountType string    `json:"account_type" gorm:"column:account_type;type:varchar(20)"`
+	UserID      uint64    `json:"user_id,omitempty" gorm:"column:user_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (*LedgerAccount) TableName() string {
+	return "ledger_accounts"
+}
+
+type JournalEntry struct {
+	ID          int           `json:"id"`
+	Reference   string        `json:"reference" gorm:"column:reference;type:varchar(255)"`
+	EntryType   string        `json:"entry_type" gorm:"column:entry_type;type:varchar(20)"`
+	Description string        `json:"description" gorm:"column:description;type:varchar(255)"`
+	Lines       []JournalLine `json:"lines" gorm:"foreignKey:JournalEntryID"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+func (*JournalEntry) TableName() string {
+	return "journal_entries"
+}
+
+// Validate checks the double-entry invariant: at least two lines, positive amounts
+// and debits equal to credits, compared in cents to avoid float drift.
+func (l JournalEntry) Validate() error {
+	if len(l.Lines) < 2 {
+		return fmt.Errorf("journal entry %s/%s needs at least two lines", l.Reference, l.EntryType)
+	}
+
+	var net int64
+	for _, line := range l.Lines {
+		if line.AccountID == 0 {
+			return fmt.Errorf("journal entry %s/%s has a line without account", l.Reference, l.EntryType)
+		}
+
+		cents := line.Cents()
+		if cents <= 0 {
+			return fmt.Errorf("journal entry %s/%s has a non positive amount %.2f", l.Reference, l.EntryType, line.Amount)
+		}
+
+		switch line.Direction {
+		case constants.JournalDirectionDebit:
+			net += cents
+		case constants.JournalDirectionCredit:
+			net -= cents
+		default:
+			return fmt.Errorf("journal entry %s/%s has an invalid direction %q", l.Reference, l.EntryType, line.Direction)
+		}
+	}
+
+	if net != 0 {
+		return fmt.Errorf("journal entry %s/%s does not net to zero, off by %.2f", l.Reference, l.EntryType, float64(net)/100)
+	}
+
+	return nil
+}
+
+type JournalLine struct {
+	ID             int       `json:"id"`
+	JournalEntryID int       `json:"journal_entry_id" gorm:"column:journal_entry_id"`
+	AccountID      int       `json:"account_id" gorm:"column:account_id"`
+	Direction      string    `json:"direction" gorm:"column:direction;type:varchar(10)"`
+	Amount         float64   `json:"amount" gorm:"column:amount;type:decimal(15,2)"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func (*JournalLine) TableName() string {
+	return "journal_lines"
+}
+
+func (l JournalLine) Cents() int64 {
+	return helpers.Cents(l.Amount)
+}
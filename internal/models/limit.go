@@ -0,0 +1,91 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrLimitExceeded is matched by every LimitExceededError.
+var ErrLimitExceeded = errors.New("LIMIT_EXCEEDED")
+
+// ErrInvalidLimit is returned for an unknown tier or type, or a negative limit.
+var ErrInvalidLimit = errors.New("invalid limit")
+
+// TransactionLimit caps the transactions of one type for the users of a KYC tier.
+// A zero value doesn't limit, the daily and monthly ones add up the transactions of the period.
+type TransactionLimit struct {
+	KYCTier         string    `json:"kyc_tier" gorm:"column:kyc_tier;type:varchar(20);primaryKey"`
+	TransactionType string    `json:"transaction_type" gorm:"column:transaction_type;type:varchar(20);primaryKey"`
+	MaxAmount       float64   `json:"max_amount" gorm:"column:max_amount;type:decimal(15,2)"`
+	DailyAmount     float64   `json:"daily_amount" gorm:"column:daily_amount;type:decimal(15,2)"`
+	DailyCount      int64     `json:"daily_count" gorm:"column:daily_count"`
+	MonthlyAmount   float64   `json:"monthly_amount" gorm:"column:monthly_amount;type:decimal(15,2)"`
+	MonthlyCount    int64     `json:"monthly_count" gorm:"column:monthly_count"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+func (*TransactionLimit) TableName() string {
+	return "transaction_limits"
+}
+
+// UserKYCTier is the tier of a user whose identity was checked, the others are DefaultKYCTier.
+type UserKYCTier struct {
+	UserID    uint64    `json:"user_id" gorm:"column:user_id;primaryKey;autoIncrement:false"`
+	KYCTier   string    `json:"kyc_tier" gorm:"column:kyc_tier;type:varchar(20)"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (*UserKYCTier) TableName() string {
+	return "user_kyc_tiers"
+}
+
+// UserLimitLock is the row locked while the limits of a transaction of the user are checked, so two
+// concurrent transactions can't both fit in what is left of a limit.
+type UserLimitLock struct {
+	UserID uint64 `gorm:"column:user_id;primaryKey;autoIncrement:false"`
+}
+
+func (*UserLimitLock) TableName() string {
+	return "user_limit_locks"
+}
+
+// LimitUsage adds up the transactions of a user and a type since the start of the day and of the month.
+type LimitUsage struct {
+	DailyCount    int64   `gorm:"column:daily_count"`
+	DailyAmount   float64 `gorm:"column:daily_amount"`
+	MonthlyCount  int64   `gorm:"column:monthly_count"`
+	MonthlyAmount float64 `gorm:"column:monthly_amount"`
+}
+
+// LimitExceededError tells which limit a transaction would exceed, Current includes the transaction.
+// It matches ErrLimitExceeded with errors.Is.
+type LimitExceededError struct {
+	Code            string  `json:"code"`
+	Limit           string  `json:"limit"`
+	KYCTier         string  `json:"kyc_tier"`
+	TransactionType string  `json:"transaction_type"`
+	Max             float64 `json:"max"`
+	Current         float64 `json:"current"`
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s: %s of %s for tier %s is %.2f, the transaction makes it %.2f",
+		ErrLimitExceeded.Error(), e.Limit, e.TransactionType, e.KYCTier, e.Max, e.Current)
+}
+
+func (e *LimitExceededError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// AdminLimitRequest creates or replaces the limit of a tier and a type.
+type AdminLimitRequest struct {
+	TransactionLimit
+	Reason string `json:"reason"`
+}
+
+// AdminKYCTierRequest sets the KYC tier of a user.
+type AdminKYCTierRequest struct {
+	KYCTier string `json:"kyc_tier"`
+	Reason  string `json:"reason"`
+}
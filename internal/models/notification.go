@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Notification is a queued message, the worker looks up the contact of UserID when Recipient is empty.
+type Notification struct {
+	Event        string
+	UserID       uint64
+	Recipient    string
+	TemplateName string
+	PlaceHolder  map[string]string
+}
+
+// UserContact is the email of a user as given by their last validated token, it reaches the owner
+// of a transaction changed without that token, e.g. by the expiry, the settlement or an admin.
+type UserContact struct {
+	UserID    uint64    `json:"user_id" gorm:"column:user_id;primaryKey;autoIncrement:false"`
+	Email     string    `json:"email" gorm:"column:email;type:varchar(255)"`
+	Fullname  string    `json:"full_name" gorm:"column:full_name;type:varchar(255)"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (*UserContact) TableName() string {
+	return "user_contacts"
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// RateLimit is a token bucket of Requests tokens that refills completely over Period,
+// so a client may burst Requests requests and then make Requests per Period.
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// RateLimitResult is the outcome of taking one token from a bucket,
+// RetryAfter is how long until the next token when the request is not allowed.
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
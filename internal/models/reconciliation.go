@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// ReconciliationMismatch is a transaction whose local status disagrees with the wallet movements.
+type ReconciliationMismatch struct {
+	Reference         string  `json:"reference"`
+	TransactionType   string  `json:"transaction_type"`
+	TransactionStatus string  `json:"transaction_status"`
+	Kind              string  `json:"kind"`
+	ExpectedAmount    float64 `json:"expected_amount"`
+	WalletAmount      float64 `json:"wallet_amount"`
+	Detail            string  `json:"detail"`
+	// RepairStatus is the local status that matches the wallet, empty when the case is not safe to repair.
+	RepairStatus string `json:"repair_status,omitempty"`
+	Repaired     bool   `json:"repaired"`
+}
+
+type ReconciliationReport struct {
+	From       time.Time                `json:"from"`
+	To         time.Time                `json:"to"`
+	Checked    int                      `json:"checked"`
+	Repaired   int                      `json:"repaired"`
+	Mismatches []ReconciliationMismatch `json:"mismatches"`
+}
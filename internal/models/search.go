@@ -0,0 +1,36 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrInvalidSearchRequest is returned for a too short query, an unknown filter or a bad page.
+var ErrInvalidSearchRequest = errors.New("invalid search request")
+
+// SearchRequest matches Query against a fragment of the description, a reference prefix
+// or an exact amount, an empty Query matches everything. The filters are optional, a zero value doesn't filter.
+type SearchRequest struct {
+	Query             string `json:"q,omitempty"`
+	UserID            uint64 `json:"user_id,omitempty"`
+	TransactionType   string `json:"type,omitempty"`
+	TransactionStatus string `json:"status,omitempty"`
+	// From is inclusive and To exclusive.
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Page     int       `json:"page,omitempty"`
+	PageSize int       `json:"page_size,omitempty"`
+}
+
+// SearchResult is a matched transaction, a higher Score ranks it first.
+type SearchResult struct {
+	Transaction
+	Score float64 `json:"score" gorm:"column:score"`
+}
+
+type SearchResponse struct {
+	Items    []SearchResult `json:"items"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"page_size"`
+	Total    int64          `json:"total"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import "errors"
+
+// ErrInvalidSettlementFile is returned when a settlement file or its provider can't be read at all.
+var ErrInvalidSettlementFile = errors.New("invalid settlement file")
+
+// SettlementMapping tells how to read the csv settlement file of a provider.
+// Columns are header names when Header is set, zero based indexes otherwise.
+type SettlementMapping struct {
+	Provider  string `json:"provider"`
+	Delimiter string `json:"delimiter"`
+	Header    bool   `json:"header"`
+	Reference string `json:"reference"`
+	Amount    string `json:"amount"`
+	// DecimalComma reads amounts written like 1.000.000,50
+	DecimalComma bool `json:"decimal_comma"`
+}
+
+type SettlementLine struct {
+	Line      int     `json:"line"`
+	Reference string  `json:"reference"`
+	Amount    float64 `json:"amount"`
+}
+
+type SettlementUnmatched struct {
+	Line      int     `json:"line"`
+	Reference string  `json:"reference"`
+	Amount    float64 `json:"amount"`
+	Reason    string  `json:"reason"`
+	Detail    string  `json:"detail"`
+}
+
+type SettlementReport struct {
+	Provider      string                `json:"provider"`
+	Lines         int                   `json:"lines"`
+	Matched       int                   `json:"matched"`
+	MarkedSuccess int                   `json:"marked_success"`
+	Unmatched     []SettlementUnmatched `json:"unmatched"`
+}
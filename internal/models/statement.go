@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+type ExportRequest struct {
+	UserID uint64
+	Format string
+	// From is inclusive and To exclusive.
+	From time.Time
+	To   time.Time
+}
+
+// StatementPeriod is one calendar month of a statement, clipped to the exported range.
+// Only successful transactions move the balance and the totals.
+type StatementPeriod struct {
+	Start       time.Time
+	End         time.Time
+	Opening     float64
+	Closing     float64
+	TotalDebit  float64
+	TotalCredit float64
+	Count       int
+}
+
+type StatementRow struct {
+	Transaction Transaction
+	Debit       float64
+	Credit      float64
+	Balance     float64
+}
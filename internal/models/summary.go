@@ -0,0 +1,62 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrInvalidSummaryRequest is returned for an unknown period or timezone.
+var ErrInvalidSummaryRequest = errors.New("invalid summary request")
+
+type SummaryRequest struct {
+	Period string
+	// Timezone is an IANA name, empty means the configured default.
+	Timezone string
+	// GroupBy optionally breaks the purchases down by category, merchant or tag.
+	GroupBy string
+}
+
+// SummaryBucket is one aggregated row, the successful transactions of one type in one bucket.
+type SummaryBucket struct {
+	Bucket          int     `gorm:"column:bucket"`
+	TransactionType string  `gorm:"column:transaction_type"`
+	Count           int     `gorm:"column:count"`
+	Amount          float64 `gorm:"column:amount"`
+}
+
+// SummaryGroup is the successful purchases sharing a category, merchant or tag,
+// an empty Key groups the purchases without one.
+type SummaryGroup struct {
+	Key    string  `json:"key" gorm:"column:group_key"`
+	Name   string  `json:"name,omitempty" gorm:"column:group_name"`
+	Count  int     `json:"count" gorm:"column:count"`
+	Amount float64 `json:"amount" gorm:"column:amount"`
+}
+
+type SummaryTotal struct {
+	TransactionType string  `json:"transaction_type"`
+	Count           int     `json:"count"`
+	Amount          float64 `json:"amount"`
+}
+
+type SummaryDay struct {
+	Date    string  `json:"date"`
+	Count   int     `json:"count"`
+	Credit  float64 `json:"credit"`
+	Debit   float64 `json:"debit"`
+	NetFlow float64 `json:"net_flow"`
+}
+
+// TransactionSummary covers the successful transactions of the current day, week or month
+// in the user's timezone, NetFlow is what came into the wallet minus what was spent.
+type TransactionSummary struct {
+	Period   string         `json:"period"`
+	Timezone string         `json:"timezone"`
+	From     time.Time      `json:"from"`
+	To       time.Time      `json:"to"`
+	Totals   []SummaryTotal `json:"totals"`
+	NetFlow  float64        `json:"net_flow"`
+	Series   []SummaryDay   `json:"series"`
+	GroupBy  string         `json:"group_by,omitempty"`
+	Groups   []SummaryGroup `json:"groups,omitempty"`
+}
@@ -1,6 +1,10 @@
 package models
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator"
@@ -10,11 +14,17 @@ type Transaction struct {
 	ID                int       `json:"id"`
 	UserID            uint64    `json:"user_id" valid:"required"`
 	Amount            float64   `json:"amount" gorm:"column:amount;type:decimal(15,2)"`
-	TransactionType   string    `json:"transaction_type" gorm:"column:transaction_type;type:enum('TOPUP', 'PURCHASE', 'REFUND')" valid:"required"`
-	TransactionStatus string    `json:"transaction_status" gorm:"column:transaction_status;type:enum('PENDING', 'SUCCESS', 'FAILED', 'REVERSED')"`
+	TransactionType   string    `json:"transaction_type" gorm:"column:transaction_type;type:varchar(20)" valid:"required"`
+	TransactionStatus string    `json:"transaction_status" gorm:"column:transaction_status;type:varchar(20)"`
 	Reference         string    `json:"reference" gorm:"column:reference;type:varchar(255)"`
 	Description       string    `json:"description" gorm:"column:description;type:varchar(255)" valid:"required"`
-	AdditionalInfo    string    `json:"additional_info" gorm:"column:additional_info;type:text"`
+	AdditionalInfo    string    `json:"additional_info" gorm:"column:additional_info;type:json"`
+	MerchantID        uint64    `json:"merchant_id,omitempty" gorm:"column:merchant_id"`
+	MerchantName      string    `json:"merchant_name,omitempty" gorm:"column:merchant_name;type:varchar(255)"`
+	Category          string    `json:"category,omitempty" gorm:"column:category;type:varchar(50)"`
+	DeviceID          string    `json:"-" gorm:"column:device_id;type:varchar(100)"`
+	ClientIP          string    `json:"-" gorm:"column:client_ip;type:varchar(45)"`
+	Tags              []string  `json:"tags,omitempty" gorm:"-"`
 	CreatedAt         time.Time `json:"created_at"`
 	UpdatedAt         time.Time `json:"updated_at"`
 }
@@ -28,6 +38,66 @@ func (l Transaction) Validate() error {
 	return v.Struct(l)
 }
 
+const (
+	MaxTransactionTags = 10
+	MaxTagLength       = 32
+	MaxDeviceIDLength  = 100
+)
+
+// ErrInvalidTags is returned when a tag is empty, too long, uses other characters than
+// lowercase letters, digits, - and _, or a transaction carries too many tags.
+var ErrInvalidTags = errors.New("invalid transaction tags")
+
+// ErrInvalidMerchant is returned when a transaction pays a user that isn't a merchant.
+var ErrInvalidMerchant = errors.New("invalid merchant")
+
+// ErrStatusConflict is returned when the status of a transaction changed since it was read.
+var ErrStatusConflict = errors.New("transaction status changed")
+
+var tagPattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// NormalizeTags lowercases, trims and dedupes the tags keeping their order.
+func NormalizeTags(tags []string) ([]string, error) {
+	var (
+		resp []string
+		seen = map[string]bool{}
+	)
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if len(tag) > MaxTagLength || !tagPattern.MatchString(tag) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidTags, tag)
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		resp = append(resp, tag)
+	}
+
+	if len(resp) > MaxTransactionTags {
+		return nil, fmt.Errorf("%w: more than %d tags", ErrInvalidTags, MaxTransactionTags)
+	}
+
+	return resp, nil
+}
+
+// TransactionTag is one tag of a transaction, a transaction has at most MaxTransactionTags.
+type TransactionTag struct {
+	TransactionID int    `gorm:"column:transaction_id;primaryKey;autoIncrement:false"`
+	Tag           string `gorm:"column:tag;primaryKey;type:varchar(32)"`
+}
+
+func (*TransactionTag) TableName() string {
+	return "transaction_tags"
+}
+
+// TransactionFilter narrows the transaction history, empty fields don't filter.
+type TransactionFilter struct {
+	Category   string
+	MerchantID uint64
+	Tag        string
+}
+
 type CreateTransactionResponse struct {
 	Reference         string `json:"reference"`
 	TransactionStatus string `json:"transaction_status"`
@@ -37,6 +107,13 @@ type UpdateStatusTransaction struct {
 	Reference         string `json:"reference" valid:"required"`
 	TransactionStatus string `json:"transaction_status" valid:"required"`
 	AdditionalInfo    string `json:"additional_info"`
+
+	// Actor and Reason are recorded in the status history, the actor defaults to the user.
+	Actor  string `json:"-"`
+	Reason string `json:"-"`
+	// Force is an admin decision: it reverses after MaximumReversalDuration, moves a transaction out of
+	// ON_HOLD and skips the fraud screening of a purchase debit. The status flow and the limits still apply.
+	Force bool `json:"-"`
 }
 
 func (l UpdateStatusTransaction) Validate() error {
@@ -48,9 +125,26 @@ type RefundTransaction struct {
 	Reference      string `json:"reference" valid:"required"`
 	Description    string `json:"description" valid:"required"`
 	AdditionalInfo string `json:"additional_info"`
+
+	// Actor and Reason are recorded in the status history, the actor defaults to the user.
+	Actor  string `json:"-"`
+	Reason string `json:"-"`
 }
 
 func (l RefundTransaction) Validate() error {
 	v := validator.New()
 	return v.Struct(l)
 }
+
+// ExpirePendingReport is the outcome of failing the transactions left PENDING since before Before.
+type ExpirePendingReport struct {
+	Before  time.Time              `json:"before"`
+	Checked int                    `json:"checked"`
+	Expired []string               `json:"expired"`
+	Failed  []ExpirePendingFailure `json:"failed"`
+}
+
+type ExpirePendingFailure struct {
+	Reference string `json:"reference"`
+	Error     string `json:"error"`
+}
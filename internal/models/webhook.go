@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/go-playground/validator"
+)
+
+type MerchantWebhook struct {
+	ID         int       `json:"id"`
+	MerchantID uint64    `json:"merchant_id" gorm:"column:merchant_id;uniqueIndex"`
+	URL        string    `json:"url" gorm:"column:url;type:varchar(255)"`
+	Secret     string    `json:"-" gorm:"column:secret;type:varchar(255)"`
+	IsActive   bool      `json:"is_active" gorm:"column:is_active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (*MerchantWebhook) TableName() string {
+	return "merchant_webhooks"
+}
+
+type RegisterWebhook struct {
+	URL string `json:"url" valid:"required"`
+}
+
+func (l RegisterWebhook) Validate() error {
+	v := validator.New()
+	return v.Struct(l)
+}
+
+type RegisterWebhookResponse struct {
+	MerchantID uint64 `json:"merchant_id"`
+	URL        string `json:"url"`
+	Secret     string `json:"secret"`
+}
+
+type WebhookDelivery struct {
+	ID               int       `json:"id"`
+	MerchantID       uint64    `json:"merchant_id" gorm:"column:merchant_id;index:idx_webhook_deliveries_reference,priority:1"`
+	Reference        string    `json:"reference" gorm:"column:reference;type:varchar(255);index:idx_webhook_deliveries_reference,priority:2"`
+	EventID          string    `json:"event_id" gorm:"column:event_id;type:varchar(64)"`
+	EventType        string    `json:"event_type" gorm:"column:event_type;type:varchar(64)"`
+	URL              string    `json:"url" gorm:"column:url;type:varchar(255)"`
+	Payload          string    `json:"payload" gorm:"column:payload;type:text"`
+	Status           string    `json:"status" gorm:"column:status;type:varchar(20);index:idx_webhook_deliveries_due,priority:1"`
+	Attempts         int       `json:"attempts" gorm:"column:attempts"`
+	LastResponseCode int       `json:"last_response_code" gorm:"column:last_response_code"`
+	LastError        string    `json:"last_error" gorm:"column:last_error;type:text"`
+	NextAttemptAt    time.Time `json:"next_attempt_at" gorm:"column:next_attempt_at;index:idx_webhook_deliveries_due,priority:2"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+func (*WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+type WebhookPayload struct {
+	EventID           string    `json:"event_id"`
+	EventType         string    `json:"event_type"`
+	MerchantID        uint64    `json:"merchant_id"`
+	Reference         string    `json:"reference"`
+	Amount            float64   `json:"amount"`
+	TransactionStatus string    `json:"transaction_status"`
+	PreviousStatus    string    `json:"previous_status"`
+	OccurredAt        time.Time `json:"occurred_at"`
+}
@@ -0,0 +1,16 @@
+package audit
+
+import (
+	"context"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+)
+
+func (r *repository) CreateAuditLog(ctx context.Context, log *models.AdminAuditLog) error {
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Write)
+	defer cancel()
+
+	err := r.DB.WithContext(ctx).Create(log).Error
+
+	return helpers.QueryError(ctx, err)
+}
@@ -0,0 +1,39 @@
+package audit
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/migration/migrationtest"
+	"ewallet-transaction/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_repository_E2E(t *testing.T) {
+	ctx := context.Background()
+	db := migrationtest.NewSQLiteDB(t)
+	r := NewRepository(db, helpers.QueryTimeouts{})
+
+	log := &models.AdminAuditLog{
+		AdminUserID: 7,
+		Action:      constants.AdminActionForceStatus,
+		Reference:   "REFERENCE",
+		Reason:      "chargeback",
+		Payload:     `{"transaction_status":"REVERSED","reason":"chargeback"}`,
+		Outcome:     constants.AdminAuditOutcomeFailed,
+		Error:       "current transaction status is not success",
+	}
+	err := r.CreateAuditLog(ctx, log)
+	assert.NoError(t, err)
+	assert.NotZero(t, log.ID)
+	assert.False(t, log.CreatedAt.IsZero())
+
+	var got models.AdminAuditLog
+	err = db.Where("admin_user_id = ? AND reference = ?", 7, "REFERENCE").First(&got).Error
+	assert.NoError(t, err)
+	assert.Equal(t, log.Payload, got.Payload)
+	assert.Equal(t, log.Error, got.Error)
+	assert.Equal(t, constants.AdminAuditOutcomeFailed, got.Outcome)
+}
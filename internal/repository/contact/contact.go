@@ -0,0 +1,33 @@
+package contact
+
+import (
+	"context"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+
+	"gorm.io/gorm/clause"
+)
+
+func (r *repository) UpsertContact(ctx context.Context, contact *models.UserContact) error {
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Write)
+	defer cancel()
+
+	err := r.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"email", "full_name", "updated_at"}),
+	}).Create(contact).Error
+
+	return helpers.QueryError(ctx, err)
+}
+
+func (r *repository) GetContact(ctx context.Context, userID uint64) (models.UserContact, error) {
+	var (
+		resp models.UserContact
+	)
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Read)
+	defer cancel()
+
+	err := r.DB.WithContext(ctx).Where("user_id = ?", userID).First(&resp).Error
+
+	return resp, helpers.QueryError(ctx, err)
+}
@@ -0,0 +1,33 @@
+package contact
+
+import (
+	"context"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/migration/migrationtest"
+	"ewallet-transaction/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func Test_repository_E2E(t *testing.T) {
+	ctx := context.Background()
+	db := migrationtest.NewSQLiteDB(t)
+	r := NewRepository(db, helpers.QueryTimeouts{})
+
+	_, err := r.GetContact(ctx, 1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	err = r.UpsertContact(ctx, &models.UserContact{UserID: 1, Email: "old@gmail.com", Fullname: "FULLNAME"})
+	assert.NoError(t, err)
+
+	err = r.UpsertContact(ctx, &models.UserContact{UserID: 1, Email: "new@gmail.com", Fullname: "FULLNAME"})
+	assert.NoError(t, err)
+
+	got, err := r.GetContact(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "new@gmail.com", got.Email, "the last token wins")
+	assert.Equal(t, "FULLNAME", got.Fullname)
+	assert.False(t, got.UpdatedAt.IsZero())
+}
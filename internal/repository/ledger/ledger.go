@@ -0,0 +1,73 @@
+package ledger
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetOrCreateAccount fills account with the stored row of its code, creating it on first use.
+func (r *repository) GetOrCreateAccount(ctx context.Context, account *models.LedgerAccount) error {
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Write)
+	defer cancel()
+
+	code := account.Code
+	err := r.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "code"}},
+		DoNothing: true,
+	}).Create(account).Error
+	if err != nil {
+		return helpers.QueryError(ctx, err)
+	}
+
+	*account = models.LedgerAccount{}
+	err = r.DB.WithContext(ctx).Where("code = ?", code).First(account).Error
+
+	return helpers.QueryError(ctx, err)
+}
+
+// CreateJournalEntry inserts the entry and its lines in one transaction.
+func (r *repository) CreateJournalEntry(ctx context.Context, entry *models.JournalEntry) error {
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Write)
+	defer cancel()
+
+	err := r.DB.WithContext(ctx).Create(entry).Error
+
+	return helpers.QueryError(ctx, err)
+}
+
+func (r *repository) GetJournalEntry(ctx context.Context, reference string, entryType string) (models.JournalEntry, error) {
+	var (
+		resp models.JournalEntry
+	)
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Read)
+	defer cancel()
+
+	err := r.DB.WithContext(ctx).Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Where("reference = ? AND entry_type = ?", reference, entryType).First(&resp).Error
+
+	return resp, helpers.QueryError(ctx, err)
+}
+
+// GetUnbalancedEntries returns the id of every stored journal entry whose lines don't net to zero.
+func (r *repository) GetUnbalancedEntries(ctx context.Context) ([]int, error) {
+	var (
+		resp []int
+	)
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Read)
+	defer cancel()
+
+	err := r.DB.WithContext(ctx).Model(&models.JournalLine{}).
+		Select("journal_entry_id").
+		Group("journal_entry_id").
+		Having("ROUND(SUM(CASE WHEN direction = ? THEN amount ELSE -amount END), 2) <> 0", constants.JournalDirectionDebit).
+		Order("journal_entry_id ASC").
+		Pluck("journal_entry_id", &resp).Error
+
+	return resp, helpers.QueryError(ctx, err)
+}
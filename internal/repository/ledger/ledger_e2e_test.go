@@ -0,0 +1,84 @@
+package ledger
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/migration/migrationtest"
+	"ewallet-transaction/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func Test_repository_E2E(t *testing.T) {
+	ctx := context.Background()
+	db := migrationtest.NewSQLiteDB(t)
+	r := NewRepository(db, helpers.QueryTimeouts{})
+
+	wallet := models.LedgerAccount{Code: "WALLET-1", AccountType: constants.LedgerAccountTypeWallet, UserID: 1}
+	err := r.GetOrCreateAccount(ctx, &wallet)
+	assert.NoError(t, err)
+	assert.NotZero(t, wallet.ID)
+
+	again := models.LedgerAccount{Code: "WALLET-1", AccountType: constants.LedgerAccountTypeWallet, UserID: 1}
+	err = r.GetOrCreateAccount(ctx, &again)
+	assert.NoError(t, err)
+	assert.Equal(t, wallet.ID, again.ID)
+
+	settlement := models.LedgerAccount{Code: constants.LedgerAccountSettlement, AccountType: constants.LedgerAccountTypeSettlement}
+	err = r.GetOrCreateAccount(ctx, &settlement)
+	assert.NoError(t, err)
+	assert.NotEqual(t, wallet.ID, settlement.ID)
+
+	err = r.CreateJournalEntry(ctx, &models.JournalEntry{
+		Reference: "REFERENCE",
+		EntryType: constants.JournalEntryTypeSuccess,
+		Lines: []models.JournalLine{
+			{AccountID: settlement.ID, Direction: constants.JournalDirectionDebit, Amount: 100.25},
+			{AccountID: wallet.ID, Direction: constants.JournalDirectionCredit, Amount: 100.25},
+		},
+	})
+	assert.NoError(t, err)
+
+	entry, err := r.GetJournalEntry(ctx, "REFERENCE", constants.JournalEntryTypeSuccess)
+	assert.NoError(t, err)
+	assert.Len(t, entry.Lines, 2)
+	assert.NoError(t, entry.Validate())
+
+	_, err = r.GetJournalEntry(ctx, "REFERENCE", constants.JournalEntryTypeReversed)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	err = r.CreateJournalEntry(ctx, &models.JournalEntry{
+		Reference: "REFERENCE",
+		EntryType: constants.JournalEntryTypeSuccess,
+		Lines: []models.JournalLine{
+			{AccountID: settlement.ID, Direction: constants.JournalDirectionDebit, Amount: 1},
+			{AccountID: wallet.ID, Direction: constants.JournalDirectionCredit, Amount: 1},
+		},
+	})
+	assert.Error(t, err, "reference and entry type are unique")
+
+	ids, err := r.GetUnbalancedEntries(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+
+	// the service never posts an unbalanced entry, write one behind its back
+	err = db.Create(&models.JournalEntry{
+		Reference: "BROKEN",
+		EntryType: constants.JournalEntryTypeSuccess,
+		Lines: []models.JournalLine{
+			{AccountID: settlement.ID, Direction: constants.JournalDirectionDebit, Amount: 10},
+			{AccountID: wallet.ID, Direction: constants.JournalDirectionCredit, Amount: 9.99},
+		},
+	}).Error
+	assert.NoError(t, err)
+
+	broken, err := r.GetJournalEntry(ctx, "BROKEN", constants.JournalEntryTypeSuccess)
+	assert.NoError(t, err)
+
+	ids, err = r.GetUnbalancedEntries(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{broken.ID}, ids)
+}
@@ -0,0 +1,17 @@
+package ledger
+
+import (
+	"ewallet-transaction/helpers"
+
+	"gorm.io/gorm"
+)
+
+// repository only inserts and reads, the ledger is append-only.
+type repository struct {
+	DB       *gorm.DB
+	Timeouts helpers.QueryTimeouts
+}
+
+func NewRepository(db *gorm.DB, timeouts helpers.QueryTimeouts) *repository {
+	return &repository{DB: db, Timeouts: timeouts}
+}
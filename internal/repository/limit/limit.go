@@ -0,0 +1,69 @@
+package limit
+
+import (
+	"context"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+
+	"gorm.io/gorm/clause"
+)
+
+func (r *repository) GetLimit(ctx context.Context, kycTier string, transactionType string) (models.TransactionLimit, error) {
+	var (
+		resp models.TransactionLimit
+	)
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Read)
+	defer cancel()
+
+	err := helpers.Conn(ctx, r.DB).Where("kyc_tier = ? AND transaction_type = ?", kycTier, transactionType).First(&resp).Error
+
+	return resp, helpers.QueryError(ctx, err)
+}
+
+func (r *repository) ListLimits(ctx context.Context) ([]models.TransactionLimit, error) {
+	var (
+		resp []models.TransactionLimit
+	)
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Read)
+	defer cancel()
+
+	err := r.DB.WithContext(ctx).Order("kyc_tier ASC, transaction_type ASC").Find(&resp).Error
+
+	return resp, helpers.QueryError(ctx, err)
+}
+
+func (r *repository) UpsertLimit(ctx context.Context, limit *models.TransactionLimit) error {
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Write)
+	defer cancel()
+
+	err := r.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "kyc_tier"}, {Name: "transaction_type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"max_amount", "daily_amount", "daily_count", "monthly_amount", "monthly_count", "updated_at"}),
+	}).Create(limit).Error
+
+	return helpers.QueryError(ctx, err)
+}
+
+func (r *repository) GetUserKYCTier(ctx context.Context, userID uint64) (models.UserKYCTier, error) {
+	var (
+		resp models.UserKYCTier
+	)
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Read)
+	defer cancel()
+
+	err := helpers.Conn(ctx, r.DB).Where("user_id = ?", userID).First(&resp).Error
+
+	return resp, helpers.QueryError(ctx, err)
+}
+
+func (r *repository) UpsertUserKYCTier(ctx context.Context, tier *models.UserKYCTier) error {
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Write)
+	defer cancel()
+
+	err := r.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"kyc_tier", "updated_at"}),
+	}).Create(tier).Error
+
+	return helpers.QueryError(ctx, err)
+}
@@ -0,0 +1,57 @@
+package limit
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/migration/migrationtest"
+	"ewallet-transaction/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func Test_repository_E2E(t *testing.T) {
+	ctx := context.Background()
+	r := NewRepository(migrationtest.NewSQLiteDB(t), helpers.QueryTimeouts{})
+
+	limits, err := r.ListLimits(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, limits, 4, "seeded by the migration")
+
+	limit, err := r.GetLimit(ctx, constants.KYCTierBasic, constants.TransactionTypeTopup)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(2000000), limit.MaxAmount)
+	assert.Equal(t, int64(20), limit.DailyCount)
+
+	_, err = r.GetLimit(ctx, constants.KYCTierBasic, constants.TransactionTypeRefund)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	limit.MaxAmount = 1000000
+	limit.DailyCount = 0
+	assert.NoError(t, r.UpsertLimit(ctx, &limit))
+
+	refund := &models.TransactionLimit{KYCTier: constants.KYCTierBasic, TransactionType: constants.TransactionTypeRefund, MaxAmount: 500000}
+	assert.NoError(t, r.UpsertLimit(ctx, refund))
+
+	limits, err = r.ListLimits(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, limits, 5)
+
+	got, err := r.GetLimit(ctx, constants.KYCTierBasic, constants.TransactionTypeTopup)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1000000), got.MaxAmount)
+	assert.Zero(t, got.DailyCount)
+	assert.Equal(t, float64(5000000), got.DailyAmount)
+
+	_, err = r.GetUserKYCTier(ctx, 1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	assert.NoError(t, r.UpsertUserKYCTier(ctx, &models.UserKYCTier{UserID: 1, KYCTier: constants.KYCTierVerified}))
+	assert.NoError(t, r.UpsertUserKYCTier(ctx, &models.UserKYCTier{UserID: 1, KYCTier: constants.KYCTierBasic}))
+
+	tier, err := r.GetUserKYCTier(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, constants.KYCTierBasic, tier.KYCTier)
+}
@@ -0,0 +1,31 @@
+package transaction
+
+import (
+	"context"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+)
+
+func (r *repository) ListCategories(ctx context.Context) ([]models.TransactionCategory, error) {
+	var (
+		resp []models.TransactionCategory
+	)
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Read)
+	defer cancel()
+
+	err := r.ReadDB.WithContext(ctx).Order("code").Find(&resp).Error
+
+	return resp, helpers.QueryError(ctx, err)
+}
+
+func (r *repository) GetCategory(ctx context.Context, code string) (models.TransactionCategory, error) {
+	var (
+		resp models.TransactionCategory
+	)
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Read)
+	defer cancel()
+
+	err := r.ReadDB.WithContext(ctx).Where("code = ?", code).Take(&resp).Error
+
+	return resp, helpers.QueryError(ctx, err)
+}
@@ -0,0 +1,99 @@
+package transaction
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// testDialect lets the repository tests assert the sql of every supported database,
+// expected queries are written for mysql and converted with sql.
+type testDialect struct {
+	name      string
+	dialector func(conn gorm.ConnPool) gorm.Dialector
+}
+
+var testDialects = []testDialect{
+	{
+		name: "mysql",
+		dialector: func(conn gorm.ConnPool) gorm.Dialector {
+			return mysql.New(mysql.Config{
+				Conn:                      conn,
+				SkipInitializeWithVersion: true,
+			})
+		},
+	},
+	{
+		name: "postgres",
+		dialector: func(conn gorm.ConnPool) gorm.Dialector {
+			return postgres.New(postgres.Config{
+				Conn: conn,
+			})
+		},
+	},
+}
+
+var bindVarPattern = regexp.MustCompile(`\?`)
+
+// sql converts a mysql query to the dialect, postgres quotes with " and numbers its bind vars.
+func (d testDialect) sql(query string) string {
+	if d.name != "postgres" {
+		return query
+	}
+
+	query = strings.ReplaceAll(query, "`", `"`)
+
+	n := 0
+	return bindVarPattern.ReplaceAllStringFunc(query, func(string) string {
+		n++
+		return fmt.Sprintf("$%d", n)
+	})
+}
+
+// expectInsert expects a gorm create, postgres reads the new id with RETURNING instead of LastInsertId.
+func (d testDialect) expectInsert(mock sqlmock.Sqlmock, query string, err error, args ...driver.Value) {
+	if d.name == "postgres" {
+		expected := mock.ExpectQuery(regexp.QuoteMeta(d.sql(query) + ` RETURNING "id"`)).WithArgs(args...)
+		if err != nil {
+			expected.WillReturnError(err)
+			return
+		}
+		expected.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		return
+	}
+
+	expected := mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(args...)
+	if err != nil {
+		expected.WillReturnError(err)
+		return
+	}
+	expected.WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+func newMockDB(t *testing.T, d testDialect) (*gorm.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	gormDB, err := gorm.Open(d.dialector(db), &gorm.Config{})
+	assert.NoError(t, err)
+
+	return gormDB, mock
+}
+
+func forEachDialect(t *testing.T, fn func(t *testing.T, d testDialect, gormDB *gorm.DB, mock sqlmock.Sqlmock)) {
+	for _, d := range testDialects {
+		t.Run(d.name, func(t *testing.T) {
+			gormDB, mock := newMockDB(t, d)
+			fn(t, d, gormDB, mock)
+		})
+	}
+}
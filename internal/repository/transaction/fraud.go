@@ -0,0 +1,41 @@
+package transaction
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+	"time"
+)
+
+// GetFraudHistory describes from the primary the transactions of the user of trx since query.Since,
+// trx itself excluded. The bounds are compared in the server zone the timestamps are written in,
+// sqlite compares them as text.
+func (r *repository) GetFraudHistory(ctx context.Context, trx models.Transaction, query models.FraudHistoryQuery) (models.FraudHistory, error) {
+	var (
+		resp    models.FraudHistory
+		success = constants.TransactionStatusSuccess
+	)
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Read)
+	defer cancel()
+
+	err := r.DB.WithContext(ctx).Model(&models.Transaction{}).
+		Select("COALESCE(SUM(CASE WHEN created_at >= ? THEN 1 ELSE 0 END), 0) AS recent_count, "+
+			"COALESCE(SUM(CASE WHEN transaction_status = ? THEN 1 ELSE 0 END), 0) AS success_count, "+
+			"COALESCE(SUM(CASE WHEN transaction_status = ? AND transaction_type = ? THEN 1 ELSE 0 END), 0) AS type_count, "+
+			"COALESCE(AVG(CASE WHEN transaction_status = ? AND transaction_type = ? THEN amount END), 0) AS average_amount, "+
+			"COALESCE(SUM(CASE WHEN transaction_status = ? AND device_id = ? THEN 1 ELSE 0 END), 0) AS device_count, "+
+			"COALESCE(SUM(CASE WHEN transaction_status = ? AND client_ip = ? THEN 1 ELSE 0 END), 0) AS ip_count, "+
+			"COALESCE(SUM(CASE WHEN transaction_type = ? AND created_at >= ? THEN 1 ELSE 0 END), 0) AS refund_count",
+			query.VelocitySince.In(time.Local),
+			success,
+			success, trx.TransactionType,
+			success, trx.TransactionType,
+			success, trx.DeviceID,
+			success, trx.ClientIP,
+			constants.TransactionTypeRefund, query.RefundSince.In(time.Local)).
+		Where("user_id = ? AND id <> ? AND created_at >= ?", trx.UserID, trx.ID, query.Since.In(time.Local)).
+		Scan(&resp).Error
+
+	return resp, helpers.QueryError(ctx, err)
+}
@@ -0,0 +1,30 @@
+package transaction
+
+import (
+	"context"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+)
+
+func (r *repository) CreateStatusHistory(ctx context.Context, history *models.TransactionStatusHistory) error {
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Write)
+	defer cancel()
+
+	err := r.DB.WithContext(ctx).Create(history).Error
+
+	return helpers.QueryError(ctx, err)
+}
+
+// GetStatusHistory reads the statuses of a reference from the primary, oldest first,
+// an operator looking at a transaction right after changing it must see the change.
+func (r *repository) GetStatusHistory(ctx context.Context, reference string) ([]models.TransactionStatusHistory, error) {
+	var (
+		resp []models.TransactionStatusHistory
+	)
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Read)
+	defer cancel()
+
+	err := r.DB.WithContext(ctx).Where("reference = ?", reference).Order("created_at ASC, id ASC").Find(&resp).Error
+
+	return resp, helpers.QueryError(ctx, err)
+}
@@ -1,13 +1,23 @@
 package transaction
 
 import (
+	"ewallet-transaction/helpers"
+
 	"gorm.io/gorm"
 )
 
 type repository struct {
-	DB *gorm.DB
+	DB       *gorm.DB
+	ReadDB   *gorm.DB
+	Timeouts helpers.QueryTimeouts
 }
 
-func NewRepository(db *gorm.DB) *repository {
-	return &repository{DB: db}
+// NewRepository routes writes and consistency sensitive reads to db and history reads to replica,
+// replica may be nil to read everything from the primary.
+func NewRepository(db *gorm.DB, replica *gorm.DB, timeouts helpers.QueryTimeouts) *repository {
+	if replica == nil {
+		replica = db
+	}
+
+	return &repository{DB: db, ReadDB: replica, Timeouts: timeouts}
 }
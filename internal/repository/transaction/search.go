@@ -0,0 +1,116 @@
+package transaction
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// searchTermPattern keeps the words of a query, anything else could be an operator of the full-text syntax.
+var searchTermPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// likeEscaper escapes the wildcards of a LIKE pattern with '!', an escape char every dialect accepts.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// SearchTransactions ranks the transactions of every user matching the query, by an exact reference,
+// then a reference prefix, an equal amount and the relevance of the description. Descriptions are
+// matched by word prefix on the FULLTEXT index of mysql and the tsvector index of postgres, sqlite scans them with LIKE.
+// An empty query matches every transaction, newest first.
+func (r *repository) SearchTransactions(ctx context.Context, req models.SearchRequest) ([]models.SearchResult, int64, error) {
+	var (
+		resp  []models.SearchResult
+		total int64
+	)
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Read)
+	defer cancel()
+
+	match, matchArgs, score, scoreArgs := searchExpr(r.ReadDB.Dialector.Name(), req.Query)
+
+	query := func() *gorm.DB {
+		sql := r.ReadDB.WithContext(ctx).Model(&models.Transaction{})
+		if match != "" {
+			sql = sql.Where(match, matchArgs...)
+		}
+		if req.UserID != 0 {
+			sql = sql.Where("user_id = ?", req.UserID)
+		}
+		if req.TransactionType != "" {
+			sql = sql.Where("transaction_type = ?", req.TransactionType)
+		}
+		if req.TransactionStatus != "" {
+			sql = sql.Where("transaction_status = ?", req.TransactionStatus)
+		}
+		if !req.From.IsZero() {
+			sql = sql.Where("created_at >= ?", req.From)
+		}
+		if !req.To.IsZero() {
+			sql = sql.Where("created_at < ?", req.To)
+		}
+		return sql
+	}
+
+	err := query().Count(&total).Error
+	if err != nil || total == 0 {
+		return resp, total, helpers.QueryError(ctx, err)
+	}
+
+	err = query().Select("*, "+score+" AS score", scoreArgs...).
+		Order("score DESC, created_at DESC, id DESC").
+		Offset((req.Page - 1) * req.PageSize).Limit(req.PageSize).
+		Find(&resp).Error
+
+	return resp, total, helpers.QueryError(ctx, err)
+}
+
+// searchExpr builds the condition matching the query and the expression scoring a matched row.
+func searchExpr(dialect string, query string) (string, []interface{}, string, []interface{}) {
+	if query == "" {
+		return "", nil, "0", nil
+	}
+
+	prefix := likeEscaper.Replace(query) + "%"
+
+	conds := []string{"reference LIKE ? ESCAPE '!'"}
+	condArgs := []interface{}{prefix}
+	scores := []string{"CASE WHEN reference = ? THEN 100 WHEN reference LIKE ? ESCAPE '!' THEN 10 ELSE 0 END"}
+	scoreArgs := []interface{}{query, prefix}
+
+	if amount, err := strconv.ParseFloat(query, 64); err == nil && !math.IsInf(amount, 0) && !math.IsNaN(amount) {
+		conds = append(conds, "amount = ?")
+		condArgs = append(condArgs, amount)
+		scores = append(scores, "CASE WHEN amount = ? THEN 5 ELSE 0 END")
+		scoreArgs = append(scoreArgs, amount)
+	}
+
+	terms := searchTermPattern.FindAllString(query, -1)
+	switch {
+	case dialect == constants.DBDriverSQLite:
+		fragment := "%" + likeEscaper.Replace(query) + "%"
+		conds = append(conds, "description LIKE ? ESCAPE '!'")
+		condArgs = append(condArgs, fragment)
+		scores = append(scores, "CASE WHEN description LIKE ? ESCAPE '!' THEN 1 ELSE 0 END")
+		scoreArgs = append(scoreArgs, fragment)
+	case len(terms) == 0:
+	case dialect == constants.DBDriverPostgres:
+		tsquery := strings.Join(terms, ":* & ") + ":*"
+		conds = append(conds, "to_tsvector('simple', COALESCE(description, '')) @@ to_tsquery('simple', ?)")
+		condArgs = append(condArgs, tsquery)
+		scores = append(scores, "ts_rank(to_tsvector('simple', COALESCE(description, '')), to_tsquery('simple', ?))")
+		scoreArgs = append(scoreArgs, tsquery)
+	default:
+		against := "+" + strings.Join(terms, "* +") + "*"
+		conds = append(conds, "MATCH(description) AGAINST (? IN BOOLEAN MODE)")
+		condArgs = append(condArgs, against)
+		scores = append(scores, "MATCH(description) AGAINST (? IN BOOLEAN MODE)")
+		scoreArgs = append(scoreArgs, against)
+	}
+
+	return "(" + strings.Join(conds, " OR ") + ")", condArgs, strings.Join(scores, " + "), scoreArgs
+}
@@ -3,35 +3,382 @@ package transaction
 import (
 	"context"
 	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
 	"ewallet-transaction/internal/models"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+const tagBatchSize = 1000
+
 func (r *repository) CreateTransaction(ctx context.Context, trx *models.Transaction) error {
-	return r.DB.Create(trx).Error
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Write)
+	defer cancel()
+
+	db := helpers.Conn(ctx, r.DB)
+	// additional_info is a json column, empty is stored as NULL rather than an invalid ''
+	if trx.AdditionalInfo == "" {
+		db = db.Omit("additional_info")
+	}
+
+	// the tags are written in the same db transaction, a failed create never leaves orphans. Under
+	// LockUserLimits it is a savepoint, a duplicate reference doesn't abort the lock transaction.
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(trx).Error; err != nil {
+			return err
+		}
+		if len(trx.Tags) == 0 {
+			return nil
+		}
+
+		tags := make([]models.TransactionTag, len(trx.Tags))
+		for i, tag := range trx.Tags {
+			tags[i] = models.TransactionTag{TransactionID: trx.ID, Tag: tag}
+		}
+		return tx.Create(&tags).Error
+	})
+
+	return helpers.QueryError(ctx, err)
 }
 
+// GetTransactionByReference reads the transaction and its tags from the primary, the events published after
+// an update carry them.
 func (r *repository) GetTransactionByReference(ctx context.Context, reference string, includeRefund bool) (models.Transaction, error) {
 	var (
 		resp models.Transaction
 	)
-	sql := r.DB.Where("reference = ?", reference)
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Read)
+	defer cancel()
+
+	sql := r.DB.WithContext(ctx).Where("reference = ?", reference)
 	if !includeRefund {
 		sql = sql.Where("transaction_type != ?", constants.TransactionTypeRefund)
 	}
 	err := sql.Last(&resp).Error
+	if err != nil {
+		return resp, helpers.QueryError(ctx, err)
+	}
 
-	return resp, err
+	trxs := []models.Transaction{resp}
+	err = r.loadTags(ctx, r.DB, trxs)
+
+	return trxs[0], helpers.QueryError(ctx, err)
 }
 
-func (r *repository) UpdateStatusTransaction(ctx context.Context, reference string, status string, additionalInfo string) error {
-	return r.DB.Exec("UPDATE transactions SET transaction_status = ?, additional_info = ? WHERE reference = ?", status, additionalInfo, reference).Error
+func (r *repository) GetTransactionDetail(ctx context.Context, reference string) (models.Transaction, error) {
+	var (
+		resp models.Transaction
+	)
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Read)
+	defer cancel()
+
+	err := r.ReadDB.WithContext(ctx).Where("reference = ?", reference).Last(&resp).Error
+	if err != nil {
+		return resp, helpers.QueryError(ctx, err)
+	}
+
+	trxs := []models.Transaction{resp}
+	err = r.loadTags(ctx, r.ReadDB, trxs)
+
+	return trxs[0], helpers.QueryError(ctx, err)
 }
 
-func (r *repository) GetTransaction(ctx context.Context, userID uint64) ([]models.Transaction, error) {
+// UpdateStatusTransaction moves the transaction, never its refund, from currentStatus to status. The current
+// status is compared in the same statement, when another update changed it first nothing is written and
+// ErrStatusConflict is returned.
+func (r *repository) UpdateStatusTransaction(ctx context.Context, reference string, currentStatus string, status string, additionalInfo string) error {
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Write)
+	defer cancel()
+
+	var info interface{} = additionalInfo
+	if additionalInfo == "" {
+		info = nil
+	}
+
+	result := helpers.Conn(ctx, r.DB).Model(&models.Transaction{}).
+		Where("reference = ? AND transaction_type != ? AND transaction_status = ?", reference, constants.TransactionTypeRefund, currentStatus).
+		Updates(map[string]interface{}{
+			"transaction_status": status,
+			"additional_info":    info,
+		})
+	if result.Error != nil {
+		return helpers.QueryError(ctx, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return models.ErrStatusConflict
+	}
+
+	return nil
+}
+
+func (r *repository) GetTransaction(ctx context.Context, userID uint64, filter models.TransactionFilter) ([]models.Transaction, error) {
 	var (
 		resp []models.Transaction
 	)
-	err := r.DB.Order("id DESC").Where("user_id = ?", userID).Find(&resp).Error
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Read)
+	defer cancel()
+
+	sql := r.ReadDB.WithContext(ctx).Order("created_at DESC, id DESC").Where("user_id = ?", userID)
+	if filter.Category != "" {
+		sql = sql.Where("category = ?", filter.Category)
+	}
+	if filter.MerchantID != 0 {
+		sql = sql.Where("merchant_id = ?", filter.MerchantID)
+	}
+	if filter.Tag != "" {
+		sql = sql.Where("id IN (?)", r.ReadDB.Model(&models.TransactionTag{}).Select("transaction_id").Where("tag = ?", filter.Tag))
+	}
+
+	err := sql.Find(&resp).Error
+	if err != nil {
+		return resp, helpers.QueryError(ctx, err)
+	}
+
+	err = r.loadTags(ctx, r.ReadDB, resp)
+
+	return resp, helpers.QueryError(ctx, err)
+}
+
+// loadTags fills the tags of the transactions, in batches to stay under the bind var limits.
+func (r *repository) loadTags(ctx context.Context, db *gorm.DB, trxs []models.Transaction) error {
+	index := make(map[int]int, len(trxs))
+	ids := make([]int, len(trxs))
+	for i, trx := range trxs {
+		index[trx.ID] = i
+		ids[i] = trx.ID
+	}
+
+	for start := 0; start < len(ids); start += tagBatchSize {
+		end := min(start+tagBatchSize, len(ids))
+
+		var tags []models.TransactionTag
+		err := db.WithContext(ctx).Where("transaction_id IN ?", ids[start:end]).Order("transaction_id, tag").Find(&tags).Error
+		if err != nil {
+			return err
+		}
+
+		for _, tag := range tags {
+			i := index[tag.TransactionID]
+			trxs[i].Tags = append(trxs[i].Tags, tag.Tag)
+		}
+	}
+
+	return nil
+}
+
+// GetPendingTransactions reads from the primary at most limit transactions still PENDING
+// that were created before the given time, oldest first.
+func (r *repository) GetPendingTransactions(ctx context.Context, before time.Time, limit int) ([]models.Transaction, error) {
+	var (
+		resp []models.Transaction
+	)
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Read)
+	defer cancel()
+
+	err := r.DB.WithContext(ctx).Where("transaction_status = ? AND created_at < ?", constants.TransactionStatusPending, before).
+		Order("created_at ASC, id ASC").Limit(limit).Find(&resp).Error
+
+	return resp, helpers.QueryError(ctx, err)
+}
+
+// GetLimitUsage adds up from the primary the transactions of a user and a type in the given statuses,
+// created since the start of the month and since the start of the day. The bounds are compared in the
+// server zone the timestamps are written in, sqlite compares them as text.
+// LockUserLimits runs fn in a db transaction holding the limit lock of the user, a concurrent call for
+// the same user waits until fn returns. The limit usage read and the writes fn makes through the
+// context it is given are committed together, or rolled back when fn returns an error.
+func (r *repository) LockUserLimits(ctx context.Context, userID uint64, fn func(ctx context.Context) error) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		lockCtx, cancel := helpers.QueryContext(ctx, r.Timeouts.Write)
+		defer cancel()
+
+		lock := models.UserLimitLock{UserID: userID}
+		err := tx.WithContext(lockCtx).Clauses(clause.OnConflict{DoNothing: true}).Create(&lock).Error
+		if err != nil {
+			return helpers.QueryError(lockCtx, err)
+		}
+
+		err = tx.WithContext(lockCtx).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).First(&lock).Error
+		if err != nil {
+			return helpers.QueryError(lockCtx, err)
+		}
+
+		return fn(helpers.WithTx(ctx, tx))
+	})
+}
+
+func (r *repository) GetLimitUsage(ctx context.Context, userID uint64, transactionType string, statuses []string, dayStart time.Time, monthStart time.Time) (models.LimitUsage, error) {
+	var (
+		resp models.LimitUsage
+	)
+	dayStart, monthStart = dayStart.In(time.Local), monthStart.In(time.Local)
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Read)
+	defer cancel()
+
+	err := helpers.Conn(ctx, r.DB).Model(&models.Transaction{}).
+		Select("COUNT(*) AS monthly_count, COALESCE(SUM(amount), 0) AS monthly_amount, "+
+			"COALESCE(SUM(CASE WHEN created_at >= ? THEN 1 ELSE 0 END), 0) AS daily_count, "+
+			"COALESCE(SUM(CASE WHEN created_at >= ? THEN amount ELSE 0 END), 0) AS daily_amount", dayStart, dayStart).
+		Where("user_id = ? AND transaction_type = ? AND transaction_status IN ? AND created_at >= ?", userID, transactionType, statuses, monthStart).
+		Scan(&resp).Error
+
+	return resp, helpers.QueryError(ctx, err)
+}
+
+// GetTransactionsByTimeRange reads the transactions created in [from, to) from the primary,
+// reconciliation must not miss rows the replica hasn't caught up with.
+func (r *repository) GetTransactionsByTimeRange(ctx context.Context, from time.Time, to time.Time) ([]models.Transaction, error) {
+	var (
+		resp []models.Transaction
+	)
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Read)
+	defer cancel()
+
+	err := r.DB.WithContext(ctx).Order("created_at ASC, id ASC").Where("created_at >= ? AND created_at < ?", from, to).Find(&resp).Error
+
+	return resp, helpers.QueryError(ctx, err)
+}
+
+// StreamTransactions calls fn for each transaction of the user created in [from, to), oldest first,
+// reading row by row so an export never holds the whole history in memory.
+// The read timeout only bounds the query until its first row, fn then writes the rows out
+// for as long as the request lasts, a long export to a slow client is not cut.
+func (r *repository) StreamTransactions(ctx context.Context, userID uint64, from time.Time, to time.Time, fn func(models.Transaction) error) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var timer *time.Timer
+	if r.Timeouts.Read > 0 {
+		timer = time.AfterFunc(r.Timeouts.Read, func() {
+			cancel(context.DeadlineExceeded)
+		})
+		defer timer.Stop()
+	}
+
+	db := r.ReadDB.WithContext(ctx)
+	rows, err := db.Model(&models.Transaction{}).Order("created_at ASC, id ASC").
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, from, to).Rows()
+	if err != nil {
+		return helpers.QueryError(ctx, err)
+	}
+	defer rows.Close()
+
+	for first := true; rows.Next(); first = false {
+		if first && timer != nil {
+			timer.Stop()
+		}
+
+		var trx models.Transaction
+		if err := db.ScanRows(rows, &trx); err != nil {
+			return helpers.QueryError(ctx, err)
+		}
+		if err := fn(trx); err != nil {
+			return err
+		}
+	}
+
+	return helpers.QueryError(ctx, rows.Err())
+}
+
+// GetBalanceBefore sums the successful transactions of the user created before the given time,
+// purchases debit the wallet while topups and refunds credit it.
+func (r *repository) GetBalanceBefore(ctx context.Context, userID uint64, before time.Time) (float64, error) {
+	var (
+		resp float64
+	)
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Read)
+	defer cancel()
+
+	err := r.ReadDB.WithContext(ctx).Model(&models.Transaction{}).
+		Select("COALESCE(SUM(CASE WHEN transaction_type = ? THEN -amount ELSE amount END), 0)", constants.TransactionTypePurchase).
+		Where("user_id = ? AND transaction_status = ? AND created_at < ?", userID, constants.TransactionStatusSuccess, before).
+		Scan(&resp).Error
+
+	return resp, helpers.QueryError(ctx, err)
+}
+
+// GetSummaryBuckets aggregates the successful transactions of the user per type and per bucket,
+// bucket i covers [bounds[i], bounds[i+1]). The bounds are computed by the caller in the user's
+// timezone, so one portable query groups by local day on every dialect.
+func (r *repository) GetSummaryBuckets(ctx context.Context, userID uint64, bounds []time.Time) ([]models.SummaryBucket, error) {
+	var (
+		resp []models.SummaryBucket
+	)
+	if len(bounds) < 2 {
+		return resp, nil
+	}
+
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Read)
+	defer cancel()
+
+	// timestamps are written in the server zone, sqlite compares them as text
+	local := make([]time.Time, len(bounds))
+	for i, bound := range bounds {
+		local[i] = bound.In(time.Local)
+	}
+
+	var (
+		bucket strings.Builder
+		args   []interface{}
+	)
+	bucket.WriteString("CASE")
+	for i, end := range local[1 : len(local)-1] {
+		bucket.WriteString(" WHEN created_at < ? THEN " + strconv.Itoa(i))
+		args = append(args, end)
+	}
+	bucket.WriteString(" ELSE " + strconv.Itoa(len(local)-2) + " END")
+
+	err := r.ReadDB.WithContext(ctx).Model(&models.Transaction{}).
+		Select(bucket.String()+" AS bucket, transaction_type, COUNT(*) AS count, SUM(amount) AS amount", args...).
+		Where("user_id = ? AND transaction_status = ? AND created_at >= ? AND created_at < ?", userID, constants.TransactionStatusSuccess, local[0], local[len(local)-1]).
+		Group("bucket, transaction_type").
+		Order("bucket, transaction_type").
+		Scan(&resp).Error
+
+	return resp, helpers.QueryError(ctx, err)
+}
+
+// GetSummaryGroups aggregates the successful purchases of the user created in [from, to)
+// per category, merchant or tag. A purchase with several tags counts once for each of them
+// and untagged purchases are left out of the tag groups.
+func (r *repository) GetSummaryGroups(ctx context.Context, userID uint64, from time.Time, to time.Time, groupBy string) ([]models.SummaryGroup, error) {
+	var (
+		resp []models.SummaryGroup
+	)
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Read)
+	defer cancel()
+
+	sql := r.ReadDB.WithContext(ctx).Model(&models.Transaction{})
+	switch groupBy {
+	case constants.SummaryGroupByCategory:
+		sql = sql.Select("COALESCE(transactions.category, '') AS group_key, MAX(transaction_categories.name) AS group_name, COUNT(*) AS count, SUM(transactions.amount) AS amount").
+			Joins("LEFT JOIN transaction_categories ON transaction_categories.code = transactions.category")
+	case constants.SummaryGroupByMerchant:
+		sql = sql.Select("COALESCE(transactions.merchant_id, 0) AS group_key, MAX(transactions.merchant_name) AS group_name, COUNT(*) AS count, SUM(transactions.amount) AS amount")
+	case constants.SummaryGroupByTag:
+		sql = sql.Select("transaction_tags.tag AS group_key, COUNT(*) AS count, SUM(transactions.amount) AS amount").
+			Joins("JOIN transaction_tags ON transaction_tags.transaction_id = transactions.id")
+	default:
+		return resp, fmt.Errorf("unknown summary group %q", groupBy)
+	}
+
+	// timestamps are written in the server zone, sqlite compares them as text
+	err := sql.Where("transactions.user_id = ? AND transactions.transaction_type = ? AND transactions.transaction_status = ? AND transactions.created_at >= ? AND transactions.created_at < ?",
+		userID, constants.TransactionTypePurchase, constants.TransactionStatusSuccess, from.In(time.Local), to.In(time.Local)).
+		Group("group_key").
+		Order("amount DESC, group_key").
+		Scan(&resp).Error
+
+	for i := range resp {
+		if groupBy == constants.SummaryGroupByMerchant && resp[i].Key == "0" {
+			resp[i].Key = ""
+		}
+	}
 
-	return resp, err
+	return resp, helpers.QueryError(ctx, err)
 }
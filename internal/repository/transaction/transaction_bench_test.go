@@ -0,0 +1,188 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/migration"
+	"ewallet-transaction/internal/models"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// The query plan suite runs against a real MySQL database given by TEST_MYSQL_DSN, e.g.
+// TEST_MYSQL_DSN="root:root@tcp(127.0.0.1:3306)/ewallet_test?parseTime=true" go test -run Plan -bench . ./internal/repository/transaction/
+// The transactions table of that database is truncated and seeded, never point it at real data.
+const (
+	seedUsers        = 200
+	seedTransactions = 20000
+)
+
+type explainRow struct {
+	Table        string
+	Type         string
+	PossibleKeys sql.NullString
+	Key          sql.NullString
+	Rows         int64
+	Extra        sql.NullString
+}
+
+func setupSeededDB(tb testing.TB) *gorm.DB {
+	tb.Helper()
+
+	dsn := os.Getenv("TEST_MYSQL_DSN")
+	if dsn == "" {
+		tb.Skip("TEST_MYSQL_DSN is not set")
+	}
+
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	m, err := migration.NewMigrator(sqlDB, constants.DBDriverMySQL)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if _, err := m.Up(context.Background()); err != nil {
+		tb.Fatal(err)
+	}
+
+	if err := db.Exec("TRUNCATE TABLE transactions").Error; err != nil {
+		tb.Fatal(err)
+	}
+
+	types := []string{constants.TransactionTypeTopup, constants.TransactionTypePurchase}
+	start := time.Now().Add(-seedTransactions * time.Minute)
+	trxs := make([]models.Transaction, 0, seedTransactions)
+	for i := 0; i < seedTransactions; i++ {
+		status := constants.TransactionStatusSuccess
+		// keep pending rare like in production, most transactions settle within minutes
+		if i%50 == 0 {
+			status = constants.TransactionStatusPending
+		}
+
+		createdAt := start.Add(time.Duration(i) * time.Minute)
+		trxs = append(trxs, models.Transaction{
+			UserID:            uint64(i%seedUsers + 1),
+			Amount:            float64(i%1000) * 100,
+			TransactionType:   types[i%len(types)],
+			TransactionStatus: status,
+			Reference:         fmt.Sprintf("REF-%08d", i),
+			Description:       "seed",
+			CreatedAt:         createdAt,
+			UpdatedAt:         createdAt,
+		})
+	}
+	if err := db.CreateInBatches(trxs, 1000).Error; err != nil {
+		tb.Fatal(err)
+	}
+
+	if err := db.Exec("ANALYZE TABLE transactions").Error; err != nil {
+		tb.Fatal(err)
+	}
+
+	return db
+}
+
+func explain(tb testing.TB, db *gorm.DB, query string, args ...interface{}) explainRow {
+	tb.Helper()
+
+	var rows []explainRow
+	if err := db.Raw("EXPLAIN "+query, args...).Scan(&rows).Error; err != nil {
+		tb.Fatal(err)
+	}
+	if len(rows) != 1 {
+		tb.Fatalf("expected a single table plan, got %d rows", len(rows))
+	}
+
+	return rows[0]
+}
+
+func TestTransactionQueryPlans(t *testing.T) {
+	db := setupSeededDB(t)
+
+	tests := []struct {
+		name    string
+		query   string
+		args    []interface{}
+		wantKey string
+	}{
+		{
+			name:    "get transaction by reference",
+			query:   "SELECT * FROM `transactions` WHERE reference = ? AND transaction_type != ? ORDER BY `transactions`.`id` DESC LIMIT 1",
+			args:    []interface{}{"REF-00001234", constants.TransactionTypeRefund},
+			wantKey: "idx_transactions_reference_type",
+		},
+		{
+			name:    "get transaction by reference include refund",
+			query:   "SELECT * FROM `transactions` WHERE reference = ? ORDER BY `transactions`.`id` DESC LIMIT 1",
+			args:    []interface{}{"REF-00001234"},
+			wantKey: "idx_transactions_reference_type",
+		},
+		{
+			name:    "update status by reference",
+			query:   "UPDATE transactions SET transaction_status = ?, additional_info = ? WHERE reference = ?",
+			args:    []interface{}{constants.TransactionStatusSuccess, nil, "REF-00001234"},
+			wantKey: "idx_transactions_reference_type",
+		},
+		{
+			name:    "get transaction of user",
+			query:   "SELECT * FROM `transactions` WHERE user_id = ? ORDER BY created_at DESC, id DESC",
+			args:    []interface{}{42},
+			wantKey: "idx_transactions_user_created",
+		},
+		{
+			name:    "expire pending transactions",
+			query:   "SELECT * FROM `transactions` WHERE transaction_status = ? AND created_at < ?",
+			args:    []interface{}{constants.TransactionStatusPending, time.Now().Add(-time.Hour)},
+			wantKey: "idx_transactions_status_created",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := explain(t, db, tt.query, tt.args...)
+			assert.Equal(t, tt.wantKey, plan.Key.String, "plan: %+v", plan)
+			assert.NotEqual(t, "ALL", plan.Type, "plan: %+v", plan)
+			assert.False(t, strings.Contains(plan.Extra.String, "Using filesort"), "plan: %+v", plan)
+		})
+	}
+}
+
+func BenchmarkRepository_GetTransactionByReference(b *testing.B) {
+	r := NewRepository(setupSeededDB(b), nil, helpers.QueryTimeouts{})
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := r.GetTransactionByReference(ctx, fmt.Sprintf("REF-%08d", i%seedTransactions), false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRepository_GetTransaction(b *testing.B) {
+	r := NewRepository(setupSeededDB(b), nil, helpers.QueryTimeouts{})
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := r.GetTransaction(ctx, uint64(i%seedUsers+1), models.TransactionFilter{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
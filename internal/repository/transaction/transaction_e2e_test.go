@@ -0,0 +1,486 @@
+package transaction
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/migration/migrationtest"
+	"ewallet-transaction/internal/models"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func Test_repository_E2E(t *testing.T) {
+	ctx := context.Background()
+	r := NewRepository(migrationtest.NewSQLiteDB(t), nil, helpers.QueryTimeouts{})
+
+	topup := &models.Transaction{
+		UserID:            1,
+		Amount:            100000,
+		TransactionType:   constants.TransactionTypeTopup,
+		TransactionStatus: constants.TransactionStatusPending,
+		Reference:         "REFERENCE",
+		Description:       "DESCRIPTION",
+	}
+	assert.NoError(t, r.CreateTransaction(ctx, topup))
+	assert.NotZero(t, topup.ID)
+
+	refund := &models.Transaction{
+		UserID:            1,
+		Amount:            100000,
+		TransactionType:   constants.TransactionTypeRefund,
+		TransactionStatus: constants.TransactionStatusSuccess,
+		Reference:         "REFERENCE",
+		Description:       "DESCRIPTION",
+	}
+	assert.NoError(t, r.CreateTransaction(ctx, refund))
+
+	duplicate := *topup
+	duplicate.ID = 0
+	assert.ErrorIs(t, r.CreateTransaction(ctx, &duplicate), gorm.ErrDuplicatedKey, "reference and type must be unique")
+	duplicate.ID = 0
+	duplicate.Tags = []string{"trip"}
+	assert.ErrorIs(t, r.CreateTransaction(ctx, &duplicate), gorm.ErrDuplicatedKey, "with tags")
+
+	other := &models.Transaction{
+		UserID:            2,
+		Amount:            5000,
+		TransactionType:   constants.TransactionTypePurchase,
+		TransactionStatus: constants.TransactionStatusSuccess,
+		Reference:         "OTHER",
+		Description:       "DESCRIPTION",
+		MerchantID:        9,
+		MerchantName:      "MERCHANT",
+		Category:          "FOOD_AND_DRINK",
+		Tags:              []string{"trip", "work"},
+	}
+	assert.NoError(t, r.CreateTransaction(ctx, other))
+
+	got, err := r.GetTransactionByReference(ctx, "REFERENCE", false)
+	assert.NoError(t, err)
+	assert.Equal(t, topup.ID, got.ID)
+	assert.Equal(t, 100000.0, got.Amount)
+
+	got, err = r.GetTransactionByReference(ctx, "REFERENCE", true)
+	assert.NoError(t, err)
+	assert.Equal(t, refund.ID, got.ID)
+
+	got, err = r.GetTransactionDetail(ctx, "OTHER")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(9), got.MerchantID)
+	assert.Equal(t, "MERCHANT", got.MerchantName)
+	assert.Equal(t, "FOOD_AND_DRINK", got.Category)
+	assert.Equal(t, []string{"trip", "work"}, got.Tags)
+
+	for _, filter := range []models.TransactionFilter{
+		{Category: "FOOD_AND_DRINK"},
+		{MerchantID: 9},
+		{Tag: "work"},
+		{Category: "FOOD_AND_DRINK", MerchantID: 9, Tag: "trip"},
+	} {
+		filtered, err := r.GetTransaction(ctx, 2, filter)
+		assert.NoError(t, err)
+		if assert.Len(t, filtered, 1, "%+v", filter) {
+			assert.Equal(t, other.ID, filtered[0].ID)
+			assert.Equal(t, []string{"trip", "work"}, filtered[0].Tags)
+		}
+	}
+
+	filtered, err := r.GetTransaction(ctx, 2, models.TransactionFilter{Tag: "holiday"})
+	assert.NoError(t, err)
+	assert.Empty(t, filtered)
+
+	categories, err := r.ListCategories(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, categories, 8, "seeded by the migration")
+
+	category, err := r.GetCategory(ctx, "FOOD_AND_DRINK")
+	assert.NoError(t, err)
+	assert.Equal(t, "Makanan & Minuman", category.Name)
+
+	_, err = r.GetCategory(ctx, "UNKNOWN")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	_, err = r.GetTransactionByReference(ctx, "MISSING", true)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	pending, err := r.GetPendingTransactions(ctx, other.CreatedAt.Add(time.Second), 10)
+	assert.NoError(t, err)
+	if assert.Len(t, pending, 1) {
+		assert.Equal(t, topup.ID, pending[0].ID)
+	}
+
+	pending, err = r.GetPendingTransactions(ctx, topup.CreatedAt, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, pending, "created before is exclusive")
+
+	time.Sleep(time.Millisecond)
+	err = r.UpdateStatusTransaction(ctx, "REFERENCE", constants.TransactionStatusPending, constants.TransactionStatusSuccess, `{"note":"done"}`)
+	assert.NoError(t, err)
+
+	err = r.UpdateStatusTransaction(ctx, "REFERENCE", constants.TransactionStatusPending, constants.TransactionStatusFailed, "")
+	assert.ErrorIs(t, err, models.ErrStatusConflict, "the status was read before the first update")
+
+	got, err = r.GetTransactionByReference(ctx, "REFERENCE", false)
+	assert.NoError(t, err)
+	assert.Equal(t, constants.TransactionStatusSuccess, got.TransactionStatus)
+	assert.Equal(t, `{"note":"done"}`, got.AdditionalInfo)
+	assert.True(t, got.UpdatedAt.After(topup.UpdatedAt))
+
+	var noted int64
+	err = r.ReadDB.Model(&models.Transaction{}).Where("json_extract(additional_info, '$.note') = ?", "done").Count(&noted).Error
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), noted, "additional_info fields are queryable, the refund sharing the reference is not updated")
+
+	var empty int64
+	err = r.ReadDB.Model(&models.Transaction{}).Where("reference = ? AND additional_info IS NULL", "OTHER").Count(&empty).Error
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), empty, "empty additional_info is stored as NULL")
+
+	history, err := r.GetTransaction(ctx, 1, models.TransactionFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, refund.ID, history[0].ID, "newest first")
+
+	history, err = r.GetTransaction(ctx, 3, models.TransactionFilter{})
+	assert.NoError(t, err)
+	assert.Empty(t, history)
+
+	window, err := r.GetTransactionsByTimeRange(ctx, topup.CreatedAt, other.CreatedAt)
+	assert.NoError(t, err)
+	assert.Len(t, window, 2, "the end of the window is exclusive")
+	assert.Equal(t, topup.ID, window[0].ID)
+
+	window, err = r.GetTransactionsByTimeRange(ctx, other.CreatedAt.Add(time.Second), other.CreatedAt.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Empty(t, window)
+
+	var streamed []int
+	err = r.StreamTransactions(ctx, 1, topup.CreatedAt, refund.CreatedAt.Add(time.Second), func(trx models.Transaction) error {
+		streamed = append(streamed, trx.ID)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{topup.ID, refund.ID}, streamed, "oldest first")
+
+	err = r.StreamTransactions(ctx, 1, topup.CreatedAt, refund.CreatedAt.Add(time.Second), func(trx models.Transaction) error {
+		return assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+
+	// the read timeout doesn't cut a stream written out slower than it
+	slow := NewRepository(r.DB, nil, helpers.QueryTimeouts{Read: 20 * time.Millisecond})
+	streamed = nil
+	err = slow.StreamTransactions(ctx, 1, topup.CreatedAt, refund.CreatedAt.Add(time.Second), func(trx models.Transaction) error {
+		time.Sleep(30 * time.Millisecond)
+		streamed = append(streamed, trx.ID)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, streamed, 2)
+
+	found, total, err := r.SearchTransactions(ctx, models.SearchRequest{Query: "REFER", Page: 1, PageSize: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total, "reference prefix")
+	assert.Len(t, found, 1, "one page")
+
+	found, total, err = r.SearchTransactions(ctx, models.SearchRequest{Query: "REFERENCE", TransactionType: constants.TransactionTypeRefund, Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	if assert.Len(t, found, 1) {
+		assert.Equal(t, refund.ID, found[0].ID)
+		assert.Equal(t, 100.0, found[0].Score, "exact reference")
+	}
+
+	found, total, err = r.SearchTransactions(ctx, models.SearchRequest{Query: "scrip", UserID: 2, Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total, "description fragment")
+	if assert.Len(t, found, 1) {
+		assert.Equal(t, other.ID, found[0].ID)
+	}
+
+	found, _, err = r.SearchTransactions(ctx, models.SearchRequest{Query: "5000", Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	if assert.Len(t, found, 1, "amount") {
+		assert.Equal(t, other.ID, found[0].ID)
+	}
+
+	found, total, err = r.SearchTransactions(ctx, models.SearchRequest{Query: "%", Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	assert.Zero(t, total, "wildcards are matched literally")
+	assert.Empty(t, found)
+
+	found, total, err = r.SearchTransactions(ctx, models.SearchRequest{UserID: 1, Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total, "no query lists every transaction")
+	if assert.Len(t, found, 2) {
+		assert.Equal(t, refund.ID, found[0].ID, "newest first")
+	}
+
+	for _, status := range []string{constants.TransactionStatusPending, constants.TransactionStatusSuccess} {
+		err = r.CreateStatusHistory(ctx, &models.TransactionStatusHistory{
+			TransactionID: topup.ID,
+			Reference:     topup.Reference,
+			ToStatus:      status,
+			Actor:         "user:1",
+		})
+		assert.NoError(t, err)
+	}
+
+	statuses, err := r.GetStatusHistory(ctx, topup.Reference)
+	assert.NoError(t, err)
+	if assert.Len(t, statuses, 2) {
+		assert.Equal(t, constants.TransactionStatusPending, statuses[0].ToStatus, "oldest first")
+		assert.Equal(t, constants.TransactionStatusSuccess, statuses[1].ToStatus)
+	}
+
+	statuses, err = r.GetStatusHistory(ctx, "MISSING")
+	assert.NoError(t, err)
+	assert.Empty(t, statuses)
+
+	purchase := &models.Transaction{
+		UserID:            1,
+		Amount:            2500.50,
+		TransactionType:   constants.TransactionTypePurchase,
+		TransactionStatus: constants.TransactionStatusSuccess,
+		Reference:         "PURCHASE",
+		Description:       "DESCRIPTION",
+	}
+	assert.NoError(t, r.CreateTransaction(ctx, purchase))
+
+	balance, err := r.GetBalanceBefore(ctx, 1, purchase.CreatedAt.Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, 197499.50, balance, "topup and refund credit, purchase debits")
+
+	balance, err = r.GetBalanceBefore(ctx, 1, topup.CreatedAt)
+	assert.NoError(t, err)
+	assert.Zero(t, balance)
+
+	// the topup and the refund fall in the first bucket, the purchase in the second
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	bounds := []time.Time{
+		topup.CreatedAt.In(jakarta),
+		purchase.CreatedAt.In(jakarta),
+		purchase.CreatedAt.Add(time.Second).In(jakarta),
+	}
+	buckets, err := r.GetSummaryBuckets(ctx, 1, bounds)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.SummaryBucket{
+		{Bucket: 0, TransactionType: constants.TransactionTypeRefund, Count: 1, Amount: 100000},
+		{Bucket: 0, TransactionType: constants.TransactionTypeTopup, Count: 1, Amount: 100000},
+		{Bucket: 1, TransactionType: constants.TransactionTypePurchase, Count: 1, Amount: 2500.50},
+	}, buckets)
+
+	buckets, err = r.GetSummaryBuckets(ctx, 3, bounds)
+	assert.NoError(t, err)
+	assert.Empty(t, buckets)
+
+	lunch := &models.Transaction{
+		UserID:            2,
+		Amount:            1000,
+		TransactionType:   constants.TransactionTypePurchase,
+		TransactionStatus: constants.TransactionStatusSuccess,
+		Reference:         "LUNCH",
+		Description:       "DESCRIPTION",
+		Tags:              []string{"work"},
+	}
+	assert.NoError(t, r.CreateTransaction(ctx, lunch))
+
+	from, to := other.CreatedAt.In(jakarta), lunch.CreatedAt.Add(time.Second).In(jakarta)
+	groups, err := r.GetSummaryGroups(ctx, 2, from, to, constants.SummaryGroupByCategory)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.SummaryGroup{
+		{Key: "FOOD_AND_DRINK", Name: "Makanan & Minuman", Count: 1, Amount: 5000},
+		{Key: "", Count: 1, Amount: 1000},
+	}, groups)
+
+	groups, err = r.GetSummaryGroups(ctx, 2, from, to, constants.SummaryGroupByMerchant)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.SummaryGroup{
+		{Key: "9", Name: "MERCHANT", Count: 1, Amount: 5000},
+		{Key: "", Count: 1, Amount: 1000},
+	}, groups)
+
+	groups, err = r.GetSummaryGroups(ctx, 2, from, to, constants.SummaryGroupByTag)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.SummaryGroup{
+		{Key: "work", Count: 2, Amount: 6000},
+		{Key: "trip", Count: 1, Amount: 5000},
+	}, groups)
+}
+
+func Test_repository_GetLimitUsage_E2E(t *testing.T) {
+	ctx := context.Background()
+	db := migrationtest.NewSQLiteDB(t)
+	r := NewRepository(db, nil, helpers.QueryTimeouts{})
+
+	// the days and months of the limits are in the zone of the user, the timestamps in the server zone
+	now := time.Now().In(time.FixedZone("WIB", 7*60*60))
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	create := func(reference string, trxType string, status string, amount float64, createdAt time.Time) {
+		trx := &models.Transaction{
+			UserID:            1,
+			Amount:            amount,
+			TransactionType:   trxType,
+			TransactionStatus: status,
+			Reference:         reference,
+			CreatedAt:         createdAt.In(time.Local),
+		}
+		assert.NoError(t, r.CreateTransaction(ctx, trx))
+	}
+	create("TODAY-1", constants.TransactionTypeTopup, constants.TransactionStatusSuccess, 100000, dayStart.Add(time.Minute))
+	create("TODAY-2", constants.TransactionTypeTopup, constants.TransactionStatusPending, 50000, dayStart.Add(2*time.Minute))
+	create("TODAY-3", constants.TransactionTypeTopup, constants.TransactionStatusFailed, 70000, dayStart.Add(3*time.Minute))
+	create("TODAY-4", constants.TransactionTypePurchase, constants.TransactionStatusSuccess, 20000, dayStart.Add(4*time.Minute))
+	create("MONTH-1", constants.TransactionTypeTopup, constants.TransactionStatusSuccess, 30000, monthStart.Add(-time.Minute).AddDate(0, 0, 1))
+	create("LAST-MONTH", constants.TransactionTypeTopup, constants.TransactionStatusSuccess, 90000, monthStart.Add(-time.Minute))
+
+	usage, err := r.GetLimitUsage(ctx, 1, constants.TransactionTypeTopup,
+		[]string{constants.TransactionStatusPending, constants.TransactionStatusSuccess}, dayStart, monthStart)
+	assert.NoError(t, err)
+	if now.Day() == 1 {
+		// MONTH-1 is created today
+		assert.Equal(t, models.LimitUsage{DailyCount: 3, DailyAmount: 180000, MonthlyCount: 3, MonthlyAmount: 180000}, usage)
+	} else {
+		assert.Equal(t, models.LimitUsage{DailyCount: 2, DailyAmount: 150000, MonthlyCount: 3, MonthlyAmount: 180000}, usage)
+	}
+
+	usage, err = r.GetLimitUsage(ctx, 1, constants.TransactionTypePurchase, []string{constants.TransactionStatusSuccess}, dayStart, monthStart)
+	assert.NoError(t, err)
+	assert.Equal(t, models.LimitUsage{DailyCount: 1, DailyAmount: 20000, MonthlyCount: 1, MonthlyAmount: 20000}, usage)
+
+	usage, err = r.GetLimitUsage(ctx, 2, constants.TransactionTypeTopup, []string{constants.TransactionStatusSuccess}, dayStart, monthStart)
+	assert.NoError(t, err)
+	assert.Equal(t, models.LimitUsage{}, usage)
+}
+
+func Test_repository_LockUserLimits_E2E(t *testing.T) {
+	ctx := context.Background()
+	db := migrationtest.NewSQLiteDB(t)
+	r := NewRepository(db, nil, helpers.QueryTimeouts{})
+
+	monthStart := time.Now().AddDate(0, -1, 0)
+	statuses := []string{constants.TransactionStatusPending, constants.TransactionStatusSuccess}
+	topup := func(reference string) *models.Transaction {
+		return &models.Transaction{
+			UserID:            1,
+			Amount:            100000,
+			TransactionType:   constants.TransactionTypeTopup,
+			TransactionStatus: constants.TransactionStatusPending,
+			Reference:         reference,
+		}
+	}
+
+	// every caller reads the usage and inserts under the lock, at most 3 fit in the limit
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := r.LockUserLimits(ctx, 1, func(ctx context.Context) error {
+				usage, err := r.GetLimitUsage(ctx, 1, constants.TransactionTypeTopup, statuses, monthStart, monthStart)
+				if err != nil || usage.MonthlyCount >= 3 {
+					return err
+				}
+				return r.CreateTransaction(ctx, topup(fmt.Sprintf("CONCURRENT-%d", i)))
+			})
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	usage, err := r.GetLimitUsage(ctx, 1, constants.TransactionTypeTopup, statuses, monthStart, monthStart)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), usage.MonthlyCount)
+
+	// a duplicate reference doesn't abort the lock transaction, the retry is committed
+	assert.NoError(t, r.CreateTransaction(ctx, topup("EXISTING")))
+	err = r.LockUserLimits(ctx, 1, func(ctx context.Context) error {
+		assert.ErrorIs(t, r.CreateTransaction(ctx, topup("EXISTING")), gorm.ErrDuplicatedKey)
+		assert.ErrorIs(t, r.CreateTransaction(ctx, topup("EXISTING")), gorm.ErrDuplicatedKey)
+		return r.CreateTransaction(ctx, topup("RETRIED"))
+	})
+	assert.NoError(t, err)
+	_, err = r.GetTransactionByReference(ctx, "RETRIED", false)
+	assert.NoError(t, err)
+
+	// an error of fn rolls back what it wrote
+	err = r.LockUserLimits(ctx, 1, func(ctx context.Context) error {
+		assert.NoError(t, r.CreateTransaction(ctx, topup("ROLLED-BACK")))
+		assert.NoError(t, r.UpdateStatusTransaction(ctx, "RETRIED", constants.TransactionStatusPending, constants.TransactionStatusSuccess, ""))
+		return assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+	_, err = r.GetTransactionByReference(ctx, "ROLLED-BACK", false)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	got, err := r.GetTransactionByReference(ctx, "RETRIED", false)
+	assert.NoError(t, err)
+	assert.Equal(t, constants.TransactionStatusPending, got.TransactionStatus)
+}
+
+func Test_repository_GetFraudHistory_E2E(t *testing.T) {
+	ctx := context.Background()
+	db := migrationtest.NewSQLiteDB(t)
+	r := NewRepository(db, nil, helpers.QueryTimeouts{})
+
+	now := time.Now()
+	create := func(reference string, trxType string, status string, amount float64, deviceID string, createdAt time.Time) models.Transaction {
+		trx := &models.Transaction{
+			UserID:            1,
+			Amount:            amount,
+			TransactionType:   trxType,
+			TransactionStatus: status,
+			Reference:         reference,
+			DeviceID:          deviceID,
+			ClientIP:          "10.0.0.1",
+			CreatedAt:         createdAt,
+		}
+		assert.NoError(t, r.CreateTransaction(ctx, trx))
+		return *trx
+	}
+	create("OLD", constants.TransactionTypePurchase, constants.TransactionStatusSuccess, 10000, "PHONE", now.Add(-48*time.Hour))
+	create("DAY", constants.TransactionTypePurchase, constants.TransactionStatusSuccess, 30000, "PHONE", now.Add(-2*time.Hour))
+	create("TOPUP", constants.TransactionTypeTopup, constants.TransactionStatusSuccess, 500000, "TABLET", now.Add(-time.Hour))
+	create("REFUND", constants.TransactionTypeRefund, constants.TransactionStatusSuccess, 30000, "", now.Add(-time.Hour))
+	create("FAILED", constants.TransactionTypePurchase, constants.TransactionStatusFailed, 90000, "LAPTOP", now.Add(-5*time.Minute))
+	create("EXPIRED", constants.TransactionTypePurchase, constants.TransactionStatusSuccess, 99000, "LAPTOP", now.Add(-30*24*time.Hour))
+	screened := create("SCREENED", constants.TransactionTypePurchase, constants.TransactionStatusPending, 100000, "LAPTOP", now)
+
+	history, err := r.GetFraudHistory(ctx, screened, models.FraudHistoryQuery{
+		Since:         now.Add(-7 * 24 * time.Hour),
+		VelocitySince: now.Add(-10 * time.Minute),
+		RefundSince:   now.Add(-24 * time.Hour),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, models.FraudHistory{
+		RecentCount:   1,
+		SuccessCount:  4,
+		TypeCount:     2,
+		AverageAmount: 20000,
+		DeviceCount:   0,
+		IPCount:       4,
+		RefundCount:   1,
+	}, history)
+
+	screened.DeviceID = "PHONE"
+	screened.ClientIP = ""
+	history, err = r.GetFraudHistory(ctx, screened, models.FraudHistoryQuery{
+		Since:         now.Add(-7 * 24 * time.Hour),
+		VelocitySince: now.Add(-10 * time.Minute),
+		RefundSince:   now.Add(-24 * time.Hour),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), history.DeviceCount)
+	assert.Zero(t, history.IPCount)
+
+	got, err := r.GetTransactionByReference(ctx, "SCREENED", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "LAPTOP", got.DeviceID)
+	assert.Equal(t, "10.0.0.1", got.ClientIP)
+}
@@ -3,6 +3,7 @@ package transaction
 import (
 	"context"
 	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
 	"ewallet-transaction/internal/models"
 	"reflect"
 	"regexp"
@@ -11,412 +12,874 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
 
 func Test_repository_CreateTransaction(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	assert.NoError(t, err)
-	defer db.Close()
-
-	gormDB, err := gorm.Open(mysql.New(mysql.Config{
-		Conn:                      db,
-		SkipInitializeWithVersion: true,
-	}), &gorm.Config{})
+	forEachDialect(t, func(t *testing.T, d testDialect, gormDB *gorm.DB, mock sqlmock.Sqlmock) {
+		type args struct {
+			ctx context.Context
+			trx *models.Transaction
+		}
+		tests := []struct {
+			name    string
+			args    args
+			wantErr bool
+			mockFn  func(args args)
+		}{
+			{
+				name: "success",
+				args: args{
+					ctx: context.Background(),
+					trx: &models.Transaction{
+						UserID:            1,
+						Amount:            100000,
+						TransactionType:   "DEBIT",
+						TransactionStatus: "PENDING",
+						Reference:         "REFERENCE",
+						Description:       "DESCRIPTION",
+						AdditionalInfo:    `{"note":"done"}`,
+					},
+				},
+				wantErr: false,
+				mockFn: func(args args) {
+					mock.ExpectBegin()
+					d.expectInsert(mock, "INSERT INTO `transactions` (`user_id`,`amount`,`transaction_type`,`transaction_status`,`reference`,`description`,`additional_info`,`merchant_id`,`merchant_name`,`category`,`device_id`,`client_ip`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?)", nil,
+						args.trx.UserID,
+						args.trx.Amount,
+						args.trx.TransactionType,
+						args.trx.TransactionStatus,
+						args.trx.Reference,
+						args.trx.Description,
+						args.trx.AdditionalInfo,
+						args.trx.MerchantID,
+						args.trx.MerchantName,
+						args.trx.Category,
+						args.trx.DeviceID,
+						args.trx.ClientIP,
+						sqlmock.AnyArg(),
+						sqlmock.AnyArg(),
+					)
+					mock.ExpectCommit()
+				},
+			},
+			{
+				name: "error",
+				args: args{
+					ctx: context.Background(),
+					trx: &models.Transaction{
+						UserID:            1,
+						Amount:            100000,
+						TransactionType:   "DEBIT",
+						TransactionStatus: "PENDING",
+						Reference:         "REFERENCE",
+						Description:       "DESCRIPTION",
+						AdditionalInfo:    "",
+					},
+				},
+				wantErr: true,
+				mockFn: func(args args) {
+					mock.ExpectBegin()
+					d.expectInsert(mock, "INSERT INTO `transactions` (`user_id`,`amount`,`transaction_type`,`transaction_status`,`reference`,`description`,`merchant_id`,`merchant_name`,`category`,`device_id`,`client_ip`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?)", assert.AnError,
+						args.trx.UserID,
+						args.trx.Amount,
+						args.trx.TransactionType,
+						args.trx.TransactionStatus,
+						args.trx.Reference,
+						args.trx.Description,
+						args.trx.MerchantID,
+						args.trx.MerchantName,
+						args.trx.Category,
+						args.trx.DeviceID,
+						args.trx.ClientIP,
+						sqlmock.AnyArg(),
+						sqlmock.AnyArg(),
+					)
+					mock.ExpectRollback()
+				},
+			},
+			{
+				name: "success with tags",
+				args: args{
+					ctx: context.Background(),
+					trx: &models.Transaction{
+						UserID:            1,
+						Amount:            50000,
+						TransactionType:   constants.TransactionTypePurchase,
+						TransactionStatus: constants.TransactionStatusPending,
+						Reference:         "REFERENCE",
+						Description:       "DESCRIPTION",
+						MerchantID:        9,
+						MerchantName:      "MERCHANT",
+						Category:          "FOOD_AND_DRINK",
+						Tags:              []string{"trip", "work"},
+					},
+				},
+				wantErr: false,
+				mockFn: func(args args) {
+					mock.ExpectBegin()
+					d.expectInsert(mock, "INSERT INTO `transactions` (`user_id`,`amount`,`transaction_type`,`transaction_status`,`reference`,`description`,`merchant_id`,`merchant_name`,`category`,`device_id`,`client_ip`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?)", nil,
+						args.trx.UserID,
+						args.trx.Amount,
+						args.trx.TransactionType,
+						args.trx.TransactionStatus,
+						args.trx.Reference,
+						args.trx.Description,
+						args.trx.MerchantID,
+						args.trx.MerchantName,
+						args.trx.Category,
+						args.trx.DeviceID,
+						args.trx.ClientIP,
+						sqlmock.AnyArg(),
+						sqlmock.AnyArg(),
+					)
+					mock.ExpectExec(regexp.QuoteMeta(d.sql("INSERT INTO `transaction_tags` (`transaction_id`,`tag`) VALUES (?,?),(?,?)"))).WithArgs(
+						1, "trip", 1, "work",
+					).WillReturnResult(sqlmock.NewResult(0, 2))
+					mock.ExpectCommit()
+				},
+			},
+			{
+				name: "error tags",
+				args: args{
+					ctx: context.Background(),
+					trx: &models.Transaction{
+						UserID:            1,
+						Amount:            50000,
+						TransactionType:   constants.TransactionTypePurchase,
+						TransactionStatus: constants.TransactionStatusPending,
+						Reference:         "REFERENCE",
+						Description:       "DESCRIPTION",
+						Tags:              []string{"trip"},
+					},
+				},
+				wantErr: true,
+				mockFn: func(args args) {
+					mock.ExpectBegin()
+					d.expectInsert(mock, "INSERT INTO `transactions` (`user_id`,`amount`,`transaction_type`,`transaction_status`,`reference`,`description`,`merchant_id`,`merchant_name`,`category`,`device_id`,`client_ip`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?)", nil,
+						args.trx.UserID,
+						args.trx.Amount,
+						args.trx.TransactionType,
+						args.trx.TransactionStatus,
+						args.trx.Reference,
+						args.trx.Description,
+						args.trx.MerchantID,
+						args.trx.MerchantName,
+						args.trx.Category,
+						args.trx.DeviceID,
+						args.trx.ClientIP,
+						sqlmock.AnyArg(),
+						sqlmock.AnyArg(),
+					)
+					mock.ExpectExec(regexp.QuoteMeta(d.sql("INSERT INTO `transaction_tags` (`transaction_id`,`tag`) VALUES (?,?)"))).WithArgs(
+						1, "trip",
+					).WillReturnError(assert.AnError)
+					mock.ExpectRollback()
+				},
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				tt.mockFn(tt.args)
+				r := &repository{
+					DB: gormDB,
+				}
+				if err := r.CreateTransaction(tt.args.ctx, tt.args.trx); (err != nil) != tt.wantErr {
+					t.Errorf("repository.CreateTransaction() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	})
+}
 
-	assert.NoError(t, err)
+func Test_repository_GetTransactionByReference(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, d testDialect, gormDB *gorm.DB, mock sqlmock.Sqlmock) {
+		now := time.Now()
 
-	type args struct {
-		ctx context.Context
-		trx *models.Transaction
-	}
-	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
-		mockFn  func(args args)
-	}{
-		{
-			name: "success",
-			args: args{
-				ctx: context.Background(),
-				trx: &models.Transaction{
+		type args struct {
+			ctx           context.Context
+			reference     string
+			includeRefund bool
+		}
+		tests := []struct {
+			name    string
+			args    args
+			want    models.Transaction
+			wantErr bool
+			mockFn  func(args args)
+		}{
+			{
+				name: "success without include refund",
+				args: args{
+					ctx:           context.Background(),
+					reference:     "REFERENCE",
+					includeRefund: false,
+				},
+				want: models.Transaction{
+					ID:                1,
 					UserID:            1,
 					Amount:            100000,
 					TransactionType:   "DEBIT",
 					TransactionStatus: "PENDING",
 					Reference:         "REFERENCE",
 					Description:       "DESCRIPTION",
-					AdditionalInfo:    "",
+					AdditionalInfo:    "ADDINFO",
+					Tags:              []string{"food"},
+					CreatedAt:         now,
+					UpdatedAt:         now,
+				},
+				wantErr: false,
+				mockFn: func(args args) {
+					mock.ExpectQuery(regexp.QuoteMeta(d.sql("SELECT * FROM `transactions` WHERE reference = ? AND transaction_type != ? ORDER BY `transactions`.`id` DESC LIMIT ?"))).WithArgs(
+						args.reference,
+						constants.TransactionTypeRefund,
+						1,
+					).WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "amount", "transaction_type", "transaction_status", "reference", "description", "additional_info", "created_at", "updated_at"}).AddRow(1, 1, 100000, "DEBIT", "PENDING", "REFERENCE", "DESCRIPTION", "ADDINFO", now, now))
+					mock.ExpectQuery(regexp.QuoteMeta(d.sql("SELECT * FROM `transaction_tags` WHERE transaction_id IN (?) ORDER BY transaction_id, tag"))).WithArgs(
+						1,
+					).WillReturnRows(sqlmock.NewRows([]string{"transaction_id", "tag"}).AddRow(1, "food"))
 				},
 			},
-			wantErr: false,
-			mockFn: func(args args) {
-				mock.ExpectBegin()
-				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `transactions` (`user_id`,`amount`,`transaction_type`,`transaction_status`,`reference`,`description`,`additional_info`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?,?,?,?)")).WithArgs(
-					args.trx.UserID,
-					args.trx.Amount,
-					args.trx.TransactionType,
-					args.trx.TransactionStatus,
-					args.trx.Reference,
-					args.trx.Description,
-					args.trx.AdditionalInfo,
-					sqlmock.AnyArg(),
-					sqlmock.AnyArg(),
-				).WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectCommit()
-			},
-		},
-		{
-			name: "error",
-			args: args{
-				ctx: context.Background(),
-				trx: &models.Transaction{
+			{
+				name: "success with include refund",
+				args: args{
+					ctx:           context.Background(),
+					reference:     "REFERENCE",
+					includeRefund: true,
+				},
+				want: models.Transaction{
+					ID:                1,
 					UserID:            1,
 					Amount:            100000,
 					TransactionType:   "DEBIT",
 					TransactionStatus: "PENDING",
 					Reference:         "REFERENCE",
 					Description:       "DESCRIPTION",
-					AdditionalInfo:    "",
+					AdditionalInfo:    "ADDINFO",
+					Tags:              []string{"food"},
+					CreatedAt:         now,
+					UpdatedAt:         now,
+				},
+				wantErr: false,
+				mockFn: func(args args) {
+					mock.ExpectQuery(regexp.QuoteMeta(d.sql("SELECT * FROM `transactions` WHERE reference = ? ORDER BY `transactions`.`id` DESC LIMIT ?"))).WithArgs(
+						args.reference,
+						1,
+					).WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "amount", "transaction_type", "transaction_status", "reference", "description", "additional_info", "created_at", "updated_at"}).AddRow(1, 1, 100000, "DEBIT", "PENDING", "REFERENCE", "DESCRIPTION", "ADDINFO", now, now))
+					mock.ExpectQuery(regexp.QuoteMeta(d.sql("SELECT * FROM `transaction_tags` WHERE transaction_id IN (?) ORDER BY transaction_id, tag"))).WithArgs(
+						1,
+					).WillReturnRows(sqlmock.NewRows([]string{"transaction_id", "tag"}).AddRow(1, "food"))
 				},
 			},
-			wantErr: true,
-			mockFn: func(args args) {
-				mock.ExpectBegin()
-				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `transactions` (`user_id`,`amount`,`transaction_type`,`transaction_status`,`reference`,`description`,`additional_info`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?,?,?,?)")).WithArgs(
-					args.trx.UserID,
-					args.trx.Amount,
-					args.trx.TransactionType,
-					args.trx.TransactionStatus,
-					args.trx.Reference,
-					args.trx.Description,
-					args.trx.AdditionalInfo,
-					sqlmock.AnyArg(),
-					sqlmock.AnyArg(),
-				).WillReturnError(assert.AnError)
-				mock.ExpectRollback()
+			{
+				name: "error without include refund",
+				args: args{
+					ctx:           context.Background(),
+					reference:     "REFERENCE",
+					includeRefund: false,
+				},
+				want:    models.Transaction{},
+				wantErr: true,
+				mockFn: func(args args) {
+					mock.ExpectQuery(regexp.QuoteMeta(d.sql("SELECT * FROM `transactions` WHERE reference = ? AND transaction_type != ? ORDER BY `transactions`.`id` DESC LIMIT ?"))).WithArgs(
+						args.reference,
+						constants.TransactionTypeRefund,
+						1,
+					).WillReturnError(assert.AnError)
+				},
 			},
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			tt.mockFn(tt.args)
-			r := &repository{
-				DB: gormDB,
-			}
-			if err := r.CreateTransaction(tt.args.ctx, tt.args.trx); (err != nil) != tt.wantErr {
-				t.Errorf("repository.CreateTransaction() error = %v, wantErr %v", err, tt.wantErr)
-			}
-			assert.NoError(t, mock.ExpectationsWereMet())
-		})
-	}
-}
-
-func Test_repository_GetTransactionByReference(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	assert.NoError(t, err)
-	defer db.Close()
-
-	gormDB, err := gorm.Open(mysql.New(mysql.Config{
-		Conn:                      db,
-		SkipInitializeWithVersion: true,
-	}), &gorm.Config{})
+			{
+				name: "error with include refund",
+				args: args{
+					ctx:           context.Background(),
+					reference:     "reference",
+					includeRefund: true,
+				},
+				want:    models.Transaction{},
+				wantErr: true,
+				mockFn: func(args args) {
+					mock.ExpectQuery(regexp.QuoteMeta(d.sql("SELECT * FROM `transactions` WHERE reference = ? ORDER BY `transactions`.`id` DESC LIMIT ?"))).WithArgs(
+						args.reference,
+						1,
+					).WillReturnError(assert.AnError)
+				},
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				tt.mockFn(tt.args)
+				r := &repository{
+					DB: gormDB,
+				}
+				got, err := r.GetTransactionByReference(tt.args.ctx, tt.args.reference, tt.args.includeRefund)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("repository.GetTransactionByReference() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("repository.GetTransactionByReference() = %v, want %v", got, tt.want)
+				}
 
-	assert.NoError(t, err)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	})
+}
 
-	now := time.Now()
+func Test_repository_LockUserLimits(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, d testDialect, gormDB *gorm.DB, mock sqlmock.Sqlmock) {
+		insertLock := "INSERT INTO `user_limit_locks` (`user_id`) VALUES (?) ON DUPLICATE KEY UPDATE `user_id`=`user_id`"
+		if d.name == "postgres" {
+			insertLock = "INSERT INTO `user_limit_locks` (`user_id`) VALUES (?) ON CONFLICT DO NOTHING"
+		}
+		selectLock := "SELECT * FROM `user_limit_locks` WHERE `user_limit_locks`.`user_id` = ? ORDER BY `user_limit_locks`.`user_id` LIMIT ? FOR UPDATE"
 
-	type args struct {
-		ctx           context.Context
-		reference     string
-		includeRefund bool
-	}
-	tests := []struct {
-		name    string
-		args    args
-		want    models.Transaction
-		wantErr bool
-		mockFn  func(args args)
-	}{
-		{
-			name: "success without include refund",
-			args: args{
-				ctx:           context.Background(),
-				reference:     "REFERENCE",
-				includeRefund: false,
-			},
-			want: models.Transaction{
-				ID:                1,
-				UserID:            1,
-				Amount:            100000,
-				TransactionType:   "DEBIT",
-				TransactionStatus: "PENDING",
-				Reference:         "REFERENCE",
-				Description:       "DESCRIPTION",
-				AdditionalInfo:    "ADDINFO",
-				CreatedAt:         now,
-				UpdatedAt:         now,
-			},
-			wantErr: false,
-			mockFn: func(args args) {
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `transactions` WHERE reference = ? AND transaction_type != ? ORDER BY `transactions`.`id` DESC LIMIT ?")).WithArgs(
-					args.reference,
-					constants.TransactionTypeRefund,
-					1,
-				).WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "amount", "transaction_type", "transaction_status", "reference", "description", "additional_info", "created_at", "updated_at"}).AddRow(1, 1, 100000, "DEBIT", "PENDING", "REFERENCE", "DESCRIPTION", "ADDINFO", now, now))
-			},
-		},
-		{
-			name: "success with include refund",
-			args: args{
-				ctx:           context.Background(),
-				reference:     "REFERENCE",
-				includeRefund: true,
-			},
-			want: models.Transaction{
-				ID:                1,
-				UserID:            1,
-				Amount:            100000,
-				TransactionType:   "DEBIT",
-				TransactionStatus: "PENDING",
-				Reference:         "REFERENCE",
-				Description:       "DESCRIPTION",
-				AdditionalInfo:    "ADDINFO",
-				CreatedAt:         now,
-				UpdatedAt:         now,
-			},
-			wantErr: false,
-			mockFn: func(args args) {
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `transactions` WHERE reference = ? ORDER BY `transactions`.`id` DESC LIMIT ?")).WithArgs(
-					args.reference,
-					1,
-				).WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "amount", "transaction_type", "transaction_status", "reference", "description", "additional_info", "created_at", "updated_at"}).AddRow(1, 1, 100000, "DEBIT", "PENDING", "REFERENCE", "DESCRIPTION", "ADDINFO", now, now))
-			},
-		},
-		{
-			name: "error without include refund",
-			args: args{
-				ctx:           context.Background(),
-				reference:     "REFERENCE",
-				includeRefund: false,
-			},
-			want:    models.Transaction{},
-			wantErr: true,
-			mockFn: func(args args) {
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `transactions` WHERE reference = ? AND transaction_type != ? ORDER BY `transactions`.`id` DESC LIMIT ?")).WithArgs(
-					args.reference,
-					constants.TransactionTypeRefund,
-					1,
-				).WillReturnError(assert.AnError)
+		tests := []struct {
+			name    string
+			fnErr   error
+			wantErr bool
+			mockFn  func()
+		}{
+			{
+				name: "success",
+				mockFn: func() {
+					mock.ExpectBegin()
+					mock.ExpectExec(regexp.QuoteMeta(d.sql(insertLock))).WithArgs(uint64(1)).WillReturnResult(sqlmock.NewResult(0, 0))
+					mock.ExpectQuery(regexp.QuoteMeta(d.sql(selectLock))).WithArgs(uint64(1), 1).WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+					mock.ExpectExec(regexp.QuoteMeta(d.sql("UPDATE `transactions` SET `additional_info`=?,`transaction_status`=?,`updated_at`=? WHERE reference = ? AND transaction_type != ? AND transaction_status = ?"))).WithArgs(
+						nil,
+						constants.TransactionStatusSuccess,
+						sqlmock.AnyArg(),
+						"REFERENCE",
+						constants.TransactionTypeRefund,
+						constants.TransactionStatusPending,
+					).WillReturnResult(sqlmock.NewResult(0, 1))
+					mock.ExpectCommit()
+				},
 			},
-		},
-		{
-			name: "error with include refund",
-			args: args{
-				ctx:           context.Background(),
-				reference:     "reference",
-				includeRefund: true,
+			{
+				name:    "error of fn rolls back",
+				fnErr:   assert.AnError,
+				wantErr: true,
+				mockFn: func() {
+					mock.ExpectBegin()
+					mock.ExpectExec(regexp.QuoteMeta(d.sql(insertLock))).WithArgs(uint64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+					mock.ExpectQuery(regexp.QuoteMeta(d.sql(selectLock))).WithArgs(uint64(1), 1).WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+					mock.ExpectExec(regexp.QuoteMeta(d.sql("UPDATE `transactions` SET `additional_info`=?,`transaction_status`=?,`updated_at`=? WHERE reference = ? AND transaction_type != ? AND transaction_status = ?"))).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+					mock.ExpectRollback()
+				},
 			},
-			want:    models.Transaction{},
-			wantErr: true,
-			mockFn: func(args args) {
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `transactions` WHERE reference = ? ORDER BY `transactions`.`id` DESC LIMIT ?")).WithArgs(
-					args.reference,
-					1,
-				).WillReturnError(assert.AnError)
+			{
+				name:    "error lock",
+				wantErr: true,
+				mockFn: func() {
+					mock.ExpectBegin()
+					mock.ExpectExec(regexp.QuoteMeta(d.sql(insertLock))).WithArgs(uint64(1)).WillReturnResult(sqlmock.NewResult(0, 0))
+					mock.ExpectQuery(regexp.QuoteMeta(d.sql(selectLock))).WithArgs(uint64(1), 1).WillReturnError(assert.AnError)
+					mock.ExpectRollback()
+				},
 			},
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			tt.mockFn(tt.args)
-			r := &repository{
-				DB: gormDB,
-			}
-			got, err := r.GetTransactionByReference(tt.args.ctx, tt.args.reference, tt.args.includeRefund)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("repository.GetTransactionByReference() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("repository.GetTransactionByReference() = %v, want %v", got, tt.want)
-			}
-
-			assert.NoError(t, mock.ExpectationsWereMet())
-		})
-	}
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				tt.mockFn()
+				r := &repository{
+					DB: gormDB,
+				}
+				err := r.LockUserLimits(context.Background(), 1, func(ctx context.Context) error {
+					err := r.UpdateStatusTransaction(ctx, "REFERENCE", constants.TransactionStatusPending, constants.TransactionStatusSuccess, "")
+					if err != nil {
+						return err
+					}
+					return tt.fnErr
+				})
+				if (err != nil) != tt.wantErr {
+					t.Errorf("repository.LockUserLimits() error = %v, wantErr %v", err, tt.wantErr)
+				}
+
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	})
 }
 
 func Test_repository_UpdateStatusTransaction(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	assert.NoError(t, err)
-	defer db.Close()
-
-	gormDB, err := gorm.Open(mysql.New(mysql.Config{
-		Conn:                      db,
-		SkipInitializeWithVersion: true,
-	}), &gorm.Config{})
-
-	assert.NoError(t, err)
-
-	type args struct {
-		ctx            context.Context
-		reference      string
-		status         string
-		additionalInfo string
-	}
-	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
-		mockFn  func(args args)
-	}{
-		{
-			name: "success",
-			args: args{
-				ctx:            context.Background(),
-				reference:      "REFERENCE",
-				status:         "SUCCESS",
-				additionalInfo: "ADDINFO",
-			},
-			wantErr: false,
-			mockFn: func(args args) {
-				mock.ExpectExec(regexp.QuoteMeta("UPDATE transactions SET transaction_status = ?, additional_info = ? WHERE reference = ?")).WithArgs(
-					args.status,
-					args.additionalInfo,
-					args.reference,
-				).WillReturnResult(sqlmock.NewResult(1, 1))
+	forEachDialect(t, func(t *testing.T, d testDialect, gormDB *gorm.DB, mock sqlmock.Sqlmock) {
+		type args struct {
+			ctx            context.Context
+			reference      string
+			currentStatus  string
+			status         string
+			additionalInfo string
+		}
+		tests := []struct {
+			name    string
+			args    args
+			wantErr error
+			mockFn  func(args args)
+		}{
+			{
+				name: "success",
+				args: args{
+					ctx:            context.Background(),
+					reference:      "REFERENCE",
+					currentStatus:  "PENDING",
+					status:         "SUCCESS",
+					additionalInfo: "ADDINFO",
+				},
+				wantErr: nil,
+				mockFn: func(args args) {
+					mock.ExpectBegin()
+					mock.ExpectExec(regexp.QuoteMeta(d.sql("UPDATE `transactions` SET `additional_info`=?,`transaction_status`=?,`updated_at`=? WHERE reference = ? AND transaction_type != ? AND transaction_status = ?"))).WithArgs(
+						args.additionalInfo,
+						args.status,
+						sqlmock.AnyArg(),
+						args.reference,
+						constants.TransactionTypeRefund,
+						args.currentStatus,
+					).WillReturnResult(sqlmock.NewResult(1, 1))
+					mock.ExpectCommit()
+				},
 			},
-		},
-		{
-			name: "error",
-			args: args{
-				ctx:            context.Background(),
-				reference:      "REFERENCE",
-				status:         "SUCCESS",
-				additionalInfo: "ADDINFO",
+			{
+				name: "error",
+				args: args{
+					ctx:            context.Background(),
+					reference:      "REFERENCE",
+					currentStatus:  "PENDING",
+					status:         "SUCCESS",
+					additionalInfo: "ADDINFO",
+				},
+				wantErr: assert.AnError,
+				mockFn: func(args args) {
+					mock.ExpectBegin()
+					mock.ExpectExec(regexp.QuoteMeta(d.sql("UPDATE `transactions` SET `additional_info`=?,`transaction_status`=?,`updated_at`=? WHERE reference = ? AND transaction_type != ? AND transaction_status = ?"))).WithArgs(
+						args.additionalInfo,
+						args.status,
+						sqlmock.AnyArg(),
+						args.reference,
+						constants.TransactionTypeRefund,
+						args.currentStatus,
+					).WillReturnError(assert.AnError)
+					mock.ExpectRollback()
+				},
 			},
-			wantErr: true,
-			mockFn: func(args args) {
-				mock.ExpectExec(regexp.QuoteMeta("UPDATE transactions SET transaction_status = ?, additional_info = ? WHERE reference = ?")).WithArgs(
-					args.status,
-					args.additionalInfo,
-					args.reference,
-				).WillReturnError(assert.AnError)
+			{
+				name: "error status changed meanwhile",
+				args: args{
+					ctx:            context.Background(),
+					reference:      "REFERENCE",
+					currentStatus:  "PENDING",
+					status:         "SUCCESS",
+					additionalInfo: "ADDINFO",
+				},
+				wantErr: models.ErrStatusConflict,
+				mockFn: func(args args) {
+					mock.ExpectBegin()
+					mock.ExpectExec(regexp.QuoteMeta(d.sql("UPDATE `transactions` SET `additional_info`=?,`transaction_status`=?,`updated_at`=? WHERE reference = ? AND transaction_type != ? AND transaction_status = ?"))).WithArgs(
+						args.additionalInfo,
+						args.status,
+						sqlmock.AnyArg(),
+						args.reference,
+						constants.TransactionTypeRefund,
+						args.currentStatus,
+					).WillReturnResult(sqlmock.NewResult(0, 0))
+					mock.ExpectCommit()
+				},
 			},
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			tt.mockFn(tt.args)
-			r := &repository{
-				DB: gormDB,
-			}
-			if err := r.UpdateStatusTransaction(tt.args.ctx, tt.args.reference, tt.args.status, tt.args.additionalInfo); (err != nil) != tt.wantErr {
-				t.Errorf("repository.UpdateStatusTransaction() error = %v, wantErr %v", err, tt.wantErr)
-			}
-
-			assert.NoError(t, mock.ExpectationsWereMet())
-		})
-	}
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				tt.mockFn(tt.args)
+				r := &repository{
+					DB: gormDB,
+				}
+				err := r.UpdateStatusTransaction(tt.args.ctx, tt.args.reference, tt.args.currentStatus, tt.args.status, tt.args.additionalInfo)
+				assert.ErrorIs(t, err, tt.wantErr)
+
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	})
 }
 
 func Test_repository_GetTransaction(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	assert.NoError(t, err)
-	defer db.Close()
-
-	gormDB, err := gorm.Open(mysql.New(mysql.Config{
-		Conn:                      db,
-		SkipInitializeWithVersion: true,
-	}), &gorm.Config{})
-
-	assert.NoError(t, err)
-
-	now := time.Now()
-	type args struct {
-		ctx    context.Context
-		userID uint64
-	}
-	tests := []struct {
-		name    string
-		args    args
-		want    []models.Transaction
-		wantErr bool
-		mockFn  func(args args)
-	}{
-		{
-			name: "success",
-			args: args{
-				ctx:    context.Background(),
-				userID: 1,
-			},
-			want: []models.Transaction{
-				{
-					ID:                1,
-					UserID:            1,
-					Amount:            100000,
-					TransactionType:   "DEBIT",
-					TransactionStatus: "SUCCESS",
-					Reference:         "REFERENCE",
-					Description:       "DESCRIPTION",
-					AdditionalInfo:    "ADDINFO",
-					CreatedAt:         now,
-					UpdatedAt:         now,
+	forEachDialect(t, func(t *testing.T, d testDialect, gormDB *gorm.DB, mock sqlmock.Sqlmock) {
+		now := time.Now()
+		type args struct {
+			ctx    context.Context
+			userID uint64
+			filter models.TransactionFilter
+		}
+		tests := []struct {
+			name    string
+			args    args
+			want    []models.Transaction
+			wantErr bool
+			mockFn  func(args args)
+		}{
+			{
+				name: "success",
+				args: args{
+					ctx:    context.Background(),
+					userID: 1,
 				},
-				{
-					ID:                2,
-					UserID:            1,
-					Amount:            100000,
-					TransactionType:   "DEBIT",
-					TransactionStatus: "PENDING",
-					Reference:         "REFERENCE",
-					Description:       "DESCRIPTION",
-					AdditionalInfo:    "ADDINFO",
-					CreatedAt:         now,
-					UpdatedAt:         now,
+				want: []models.Transaction{
+					{
+						ID:                1,
+						UserID:            1,
+						Amount:            100000,
+						TransactionType:   "DEBIT",
+						TransactionStatus: "SUCCESS",
+						Reference:         "REFERENCE",
+						Description:       "DESCRIPTION",
+						AdditionalInfo:    "ADDINFO",
+						Tags:              []string{"trip", "work"},
+						CreatedAt:         now,
+						UpdatedAt:         now,
+					},
+					{
+						ID:                2,
+						UserID:            1,
+						Amount:            100000,
+						TransactionType:   "DEBIT",
+						TransactionStatus: "PENDING",
+						Reference:         "REFERENCE",
+						Description:       "DESCRIPTION",
+						AdditionalInfo:    "ADDINFO",
+						CreatedAt:         now,
+						UpdatedAt:         now,
+					},
+				},
+				wantErr: false,
+				mockFn: func(args args) {
+					mock.ExpectQuery(regexp.QuoteMeta(d.sql("SELECT * FROM `transactions` WHERE user_id = ? ORDER BY created_at DESC, id DESC"))).WithArgs(
+						args.userID,
+					).WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "amount", "transaction_type", "transaction_status", "reference", "description", "additional_info", "created_at", "updated_at"}).AddRow(1, 1, 100000, "DEBIT", "SUCCESS", "REFERENCE", "DESCRIPTION", "ADDINFO", now, now).AddRow(2, 1, 100000, "DEBIT", "PENDING", "REFERENCE", "DESCRIPTION", "ADDINFO", now, now))
+					mock.ExpectQuery(regexp.QuoteMeta(d.sql("SELECT * FROM `transaction_tags` WHERE transaction_id IN (?,?) ORDER BY transaction_id, tag"))).WithArgs(
+						1, 2,
+					).WillReturnRows(sqlmock.NewRows([]string{"transaction_id", "tag"}).AddRow(1, "trip").AddRow(1, "work"))
 				},
 			},
-			wantErr: false,
-			mockFn: func(args args) {
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `transactions` WHERE user_id = ? ORDER BY id DESC")).WithArgs(
-					args.userID,
-				).WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "amount", "transaction_type", "transaction_status", "reference", "description", "additional_info", "created_at", "updated_at"}).AddRow(1, 1, 100000, "DEBIT", "SUCCESS", "REFERENCE", "DESCRIPTION", "ADDINFO", now, now).AddRow(2, 1, 100000, "DEBIT", "PENDING", "REFERENCE", "DESCRIPTION", "ADDINFO", now, now))
+			{
+				name: "success with filter",
+				args: args{
+					ctx:    context.Background(),
+					userID: 1,
+					filter: models.TransactionFilter{
+						Category:   "FOOD_AND_DRINK",
+						MerchantID: 9,
+						Tag:        "trip",
+					},
+				},
+				want:    []models.Transaction{},
+				wantErr: false,
+				mockFn: func(args args) {
+					mock.ExpectQuery(regexp.QuoteMeta(d.sql("SELECT * FROM `transactions` WHERE user_id = ? AND category = ? AND merchant_id = ? AND id IN (SELECT `transaction_id` FROM `transaction_tags` WHERE tag = ?) ORDER BY created_at DESC, id DESC"))).WithArgs(
+						args.userID,
+						args.filter.Category,
+						args.filter.MerchantID,
+						args.filter.Tag,
+					).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				},
 			},
-		},
-		{
-			name: "error",
-			args: args{
-				ctx:    context.Background(),
-				userID: 1,
+			{
+				name: "error tags",
+				args: args{
+					ctx:    context.Background(),
+					userID: 1,
+				},
+				want: []models.Transaction{
+					{ID: 1},
+				},
+				wantErr: true,
+				mockFn: func(args args) {
+					mock.ExpectQuery(regexp.QuoteMeta(d.sql("SELECT * FROM `transactions` WHERE user_id = ? ORDER BY created_at DESC, id DESC"))).WithArgs(
+						args.userID,
+					).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+					mock.ExpectQuery(regexp.QuoteMeta(d.sql("SELECT * FROM `transaction_tags` WHERE transaction_id IN (?) ORDER BY transaction_id, tag"))).WithArgs(
+						1,
+					).WillReturnError(assert.AnError)
+				},
 			},
-			want:    nil,
-			wantErr: true,
-			mockFn: func(args args) {
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `transactions` WHERE user_id = ? ORDER BY id DESC")).WithArgs(
-					args.userID,
-				).WillReturnError(assert.AnError)
+			{
+				name: "error",
+				args: args{
+					ctx:    context.Background(),
+					userID: 1,
+				},
+				want:    nil,
+				wantErr: true,
+				mockFn: func(args args) {
+					mock.ExpectQuery(regexp.QuoteMeta(d.sql("SELECT * FROM `transactions` WHERE user_id = ? ORDER BY created_at DESC, id DESC"))).WithArgs(
+						args.userID,
+					).WillReturnError(assert.AnError)
+				},
 			},
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			tt.mockFn(tt.args)
-			r := &repository{
-				DB: gormDB,
-			}
-			got, err := r.GetTransaction(tt.args.ctx, tt.args.userID)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("repository.GetTransaction() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("repository.GetTransaction() = %v, want %v", got, tt.want)
-			}
-
-			assert.NoError(t, mock.ExpectationsWereMet())
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				tt.mockFn(tt.args)
+				r := &repository{
+					DB:     gormDB,
+					ReadDB: gormDB,
+				}
+				got, err := r.GetTransaction(tt.args.ctx, tt.args.userID, tt.args.filter)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("repository.GetTransaction() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("repository.GetTransaction() = %v, want %v", got, tt.want)
+				}
+
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	})
+}
+
+func Test_repository_ReadReplica(t *testing.T) {
+	for _, d := range testDialects {
+		t.Run(d.name, func(t *testing.T) {
+			primaryDB, primaryMock := newMockDB(t, d)
+			replicaDB, replicaMock := newMockDB(t, d)
+
+			now := time.Now()
+			columns := []string{"id", "user_id", "amount", "transaction_type", "transaction_status", "reference", "description", "additional_info", "created_at", "updated_at"}
+
+			r := NewRepository(primaryDB, replicaDB, helpers.QueryTimeouts{})
+
+			replicaMock.ExpectQuery(regexp.QuoteMeta(d.sql("SELECT * FROM `transactions` WHERE reference = ? ORDER BY `transactions`.`id` DESC LIMIT ?"))).WithArgs(
+				"REFERENCE",
+				1,
+			).WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 1, 100000, constants.TransactionTypeTopup, constants.TransactionStatusSuccess, "REFERENCE", "DESCRIPTION", "", now, now))
+			replicaMock.ExpectQuery(regexp.QuoteMeta(d.sql("SELECT * FROM `transaction_tags` WHERE transaction_id IN (?) ORDER BY transaction_id, tag"))).WithArgs(
+				1,
+			).WillReturnRows(sqlmock.NewRows([]string{"transaction_id", "tag"}))
+			_, err := r.GetTransactionDetail(context.Background(), "REFERENCE")
+			assert.NoError(t, err)
+
+			replicaMock.ExpectQuery(regexp.QuoteMeta(d.sql("SELECT * FROM `transactions` WHERE user_id = ? ORDER BY created_at DESC, id DESC"))).WithArgs(
+				uint64(1),
+			).WillReturnRows(sqlmock.NewRows(columns))
+			_, err = r.GetTransaction(context.Background(), 1, models.TransactionFilter{})
+			assert.NoError(t, err)
+
+			primaryMock.ExpectQuery(regexp.QuoteMeta(d.sql("SELECT * FROM `transactions` WHERE reference = ? AND transaction_type != ? ORDER BY `transactions`.`id` DESC LIMIT ?"))).WithArgs(
+				"REFERENCE",
+				constants.TransactionTypeRefund,
+				1,
+			).WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 1, 100000, constants.TransactionTypeTopup, constants.TransactionStatusSuccess, "REFERENCE", "DESCRIPTION", "", now, now))
+			primaryMock.ExpectQuery(regexp.QuoteMeta(d.sql("SELECT * FROM `transaction_tags` WHERE transaction_id IN (?) ORDER BY transaction_id, tag"))).WithArgs(
+				1,
+			).WillReturnRows(sqlmock.NewRows([]string{"transaction_id", "tag"}))
+			_, err = r.GetTransactionByReference(context.Background(), "REFERENCE", false)
+			assert.NoError(t, err)
+
+			primaryMock.ExpectBegin()
+			primaryMock.ExpectExec(regexp.QuoteMeta(d.sql("UPDATE `transactions` SET `additional_info`=?,`transaction_status`=?,`updated_at`=? WHERE reference = ? AND transaction_type != ? AND transaction_status = ?"))).WithArgs(
+				nil,
+				constants.TransactionStatusReversed,
+				sqlmock.AnyArg(),
+				"REFERENCE",
+				constants.TransactionTypeRefund,
+				constants.TransactionStatusSuccess,
+			).WillReturnResult(sqlmock.NewResult(0, 1))
+			primaryMock.ExpectCommit()
+			err = r.UpdateStatusTransaction(context.Background(), "REFERENCE", constants.TransactionStatusSuccess, constants.TransactionStatusReversed, "")
+			assert.NoError(t, err)
+
+			assert.NoError(t, primaryMock.ExpectationsWereMet())
+			assert.NoError(t, replicaMock.ExpectationsWereMet())
 		})
 	}
 }
+
+func Test_repository_QueryContext(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, d testDialect, gormDB *gorm.DB, mock sqlmock.Sqlmock) {
+		columns := []string{"id", "user_id", "amount", "transaction_type", "transaction_status", "reference", "description", "additional_info", "created_at", "updated_at"}
+		query := regexp.QuoteMeta(d.sql("SELECT * FROM `transactions` WHERE reference = ? ORDER BY `transactions`.`id` DESC LIMIT ?"))
+
+		r := NewRepository(gormDB, nil, helpers.QueryTimeouts{Read: 10 * time.Millisecond})
+
+		mock.ExpectQuery(query).WithArgs("REFERENCE", 1).WillDelayFor(time.Second).WillReturnRows(sqlmock.NewRows(columns))
+		_, err := r.GetTransactionDetail(context.Background(), "REFERENCE")
+		assert.ErrorIs(t, err, helpers.ErrQueryTimeout)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = r.GetTransactionDetail(ctx, "REFERENCE")
+		assert.ErrorIs(t, err, helpers.ErrQueryCanceled)
+
+		mock.ExpectQuery(query).WithArgs("REFERENCE", 1).WillReturnError(assert.AnError)
+		_, err = r.GetTransactionDetail(context.Background(), "REFERENCE")
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.NotErrorIs(t, err, helpers.ErrQueryTimeout)
+		assert.NotErrorIs(t, err, helpers.ErrQueryCanceled)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_repository_GetSummaryBuckets(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, d testDialect, gormDB *gorm.DB, mock sqlmock.Sqlmock) {
+		day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)
+		bounds := []time.Time{day, day.AddDate(0, 0, 1), day.AddDate(0, 0, 2), day.AddDate(0, 0, 3)}
+		query := "SELECT CASE WHEN created_at < ? THEN 0 WHEN created_at < ? THEN 1 ELSE 2 END AS bucket, transaction_type, COUNT(*) AS count, SUM(amount) AS amount FROM `transactions` WHERE user_id = ? AND transaction_status = ? AND created_at >= ? AND created_at < ? GROUP BY bucket, transaction_type ORDER BY bucket, transaction_type"
+
+		r := &repository{
+			DB:     gormDB,
+			ReadDB: gormDB,
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(d.sql(query))).WithArgs(
+			bounds[1], bounds[2], uint64(1), constants.TransactionStatusSuccess, bounds[0], bounds[3],
+		).WillReturnRows(sqlmock.NewRows([]string{"bucket", "transaction_type", "count", "amount"}).
+			AddRow(0, constants.TransactionTypeTopup, 2, 150000).
+			AddRow(2, constants.TransactionTypePurchase, 1, 2500.5))
+
+		got, err := r.GetSummaryBuckets(context.Background(), 1, bounds)
+		assert.NoError(t, err)
+		assert.Equal(t, []models.SummaryBucket{
+			{Bucket: 0, TransactionType: constants.TransactionTypeTopup, Count: 2, Amount: 150000},
+			{Bucket: 2, TransactionType: constants.TransactionTypePurchase, Count: 1, Amount: 2500.5},
+		}, got)
+
+		mock.ExpectQuery(regexp.QuoteMeta(d.sql(query))).WillReturnError(assert.AnError)
+		_, err = r.GetSummaryBuckets(context.Background(), 1, bounds)
+		assert.Error(t, err)
+
+		got, err = r.GetSummaryBuckets(context.Background(), 1, bounds[:1])
+		assert.NoError(t, err)
+		assert.Empty(t, got)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_repository_GetSummaryGroups(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, d testDialect, gormDB *gorm.DB, mock sqlmock.Sqlmock) {
+		from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)
+		to := from.AddDate(0, 1, 0)
+		where := " WHERE transactions.user_id = ? AND transactions.transaction_type = ? AND transactions.transaction_status = ? AND transactions.created_at >= ? AND transactions.created_at < ? GROUP BY `group_key` ORDER BY amount DESC, group_key"
+
+		r := &repository{
+			DB:     gormDB,
+			ReadDB: gormDB,
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(d.sql("SELECT COALESCE(transactions.category, '') AS group_key, MAX(transaction_categories.name) AS group_name, COUNT(*) AS count, SUM(transactions.amount) AS amount FROM `transactions` LEFT JOIN transaction_categories ON transaction_categories.code = transactions.category"+where))).WithArgs(
+			uint64(1), constants.TransactionTypePurchase, constants.TransactionStatusSuccess, from, to,
+		).WillReturnRows(sqlmock.NewRows([]string{"group_key", "group_name", "count", "amount"}).
+			AddRow("FOOD_AND_DRINK", "Makanan & Minuman", 3, 75000).
+			AddRow("", nil, 1, 2500.5))
+
+		got, err := r.GetSummaryGroups(context.Background(), 1, from, to, constants.SummaryGroupByCategory)
+		assert.NoError(t, err)
+		assert.Equal(t, []models.SummaryGroup{
+			{Key: "FOOD_AND_DRINK", Name: "Makanan & Minuman", Count: 3, Amount: 75000},
+			{Key: "", Count: 1, Amount: 2500.5},
+		}, got)
+
+		mock.ExpectQuery(regexp.QuoteMeta(d.sql("SELECT COALESCE(transactions.merchant_id, 0) AS group_key, MAX(transactions.merchant_name) AS group_name, COUNT(*) AS count, SUM(transactions.amount) AS amount FROM `transactions`"+where))).WithArgs(
+			uint64(1), constants.TransactionTypePurchase, constants.TransactionStatusSuccess, from, to,
+		).WillReturnRows(sqlmock.NewRows([]string{"group_key", "group_name", "count", "amount"}).
+			AddRow(9, "MERCHANT", 2, 50000).
+			AddRow(0, nil, 1, 1000))
+
+		got, err = r.GetSummaryGroups(context.Background(), 1, from, to, constants.SummaryGroupByMerchant)
+		assert.NoError(t, err)
+		assert.Equal(t, []models.SummaryGroup{
+			{Key: "9", Name: "MERCHANT", Count: 2, Amount: 50000},
+			{Key: "", Count: 1, Amount: 1000},
+		}, got)
+
+		mock.ExpectQuery(regexp.QuoteMeta(d.sql("SELECT transaction_tags.tag AS group_key, COUNT(*) AS count, SUM(transactions.amount) AS amount FROM `transactions` JOIN transaction_tags ON transaction_tags.transaction_id = transactions.id" + where))).
+			WillReturnError(assert.AnError)
+
+		_, err = r.GetSummaryGroups(context.Background(), 1, from, to, constants.SummaryGroupByTag)
+		assert.Error(t, err)
+
+		_, err = r.GetSummaryGroups(context.Background(), 1, from, to, "unknown")
+		assert.Error(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_repository_SearchTransactions(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, d testDialect, gormDB *gorm.DB, mock sqlmock.Sqlmock) {
+		matchExpr := "MATCH(description) AGAINST (? IN BOOLEAN MODE)"
+		scoreExpr := matchExpr
+		terms := "+kopi* +susu*"
+		if d.name == "postgres" {
+			matchExpr = "to_tsvector('simple', COALESCE(description, '')) @@ to_tsquery('simple', ?)"
+			scoreExpr = "ts_rank(to_tsvector('simple', COALESCE(description, '')), to_tsquery('simple', ?))"
+			terms = "kopi:* & susu:*"
+		}
+		where := " FROM `transactions` WHERE ((reference LIKE ? ESCAPE '!' OR " + matchExpr + ")) AND user_id = ? AND transaction_type = ?"
+
+		r := &repository{
+			DB:     gormDB,
+			ReadDB: gormDB,
+		}
+		req := models.SearchRequest{
+			Query:           "kopi susu",
+			UserID:          1,
+			TransactionType: constants.TransactionTypePurchase,
+			Page:            2,
+			PageSize:        10,
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(d.sql("SELECT count(*)"+where))).WithArgs(
+			"kopi susu%", terms, uint64(1), constants.TransactionTypePurchase,
+		).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+		mock.ExpectQuery(regexp.QuoteMeta(d.sql("SELECT *, CASE WHEN reference = ? THEN 100 WHEN reference LIKE ? ESCAPE '!' THEN 10 ELSE 0 END + "+scoreExpr+" AS score"+where+" ORDER BY score DESC, created_at DESC, id DESC LIMIT ? OFFSET ?"))).WithArgs(
+			"kopi susu", "kopi susu%", terms, "kopi susu%", terms, uint64(1), constants.TransactionTypePurchase, 10, 10,
+		).WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "description", "score"}).AddRow(11, 1, "kopi susu gula aren", 0.5))
+
+		got, total, err := r.SearchTransactions(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(11), total)
+		assert.Equal(t, []models.SearchResult{{Transaction: models.Transaction{ID: 11, UserID: 1, Description: "kopi susu gula aren"}, Score: 0.5}}, got)
+
+		amountTerms := "+15000*"
+		if d.name == "postgres" {
+			amountTerms = "15000:*"
+		}
+		mock.ExpectQuery(regexp.QuoteMeta(d.sql("SELECT count(*) FROM `transactions` WHERE (reference LIKE ? ESCAPE '!' OR amount = ? OR "+matchExpr+")"))).WithArgs(
+			"15000%", 15000.0, amountTerms,
+		).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		got, total, err = r.SearchTransactions(context.Background(), models.SearchRequest{Query: "15000", Page: 1, PageSize: 10})
+		assert.NoError(t, err)
+		assert.Zero(t, total)
+		assert.Empty(t, got)
+
+		// without words only the reference prefix is matched, its wildcards are escaped
+		mock.ExpectQuery(regexp.QuoteMeta(d.sql("SELECT count(*) FROM `transactions` WHERE (reference LIKE ? ESCAPE '!')"))).WithArgs(
+			"!%!%%",
+		).WillReturnError(assert.AnError)
+
+		_, _, err = r.SearchTransactions(context.Background(), models.SearchRequest{Query: "%%", Page: 1, PageSize: 10})
+		assert.Error(t, err)
+
+		// without a query the filtered transactions are listed, newest first
+		mock.ExpectQuery(regexp.QuoteMeta(d.sql("SELECT count(*) FROM `transactions` WHERE transaction_status = ?"))).WithArgs(
+			constants.TransactionStatusPending,
+		).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(regexp.QuoteMeta(d.sql("SELECT *, 0 AS score FROM `transactions` WHERE transaction_status = ? ORDER BY score DESC, created_at DESC, id DESC LIMIT ?"))).WithArgs(
+			constants.TransactionStatusPending, 10,
+		).WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "score"}).AddRow(12, 2, 0))
+
+		got, total, err = r.SearchTransactions(context.Background(), models.SearchRequest{TransactionStatus: constants.TransactionStatusPending, Page: 1, PageSize: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, []models.SearchResult{{Transaction: models.Transaction{ID: 12, UserID: 2}}}, got)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,16 @@
+package webhook
+
+import (
+	"ewallet-transaction/helpers"
+
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	DB       *gorm.DB
+	Timeouts helpers.QueryTimeouts
+}
+
+func NewRepository(db *gorm.DB, timeouts helpers.QueryTimeouts) *repository {
+	return &repository{DB: db, Timeouts: timeouts}
+}
@@ -0,0 +1,103 @@
+package webhook
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+func (r *repository) UpsertWebhook(ctx context.Context, webhook *models.MerchantWebhook) error {
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Write)
+	defer cancel()
+
+	err := r.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "merchant_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"url", "secret", "is_active", "updated_at"}),
+	}).Create(webhook).Error
+
+	return helpers.QueryError(ctx, err)
+}
+
+func (r *repository) GetWebhookByMerchantID(ctx context.Context, merchantID uint64) (models.MerchantWebhook, error) {
+	var (
+		resp models.MerchantWebhook
+	)
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Read)
+	defer cancel()
+
+	err := r.DB.WithContext(ctx).Where("merchant_id = ?", merchantID).First(&resp).Error
+
+	return resp, helpers.QueryError(ctx, err)
+}
+
+func (r *repository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Write)
+	defer cancel()
+
+	err := r.DB.WithContext(ctx).Create(delivery).Error
+
+	return helpers.QueryError(ctx, err)
+}
+
+func (r *repository) GetDeliveryByID(ctx context.Context, id int) (models.WebhookDelivery, error) {
+	var (
+		resp models.WebhookDelivery
+	)
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Read)
+	defer cancel()
+
+	err := r.DB.WithContext(ctx).Where("id = ?", id).First(&resp).Error
+
+	return resp, helpers.QueryError(ctx, err)
+}
+
+func (r *repository) GetDeliveriesByReference(ctx context.Context, merchantID uint64, reference string) ([]models.WebhookDelivery, error) {
+	var (
+		resp []models.WebhookDelivery
+	)
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Read)
+	defer cancel()
+
+	err := r.DB.WithContext(ctx).Order("id DESC").Where("merchant_id = ? AND reference = ?", merchantID, reference).Find(&resp).Error
+
+	return resp, helpers.QueryError(ctx, err)
+}
+
+func (r *repository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var (
+		resp []models.WebhookDelivery
+	)
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Read)
+	defer cancel()
+
+	err := r.DB.WithContext(ctx).Order("next_attempt_at ASC").Where("status = ? AND next_attempt_at <= ?", constants.WebhookDeliveryStatusPending, now).Limit(limit).Find(&resp).Error
+
+	return resp, helpers.QueryError(ctx, err)
+}
+
+// ClaimDelivery moves next_attempt_at of a due delivery forward so other replicas skip it
+// while it is being sent. It returns false when the delivery was claimed by someone else.
+func (r *repository) ClaimDelivery(ctx context.Context, id int, now time.Time, leaseUntil time.Time) (bool, error) {
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Write)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Exec("UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id = ? AND status = ? AND next_attempt_at <= ?", leaseUntil, id, constants.WebhookDeliveryStatusPending, now)
+	if result.Error != nil {
+		return false, helpers.QueryError(ctx, result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *repository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	ctx, cancel := helpers.QueryContext(ctx, r.Timeouts.Write)
+	defer cancel()
+
+	err := r.DB.WithContext(ctx).Save(delivery).Error
+
+	return helpers.QueryError(ctx, err)
+}
@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/migration/migrationtest"
+	"ewallet-transaction/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func Test_repository_E2E(t *testing.T) {
+	ctx := context.Background()
+	r := NewRepository(migrationtest.NewSQLiteDB(t), helpers.QueryTimeouts{})
+
+	err := r.UpsertWebhook(ctx, &models.MerchantWebhook{MerchantID: 9, URL: "https://old.merchant.test", Secret: "OLD", IsActive: true})
+	assert.NoError(t, err)
+	err = r.UpsertWebhook(ctx, &models.MerchantWebhook{MerchantID: 9, URL: "https://merchant.test", Secret: "NEW", IsActive: true})
+	assert.NoError(t, err)
+
+	webhook, err := r.GetWebhookByMerchantID(ctx, 9)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://merchant.test", webhook.URL)
+	assert.Equal(t, "NEW", webhook.Secret)
+
+	_, err = r.GetWebhookByMerchantID(ctx, 10)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	now := time.Now().UTC()
+	due := &models.WebhookDelivery{
+		MerchantID:    9,
+		Reference:     "REFERENCE",
+		EventID:       "EVENT-1",
+		EventType:     constants.EventTransactionStatusChanged,
+		URL:           webhook.URL,
+		Payload:       "{}",
+		Status:        constants.WebhookDeliveryStatusPending,
+		NextAttemptAt: now.Add(-time.Minute),
+	}
+	later := &models.WebhookDelivery{
+		MerchantID:    9,
+		Reference:     "REFERENCE",
+		EventID:       "EVENT-2",
+		EventType:     constants.EventTransactionStatusChanged,
+		URL:           webhook.URL,
+		Payload:       "{}",
+		Status:        constants.WebhookDeliveryStatusPending,
+		NextAttemptAt: now.Add(time.Hour),
+	}
+	assert.NoError(t, r.CreateDelivery(ctx, due))
+	assert.NoError(t, r.CreateDelivery(ctx, later))
+
+	deliveries, err := r.GetDeliveriesByReference(ctx, 9, "REFERENCE")
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 2)
+	assert.Equal(t, later.ID, deliveries[0].ID)
+
+	dueDeliveries, err := r.GetDueDeliveries(ctx, now, 10)
+	assert.NoError(t, err)
+	assert.Len(t, dueDeliveries, 1)
+	assert.Equal(t, due.ID, dueDeliveries[0].ID)
+
+	claimed, err := r.ClaimDelivery(ctx, due.ID, now, now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+
+	claimed, err = r.ClaimDelivery(ctx, due.ID, now, now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, claimed, "a claimed delivery is no longer due")
+
+	due.Status = constants.WebhookDeliveryStatusSuccess
+	due.Attempts = 1
+	assert.NoError(t, r.UpdateDelivery(ctx, due))
+
+	got, err := r.GetDeliveryByID(ctx, due.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, constants.WebhookDeliveryStatusSuccess, got.Status)
+	assert.Equal(t, 1, got.Attempts)
+}
@@ -0,0 +1,376 @@
+package webhook
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func Test_repository_UpsertWebhook(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{})
+
+	assert.NoError(t, err)
+
+	type args struct {
+		ctx     context.Context
+		webhook *models.MerchantWebhook
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+		mockFn  func(args args)
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx: context.Background(),
+				webhook: &models.MerchantWebhook{
+					MerchantID: 1,
+					URL:        "https://merchant.test/callback",
+					Secret:     "SECRET",
+					IsActive:   true,
+				},
+			},
+			wantErr: false,
+			mockFn: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `merchant_webhooks` (`merchant_id`,`url`,`secret`,`is_active`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?) ON DUPLICATE KEY UPDATE `url`=VALUES(`url`),`secret`=VALUES(`secret`),`is_active`=VALUES(`is_active`),`updated_at`=VALUES(`updated_at`)")).WithArgs(
+					args.webhook.MerchantID,
+					args.webhook.URL,
+					args.webhook.Secret,
+					args.webhook.IsActive,
+					sqlmock.AnyArg(),
+					sqlmock.AnyArg(),
+				).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "error",
+			args: args{
+				ctx: context.Background(),
+				webhook: &models.MerchantWebhook{
+					MerchantID: 1,
+					URL:        "https://merchant.test/callback",
+					Secret:     "SECRET",
+					IsActive:   true,
+				},
+			},
+			wantErr: true,
+			mockFn: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `merchant_webhooks`")).WillReturnError(assert.AnError)
+				mock.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn(tt.args)
+			r := &repository{
+				DB: gormDB,
+			}
+			if err := r.UpsertWebhook(tt.args.ctx, tt.args.webhook); (err != nil) != tt.wantErr {
+				t.Errorf("repository.UpsertWebhook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_repository_GetWebhookByMerchantID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{})
+
+	assert.NoError(t, err)
+
+	now := time.Now()
+
+	type args struct {
+		ctx        context.Context
+		merchantID uint64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    models.MerchantWebhook
+		wantErr bool
+		mockFn  func(args args)
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx:        context.Background(),
+				merchantID: 1,
+			},
+			want: models.MerchantWebhook{
+				ID:         1,
+				MerchantID: 1,
+				URL:        "https://merchant.test/callback",
+				Secret:     "SECRET",
+				IsActive:   true,
+				CreatedAt:  now,
+				UpdatedAt:  now,
+			},
+			wantErr: false,
+			mockFn: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `merchant_webhooks` WHERE merchant_id = ? ORDER BY `merchant_webhooks`.`id` LIMIT ?")).WithArgs(
+					args.merchantID,
+					1,
+				).WillReturnRows(sqlmock.NewRows([]string{"id", "merchant_id", "url", "secret", "is_active", "created_at", "updated_at"}).AddRow(1, 1, "https://merchant.test/callback", "SECRET", true, now, now))
+			},
+		},
+		{
+			name: "error",
+			args: args{
+				ctx:        context.Background(),
+				merchantID: 1,
+			},
+			want:    models.MerchantWebhook{},
+			wantErr: true,
+			mockFn: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `merchant_webhooks` WHERE merchant_id = ? ORDER BY `merchant_webhooks`.`id` LIMIT ?")).WithArgs(
+					args.merchantID,
+					1,
+				).WillReturnError(assert.AnError)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn(tt.args)
+			r := &repository{
+				DB: gormDB,
+			}
+			got, err := r.GetWebhookByMerchantID(tt.args.ctx, tt.args.merchantID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("repository.GetWebhookByMerchantID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("repository.GetWebhookByMerchantID() = %v, want %v", got, tt.want)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_repository_GetDeliveriesByReference(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{})
+
+	assert.NoError(t, err)
+
+	now := time.Now()
+	columns := []string{"id", "merchant_id", "reference", "event_id", "event_type", "url", "payload", "status", "attempts", "last_response_code", "last_error", "next_attempt_at", "created_at", "updated_at"}
+
+	type args struct {
+		ctx        context.Context
+		merchantID uint64
+		reference  string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []models.WebhookDelivery
+		wantErr bool
+		mockFn  func(args args)
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx:        context.Background(),
+				merchantID: 1,
+				reference:  "REFERENCE",
+			},
+			want: []models.WebhookDelivery{
+				{
+					ID:               2,
+					MerchantID:       1,
+					Reference:        "REFERENCE",
+					EventID:          "EVENT",
+					EventType:        constants.EventTransactionStatusChanged,
+					URL:              "https://merchant.test/callback",
+					Payload:          "{}",
+					Status:           constants.WebhookDeliveryStatusSuccess,
+					Attempts:         1,
+					LastResponseCode: 200,
+					NextAttemptAt:    now,
+					CreatedAt:        now,
+					UpdatedAt:        now,
+				},
+			},
+			wantErr: false,
+			mockFn: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `webhook_deliveries` WHERE merchant_id = ? AND reference = ? ORDER BY id DESC")).WithArgs(
+					args.merchantID,
+					args.reference,
+				).WillReturnRows(sqlmock.NewRows(columns).AddRow(2, 1, "REFERENCE", "EVENT", constants.EventTransactionStatusChanged, "https://merchant.test/callback", "{}", constants.WebhookDeliveryStatusSuccess, 1, 200, "", now, now, now))
+			},
+		},
+		{
+			name: "error",
+			args: args{
+				ctx:        context.Background(),
+				merchantID: 1,
+				reference:  "REFERENCE",
+			},
+			want:    nil,
+			wantErr: true,
+			mockFn: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `webhook_deliveries` WHERE merchant_id = ? AND reference = ? ORDER BY id DESC")).WithArgs(
+					args.merchantID,
+					args.reference,
+				).WillReturnError(assert.AnError)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn(tt.args)
+			r := &repository{
+				DB: gormDB,
+			}
+			got, err := r.GetDeliveriesByReference(tt.args.ctx, tt.args.merchantID, tt.args.reference)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("repository.GetDeliveriesByReference() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("repository.GetDeliveriesByReference() = %v, want %v", got, tt.want)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_repository_ClaimDelivery(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{})
+
+	assert.NoError(t, err)
+
+	now := time.Now()
+
+	type args struct {
+		ctx        context.Context
+		id         int
+		now        time.Time
+		leaseUntil time.Time
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    bool
+		wantErr bool
+		mockFn  func(args args)
+	}{
+		{
+			name: "success claimed",
+			args: args{
+				ctx:        context.Background(),
+				id:         1,
+				now:        now,
+				leaseUntil: now.Add(time.Minute),
+			},
+			want:    true,
+			wantErr: false,
+			mockFn: func(args args) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id = ? AND status = ? AND next_attempt_at <= ?")).WithArgs(
+					args.leaseUntil,
+					args.id,
+					constants.WebhookDeliveryStatusPending,
+					args.now,
+				).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "success already claimed",
+			args: args{
+				ctx:        context.Background(),
+				id:         1,
+				now:        now,
+				leaseUntil: now.Add(time.Minute),
+			},
+			want:    false,
+			wantErr: false,
+			mockFn: func(args args) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id = ? AND status = ? AND next_attempt_at <= ?")).WithArgs(
+					args.leaseUntil,
+					args.id,
+					constants.WebhookDeliveryStatusPending,
+					args.now,
+				).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "error",
+			args: args{
+				ctx:        context.Background(),
+				id:         1,
+				now:        now,
+				leaseUntil: now.Add(time.Minute),
+			},
+			want:    false,
+			wantErr: true,
+			mockFn: func(args args) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id = ? AND status = ? AND next_attempt_at <= ?")).WithArgs(
+					args.leaseUntil,
+					args.id,
+					constants.WebhookDeliveryStatusPending,
+					args.now,
+				).WillReturnError(assert.AnError)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn(tt.args)
+			r := &repository{
+				DB: gormDB,
+			}
+			got, err := r.ClaimDelivery(tt.args.ctx, tt.args.id, tt.args.now, tt.args.leaseUntil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("repository.ClaimDelivery() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ListTransactions lists the transactions of every user matching the filters, newest first.
+func (s *service) ListTransactions(ctx context.Context, admin models.TokenData, req models.SearchRequest) (models.SearchResponse, error) {
+	req.Query = ""
+	resp, err := s.searcher.Search(ctx, req)
+	s.audit(ctx, admin, constants.AdminActionListTransactions, "", "", req, err)
+
+	return resp, err
+}
+
+// SearchTransactions ranks the transactions of every user matching the query, which is required.
+func (s *service) SearchTransactions(ctx context.Context, admin models.TokenData, req models.SearchRequest) (models.SearchResponse, error) {
+	var (
+		resp models.SearchResponse
+		err  error
+	)
+
+	if strings.TrimSpace(req.Query) == "" {
+		err = errors.Wrap(models.ErrInvalidSearchRequest, "query is required")
+	} else {
+		resp, err = s.searcher.Search(ctx, req)
+	}
+	s.audit(ctx, admin, constants.AdminActionSearchTransactions, "", "", req, err)
+
+	return resp, err
+}
+
+// ForceStatus moves a transaction on behalf of its user. The status flow still applies
+// but a SUCCESS transaction can be reversed after MaximumReversalDuration.
+func (s *service) ForceStatus(ctx context.Context, admin models.TokenData, reference string, req models.AdminStatusRequest) error {
+	err := s.forceStatus(ctx, admin, reference, req)
+	s.audit(ctx, admin, constants.AdminActionForceStatus, reference, req.Reason, req, err)
+
+	return err
+}
+
+func (s *service) forceStatus(ctx context.Context, admin models.TokenData, reference string, req models.AdminStatusRequest) error {
+	if strings.TrimSpace(req.Reason) == "" {
+		return errors.Wrap(models.ErrInvalidAdminRequest, "reason is required")
+	}
+	if !constants.MapTransactionStatus[req.TransactionStatus] {
+		return errors.Wrapf(models.ErrInvalidAdminRequest, "unknown transaction status %q", req.TransactionStatus)
+	}
+
+	trx, err := s.repository.GetTransactionByReference(ctx, reference, false)
+	if err != nil {
+		return errors.Wrap(err, "failed to get transaction")
+	}
+
+	return s.transaction.UpdateStatusTransaction(ctx, s.onBehalfOf(trx), &models.UpdateStatusTransaction{
+		Reference:         reference,
+		TransactionStatus: req.TransactionStatus,
+		AdditionalInfo:    req.AdditionalInfo,
+		Actor:             actorAdmin(admin),
+		Reason:            req.Reason,
+		Force:             true,
+	})
+}
+
+// Refund refunds a SUCCESS purchase on behalf of its user.
+func (s *service) Refund(ctx context.Context, admin models.TokenData, reference string, req models.AdminRefundRequest) (models.CreateTransactionResponse, error) {
+	resp, err := s.refund(ctx, admin, reference, req)
+	s.audit(ctx, admin, constants.AdminActionRefund, reference, req.Reason, req, err)
+
+	return resp, err
+}
+
+func (s *service) refund(ctx context.Context, admin models.TokenData, reference string, req models.AdminRefundRequest) (models.CreateTransactionResponse, error) {
+	if strings.TrimSpace(req.Reason) == "" {
+		return models.CreateTransactionResponse{}, errors.Wrap(models.ErrInvalidAdminRequest, "reason is required")
+	}
+	if req.Description == "" {
+		req.Description = req.Reason
+	}
+
+	trx, err := s.repository.GetTransactionByReference(ctx, reference, false)
+	if err != nil {
+		return models.CreateTransactionResponse{}, errors.Wrap(err, "failed to get transaction")
+	}
+
+	return s.transaction.RefundTransaction(ctx, s.onBehalfOf(trx), &models.RefundTransaction{
+		Reference:      reference,
+		Description:    req.Description,
+		AdditionalInfo: req.AdditionalInfo,
+		Actor:          actorAdmin(admin),
+		Reason:         req.Reason,
+	})
+}
+
+// Approve debits a transaction parked ON_HOLD by the fraud screening, the rules are not run again.
+func (s *service) Approve(ctx context.Context, admin models.TokenData, reference string, req models.AdminReviewRequest) error {
+	err := s.review(ctx, admin, reference, constants.TransactionStatusSuccess, req)
+	s.audit(ctx, admin, constants.AdminActionApprove, reference, req.Reason, req, err)
+
+	return err
+}
+
+// Reject fails a transaction parked ON_HOLD by the fraud screening.
+func (s *service) Reject(ctx context.Context, admin models.TokenData, reference string, req models.AdminReviewRequest) error {
+	err := s.review(ctx, admin, reference, constants.TransactionStatusFailed, req)
+	s.audit(ctx, admin, constants.AdminActionReject, reference, req.Reason, req, err)
+
+	return err
+}
+
+func (s *service) review(ctx context.Context, admin models.TokenData, reference string, status string, req models.AdminReviewRequest) error {
+	if strings.TrimSpace(req.Reason) == "" {
+		return errors.Wrap(models.ErrInvalidAdminRequest, "reason is required")
+	}
+
+	trx, err := s.repository.GetTransactionByReference(ctx, reference, false)
+	if err != nil {
+		return errors.Wrap(err, "failed to get transaction")
+	}
+	if trx.TransactionStatus != constants.TransactionStatusOnHold {
+		return errors.Wrapf(models.ErrInvalidAdminRequest, "transaction is %s, not on hold", trx.TransactionStatus)
+	}
+
+	return s.transaction.UpdateStatusTransaction(ctx, s.onBehalfOf(trx), &models.UpdateStatusTransaction{
+		Reference:         reference,
+		TransactionStatus: status,
+		AdditionalInfo:    req.AdditionalInfo,
+		Actor:             actorAdmin(admin),
+		Reason:            req.Reason,
+		Force:             true,
+	})
+}
+
+// StatusHistory returns the statuses a transaction went through, oldest first.
+func (s *service) StatusHistory(ctx context.Context, admin models.TokenData, reference string) ([]models.TransactionStatusHistory, error) {
+	history, err := s.repository.GetStatusHistory(ctx, reference)
+	if err != nil {
+		err = errors.Wrap(err, "failed to get status history")
+	}
+	s.audit(ctx, admin, constants.AdminActionStatusHistory, reference, "", nil, err)
+
+	if history == nil {
+		history = []models.TransactionStatusHistory{}
+	}
+
+	return history, err
+}
+
+// ListLimits lists the limits of every tier and type.
+func (s *service) ListLimits(ctx context.Context, admin models.TokenData) ([]models.TransactionLimit, error) {
+	limits, err := s.limits.ListLimits(ctx)
+	s.audit(ctx, admin, constants.AdminActionListLimits, "", "", nil, err)
+
+	return limits, err
+}
+
+// SetLimit creates or replaces the limit of a tier and a type, it applies to the next transactions.
+func (s *service) SetLimit(ctx context.Context, admin models.TokenData, req models.AdminLimitRequest) (models.TransactionLimit, error) {
+	var (
+		limit = req.TransactionLimit
+		err   error
+	)
+
+	if strings.TrimSpace(req.Reason) == "" {
+		err = errors.Wrap(models.ErrInvalidAdminRequest, "reason is required")
+	} else {
+		req.KYCTier = strings.ToUpper(req.KYCTier)
+		req.TransactionType = strings.ToUpper(req.TransactionType)
+		limit, err = s.limits.SetLimit(ctx, req.TransactionLimit)
+	}
+	s.audit(ctx, admin, constants.AdminActionSetLimit, "", req.Reason, req, err)
+
+	return limit, err
+}
+
+// GetUserKYCTier returns the tier the limits of a user are taken from.
+func (s *service) GetUserKYCTier(ctx context.Context, admin models.TokenData, userID uint64) (models.UserKYCTier, error) {
+	tier, err := s.limits.GetUserKYCTier(ctx, userID)
+	s.audit(ctx, admin, constants.AdminActionGetKYCTier, "", "", kycTierPayload{UserID: userID}, err)
+
+	return tier, err
+}
+
+// SetUserKYCTier moves a user to another tier once their identity was checked, or back.
+func (s *service) SetUserKYCTier(ctx context.Context, admin models.TokenData, userID uint64, req models.AdminKYCTierRequest) (models.UserKYCTier, error) {
+	var (
+		tier = models.UserKYCTier{UserID: userID, KYCTier: req.KYCTier}
+		err  error
+	)
+
+	if strings.TrimSpace(req.Reason) == "" {
+		err = errors.Wrap(models.ErrInvalidAdminRequest, "reason is required")
+	} else {
+		tier, err = s.limits.SetUserKYCTier(ctx, userID, strings.ToUpper(req.KYCTier))
+	}
+	s.audit(ctx, admin, constants.AdminActionSetKYCTier, "", req.Reason, kycTierPayload{UserID: userID, KYCTier: req.KYCTier}, err)
+
+	return tier, err
+}
+
+// kycTierPayload is audited for the KYC tier actions, which have no transaction reference.
+type kycTierPayload struct {
+	UserID  uint64 `json:"user_id"`
+	KYCTier string `json:"kyc_tier,omitempty"`
+}
+
+// onBehalfOf acts as the owner of the transaction with the service token, as the settlement does.
+// The token carries no email, the transaction service resolves the owner by user id to notify them.
+func (s *service) onBehalfOf(trx models.Transaction) models.TokenData {
+	return models.TokenData{
+		UserID: trx.UserID,
+		Token:  s.config.ServiceToken,
+	}
+}
+
+// audit records the outcome of an admin action, a failure to record it is only logged
+// and the audit outlives a request cancelled by the client.
+func (s *service) audit(ctx context.Context, admin models.TokenData, action string, reference string, reason string, payload interface{}, actionErr error) {
+	log := &models.AdminAuditLog{
+		AdminUserID: admin.UserID,
+		Action:      action,
+		Reference:   reference,
+		Reason:      reason,
+		Outcome:     constants.AdminAuditOutcomeSuccess,
+	}
+
+	if payload != nil {
+		body, err := json.Marshal(payload)
+		if err == nil {
+			log.Payload = string(body)
+		}
+	}
+
+	if actionErr != nil {
+		log.Outcome = constants.AdminAuditOutcomeFailed
+		log.Error = actionErr.Error()
+	}
+
+	err := s.auditor.CreateAuditLog(context.WithoutCancel(ctx), log)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"admin_user_id": admin.UserID,
+			"action":        action,
+			"reference":     reference,
+		}).Error("failed to write admin audit log: ", err)
+	}
+}
+
+func actorAdmin(admin models.TokenData) string {
+	return constants.StatusActorAdminPrefix + strconv.FormatUint(admin.UserID, 10)
+}
@@ -0,0 +1,417 @@
+package admin
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+var adminToken = models.TokenData{
+	UserID: 7,
+	Token:  "ADMIN_TOKEN",
+}
+
+func Test_service_ForceStatus(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+	mockTrx := NewMocktransactionService(ctrlMock)
+	mockAuditor := NewMockauditor(ctrlMock)
+
+	tests := []struct {
+		name    string
+		req     models.AdminStatusRequest
+		wantErr error
+		mockFn  func(req models.AdminStatusRequest)
+	}{
+		{
+			name: "success",
+			req: models.AdminStatusRequest{
+				TransactionStatus: constants.TransactionStatusReversed,
+				Reason:            "chargeback",
+			},
+			mockFn: func(req models.AdminStatusRequest) {
+				mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "REFERENCE", false).Return(models.Transaction{ID: 1, UserID: 3, Reference: "REFERENCE"}, nil)
+				mockTrx.EXPECT().UpdateStatusTransaction(gomock.Any(), models.TokenData{UserID: 3, Token: "SERVICE_TOKEN"}, &models.UpdateStatusTransaction{
+					Reference:         "REFERENCE",
+					TransactionStatus: constants.TransactionStatusReversed,
+					Actor:             "admin:7",
+					Reason:            "chargeback",
+					Force:             true,
+				}).Return(nil)
+				mockAuditor.EXPECT().CreateAuditLog(gomock.Any(), &models.AdminAuditLog{
+					AdminUserID: 7,
+					Action:      constants.AdminActionForceStatus,
+					Reference:   "REFERENCE",
+					Reason:      "chargeback",
+					Payload:     `{"transaction_status":"REVERSED","reason":"chargeback","additional_info":""}`,
+					Outcome:     constants.AdminAuditOutcomeSuccess,
+				}).Return(nil)
+			},
+		},
+		{
+			name: "success even if the audit fails",
+			req: models.AdminStatusRequest{
+				TransactionStatus: constants.TransactionStatusSuccess,
+				Reason:            "paid at the counter",
+			},
+			mockFn: func(req models.AdminStatusRequest) {
+				mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "REFERENCE", false).Return(models.Transaction{ID: 1, UserID: 3}, nil)
+				mockTrx.EXPECT().UpdateStatusTransaction(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				mockAuditor.EXPECT().CreateAuditLog(gomock.Any(), gomock.Any()).Return(assert.AnError)
+			},
+		},
+		{
+			name: "error reason required",
+			req: models.AdminStatusRequest{
+				TransactionStatus: constants.TransactionStatusReversed,
+				Reason:            "  ",
+			},
+			wantErr: models.ErrInvalidAdminRequest,
+			mockFn: func(req models.AdminStatusRequest) {
+				mockAuditor.EXPECT().CreateAuditLog(gomock.Any(), gomock.Any()).Do(func(_ context.Context, log *models.AdminAuditLog) {
+					assert.Equal(t, constants.AdminAuditOutcomeFailed, log.Outcome)
+					assert.Equal(t, "reason is required: invalid admin request", log.Error)
+				}).Return(nil)
+			},
+		},
+		{
+			name: "error unknown status",
+			req: models.AdminStatusRequest{
+				TransactionStatus: "DONE",
+				Reason:            "chargeback",
+			},
+			wantErr: models.ErrInvalidAdminRequest,
+			mockFn: func(req models.AdminStatusRequest) {
+				mockAuditor.EXPECT().CreateAuditLog(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "error transaction not found",
+			req: models.AdminStatusRequest{
+				TransactionStatus: constants.TransactionStatusReversed,
+				Reason:            "chargeback",
+			},
+			wantErr: gorm.ErrRecordNotFound,
+			mockFn: func(req models.AdminStatusRequest) {
+				mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "REFERENCE", false).Return(models.Transaction{}, gorm.ErrRecordNotFound)
+				mockAuditor.EXPECT().CreateAuditLog(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "error update status",
+			req: models.AdminStatusRequest{
+				TransactionStatus: constants.TransactionStatusReversed,
+				Reason:            "chargeback",
+			},
+			wantErr: assert.AnError,
+			mockFn: func(req models.AdminStatusRequest) {
+				mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "REFERENCE", false).Return(models.Transaction{ID: 1, UserID: 3}, nil)
+				mockTrx.EXPECT().UpdateStatusTransaction(gomock.Any(), gomock.Any(), gomock.Any()).Return(assert.AnError)
+				mockAuditor.EXPECT().CreateAuditLog(gomock.Any(), gomock.Any()).Do(func(_ context.Context, log *models.AdminAuditLog) {
+					assert.Equal(t, constants.AdminAuditOutcomeFailed, log.Outcome)
+				}).Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn(tt.req)
+
+			s := NewService(nil, mockTrx, mockRepo, mockAuditor, nil, Config{ServiceToken: "SERVICE_TOKEN"})
+			err := s.ForceStatus(context.Background(), adminToken, "REFERENCE", tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_service_Refund(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+	mockTrx := NewMocktransactionService(ctrlMock)
+	mockAuditor := NewMockauditor(ctrlMock)
+
+	tests := []struct {
+		name    string
+		req     models.AdminRefundRequest
+		want    models.CreateTransactionResponse
+		wantErr error
+		mockFn  func()
+	}{
+		{
+			name: "success description defaults to the reason",
+			req:  models.AdminRefundRequest{Reason: "item out of stock"},
+			want: models.CreateTransactionResponse{Reference: "REFUND-REFERENCE", TransactionStatus: constants.TransactionStatusSuccess},
+			mockFn: func() {
+				mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "REFERENCE", false).Return(models.Transaction{ID: 1, UserID: 3}, nil)
+				mockTrx.EXPECT().RefundTransaction(gomock.Any(), models.TokenData{UserID: 3, Token: "SERVICE_TOKEN"}, &models.RefundTransaction{
+					Reference:   "REFERENCE",
+					Description: "item out of stock",
+					Actor:       "admin:7",
+					Reason:      "item out of stock",
+				}).Return(models.CreateTransactionResponse{Reference: "REFUND-REFERENCE", TransactionStatus: constants.TransactionStatusSuccess}, nil)
+				mockAuditor.EXPECT().CreateAuditLog(gomock.Any(), gomock.Any()).Do(func(_ context.Context, log *models.AdminAuditLog) {
+					assert.Equal(t, constants.AdminActionRefund, log.Action)
+					assert.Equal(t, constants.AdminAuditOutcomeSuccess, log.Outcome)
+				}).Return(nil)
+			},
+		},
+		{
+			name:    "error reason required",
+			req:     models.AdminRefundRequest{Description: "refund"},
+			wantErr: models.ErrInvalidAdminRequest,
+			mockFn: func() {
+				mockAuditor.EXPECT().CreateAuditLog(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:    "error refund",
+			req:     models.AdminRefundRequest{Description: "refund", Reason: "item out of stock"},
+			wantErr: assert.AnError,
+			mockFn: func() {
+				mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "REFERENCE", false).Return(models.Transaction{ID: 1, UserID: 3}, nil)
+				mockTrx.EXPECT().RefundTransaction(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.CreateTransactionResponse{}, assert.AnError)
+				mockAuditor.EXPECT().CreateAuditLog(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			s := NewService(nil, mockTrx, mockRepo, mockAuditor, nil, Config{ServiceToken: "SERVICE_TOKEN"})
+			got, err := s.Refund(context.Background(), adminToken, "REFERENCE", tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_service_SearchTransactions(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockSearcher := NewMocksearcher(ctrlMock)
+	mockAuditor := NewMockauditor(ctrlMock)
+
+	s := NewService(mockSearcher, nil, nil, mockAuditor, nil, Config{})
+
+	// listing ignores the query
+	mockSearcher.EXPECT().Search(gomock.Any(), models.SearchRequest{UserID: 3}).Return(models.SearchResponse{Page: 1}, nil)
+	mockAuditor.EXPECT().CreateAuditLog(gomock.Any(), gomock.Any()).Do(func(_ context.Context, log *models.AdminAuditLog) {
+		assert.Equal(t, constants.AdminActionListTransactions, log.Action)
+	}).Return(nil)
+
+	got, err := s.ListTransactions(context.Background(), adminToken, models.SearchRequest{Query: "kopi", UserID: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, models.SearchResponse{Page: 1}, got)
+
+	// searching requires it
+	mockAuditor.EXPECT().CreateAuditLog(gomock.Any(), gomock.Any()).Do(func(_ context.Context, log *models.AdminAuditLog) {
+		assert.Equal(t, constants.AdminActionSearchTransactions, log.Action)
+		assert.Equal(t, constants.AdminAuditOutcomeFailed, log.Outcome)
+	}).Return(nil)
+
+	_, err = s.SearchTransactions(context.Background(), adminToken, models.SearchRequest{Query: " "})
+	assert.ErrorIs(t, err, models.ErrInvalidSearchRequest)
+
+	mockSearcher.EXPECT().Search(gomock.Any(), models.SearchRequest{Query: "kopi"}).Return(models.SearchResponse{Total: 2}, nil)
+	mockAuditor.EXPECT().CreateAuditLog(gomock.Any(), gomock.Any()).Return(nil)
+
+	got, err = s.SearchTransactions(context.Background(), adminToken, models.SearchRequest{Query: "kopi"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), got.Total)
+}
+
+func Test_service_StatusHistory(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+	mockAuditor := NewMockauditor(ctrlMock)
+
+	s := NewService(nil, nil, mockRepo, mockAuditor, nil, Config{})
+
+	history := []models.TransactionStatusHistory{
+		{ID: 1, Reference: "REFERENCE", ToStatus: constants.TransactionStatusPending, Actor: "user:3"},
+		{ID: 2, Reference: "REFERENCE", FromStatus: constants.TransactionStatusPending, ToStatus: constants.TransactionStatusSuccess, Actor: "user:3"},
+	}
+	mockRepo.EXPECT().GetStatusHistory(gomock.Any(), "REFERENCE").Return(history, nil)
+	mockAuditor.EXPECT().CreateAuditLog(gomock.Any(), &models.AdminAuditLog{
+		AdminUserID: 7,
+		Action:      constants.AdminActionStatusHistory,
+		Reference:   "REFERENCE",
+		Outcome:     constants.AdminAuditOutcomeSuccess,
+	}).Return(nil)
+
+	got, err := s.StatusHistory(context.Background(), adminToken, "REFERENCE")
+	assert.NoError(t, err)
+	assert.Equal(t, history, got)
+
+	mockRepo.EXPECT().GetStatusHistory(gomock.Any(), "UNKNOWN").Return(nil, nil)
+	mockAuditor.EXPECT().CreateAuditLog(gomock.Any(), gomock.Any()).Return(nil)
+
+	got, err = s.StatusHistory(context.Background(), adminToken, "UNKNOWN")
+	assert.NoError(t, err)
+	assert.Equal(t, []models.TransactionStatusHistory{}, got)
+}
+
+func Test_service_Limits(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockLimits := NewMocklimits(ctrlMock)
+	mockAuditor := NewMockauditor(ctrlMock)
+
+	s := NewService(nil, nil, nil, mockAuditor, mockLimits, Config{})
+
+	limit := models.TransactionLimit{KYCTier: constants.KYCTierVerified, TransactionType: constants.TransactionTypeTopup, MaxAmount: 10_000_000}
+
+	mockLimits.EXPECT().ListLimits(gomock.Any()).Return([]models.TransactionLimit{limit}, nil)
+	mockAuditor.EXPECT().CreateAuditLog(gomock.Any(), &models.AdminAuditLog{
+		AdminUserID: 7,
+		Action:      constants.AdminActionListLimits,
+		Outcome:     constants.AdminAuditOutcomeSuccess,
+	}).Return(nil)
+
+	got, err := s.ListLimits(context.Background(), adminToken)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.TransactionLimit{limit}, got)
+
+	// the tier and the type are upper cased
+	mockLimits.EXPECT().SetLimit(gomock.Any(), limit).Return(limit, nil)
+	mockAuditor.EXPECT().CreateAuditLog(gomock.Any(), gomock.Any()).Do(func(_ context.Context, log *models.AdminAuditLog) {
+		assert.Equal(t, constants.AdminActionSetLimit, log.Action)
+		assert.Equal(t, "raised for verified users", log.Reason)
+		assert.Contains(t, log.Payload, `"kyc_tier":"VERIFIED"`)
+	}).Return(nil)
+
+	lower := limit
+	lower.KYCTier = "verified"
+	lower.TransactionType = "topup"
+	set, err := s.SetLimit(context.Background(), adminToken, models.AdminLimitRequest{TransactionLimit: lower, Reason: "raised for verified users"})
+	assert.NoError(t, err)
+	assert.Equal(t, limit, set)
+
+	mockAuditor.EXPECT().CreateAuditLog(gomock.Any(), gomock.Any()).Do(func(_ context.Context, log *models.AdminAuditLog) {
+		assert.Equal(t, constants.AdminAuditOutcomeFailed, log.Outcome)
+	}).Return(nil)
+
+	_, err = s.SetLimit(context.Background(), adminToken, models.AdminLimitRequest{TransactionLimit: limit})
+	assert.ErrorIs(t, err, models.ErrInvalidAdminRequest)
+
+	mockLimits.EXPECT().GetUserKYCTier(gomock.Any(), uint64(3)).Return(models.UserKYCTier{UserID: 3, KYCTier: constants.KYCTierBasic}, nil)
+	mockAuditor.EXPECT().CreateAuditLog(gomock.Any(), &models.AdminAuditLog{
+		AdminUserID: 7,
+		Action:      constants.AdminActionGetKYCTier,
+		Payload:     `{"user_id":3}`,
+		Outcome:     constants.AdminAuditOutcomeSuccess,
+	}).Return(nil)
+
+	tier, err := s.GetUserKYCTier(context.Background(), adminToken, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, constants.KYCTierBasic, tier.KYCTier)
+
+	mockLimits.EXPECT().SetUserKYCTier(gomock.Any(), uint64(3), constants.KYCTierVerified).Return(models.UserKYCTier{UserID: 3, KYCTier: constants.KYCTierVerified}, nil)
+	mockAuditor.EXPECT().CreateAuditLog(gomock.Any(), &models.AdminAuditLog{
+		AdminUserID: 7,
+		Action:      constants.AdminActionSetKYCTier,
+		Reason:      "identity checked",
+		Payload:     `{"user_id":3,"kyc_tier":"verified"}`,
+		Outcome:     constants.AdminAuditOutcomeSuccess,
+	}).Return(nil)
+
+	tier, err = s.SetUserKYCTier(context.Background(), adminToken, 3, models.AdminKYCTierRequest{KYCTier: "verified", Reason: "identity checked"})
+	assert.NoError(t, err)
+	assert.Equal(t, constants.KYCTierVerified, tier.KYCTier)
+
+	mockLimits.EXPECT().SetUserKYCTier(gomock.Any(), uint64(3), "GOLD").Return(models.UserKYCTier{}, models.ErrInvalidLimit)
+	mockAuditor.EXPECT().CreateAuditLog(gomock.Any(), gomock.Any()).Return(nil)
+
+	_, err = s.SetUserKYCTier(context.Background(), adminToken, 3, models.AdminKYCTierRequest{KYCTier: "GOLD", Reason: "typo"})
+	assert.ErrorIs(t, err, models.ErrInvalidLimit)
+}
+
+func Test_service_Review(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+	mockTrx := NewMocktransactionService(ctrlMock)
+	mockAuditor := NewMockauditor(ctrlMock)
+
+	s := NewService(nil, mockTrx, mockRepo, mockAuditor, nil, Config{ServiceToken: "SERVICE_TOKEN"})
+
+	onHold := models.Transaction{ID: 1, UserID: 3, Reference: "REFERENCE", TransactionStatus: constants.TransactionStatusOnHold}
+
+	// approving debits the transaction as its owner
+	mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "REFERENCE", false).Return(onHold, nil)
+	mockTrx.EXPECT().UpdateStatusTransaction(gomock.Any(), models.TokenData{UserID: 3, Token: "SERVICE_TOKEN"}, &models.UpdateStatusTransaction{
+		Reference:         "REFERENCE",
+		TransactionStatus: constants.TransactionStatusSuccess,
+		Actor:             "admin:7",
+		Reason:            "customer confirmed",
+		Force:             true,
+	}).Return(nil)
+	mockAuditor.EXPECT().CreateAuditLog(gomock.Any(), &models.AdminAuditLog{
+		AdminUserID: 7,
+		Action:      constants.AdminActionApprove,
+		Reference:   "REFERENCE",
+		Reason:      "customer confirmed",
+		Payload:     `{"reason":"customer confirmed","additional_info":""}`,
+		Outcome:     constants.AdminAuditOutcomeSuccess,
+	}).Return(nil)
+
+	err := s.Approve(context.Background(), adminToken, "REFERENCE", models.AdminReviewRequest{Reason: "customer confirmed"})
+	assert.NoError(t, err)
+
+	// rejecting fails it
+	mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "REFERENCE", false).Return(onHold, nil)
+	mockTrx.EXPECT().UpdateStatusTransaction(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, _ models.TokenData, req *models.UpdateStatusTransaction) {
+		assert.Equal(t, constants.TransactionStatusFailed, req.TransactionStatus)
+		assert.True(t, req.Force)
+	}).Return(nil)
+	mockAuditor.EXPECT().CreateAuditLog(gomock.Any(), gomock.Any()).Do(func(_ context.Context, log *models.AdminAuditLog) {
+		assert.Equal(t, constants.AdminActionReject, log.Action)
+	}).Return(nil)
+
+	err = s.Reject(context.Background(), adminToken, "REFERENCE", models.AdminReviewRequest{Reason: "stolen card"})
+	assert.NoError(t, err)
+
+	// only a transaction on hold is reviewed
+	mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "PENDING", false).Return(models.Transaction{TransactionStatus: constants.TransactionStatusPending}, nil)
+	mockAuditor.EXPECT().CreateAuditLog(gomock.Any(), gomock.Any()).Do(func(_ context.Context, log *models.AdminAuditLog) {
+		assert.Equal(t, constants.AdminAuditOutcomeFailed, log.Outcome)
+		assert.Equal(t, "transaction is PENDING, not on hold: invalid admin request", log.Error)
+	}).Return(nil)
+
+	err = s.Approve(context.Background(), adminToken, "PENDING", models.AdminReviewRequest{Reason: "customer confirmed"})
+	assert.ErrorIs(t, err, models.ErrInvalidAdminRequest)
+
+	mockAuditor.EXPECT().CreateAuditLog(gomock.Any(), gomock.Any()).Return(nil)
+
+	err = s.Reject(context.Background(), adminToken, "REFERENCE", models.AdminReviewRequest{})
+	assert.ErrorIs(t, err, models.ErrInvalidAdminRequest)
+
+	mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "MISSING", false).Return(models.Transaction{}, gorm.ErrRecordNotFound)
+	mockAuditor.EXPECT().CreateAuditLog(gomock.Any(), gomock.Any()).Return(nil)
+
+	err = s.Approve(context.Background(), adminToken, "MISSING", models.AdminReviewRequest{Reason: "customer confirmed"})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
@@ -0,0 +1,57 @@
+package admin
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+)
+
+//go:generate mockgen -source=service.go -destination=service_mock_test.go -package=admin
+type searcher interface {
+	Search(ctx context.Context, req models.SearchRequest) (models.SearchResponse, error)
+}
+
+type transactionService interface {
+	UpdateStatusTransaction(ctx context.Context, tokenData models.TokenData, req *models.UpdateStatusTransaction) error
+	RefundTransaction(ctx context.Context, tokenData models.TokenData, req *models.RefundTransaction) (models.CreateTransactionResponse, error)
+}
+
+type repository interface {
+	GetTransactionByReference(ctx context.Context, reference string, includeRefund bool) (models.Transaction, error)
+	GetStatusHistory(ctx context.Context, reference string) ([]models.TransactionStatusHistory, error)
+}
+
+type auditor interface {
+	CreateAuditLog(ctx context.Context, log *models.AdminAuditLog) error
+}
+
+type limits interface {
+	ListLimits(ctx context.Context) ([]models.TransactionLimit, error)
+	SetLimit(ctx context.Context, limit models.TransactionLimit) (models.TransactionLimit, error)
+	GetUserKYCTier(ctx context.Context, userID uint64) (models.UserKYCTier, error)
+	SetUserKYCTier(ctx context.Context, userID uint64, kycTier string) (models.UserKYCTier, error)
+}
+
+type Config struct {
+	// ServiceToken moves the wallet of a user on their behalf.
+	ServiceToken string
+}
+
+type service struct {
+	searcher    searcher
+	transaction transactionService
+	repository  repository
+	auditor     auditor
+	limits      limits
+	config      Config
+}
+
+func NewService(searcher searcher, transaction transactionService, repository repository, auditor auditor, limits limits, config Config) *service {
+	return &service{
+		searcher:    searcher,
+		transaction: transaction,
+		repository:  repository,
+		auditor:     auditor,
+		limits:      limits,
+		config:      config,
+	}
+}
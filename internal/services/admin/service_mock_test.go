@@ -0,0 +1,286 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=service_mock_test.go -package=admin
+//
+
+// Package admin is a generated GoMock package.
+package admin
+
+import (
+	context "context"
+	models "ewallet-transaction/internal/models"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// Mocksearcher is a mock of searcher interface.
+type Mocksearcher struct {
+	ctrl     *gomock.Controller
+	recorder *MocksearcherMockRecorder
+	isgomock struct{}
+}
+
+// MocksearcherMockRecorder is the mock recorder for Mocksearcher.
+type MocksearcherMockRecorder struct {
+	mock *Mocksearcher
+}
+
+// NewMocksearcher creates a new mock instance.
+func NewMocksearcher(ctrl *gomock.Controller) *Mocksearcher {
+	mock := &Mocksearcher{ctrl: ctrl}
+	mock.recorder = &MocksearcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mocksearcher) EXPECT() *MocksearcherMockRecorder {
+	return m.recorder
+}
+
+// Search mocks base method.
+func (m *Mocksearcher) Search(ctx context.Context, req models.SearchRequest) (models.SearchResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, req)
+	ret0, _ := ret[0].(models.SearchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MocksearcherMockRecorder) Search(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*Mocksearcher)(nil).Search), ctx, req)
+}
+
+// MocktransactionService is a mock of transactionService interface.
+type MocktransactionService struct {
+	ctrl     *gomock.Controller
+	recorder *MocktransactionServiceMockRecorder
+	isgomock struct{}
+}
+
+// MocktransactionServiceMockRecorder is the mock recorder for MocktransactionService.
+type MocktransactionServiceMockRecorder struct {
+	mock *MocktransactionService
+}
+
+// NewMocktransactionService creates a new mock instance.
+func NewMocktransactionService(ctrl *gomock.Controller) *MocktransactionService {
+	mock := &MocktransactionService{ctrl: ctrl}
+	mock.recorder = &MocktransactionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocktransactionService) EXPECT() *MocktransactionServiceMockRecorder {
+	return m.recorder
+}
+
+// RefundTransaction mocks base method.
+func (m *MocktransactionService) RefundTransaction(ctx context.Context, tokenData models.TokenData, req *models.RefundTransaction) (models.CreateTransactionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefundTransaction", ctx, tokenData, req)
+	ret0, _ := ret[0].(models.CreateTransactionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefundTransaction indicates an expected call of RefundTransaction.
+func (mr *MocktransactionServiceMockRecorder) RefundTransaction(ctx, tokenData, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundTransaction", reflect.TypeOf((*MocktransactionService)(nil).RefundTransaction), ctx, tokenData, req)
+}
+
+// UpdateStatusTransaction mocks base method.
+func (m *MocktransactionService) UpdateStatusTransaction(ctx context.Context, tokenData models.TokenData, req *models.UpdateStatusTransaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatusTransaction", ctx, tokenData, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatusTransaction indicates an expected call of UpdateStatusTransaction.
+func (mr *MocktransactionServiceMockRecorder) UpdateStatusTransaction(ctx, tokenData, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatusTransaction", reflect.TypeOf((*MocktransactionService)(nil).UpdateStatusTransaction), ctx, tokenData, req)
+}
+
+// Mockrepository is a mock of repository interface.
+type Mockrepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockrepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockrepositoryMockRecorder is the mock recorder for Mockrepository.
+type MockrepositoryMockRecorder struct {
+	mock *Mockrepository
+}
+
+// NewMockrepository creates a new mock instance.
+func NewMockrepository(ctrl *gomock.Controller) *Mockrepository {
+	mock := &Mockrepository{ctrl: ctrl}
+	mock.recorder = &MockrepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockrepository) EXPECT() *MockrepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetStatusHistory mocks base method.
+func (m *Mockrepository) GetStatusHistory(ctx context.Context, reference string) ([]models.TransactionStatusHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatusHistory", ctx, reference)
+	ret0, _ := ret[0].([]models.TransactionStatusHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatusHistory indicates an expected call of GetStatusHistory.
+func (mr *MockrepositoryMockRecorder) GetStatusHistory(ctx, reference any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatusHistory", reflect.TypeOf((*Mockrepository)(nil).GetStatusHistory), ctx, reference)
+}
+
+// GetTransactionByReference mocks base method.
+func (m *Mockrepository) GetTransactionByReference(ctx context.Context, reference string, includeRefund bool) (models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionByReference", ctx, reference, includeRefund)
+	ret0, _ := ret[0].(models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionByReference indicates an expected call of GetTransactionByReference.
+func (mr *MockrepositoryMockRecorder) GetTransactionByReference(ctx, reference, includeRefund any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionByReference", reflect.TypeOf((*Mockrepository)(nil).GetTransactionByReference), ctx, reference, includeRefund)
+}
+
+// Mockauditor is a mock of auditor interface.
+type Mockauditor struct {
+	ctrl     *gomock.Controller
+	recorder *MockauditorMockRecorder
+	isgomock struct{}
+}
+
+// MockauditorMockRecorder is the mock recorder for Mockauditor.
+type MockauditorMockRecorder struct {
+	mock *Mockauditor
+}
+
+// NewMockauditor creates a new mock instance.
+func NewMockauditor(ctrl *gomock.Controller) *Mockauditor {
+	mock := &Mockauditor{ctrl: ctrl}
+	mock.recorder = &MockauditorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockauditor) EXPECT() *MockauditorMockRecorder {
+	return m.recorder
+}
+
+// CreateAuditLog mocks base method.
+func (m *Mockauditor) CreateAuditLog(ctx context.Context, log *models.AdminAuditLog) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditLog", ctx, log)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAuditLog indicates an expected call of CreateAuditLog.
+func (mr *MockauditorMockRecorder) CreateAuditLog(ctx, log any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditLog", reflect.TypeOf((*Mockauditor)(nil).CreateAuditLog), ctx, log)
+}
+
+// Mocklimits is a mock of limits interface.
+type Mocklimits struct {
+	ctrl     *gomock.Controller
+	recorder *MocklimitsMockRecorder
+	isgomock struct{}
+}
+
+// MocklimitsMockRecorder is the mock recorder for Mocklimits.
+type MocklimitsMockRecorder struct {
+	mock *Mocklimits
+}
+
+// NewMocklimits creates a new mock instance.
+func NewMocklimits(ctrl *gomock.Controller) *Mocklimits {
+	mock := &Mocklimits{ctrl: ctrl}
+	mock.recorder = &MocklimitsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mocklimits) EXPECT() *MocklimitsMockRecorder {
+	return m.recorder
+}
+
+// GetUserKYCTier mocks base method.
+func (m *Mocklimits) GetUserKYCTier(ctx context.Context, userID uint64) (models.UserKYCTier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserKYCTier", ctx, userID)
+	ret0, _ := ret[0].(models.UserKYCTier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserKYCTier indicates an expected call of GetUserKYCTier.
+func (mr *MocklimitsMockRecorder) GetUserKYCTier(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserKYCTier", reflect.TypeOf((*Mocklimits)(nil).GetUserKYCTier), ctx, userID)
+}
+
+// ListLimits mocks base method.
+func (m *Mocklimits) ListLimits(ctx context.Context) ([]models.TransactionLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLimits", ctx)
+	ret0, _ := ret[0].([]models.TransactionLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLimits indicates an expected call of ListLimits.
+func (mr *MocklimitsMockRecorder) ListLimits(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLimits", reflect.TypeOf((*Mocklimits)(nil).ListLimits), ctx)
+}
+
+// SetLimit mocks base method.
+func (m *Mocklimits) SetLimit(ctx context.Context, limit models.TransactionLimit) (models.TransactionLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLimit", ctx, limit)
+	ret0, _ := ret[0].(models.TransactionLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetLimit indicates an expected call of SetLimit.
+func (mr *MocklimitsMockRecorder) SetLimit(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLimit", reflect.TypeOf((*Mocklimits)(nil).SetLimit), ctx, limit)
+}
+
+// SetUserKYCTier mocks base method.
+func (m *Mocklimits) SetUserKYCTier(ctx context.Context, userID uint64, kycTier string) (models.UserKYCTier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserKYCTier", ctx, userID, kycTier)
+	ret0, _ := ret[0].(models.UserKYCTier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUserKYCTier indicates an expected call of SetUserKYCTier.
+func (mr *MocklimitsMockRecorder) SetUserKYCTier(ctx, userID, kycTier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserKYCTier", reflect.TypeOf((*Mocklimits)(nil).SetUserKYCTier), ctx, userID, kycTier)
+}
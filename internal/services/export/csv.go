@@ -0,0 +1,84 @@
+package export
+
+import (
+	"encoding/csv"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+	"io"
+	"strconv"
+	"time"
+)
+
+var csvHeader = []string{"date", "reference", "transaction_type", "transaction_status", "description", "debit", "credit", "balance"}
+
+type csvWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) BeginPeriod(period models.StatementPeriod) error {
+	if !c.header {
+		c.header = true
+		if err := c.w.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+
+	return c.w.Write([]string{
+		period.Start.Format(constants.ExportDateLayout), "", constants.ExportRowOpening, "", "", "", "", formatAmount(period.Opening),
+	})
+}
+
+func (c *csvWriter) WriteRow(row models.StatementRow) error {
+	trx := row.Transaction
+	return c.w.Write([]string{
+		trx.CreatedAt.Format("2006-01-02 15:04:05"),
+		trx.Reference,
+		trx.TransactionType,
+		trx.TransactionStatus,
+		trx.Description,
+		formatOptionalAmount(row.Debit),
+		formatOptionalAmount(row.Credit),
+		formatAmount(row.Balance),
+	})
+}
+
+// EndPeriod also flushes, so a long statement reaches the client month by month.
+func (c *csvWriter) EndPeriod(period models.StatementPeriod) error {
+	err := c.w.Write([]string{
+		lastDay(period).Format(constants.ExportDateLayout), "", constants.ExportRowClosing, "", "",
+		formatAmount(period.TotalDebit), formatAmount(period.TotalCredit), formatAmount(period.Closing),
+	})
+	if err != nil {
+		return err
+	}
+
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+func formatOptionalAmount(amount float64) string {
+	if amount == 0 {
+		return ""
+	}
+
+	return formatAmount(amount)
+}
+
+// lastDay is the last day covered by the period, its end is exclusive.
+func lastDay(period models.StatementPeriod) time.Time {
+	return period.End.Add(-time.Nanosecond)
+}
@@ -0,0 +1,168 @@
+package export
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// statementWriter renders a statement as it is streamed, period by period.
+type statementWriter interface {
+	BeginPeriod(period models.StatementPeriod) error
+	WriteRow(row models.StatementRow) error
+	EndPeriod(period models.StatementPeriod) error
+	Close() error
+}
+
+// Validate checks an export request before anything is written to the response.
+func (s *service) Validate(req models.ExportRequest) error {
+	if _, ok := constants.MapExportContentType[req.Format]; !ok {
+		return fmt.Errorf("unsupported export format %q", req.Format)
+	}
+
+	if !req.From.Before(req.To) {
+		return errors.New("export from must be before to")
+	}
+
+	if s.config.MaxRange > 0 && req.To.Sub(req.From) > s.config.MaxRange {
+		return fmt.Errorf("export range must not be longer than %s", s.config.MaxRange)
+	}
+
+	return nil
+}
+
+// Export writes the statement of the user over [From, To) to w. The rows are streamed from the
+// repository, each calendar month gets its opening and closing balance, months without any
+// transaction included. The balance is the sum of the successful transactions only.
+func (s *service) Export(ctx context.Context, req models.ExportRequest, w io.Writer) error {
+	if err := s.Validate(req); err != nil {
+		return err
+	}
+
+	opening, err := s.repository.GetBalanceBefore(ctx, req.UserID, req.From)
+	if err != nil {
+		return errors.Wrap(err, "failed to get opening balance")
+	}
+
+	sw, err := s.newWriter(req, w)
+	if err != nil {
+		return err
+	}
+
+	st := &statement{
+		writer:  sw,
+		to:      req.To,
+		balance: helpers.Cents(opening),
+	}
+	if err := st.begin(req.From); err != nil {
+		return err
+	}
+
+	err = s.repository.StreamTransactions(ctx, req.UserID, req.From, req.To, st.write)
+	if err != nil {
+		return errors.Wrap(err, "failed to stream transactions")
+	}
+
+	if err := st.finish(); err != nil {
+		return err
+	}
+
+	return sw.Close()
+}
+
+func (s *service) newWriter(req models.ExportRequest, w io.Writer) (statementWriter, error) {
+	switch req.Format {
+	case constants.ExportFormatCSV:
+		return newCSVWriter(w), nil
+	case constants.ExportFormatXLSX:
+		return newXLSXWriter(w)
+	case constants.ExportFormatPDF:
+		return newPDFWriter(w, s.config.BrandName, req), nil
+	}
+
+	return nil, fmt.Errorf("unsupported export format %q", req.Format)
+}
+
+// statement tracks the running balance in cents and closes each period once a row passes its end.
+type statement struct {
+	writer  statementWriter
+	to      time.Time
+	balance int64
+	period  models.StatementPeriod
+	debit   int64
+	credit  int64
+}
+
+func (st *statement) begin(start time.Time) error {
+	end := time.Date(start.Year(), start.Month()+1, 1, 0, 0, 0, 0, start.Location())
+	if end.After(st.to) {
+		end = st.to
+	}
+
+	st.period = models.StatementPeriod{
+		Start:   start,
+		End:     end,
+		Opening: helpers.Amount(st.balance),
+	}
+	st.debit, st.credit = 0, 0
+
+	return st.writer.BeginPeriod(st.period)
+}
+
+func (st *statement) end() error {
+	st.period.Closing = helpers.Amount(st.balance)
+	st.period.TotalDebit = helpers.Amount(st.debit)
+	st.period.TotalCredit = helpers.Amount(st.credit)
+
+	return st.writer.EndPeriod(st.period)
+}
+
+func (st *statement) write(trx models.Transaction) error {
+	for !trx.CreatedAt.Before(st.period.End) && st.period.End.Before(st.to) {
+		if err := st.end(); err != nil {
+			return err
+		}
+		if err := st.begin(st.period.End); err != nil {
+			return err
+		}
+	}
+
+	row := models.StatementRow{Transaction: trx}
+	if trx.TransactionStatus == constants.TransactionStatusSuccess {
+		value := helpers.Cents(trx.Amount)
+		if trx.TransactionType == constants.TransactionTypePurchase {
+			st.balance -= value
+			st.debit += value
+			row.Debit = helpers.Amount(value)
+		} else {
+			st.balance += value
+			st.credit += value
+			row.Credit = helpers.Amount(value)
+		}
+	}
+	row.Balance = helpers.Amount(st.balance)
+	st.period.Count++
+
+	return st.writer.WriteRow(row)
+}
+
+// finish closes the current period and writes the empty months left until the end of the range.
+func (st *statement) finish() error {
+	for {
+		if err := st.end(); err != nil {
+			return err
+		}
+		if !st.period.End.Before(st.to) {
+			return nil
+		}
+		if err := st.begin(st.period.End); err != nil {
+			return err
+		}
+	}
+}
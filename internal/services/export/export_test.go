@@ -0,0 +1,162 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	exportFrom = time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	exportTo   = time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+)
+
+func exportTransactions() []models.Transaction {
+	trx := func(reference string, trxType string, status string, amount float64, createdAt time.Time) models.Transaction {
+		return models.Transaction{
+			UserID:            1,
+			Amount:            amount,
+			TransactionType:   trxType,
+			TransactionStatus: status,
+			Reference:         reference,
+			Description:       "desc " + reference,
+			CreatedAt:         createdAt,
+		}
+	}
+
+	return []models.Transaction{
+		trx("T1", constants.TransactionTypeTopup, constants.TransactionStatusSuccess, 500.10, time.Date(2025, 1, 20, 8, 0, 0, 0, time.UTC)),
+		trx("P1", constants.TransactionTypePurchase, constants.TransactionStatusSuccess, 200, time.Date(2025, 1, 31, 23, 59, 0, 0, time.UTC)),
+		trx("P2", constants.TransactionTypePurchase, constants.TransactionStatusFailed, 999, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)),
+		trx("R1", constants.TransactionTypeRefund, constants.TransactionStatusSuccess, 200, time.Date(2025, 3, 2, 10, 30, 0, 0, time.UTC)),
+	}
+}
+
+func expectStatement(mockRepo *Mockrepository) {
+	mockRepo.EXPECT().GetBalanceBefore(gomock.Any(), uint64(1), exportFrom).Return(1000.0, nil)
+	mockRepo.EXPECT().StreamTransactions(gomock.Any(), uint64(1), exportFrom, exportTo, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, userID uint64, from time.Time, to time.Time, fn func(models.Transaction) error) error {
+			for _, trx := range exportTransactions() {
+				if err := fn(trx); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+}
+
+func Test_service_Export_CSV(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+	expectStatement(mockRepo)
+
+	s := NewService(mockRepo, Config{MaxRange: constants.DefaultExportMaxRange})
+	var out bytes.Buffer
+	err := s.Export(context.Background(), models.ExportRequest{UserID: 1, Format: constants.ExportFormatCSV, From: exportFrom, To: exportTo}, &out)
+	assert.NoError(t, err)
+
+	assert.Equal(t, strings.Join([]string{
+		"date,reference,transaction_type,transaction_status,description,debit,credit,balance",
+		"2025-01-15,,OPENING_BALANCE,,,,,1000.00",
+		"2025-01-20 08:00:00,T1,TOPUP,SUCCESS,desc T1,,500.10,1500.10",
+		"2025-01-31 23:59:00,P1,PURCHASE,SUCCESS,desc P1,200.00,,1300.10",
+		"2025-01-31,,CLOSING_BALANCE,,,200.00,500.10,1300.10",
+		"2025-02-01,,OPENING_BALANCE,,,,,1300.10",
+		"2025-02-28,,CLOSING_BALANCE,,,0.00,0.00,1300.10",
+		"2025-03-01,,OPENING_BALANCE,,,,,1300.10",
+		"2025-03-01 00:00:00,P2,PURCHASE,FAILED,desc P2,,,1300.10",
+		"2025-03-02 10:30:00,R1,REFUND,SUCCESS,desc R1,,200.00,1500.10",
+		"2025-03-09,,CLOSING_BALANCE,,,0.00,200.00,1500.10",
+		"",
+	}, "\n"), out.String())
+}
+
+func Test_service_Export_XLSX(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+	expectStatement(mockRepo)
+
+	s := NewService(mockRepo, Config{})
+	var out bytes.Buffer
+	err := s.Export(context.Background(), models.ExportRequest{UserID: 1, Format: constants.ExportFormatXLSX, From: exportFrom, To: exportTo}, &out)
+	assert.NoError(t, err)
+
+	f, err := excelize.OpenReader(&out)
+	assert.NoError(t, err)
+	defer f.Close()
+
+	rows, err := f.GetRows(xlsxSheet, excelize.Options{RawCellValue: true})
+	assert.NoError(t, err)
+	assert.Len(t, rows, 11)
+	assert.Equal(t, csvHeader, rows[0])
+	assert.Equal(t, []string{"2025-01-20 08:00:00", "T1", "TOPUP", "SUCCESS", "desc T1", "", "500.1", "1500.1"}, rows[2])
+	assert.Equal(t, []string{"2025-03-09", "", "CLOSING_BALANCE", "", "", "0", "200", "1500.1"}, rows[10])
+}
+
+func Test_service_Export_PDF(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+	expectStatement(mockRepo)
+
+	s := NewService(mockRepo, Config{BrandName: "E-Wallet"})
+	var out bytes.Buffer
+	err := s.Export(context.Background(), models.ExportRequest{UserID: 1, Format: constants.ExportFormatPDF, From: exportFrom, To: exportTo}, &out)
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(out.Bytes(), []byte("%PDF-")))
+	assert.Contains(t, out.String(), "/Count 3", "one page per month")
+}
+
+func Test_service_Export_Error(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+	s := NewService(mockRepo, Config{MaxRange: 24 * time.Hour})
+	req := models.ExportRequest{UserID: 1, Format: constants.ExportFormatCSV, From: exportFrom, To: exportFrom.Add(time.Hour)}
+
+	invalid := req
+	invalid.Format = "doc"
+	assert.Error(t, s.Export(context.Background(), invalid, &bytes.Buffer{}))
+
+	invalid = req
+	invalid.To = invalid.From
+	assert.Error(t, s.Export(context.Background(), invalid, &bytes.Buffer{}))
+
+	invalid = req
+	invalid.To = invalid.From.Add(48 * time.Hour)
+	assert.Error(t, s.Export(context.Background(), invalid, &bytes.Buffer{}))
+
+	mockRepo.EXPECT().GetBalanceBefore(gomock.Any(), uint64(1), exportFrom).Return(0.0, assert.AnError)
+	assert.ErrorIs(t, s.Export(context.Background(), req, &bytes.Buffer{}), assert.AnError)
+
+	mockRepo.EXPECT().GetBalanceBefore(gomock.Any(), uint64(1), exportFrom).Return(0.0, nil)
+	mockRepo.EXPECT().StreamTransactions(gomock.Any(), uint64(1), exportFrom, req.To, gomock.Any()).Return(assert.AnError)
+	assert.ErrorIs(t, s.Export(context.Background(), req, &bytes.Buffer{}), assert.AnError)
+}
+
+func Test_formatRupiah(t *testing.T) {
+	tests := map[float64]string{
+		0:          "0,00",
+		5.5:        "5,50",
+		1000:       "1.000,00",
+		1250000.5:  "1.250.000,50",
+		-123456.78: "-123.456,78",
+	}
+	for amount, want := range tests {
+		assert.Equal(t, want, formatRupiah(amount))
+	}
+}
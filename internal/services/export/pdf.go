@@ -0,0 +1,234 @@
+package export
+
+import (
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/pkg/errors"
+)
+
+var pdfMonths = [...]string{"Jan", "Feb", "Mar", "Apr", "Mei", "Jun", "Jul", "Agu", "Sep", "Okt", "Nov", "Des"}
+
+var pdfColumns = []struct {
+	title string
+	width float64
+	align string
+}{
+	{"Tanggal", 24, "L"},
+	{"Referensi", 34, "L"},
+	{"Jenis", 18, "L"},
+	{"Status", 18, "L"},
+	{"Keterangan", 36, "L"},
+	{"Debit", 20, "R"},
+	{"Kredit", 20, "R"},
+	{"Saldo", 20, "R"},
+}
+
+const (
+	pdfRowHeight = 6
+	pdfMargin    = 10
+)
+
+// pdfWriter renders a monthly statement, one page or more per month. The document is built
+// in memory by gofpdf and only written on Close, the export range is capped by MaxRange.
+type pdfWriter struct {
+	out       io.Writer
+	pdf       *gofpdf.Fpdf
+	tr        func(string) string
+	brandName string
+	userID    uint64
+}
+
+func newPDFWriter(out io.Writer, brandName string, req models.ExportRequest) *pdfWriter {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, 30, pdfMargin)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetTitle("Laporan Transaksi "+brandName, true)
+	pdf.SetCreator(brandName, true)
+
+	p := &pdfWriter{
+		out:       out,
+		pdf:       pdf,
+		tr:        pdf.UnicodeTranslatorFromDescriptor(""),
+		brandName: brandName,
+		userID:    req.UserID,
+	}
+	pdf.SetHeaderFunc(p.header)
+	pdf.SetFooterFunc(p.footer)
+
+	return p
+}
+
+func (p *pdfWriter) header() {
+	width, _ := p.pdf.GetPageSize()
+
+	p.pdf.SetFillColor(0, 82, 155)
+	p.pdf.Rect(0, 0, width, 20, "F")
+
+	p.pdf.SetTextColor(255, 255, 255)
+	p.pdf.SetFont("Helvetica", "B", 16)
+	p.pdf.SetXY(pdfMargin, 6)
+	p.pdf.CellFormat(width/2, 8, p.tr(p.brandName), "", 0, "L", false, 0, "")
+
+	p.pdf.SetFont("Helvetica", "", 11)
+	p.pdf.SetXY(width/2, 6)
+	p.pdf.CellFormat(width/2-pdfMargin, 8, "Laporan Transaksi", "", 0, "R", false, 0, "")
+
+	p.pdf.SetTextColor(0, 0, 0)
+	p.pdf.SetXY(pdfMargin, 26)
+}
+
+func (p *pdfWriter) footer() {
+	_, height := p.pdf.GetPageSize()
+
+	p.pdf.SetFont("Helvetica", "I", 8)
+	p.pdf.SetTextColor(120, 120, 120)
+	p.pdf.SetXY(pdfMargin, height-12)
+	p.pdf.CellFormat(0, 6, fmt.Sprintf("Halaman %d", p.pdf.PageNo()), "", 0, "C", false, 0, "")
+	p.pdf.SetTextColor(0, 0, 0)
+}
+
+func (p *pdfWriter) tableHeader() {
+	p.pdf.SetFont("Helvetica", "B", 8)
+	p.pdf.SetFillColor(230, 236, 245)
+	for _, column := range pdfColumns {
+		p.pdf.CellFormat(column.width, pdfRowHeight, column.title, "1", 0, column.align, true, 0, "")
+	}
+	p.pdf.Ln(-1)
+	p.pdf.SetFont("Helvetica", "", 7)
+}
+
+// ensureSpace starts a new page with the table header when the next lines would run into the footer.
+func (p *pdfWriter) ensureSpace(lines int) {
+	_, height := p.pdf.GetPageSize()
+	if p.pdf.GetY()+float64(lines)*pdfRowHeight > height-20 {
+		p.pdf.AddPage()
+		p.tableHeader()
+	}
+}
+
+func (p *pdfWriter) BeginPeriod(period models.StatementPeriod) error {
+	p.pdf.AddPage()
+
+	p.pdf.SetFont("Helvetica", "B", 12)
+	p.pdf.CellFormat(0, 7, "Periode "+formatDate(period.Start)+" - "+formatDate(lastDay(period)), "", 1, "L", false, 0, "")
+	p.pdf.SetFont("Helvetica", "", 9)
+	p.pdf.CellFormat(0, 5, "ID Pengguna: "+strconv.FormatUint(p.userID, 10), "", 1, "L", false, 0, "")
+	p.pdf.CellFormat(0, 5, "Saldo Awal: Rp "+formatRupiah(period.Opening), "", 1, "L", false, 0, "")
+	p.pdf.Ln(3)
+
+	p.tableHeader()
+
+	return p.pdf.Error()
+}
+
+func (p *pdfWriter) WriteRow(row models.StatementRow) error {
+	p.ensureSpace(1)
+
+	trx := row.Transaction
+	values := []string{
+		formatDate(trx.CreatedAt) + " " + trx.CreatedAt.Format("15:04"),
+		trx.Reference,
+		trx.TransactionType,
+		trx.TransactionStatus,
+		trx.Description,
+		formatOptionalRupiah(row.Debit),
+		formatOptionalRupiah(row.Credit),
+		formatRupiah(row.Balance),
+	}
+	for i, column := range pdfColumns {
+		p.pdf.CellFormat(column.width, pdfRowHeight, p.fit(values[i], column.width), "1", 0, column.align, false, 0, "")
+	}
+	p.pdf.Ln(-1)
+
+	return p.pdf.Error()
+}
+
+func (p *pdfWriter) EndPeriod(period models.StatementPeriod) error {
+	if period.Count == 0 {
+		p.pdf.CellFormat(0, pdfRowHeight, "Tidak ada transaksi pada periode ini", "1", 1, "C", false, 0, "")
+	}
+
+	p.ensureSpace(5)
+	p.pdf.Ln(3)
+
+	summary := [][2]string{
+		{"Jumlah Transaksi", strconv.Itoa(period.Count)},
+		{"Total Debit", "Rp " + formatRupiah(period.TotalDebit)},
+		{"Total Kredit", "Rp " + formatRupiah(period.TotalCredit)},
+		{"Saldo Akhir", "Rp " + formatRupiah(period.Closing)},
+	}
+	for i, line := range summary {
+		style := ""
+		if i == len(summary)-1 {
+			style = "B"
+		}
+		p.pdf.SetFont("Helvetica", style, 9)
+		p.pdf.CellFormat(40, 5, line[0], "", 0, "L", false, 0, "")
+		p.pdf.CellFormat(50, 5, line[1], "", 1, "R", false, 0, "")
+	}
+
+	return p.pdf.Error()
+}
+
+func (p *pdfWriter) Close() error {
+	if err := p.pdf.Output(p.out); err != nil {
+		return errors.Wrap(err, "failed to write pdf")
+	}
+
+	return nil
+}
+
+// fit truncates the text so it stays inside its cell.
+func (p *pdfWriter) fit(text string, width float64) string {
+	text = p.tr(text)
+	max := width - 2*p.pdf.GetCellMargin()
+	if p.pdf.GetStringWidth(text) <= max {
+		return text
+	}
+
+	for len(text) > 0 && p.pdf.GetStringWidth(text+"...") > max {
+		text = text[:len(text)-1]
+	}
+
+	return text + "..."
+}
+
+func formatDate(t time.Time) string {
+	return fmt.Sprintf("%02d %s %d", t.Day(), pdfMonths[t.Month()-1], t.Year())
+}
+
+// formatRupiah writes the amount the indonesian way, 1.250.000,50.
+func formatRupiah(amount float64) string {
+	value := helpers.Cents(amount)
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+
+	whole := strconv.FormatInt(value/100, 10)
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	return fmt.Sprintf("%s%s,%02d", sign, grouped.String(), value%100)
+}
+
+func formatOptionalRupiah(amount float64) string {
+	if amount == 0 {
+		return ""
+	}
+
+	return formatRupiah(amount)
+}
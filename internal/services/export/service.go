@@ -0,0 +1,30 @@
+package export
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+	"time"
+)
+
+//go:generate mockgen -source=service.go -destination=service_mock_test.go -package=export
+type repository interface {
+	StreamTransactions(ctx context.Context, userID uint64, from time.Time, to time.Time, fn func(models.Transaction) error) error
+	GetBalanceBefore(ctx context.Context, userID uint64, before time.Time) (float64, error)
+}
+
+type Config struct {
+	BrandName string
+	MaxRange  time.Duration
+}
+
+type service struct {
+	repository repository
+	config     Config
+}
+
+func NewService(repository repository, config Config) *service {
+	return &service{
+		repository: repository,
+		config:     config,
+	}
+}
@@ -0,0 +1,147 @@
+package export
+
+import (
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/xuri/excelize/v2"
+)
+
+const xlsxSheet = "Statement"
+
+// xlsxWriter writes the rows through the excelize stream writer, which spills
+// to a temporary file instead of keeping every cell in memory.
+type xlsxWriter struct {
+	out         io.Writer
+	file        *excelize.File
+	sw          *excelize.StreamWriter
+	row         int
+	boldStyle   int
+	amountStyle int
+	totalStyle  int
+}
+
+func newXLSXWriter(out io.Writer) (*xlsxWriter, error) {
+	x := &xlsxWriter{out: out, file: excelize.NewFile()}
+	if err := x.setup(); err != nil {
+		x.file.Close()
+		return nil, errors.Wrap(err, "failed to setup xlsx writer")
+	}
+
+	return x, nil
+}
+
+var xlsxColumnWidths = []struct {
+	min   int
+	max   int
+	width float64
+}{
+	{1, 1, 20},
+	{2, 2, 30},
+	{5, 5, 40},
+	{6, 8, 18},
+}
+
+func (x *xlsxWriter) setup() error {
+	if err := x.file.SetSheetName("Sheet1", xlsxSheet); err != nil {
+		return err
+	}
+
+	var err error
+	if x.boldStyle, err = x.file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}}); err != nil {
+		return err
+	}
+	if x.amountStyle, err = x.file.NewStyle(&excelize.Style{NumFmt: 4}); err != nil {
+		return err
+	}
+	if x.totalStyle, err = x.file.NewStyle(&excelize.Style{NumFmt: 4, Font: &excelize.Font{Bold: true}}); err != nil {
+		return err
+	}
+
+	if x.sw, err = x.file.NewStreamWriter(xlsxSheet); err != nil {
+		return err
+	}
+	for _, column := range xlsxColumnWidths {
+		if err := x.sw.SetColWidth(column.min, column.max, column.width); err != nil {
+			return err
+		}
+	}
+
+	header := make([]interface{}, len(csvHeader))
+	for i, column := range csvHeader {
+		header[i] = excelize.Cell{StyleID: x.boldStyle, Value: column}
+	}
+
+	return x.writeRow(header)
+}
+
+func (x *xlsxWriter) writeRow(values []interface{}) error {
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+
+	return x.sw.SetRow(cell, values)
+}
+
+func (x *xlsxWriter) amount(value float64, style int) interface{} {
+	return excelize.Cell{StyleID: style, Value: value}
+}
+
+func (x *xlsxWriter) BeginPeriod(period models.StatementPeriod) error {
+	return x.writeRow([]interface{}{
+		period.Start.Format(constants.ExportDateLayout), nil,
+		excelize.Cell{StyleID: x.boldStyle, Value: constants.ExportRowOpening}, nil, nil, nil, nil,
+		x.amount(period.Opening, x.totalStyle),
+	})
+}
+
+func (x *xlsxWriter) WriteRow(row models.StatementRow) error {
+	trx := row.Transaction
+
+	var debit, credit interface{}
+	if row.Debit != 0 {
+		debit = x.amount(row.Debit, x.amountStyle)
+	}
+	if row.Credit != 0 {
+		credit = x.amount(row.Credit, x.amountStyle)
+	}
+
+	return x.writeRow([]interface{}{
+		trx.CreatedAt.Format("2006-01-02 15:04:05"),
+		trx.Reference,
+		trx.TransactionType,
+		trx.TransactionStatus,
+		trx.Description,
+		debit,
+		credit,
+		x.amount(row.Balance, x.amountStyle),
+	})
+}
+
+func (x *xlsxWriter) EndPeriod(period models.StatementPeriod) error {
+	return x.writeRow([]interface{}{
+		lastDay(period).Format(constants.ExportDateLayout), nil,
+		excelize.Cell{StyleID: x.boldStyle, Value: constants.ExportRowClosing}, nil, nil,
+		x.amount(period.TotalDebit, x.totalStyle),
+		x.amount(period.TotalCredit, x.totalStyle),
+		x.amount(period.Closing, x.totalStyle),
+	})
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.file.Close()
+
+	if err := x.sw.Flush(); err != nil {
+		return errors.Wrap(err, "failed to flush xlsx rows")
+	}
+
+	if _, err := x.file.WriteTo(x.out); err != nil {
+		return errors.Wrap(err, "failed to write xlsx")
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package fraud
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// rule flags a transaction from the history of its user, it returns no hit when it lets the transaction through.
+type rule func(trx models.Transaction, history models.FraudHistory, config Config) *models.FraudRuleHit
+
+var rules = []rule{
+	velocityRule,
+	amountDeviationRule,
+	newDeviceRule,
+	newIPRule,
+	refundCycleRule,
+}
+
+// Screen runs every rule on trx and returns the most severe decision with the rules that flagged it.
+func (s *service) Screen(ctx context.Context, trx models.Transaction) (models.FraudScreening, error) {
+	screening := models.FraudScreening{Decision: constants.FraudDecisionAllow}
+
+	now := s.now()
+	query := models.FraudHistoryQuery{
+		Since:         now.Add(-s.config.Lookback),
+		VelocitySince: now.Add(-s.config.VelocityWindow),
+		RefundSince:   now.Add(-s.config.RefundWindow),
+	}
+	for _, since := range []time.Time{query.VelocitySince, query.RefundSince} {
+		if since.Before(query.Since) {
+			query.Since = since
+		}
+	}
+
+	history, err := s.repository.GetFraudHistory(ctx, trx, query)
+	if err != nil {
+		return screening, errors.Wrap(err, "failed to get fraud history")
+	}
+
+	for _, rule := range rules {
+		hit := rule(trx, history, s.config)
+		if hit == nil {
+			continue
+		}
+
+		screening.Hits = append(screening.Hits, *hit)
+		if constants.MapFraudDecisionSeverity[hit.Decision] > constants.MapFraudDecisionSeverity[screening.Decision] {
+			screening.Decision = hit.Decision
+		}
+	}
+
+	return screening, nil
+}
+
+// velocityRule flags a burst of transactions, the screened one included.
+func velocityRule(trx models.Transaction, history models.FraudHistory, config Config) *models.FraudRuleHit {
+	count := history.RecentCount + 1
+	detail := fmt.Sprintf("%d transactions in the last %s", count, config.VelocityWindow)
+
+	switch {
+	case config.VelocityDeny > 0 && count >= int64(config.VelocityDeny):
+		return &models.FraudRuleHit{Rule: constants.FraudRuleVelocity, Decision: constants.FraudDecisionDeny, Detail: detail}
+	case config.VelocityReview > 0 && count >= int64(config.VelocityReview):
+		return &models.FraudRuleHit{Rule: constants.FraudRuleVelocity, Decision: constants.FraudDecisionReview, Detail: detail}
+	}
+
+	return nil
+}
+
+// amountDeviationRule flags an amount far above the average of the user for the type,
+// once the user has enough history for the average to mean something.
+func amountDeviationRule(trx models.Transaction, history models.FraudHistory, config Config) *models.FraudRuleHit {
+	if config.AmountFactor <= 0 || history.TypeCount < int64(config.MinHistory) || history.AverageAmount <= 0 {
+		return nil
+	}
+
+	if trx.Amount <= history.AverageAmount*float64(config.AmountFactor) {
+		return nil
+	}
+
+	return &models.FraudRuleHit{
+		Rule:     constants.FraudRuleAmountDeviation,
+		Decision: constants.FraudDecisionReview,
+		Detail:   fmt.Sprintf("amount %.2f is more than %d times the average %.2f", trx.Amount, config.AmountFactor, history.AverageAmount),
+	}
+}
+
+// newDeviceRule flags a device the user never succeeded from, a first transaction is not flagged.
+func newDeviceRule(trx models.Transaction, history models.FraudHistory, config Config) *models.FraudRuleHit {
+	if !config.NewDevice || trx.DeviceID == "" || history.SuccessCount == 0 || history.DeviceCount > 0 {
+		return nil
+	}
+
+	return &models.FraudRuleHit{
+		Rule:     constants.FraudRuleNewDevice,
+		Decision: constants.FraudDecisionReview,
+		Detail:   fmt.Sprintf("device %s is new to the user", trx.DeviceID),
+	}
+}
+
+// newIPRule flags an address the user never succeeded from, a first transaction is not flagged.
+func newIPRule(trx models.Transaction, history models.FraudHistory, config Config) *models.FraudRuleHit {
+	if !config.NewDevice || trx.ClientIP == "" || history.SuccessCount == 0 || history.IPCount > 0 {
+		return nil
+	}
+
+	return &models.FraudRuleHit{
+		Rule:     constants.FraudRuleNewIP,
+		Decision: constants.FraudDecisionReview,
+		Detail:   fmt.Sprintf("address %s is new to the user", trx.ClientIP),
+	}
+}
+
+// refundCycleRule flags a user who keeps buying and getting refunded.
+func refundCycleRule(trx models.Transaction, history models.FraudHistory, config Config) *models.FraudRuleHit {
+	detail := fmt.Sprintf("%d refunds in the last %s", history.RefundCount, config.RefundWindow)
+
+	switch {
+	case config.RefundDeny > 0 && history.RefundCount >= int64(config.RefundDeny):
+		return &models.FraudRuleHit{Rule: constants.FraudRuleRefundCycle, Decision: constants.FraudDecisionDeny, Detail: detail}
+	case config.RefundReview > 0 && history.RefundCount >= int64(config.RefundReview):
+		return &models.FraudRuleHit{Rule: constants.FraudRuleRefundCycle, Decision: constants.FraudDecisionReview, Detail: detail}
+	}
+
+	return nil
+}
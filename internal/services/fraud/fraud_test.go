@@ -0,0 +1,202 @@
+package fraud
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_service_Screen(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	config := Config{
+		VelocityWindow: 10 * time.Minute,
+		VelocityReview: 5,
+		VelocityDeny:   10,
+		Lookback:       90 * 24 * time.Hour,
+		MinHistory:     3,
+		AmountFactor:   5,
+		NewDevice:      true,
+		RefundWindow:   24 * time.Hour,
+		RefundReview:   3,
+		RefundDeny:     5,
+	}
+	trx := models.Transaction{
+		ID:              1,
+		UserID:          3,
+		Amount:          100000,
+		TransactionType: constants.TransactionTypePurchase,
+		DeviceID:        "DEVICE",
+		ClientIP:        "10.0.0.1",
+	}
+	// a regular user, from a known device
+	regular := models.FraudHistory{RecentCount: 1, SuccessCount: 10, TypeCount: 8, AverageAmount: 50000, DeviceCount: 8, IPCount: 8}
+
+	tests := []struct {
+		name    string
+		trx     models.Transaction
+		config  Config
+		history models.FraudHistory
+		want    models.FraudScreening
+		wantErr error
+	}{
+		{
+			name:    "allow",
+			trx:     trx,
+			config:  config,
+			history: regular,
+			want:    models.FraudScreening{Decision: constants.FraudDecisionAllow},
+		},
+		{
+			name:   "allow first transaction from any device",
+			trx:    trx,
+			config: config,
+			want:   models.FraudScreening{Decision: constants.FraudDecisionAllow},
+		},
+		{
+			name:    "review velocity",
+			trx:     trx,
+			config:  config,
+			history: models.FraudHistory{RecentCount: 4, SuccessCount: 10, DeviceCount: 1, IPCount: 1},
+			want: models.FraudScreening{Decision: constants.FraudDecisionReview, Hits: []models.FraudRuleHit{
+				{Rule: constants.FraudRuleVelocity, Decision: constants.FraudDecisionReview, Detail: "5 transactions in the last 10m0s"},
+			}},
+		},
+		{
+			name:    "deny velocity",
+			trx:     trx,
+			config:  config,
+			history: models.FraudHistory{RecentCount: 9},
+			want: models.FraudScreening{Decision: constants.FraudDecisionDeny, Hits: []models.FraudRuleHit{
+				{Rule: constants.FraudRuleVelocity, Decision: constants.FraudDecisionDeny, Detail: "10 transactions in the last 10m0s"},
+			}},
+		},
+		{
+			name: "review amount deviation",
+			trx: models.Transaction{
+				UserID: 3, Amount: 250000.01, TransactionType: constants.TransactionTypePurchase, DeviceID: "DEVICE", ClientIP: "10.0.0.1",
+			},
+			config:  config,
+			history: regular,
+			want: models.FraudScreening{Decision: constants.FraudDecisionReview, Hits: []models.FraudRuleHit{
+				{Rule: constants.FraudRuleAmountDeviation, Decision: constants.FraudDecisionReview, Detail: "amount 250000.01 is more than 5 times the average 50000.00"},
+			}},
+		},
+		{
+			name:    "allow large amount without enough history",
+			trx:     models.Transaction{UserID: 3, Amount: 1000000, TransactionType: constants.TransactionTypePurchase},
+			config:  config,
+			history: models.FraudHistory{SuccessCount: 2, TypeCount: 2, AverageAmount: 10000},
+			want:    models.FraudScreening{Decision: constants.FraudDecisionAllow},
+		},
+		{
+			name:    "review new device and address",
+			trx:     trx,
+			config:  config,
+			history: models.FraudHistory{SuccessCount: 10, TypeCount: 8, AverageAmount: 50000},
+			want: models.FraudScreening{Decision: constants.FraudDecisionReview, Hits: []models.FraudRuleHit{
+				{Rule: constants.FraudRuleNewDevice, Decision: constants.FraudDecisionReview, Detail: "device DEVICE is new to the user"},
+				{Rule: constants.FraudRuleNewIP, Decision: constants.FraudDecisionReview, Detail: "address 10.0.0.1 is new to the user"},
+			}},
+		},
+		{
+			name: "allow new device when disabled",
+			trx:  trx,
+			config: Config{
+				VelocityWindow: config.VelocityWindow,
+				Lookback:       config.Lookback,
+				RefundWindow:   config.RefundWindow,
+			},
+			history: models.FraudHistory{SuccessCount: 10, RefundCount: 20, RecentCount: 20},
+			want:    models.FraudScreening{Decision: constants.FraudDecisionAllow},
+		},
+		{
+			name:    "deny refund cycle over a review",
+			trx:     trx,
+			config:  config,
+			history: models.FraudHistory{RecentCount: 5, SuccessCount: 10, DeviceCount: 1, IPCount: 1, RefundCount: 5},
+			want: models.FraudScreening{Decision: constants.FraudDecisionDeny, Hits: []models.FraudRuleHit{
+				{Rule: constants.FraudRuleVelocity, Decision: constants.FraudDecisionReview, Detail: "6 transactions in the last 10m0s"},
+				{Rule: constants.FraudRuleRefundCycle, Decision: constants.FraudDecisionDeny, Detail: "5 refunds in the last 24h0m0s"},
+			}},
+		},
+		{
+			name:    "review refund cycle",
+			trx:     trx,
+			config:  config,
+			history: models.FraudHistory{SuccessCount: 10, DeviceCount: 1, IPCount: 1, RefundCount: 3},
+			want: models.FraudScreening{Decision: constants.FraudDecisionReview, Hits: []models.FraudRuleHit{
+				{Rule: constants.FraudRuleRefundCycle, Decision: constants.FraudDecisionReview, Detail: "3 refunds in the last 24h0m0s"},
+			}},
+		},
+		{
+			name:    "error history",
+			trx:     trx,
+			config:  config,
+			wantErr: assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			if tt.wantErr != nil {
+				err = tt.wantErr
+			}
+			mockRepo.EXPECT().GetFraudHistory(gomock.Any(), tt.trx, models.FraudHistoryQuery{
+				Since:         now.Add(-tt.config.Lookback),
+				VelocitySince: now.Add(-tt.config.VelocityWindow),
+				RefundSince:   now.Add(-tt.config.RefundWindow),
+			}).Return(tt.history, err)
+
+			s := NewService(mockRepo, tt.config)
+			s.now = func() time.Time { return now }
+
+			got, err := s.Screen(context.Background(), tt.trx)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_service_Screen_since(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// the history starts at the earliest window
+	mockRepo.EXPECT().GetFraudHistory(gomock.Any(), gomock.Any(), models.FraudHistoryQuery{
+		Since:         now.Add(-48 * time.Hour),
+		VelocitySince: now.Add(-10 * time.Minute),
+		RefundSince:   now.Add(-48 * time.Hour),
+	}).Return(models.FraudHistory{}, nil)
+
+	s := NewService(mockRepo, Config{VelocityWindow: 10 * time.Minute, Lookback: time.Hour, RefundWindow: 48 * time.Hour})
+	s.now = func() time.Time { return now }
+
+	got, err := s.Screen(context.Background(), models.Transaction{})
+	assert.NoError(t, err)
+	assert.Equal(t, constants.FraudDecisionAllow, got.Decision)
+}
+
+func TestFraudScreening_Reason(t *testing.T) {
+	screening := models.FraudScreening{Hits: []models.FraudRuleHit{
+		{Rule: constants.FraudRuleVelocity, Detail: "6 transactions in the last 10m0s"},
+		{Rule: constants.FraudRuleNewIP, Detail: "address 10.0.0.1 is new to the user"},
+	}}
+	assert.Equal(t, "VELOCITY: 6 transactions in the last 10m0s; NEW_IP: address 10.0.0.1 is new to the user", screening.Reason())
+}
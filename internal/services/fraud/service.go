@@ -0,0 +1,45 @@
+package fraud
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+	"time"
+)
+
+//go:generate mockgen -source=service.go -destination=service_mock_test.go -package=fraud
+type repository interface {
+	GetFraudHistory(ctx context.Context, trx models.Transaction, query models.FraudHistoryQuery) (models.FraudHistory, error)
+}
+
+// Config sets the thresholds of the rules, a zero threshold disables its rule.
+type Config struct {
+	// VelocityWindow counts the recent transactions of the user, VelocityReview of them are reviewed
+	// and VelocityDeny denied, the screened transaction included.
+	VelocityWindow time.Duration
+	VelocityReview int
+	VelocityDeny   int
+	// Lookback is the history the average amount and the known devices are taken from,
+	// a user needs MinHistory SUCCESS transactions of the type before the amount is compared.
+	Lookback     time.Duration
+	MinHistory   int
+	AmountFactor int
+	// NewDevice reviews a transaction from a device or an address the user never succeeded from.
+	NewDevice    bool
+	RefundWindow time.Duration
+	RefundReview int
+	RefundDeny   int
+}
+
+type service struct {
+	repository repository
+	config     Config
+	now        func() time.Time
+}
+
+func NewService(repository repository, config Config) *service {
+	return &service{
+		repository: repository,
+		config:     config,
+		now:        time.Now,
+	}
+}
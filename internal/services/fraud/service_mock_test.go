@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=service_mock_test.go -package=fraud
+//
+
+// Package fraud is a generated GoMock package.
+package fraud
+
+import (
+	context "context"
+	models "ewallet-transaction/internal/models"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// Mockrepository is a mock of repository interface.
+type Mockrepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockrepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockrepositoryMockRecorder is the mock recorder for Mockrepository.
+type MockrepositoryMockRecorder struct {
+	mock *Mockrepository
+}
+
+// NewMockrepository creates a new mock instance.
+func NewMockrepository(ctrl *gomock.Controller) *Mockrepository {
+	mock := &Mockrepository{ctrl: ctrl}
+	mock.recorder = &MockrepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockrepository) EXPECT() *MockrepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetFraudHistory mocks base method.
+func (m *Mockrepository) GetFraudHistory(ctx context.Context, trx models.Transaction, query models.FraudHistoryQuery) (models.FraudHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFraudHistory", ctx, trx, query)
+	ret0, _ := ret[0].(models.FraudHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFraudHistory indicates an expected call of GetFraudHistory.
+func (mr *MockrepositoryMockRecorder) GetFraudHistory(ctx, trx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFraudHistory", reflect.TypeOf((*Mockrepository)(nil).GetFraudHistory), ctx, trx, query)
+}
@@ -0,0 +1,159 @@
+package ledger
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// Post validates and stores a balanced journal entry. Posting the same reference and entry type
+// again is a no-op, so callers can safely retry.
+func (s *service) Post(ctx context.Context, entry *models.JournalEntry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+
+	_, err := s.repository.GetJournalEntry(ctx, entry.Reference, entry.EntryType)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.Wrap(err, "failed to get journal entry")
+	}
+
+	err = s.repository.CreateJournalEntry(ctx, entry)
+	if err != nil {
+		return errors.Wrap(err, "failed to create journal entry")
+	}
+
+	return nil
+}
+
+// PostSuccess posts a transaction that moved to SUCCESS.
+// A topup moves money from settlement into the user wallet, a purchase moves it from the wallet
+// to settlement minus the fee share.
+func (s *service) PostSuccess(ctx context.Context, trx models.Transaction) error {
+	wallet, err := s.walletAccount(ctx, trx.UserID)
+	if err != nil {
+		return err
+	}
+
+	settlement, err := s.account(ctx, constants.LedgerAccountSettlement, constants.LedgerAccountTypeSettlement, 0)
+	if err != nil {
+		return err
+	}
+
+	entry := &models.JournalEntry{
+		Reference:   trx.Reference,
+		EntryType:   constants.JournalEntryTypeSuccess,
+		Description: trx.Description,
+	}
+
+	switch trx.TransactionType {
+	case constants.TransactionTypeTopup:
+		entry.Lines = []models.JournalLine{
+			debit(settlement.ID, trx.Amount),
+			credit(wallet.ID, trx.Amount),
+		}
+	case constants.TransactionTypePurchase:
+		amount := models.JournalLine{Amount: trx.Amount}.Cents()
+		fee := amount * int64(s.config.PurchaseFeeBps) / 10000
+
+		entry.Lines = []models.JournalLine{
+			debit(wallet.ID, trx.Amount),
+			credit(settlement.ID, float64(amount-fee)/100),
+		}
+		if fee > 0 {
+			feeAccount, err := s.account(ctx, constants.LedgerAccountFee, constants.LedgerAccountTypeFee, 0)
+			if err != nil {
+				return err
+			}
+
+			entry.Lines = append(entry.Lines, credit(feeAccount.ID, float64(fee)/100))
+		}
+	default:
+		return fmt.Errorf("transaction type %s has no ledger posting", trx.TransactionType)
+	}
+
+	return s.Post(ctx, entry)
+}
+
+// PostReversal posts the mirror of the SUCCESS entry of a reversed transaction.
+func (s *service) PostReversal(ctx context.Context, trx models.Transaction) error {
+	return s.postMirror(ctx, trx.Reference, trx.Reference, constants.JournalEntryTypeReversed, trx.Description)
+}
+
+// PostRefund posts the mirror of the SUCCESS entry of the refunded purchase under the refund reference.
+func (s *service) PostRefund(ctx context.Context, refund models.Transaction, originalReference string) error {
+	return s.postMirror(ctx, originalReference, refund.Reference, constants.JournalEntryTypeRefund, refund.Description)
+}
+
+// CheckInvariants verifies that every stored journal entry nets to zero.
+func (s *service) CheckInvariants(ctx context.Context) error {
+	ids, err := s.repository.GetUnbalancedEntries(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get unbalanced journal entries")
+	}
+
+	if len(ids) > 0 {
+		return fmt.Errorf("ledger has %d unbalanced journal entries: %v", len(ids), ids)
+	}
+
+	return nil
+}
+
+func (s *service) postMirror(ctx context.Context, originalReference string, reference string, entryType string, description string) error {
+	original, err := s.repository.GetJournalEntry(ctx, originalReference, constants.JournalEntryTypeSuccess)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get success journal entry of %s", originalReference)
+	}
+
+	entry := &models.JournalEntry{
+		Reference:   reference,
+		EntryType:   entryType,
+		Description: description,
+		Lines:       make([]models.JournalLine, 0, len(original.Lines)),
+	}
+	for _, line := range original.Lines {
+		if line.Direction == constants.JournalDirectionDebit {
+			entry.Lines = append(entry.Lines, credit(line.AccountID, line.Amount))
+		} else {
+			entry.Lines = append(entry.Lines, debit(line.AccountID, line.Amount))
+		}
+	}
+
+	return s.Post(ctx, entry)
+}
+
+func (s *service) walletAccount(ctx context.Context, userID uint64) (models.LedgerAccount, error) {
+	code := constants.LedgerAccountWalletPrefix + strconv.FormatUint(userID, 10)
+	return s.account(ctx, code, constants.LedgerAccountTypeWallet, userID)
+}
+
+func (s *service) account(ctx context.Context, code string, accountType string, userID uint64) (models.LedgerAccount, error) {
+	account := models.LedgerAccount{
+		Code:        code,
+		AccountType: accountType,
+		UserID:      userID,
+	}
+
+	err := s.repository.GetOrCreateAccount(ctx, &account)
+	if err != nil {
+		return account, errors.Wrapf(err, "failed to get ledger account %s", code)
+	}
+
+	return account, nil
+}
+
+func debit(accountID int, amount float64) models.JournalLine {
+	return models.JournalLine{AccountID: accountID, Direction: constants.JournalDirectionDebit, Amount: amount}
+}
+
+func credit(accountID int, amount float64) models.JournalLine {
+	return models.JournalLine{AccountID: accountID, Direction: constants.JournalDirectionCredit, Amount: amount}
+}
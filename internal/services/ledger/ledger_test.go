@@ -0,0 +1,265 @@
+package ledger
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+// accounts gives every ledger account code a stable id, like the stored rows would.
+var accounts = map[string]int{
+	constants.LedgerAccountSettlement:           1,
+	constants.LedgerAccountFee:                  2,
+	constants.LedgerAccountWalletPrefix + "100": 3,
+}
+
+func expectAccounts(mockRepo *MockrepositoryMockRecorder) {
+	mockRepo.GetOrCreateAccount(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, account *models.LedgerAccount) error {
+		account.ID = accounts[account.Code]
+		return nil
+	}).AnyTimes()
+}
+
+func Test_service_Post(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+
+	balanced := func() *models.JournalEntry {
+		return &models.JournalEntry{
+			Reference: "REFERENCE",
+			EntryType: constants.JournalEntryTypeSuccess,
+			Lines: []models.JournalLine{
+				{AccountID: 1, Direction: constants.JournalDirectionDebit, Amount: 100.10},
+				{AccountID: 2, Direction: constants.JournalDirectionCredit, Amount: 100},
+				{AccountID: 3, Direction: constants.JournalDirectionCredit, Amount: 0.10},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		entry   func() *models.JournalEntry
+		wantErr bool
+		mockFn  func()
+	}{
+		{
+			name:  "success",
+			entry: balanced,
+			mockFn: func() {
+				mockRepo.EXPECT().GetJournalEntry(gomock.Any(), "REFERENCE", constants.JournalEntryTypeSuccess).Return(models.JournalEntry{}, gorm.ErrRecordNotFound)
+				mockRepo.EXPECT().CreateJournalEntry(gomock.Any(), balanced()).Return(nil)
+			},
+		},
+		{
+			name:  "success already posted",
+			entry: balanced,
+			mockFn: func() {
+				mockRepo.EXPECT().GetJournalEntry(gomock.Any(), "REFERENCE", constants.JournalEntryTypeSuccess).Return(models.JournalEntry{ID: 1}, nil)
+			},
+		},
+		{
+			name: "error not balanced",
+			entry: func() *models.JournalEntry {
+				entry := balanced()
+				entry.Lines[2].Amount = 0.09
+				return entry
+			},
+			wantErr: true,
+			mockFn:  func() {},
+		},
+		{
+			name: "error single line",
+			entry: func() *models.JournalEntry {
+				entry := balanced()
+				entry.Lines = entry.Lines[:1]
+				return entry
+			},
+			wantErr: true,
+			mockFn:  func() {},
+		},
+		{
+			name: "error negative amount",
+			entry: func() *models.JournalEntry {
+				entry := balanced()
+				entry.Lines[0].Amount = -100.10
+				entry.Lines[1].Amount = -100
+				entry.Lines[2].Amount = -0.10
+				return entry
+			},
+			wantErr: true,
+			mockFn:  func() {},
+		},
+		{
+			name: "error invalid direction",
+			entry: func() *models.JournalEntry {
+				entry := balanced()
+				entry.Lines[0].Direction = "SIDEWAYS"
+				return entry
+			},
+			wantErr: true,
+			mockFn:  func() {},
+		},
+		{
+			name:    "error get journal entry",
+			entry:   balanced,
+			wantErr: true,
+			mockFn: func() {
+				mockRepo.EXPECT().GetJournalEntry(gomock.Any(), "REFERENCE", constants.JournalEntryTypeSuccess).Return(models.JournalEntry{}, assert.AnError)
+			},
+		},
+		{
+			name:    "error create journal entry",
+			entry:   balanced,
+			wantErr: true,
+			mockFn: func() {
+				mockRepo.EXPECT().GetJournalEntry(gomock.Any(), "REFERENCE", constants.JournalEntryTypeSuccess).Return(models.JournalEntry{}, gorm.ErrRecordNotFound)
+				mockRepo.EXPECT().CreateJournalEntry(gomock.Any(), gomock.Any()).Return(assert.AnError)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+			s := NewService(mockRepo, Config{})
+			if err := s.Post(context.Background(), tt.entry()); (err != nil) != tt.wantErr {
+				t.Errorf("service.Post() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_service_PostSuccess(t *testing.T) {
+	tests := []struct {
+		name      string
+		trx       models.Transaction
+		feeBps    int
+		wantLines []models.JournalLine
+		wantErr   bool
+	}{
+		{
+			name: "success topup",
+			trx:  models.Transaction{UserID: 100, Amount: 50000, TransactionType: constants.TransactionTypeTopup, Reference: "REFERENCE"},
+			wantLines: []models.JournalLine{
+				{AccountID: 1, Direction: constants.JournalDirectionDebit, Amount: 50000},
+				{AccountID: 3, Direction: constants.JournalDirectionCredit, Amount: 50000},
+			},
+		},
+		{
+			name: "success purchase without fee",
+			trx:  models.Transaction{UserID: 100, Amount: 50000, TransactionType: constants.TransactionTypePurchase, Reference: "REFERENCE"},
+			wantLines: []models.JournalLine{
+				{AccountID: 3, Direction: constants.JournalDirectionDebit, Amount: 50000},
+				{AccountID: 1, Direction: constants.JournalDirectionCredit, Amount: 50000},
+			},
+		},
+		{
+			name:   "success purchase with fee",
+			trx:    models.Transaction{UserID: 100, Amount: 50000.55, TransactionType: constants.TransactionTypePurchase, Reference: "REFERENCE"},
+			feeBps: 150,
+			wantLines: []models.JournalLine{
+				{AccountID: 3, Direction: constants.JournalDirectionDebit, Amount: 50000.55},
+				{AccountID: 1, Direction: constants.JournalDirectionCredit, Amount: 49250.55},
+				{AccountID: 2, Direction: constants.JournalDirectionCredit, Amount: 750},
+			},
+		},
+		{
+			name:    "error refund has no success posting",
+			trx:     models.Transaction{UserID: 100, Amount: 50000, TransactionType: constants.TransactionTypeRefund, Reference: "REFERENCE"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrlMock := gomock.NewController(t)
+			defer ctrlMock.Finish()
+
+			mockRepo := NewMockrepository(ctrlMock)
+			expectAccounts(mockRepo.EXPECT())
+			if !tt.wantErr {
+				mockRepo.EXPECT().GetJournalEntry(gomock.Any(), tt.trx.Reference, constants.JournalEntryTypeSuccess).Return(models.JournalEntry{}, gorm.ErrRecordNotFound)
+				mockRepo.EXPECT().CreateJournalEntry(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, entry *models.JournalEntry) {
+					assert.Equal(t, tt.wantLines, entry.Lines)
+				}).Return(nil)
+			}
+
+			s := NewService(mockRepo, Config{PurchaseFeeBps: tt.feeBps})
+			if err := s.PostSuccess(context.Background(), tt.trx); (err != nil) != tt.wantErr {
+				t.Errorf("service.PostSuccess() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_service_PostMirror(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+
+	success := models.JournalEntry{
+		ID:        1,
+		Reference: "REFERENCE",
+		EntryType: constants.JournalEntryTypeSuccess,
+		Lines: []models.JournalLine{
+			{AccountID: 3, Direction: constants.JournalDirectionDebit, Amount: 50000},
+			{AccountID: 1, Direction: constants.JournalDirectionCredit, Amount: 49250},
+			{AccountID: 2, Direction: constants.JournalDirectionCredit, Amount: 750},
+		},
+	}
+	mirrored := []models.JournalLine{
+		{AccountID: 3, Direction: constants.JournalDirectionCredit, Amount: 50000},
+		{AccountID: 1, Direction: constants.JournalDirectionDebit, Amount: 49250},
+		{AccountID: 2, Direction: constants.JournalDirectionDebit, Amount: 750},
+	}
+
+	s := NewService(mockRepo, Config{})
+
+	mockRepo.EXPECT().GetJournalEntry(gomock.Any(), "REFERENCE", constants.JournalEntryTypeSuccess).Return(success, nil)
+	mockRepo.EXPECT().GetJournalEntry(gomock.Any(), "REFERENCE", constants.JournalEntryTypeReversed).Return(models.JournalEntry{}, gorm.ErrRecordNotFound)
+	mockRepo.EXPECT().CreateJournalEntry(gomock.Any(), &models.JournalEntry{
+		Reference: "REFERENCE",
+		EntryType: constants.JournalEntryTypeReversed,
+		Lines:     mirrored,
+	}).Return(nil)
+	err := s.PostReversal(context.Background(), models.Transaction{Reference: "REFERENCE"})
+	assert.NoError(t, err)
+
+	mockRepo.EXPECT().GetJournalEntry(gomock.Any(), "REFERENCE", constants.JournalEntryTypeSuccess).Return(success, nil)
+	mockRepo.EXPECT().GetJournalEntry(gomock.Any(), "REFUND-REFERENCE", constants.JournalEntryTypeRefund).Return(models.JournalEntry{}, gorm.ErrRecordNotFound)
+	mockRepo.EXPECT().CreateJournalEntry(gomock.Any(), &models.JournalEntry{
+		Reference:   "REFUND-REFERENCE",
+		EntryType:   constants.JournalEntryTypeRefund,
+		Description: "refund",
+		Lines:       mirrored,
+	}).Return(nil)
+	err = s.PostRefund(context.Background(), models.Transaction{Reference: "REFUND-REFERENCE", Description: "refund"}, "REFERENCE")
+	assert.NoError(t, err)
+
+	mockRepo.EXPECT().GetJournalEntry(gomock.Any(), "MISSING", constants.JournalEntryTypeSuccess).Return(models.JournalEntry{}, gorm.ErrRecordNotFound)
+	err = s.PostReversal(context.Background(), models.Transaction{Reference: "MISSING"})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func Test_service_CheckInvariants(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+	s := NewService(mockRepo, Config{})
+
+	mockRepo.EXPECT().GetUnbalancedEntries(gomock.Any()).Return(nil, nil)
+	assert.NoError(t, s.CheckInvariants(context.Background()))
+
+	mockRepo.EXPECT().GetUnbalancedEntries(gomock.Any()).Return([]int{4, 9}, nil)
+	assert.EqualError(t, s.CheckInvariants(context.Background()), "ledger has 2 unbalanced journal entries: [4 9]")
+
+	mockRepo.EXPECT().GetUnbalancedEntries(gomock.Any()).Return(nil, assert.AnError)
+	assert.Error(t, s.CheckInvariants(context.Background()))
+}
@@ -0,0 +1,30 @@
+package ledger
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+)
+
+//go:generate mockgen -source=service.go -destination=service_mock_test.go -package=ledger
+type repository interface {
+	GetOrCreateAccount(ctx context.Context, account *models.LedgerAccount) error
+	CreateJournalEntry(ctx context.Context, entry *models.JournalEntry) error
+	GetJournalEntry(ctx context.Context, reference string, entryType string) (models.JournalEntry, error)
+	GetUnbalancedEntries(ctx context.Context) ([]int, error)
+}
+
+type Config struct {
+	PurchaseFeeBps int
+}
+
+type service struct {
+	repository repository
+	config     Config
+}
+
+func NewService(repository repository, config Config) *service {
+	return &service{
+		repository: repository,
+		config:     config,
+	}
+}
@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=service_mock_test.go -package=ledger
+//
+
+// Package ledger is a generated GoMock package.
+package ledger
+
+import (
+	context "context"
+	models "ewallet-transaction/internal/models"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// Mockrepository is a mock of repository interface.
+type Mockrepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockrepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockrepositoryMockRecorder is the mock recorder for Mockrepository.
+type MockrepositoryMockRecorder struct {
+	mock *Mockrepository
+}
+
+// NewMockrepository creates a new mock instance.
+func NewMockrepository(ctrl *gomock.Controller) *Mockrepository {
+	mock := &Mockrepository{ctrl: ctrl}
+	mock.recorder = &MockrepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockrepository) EXPECT() *MockrepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateJournalEntry mocks base method.
+func (m *Mockrepository) CreateJournalEntry(ctx context.Context, entry *models.JournalEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJournalEntry", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateJournalEntry indicates an expected call of CreateJournalEntry.
+func (mr *MockrepositoryMockRecorder) CreateJournalEntry(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJournalEntry", reflect.TypeOf((*Mockrepository)(nil).CreateJournalEntry), ctx, entry)
+}
+
+// GetJournalEntry mocks base method.
+func (m *Mockrepository) GetJournalEntry(ctx context.Context, reference, entryType string) (models.JournalEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJournalEntry", ctx, reference, entryType)
+	ret0, _ := ret[0].(models.JournalEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJournalEntry indicates an expected call of GetJournalEntry.
+func (mr *MockrepositoryMockRecorder) GetJournalEntry(ctx, reference, entryType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJournalEntry", reflect.TypeOf((*Mockrepository)(nil).GetJournalEntry), ctx, reference, entryType)
+}
+
+// GetOrCreateAccount mocks base method.
+func (m *Mockrepository) GetOrCreateAccount(ctx context.Context, account *models.LedgerAccount) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrCreateAccount", ctx, account)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetOrCreateAccount indicates an expected call of GetOrCreateAccount.
+func (mr *MockrepositoryMockRecorder) GetOrCreateAccount(ctx, account any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrCreateAccount", reflect.TypeOf((*Mockrepository)(nil).GetOrCreateAccount), ctx, account)
+}
+
+// GetUnbalancedEntries mocks base method.
+func (m *Mockrepository) GetUnbalancedEntries(ctx context.Context) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnbalancedEntries", ctx)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnbalancedEntries indicates an expected call of GetUnbalancedEntries.
+func (mr *MockrepositoryMockRecorder) GetUnbalancedEntries(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnbalancedEntries", reflect.TypeOf((*Mockrepository)(nil).GetUnbalancedEntries), ctx)
+}
@@ -0,0 +1,148 @@
+package limit
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// Check returns a LimitExceededError when trx would exceed a limit of its user's tier by moving to status.
+// A new PENDING transaction is counted with the PENDING and SUCCESS ones of the period, a SUCCESS
+// transition only with the SUCCESS ones, so pending transactions that never settle don't block the user
+// once they failed, and lowered limits still apply to the transactions pending before.
+// The caller holds the limit lock of the user until the transaction is written, two concurrent
+// transactions can't both fit in what is left of a limit.
+func (s *service) Check(ctx context.Context, trx models.Transaction, status string) error {
+	tier, err := s.userKYCTier(ctx, trx.UserID)
+	if err != nil {
+		return err
+	}
+
+	limit, err := s.repository.GetLimit(ctx, tier, trx.TransactionType)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to get limit")
+	}
+
+	exceeded := func(name string, max float64, current float64) error {
+		return &models.LimitExceededError{
+			Code:            constants.LimitExceededCode,
+			Limit:           name,
+			KYCTier:         tier,
+			TransactionType: trx.TransactionType,
+			Max:             max,
+			Current:         current,
+		}
+	}
+
+	if limit.MaxAmount > 0 && helpers.Cents(trx.Amount) > helpers.Cents(limit.MaxAmount) {
+		return exceeded(constants.LimitMaxAmount, limit.MaxAmount, trx.Amount)
+	}
+
+	if limit.DailyAmount <= 0 && limit.DailyCount <= 0 && limit.MonthlyAmount <= 0 && limit.MonthlyCount <= 0 {
+		return nil
+	}
+
+	statuses := []string{constants.TransactionStatusSuccess}
+	if status == constants.TransactionStatusPending {
+		statuses = append(statuses, constants.TransactionStatusPending)
+	}
+
+	now := s.now().In(s.config.Location)
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, s.config.Location)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, s.config.Location)
+
+	usage, err := s.usage.GetLimitUsage(ctx, trx.UserID, trx.TransactionType, statuses, dayStart, monthStart)
+	if err != nil {
+		return errors.Wrap(err, "failed to get limit usage")
+	}
+
+	switch {
+	case limit.DailyCount > 0 && usage.DailyCount+1 > limit.DailyCount:
+		return exceeded(constants.LimitDailyCount, float64(limit.DailyCount), float64(usage.DailyCount+1))
+	case limit.DailyAmount > 0 && helpers.Cents(usage.DailyAmount+trx.Amount) > helpers.Cents(limit.DailyAmount):
+		return exceeded(constants.LimitDailyAmount, limit.DailyAmount, usage.DailyAmount+trx.Amount)
+	case limit.MonthlyCount > 0 && usage.MonthlyCount+1 > limit.MonthlyCount:
+		return exceeded(constants.LimitMonthlyCount, float64(limit.MonthlyCount), float64(usage.MonthlyCount+1))
+	case limit.MonthlyAmount > 0 && helpers.Cents(usage.MonthlyAmount+trx.Amount) > helpers.Cents(limit.MonthlyAmount):
+		return exceeded(constants.LimitMonthlyAmount, limit.MonthlyAmount, usage.MonthlyAmount+trx.Amount)
+	}
+
+	return nil
+}
+
+func (s *service) ListLimits(ctx context.Context) ([]models.TransactionLimit, error) {
+	limits, err := s.repository.ListLimits(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list limits")
+	}
+
+	if limits == nil {
+		limits = []models.TransactionLimit{}
+	}
+
+	return limits, nil
+}
+
+// SetLimit creates or replaces the limit of a tier and a type.
+func (s *service) SetLimit(ctx context.Context, limit models.TransactionLimit) (models.TransactionLimit, error) {
+	if !constants.MapKYCTier[limit.KYCTier] {
+		return limit, errors.Wrapf(models.ErrInvalidLimit, "unknown kyc tier %q", limit.KYCTier)
+	}
+	if !constants.MapTransactionType[limit.TransactionType] {
+		return limit, errors.Wrapf(models.ErrInvalidLimit, "unknown transaction type %q", limit.TransactionType)
+	}
+	if limit.MaxAmount < 0 || limit.DailyAmount < 0 || limit.DailyCount < 0 || limit.MonthlyAmount < 0 || limit.MonthlyCount < 0 {
+		return limit, errors.Wrap(models.ErrInvalidLimit, "limits must not be negative")
+	}
+
+	err := s.repository.UpsertLimit(ctx, &limit)
+	if err != nil {
+		return limit, errors.Wrap(err, "failed to save limit")
+	}
+
+	return limit, nil
+}
+
+// GetUserKYCTier returns the recorded tier of a user, DefaultKYCTier when none was recorded.
+func (s *service) GetUserKYCTier(ctx context.Context, userID uint64) (models.UserKYCTier, error) {
+	tier, err := s.repository.GetUserKYCTier(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.UserKYCTier{UserID: userID, KYCTier: constants.DefaultKYCTier}, nil
+	}
+	if err != nil {
+		return tier, errors.Wrap(err, "failed to get kyc tier")
+	}
+
+	return tier, nil
+}
+
+func (s *service) SetUserKYCTier(ctx context.Context, userID uint64, kycTier string) (models.UserKYCTier, error) {
+	tier := models.UserKYCTier{UserID: userID, KYCTier: kycTier}
+	if userID == 0 || !constants.MapKYCTier[kycTier] {
+		return tier, errors.Wrapf(models.ErrInvalidLimit, "unknown kyc tier %q of user %d", kycTier, userID)
+	}
+
+	err := s.repository.UpsertUserKYCTier(ctx, &tier)
+	if err != nil {
+		return tier, errors.Wrap(err, "failed to save kyc tier")
+	}
+
+	return tier, nil
+}
+
+func (s *service) userKYCTier(ctx context.Context, userID uint64) (string, error) {
+	tier, err := s.GetUserKYCTier(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	return tier.KYCTier, nil
+}
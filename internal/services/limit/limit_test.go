@@ -0,0 +1,296 @@
+package limit
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func Test_service_Check(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+	mockUsage := NewMockusageCounter(ctrlMock)
+
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	// 2025-03-31 19:30 UTC is already April 1st in Jakarta.
+	now := time.Date(2025, 3, 31, 19, 30, 0, 0, time.UTC)
+	dayStart := time.Date(2025, 4, 1, 0, 0, 0, 0, jakarta)
+	monthStart := dayStart
+
+	limit := models.TransactionLimit{
+		KYCTier:         constants.KYCTierBasic,
+		TransactionType: constants.TransactionTypeTopup,
+		MaxAmount:       2_000_000,
+		DailyAmount:     5_000_000,
+		DailyCount:      3,
+		MonthlyAmount:   20_000_000,
+		MonthlyCount:    10,
+	}
+	trx := models.Transaction{UserID: 1, TransactionType: constants.TransactionTypeTopup, Amount: 1_000_000}
+
+	tests := []struct {
+		name    string
+		trx     models.Transaction
+		status  string
+		want    *models.LimitExceededError
+		wantErr error
+		mockFn  func()
+	}{
+		{
+			name:   "success under every limit",
+			trx:    trx,
+			status: constants.TransactionStatusPending,
+			mockFn: func() {
+				mockRepo.EXPECT().GetUserKYCTier(gomock.Any(), uint64(1)).Return(models.UserKYCTier{}, gorm.ErrRecordNotFound)
+				mockRepo.EXPECT().GetLimit(gomock.Any(), constants.KYCTierBasic, constants.TransactionTypeTopup).Return(limit, nil)
+				mockUsage.EXPECT().GetLimitUsage(gomock.Any(), uint64(1), constants.TransactionTypeTopup,
+					[]string{constants.TransactionStatusSuccess, constants.TransactionStatusPending}, dayStart, monthStart).
+					Return(models.LimitUsage{DailyCount: 2, DailyAmount: 4_000_000, MonthlyCount: 9, MonthlyAmount: 19_000_000}, nil)
+			},
+		},
+		{
+			name:   "success without limit for the type",
+			trx:    trx,
+			status: constants.TransactionStatusPending,
+			mockFn: func() {
+				mockRepo.EXPECT().GetUserKYCTier(gomock.Any(), uint64(1)).Return(models.UserKYCTier{UserID: 1, KYCTier: constants.KYCTierVerified}, nil)
+				mockRepo.EXPECT().GetLimit(gomock.Any(), constants.KYCTierVerified, constants.TransactionTypeTopup).Return(models.TransactionLimit{}, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name:   "success only max amount without usage",
+			trx:    trx,
+			status: constants.TransactionStatusPending,
+			mockFn: func() {
+				mockRepo.EXPECT().GetUserKYCTier(gomock.Any(), uint64(1)).Return(models.UserKYCTier{}, gorm.ErrRecordNotFound)
+				mockRepo.EXPECT().GetLimit(gomock.Any(), constants.KYCTierBasic, constants.TransactionTypeTopup).Return(models.TransactionLimit{MaxAmount: 2_000_000}, nil)
+			},
+		},
+		{
+			name:   "error max amount",
+			trx:    models.Transaction{UserID: 1, TransactionType: constants.TransactionTypeTopup, Amount: 2_000_000.01},
+			status: constants.TransactionStatusPending,
+			want: &models.LimitExceededError{
+				Code: constants.LimitExceededCode, Limit: constants.LimitMaxAmount, KYCTier: constants.KYCTierBasic,
+				TransactionType: constants.TransactionTypeTopup, Max: 2_000_000, Current: 2_000_000.01,
+			},
+			mockFn: func() {
+				mockRepo.EXPECT().GetUserKYCTier(gomock.Any(), uint64(1)).Return(models.UserKYCTier{}, gorm.ErrRecordNotFound)
+				mockRepo.EXPECT().GetLimit(gomock.Any(), constants.KYCTierBasic, constants.TransactionTypeTopup).Return(limit, nil)
+			},
+		},
+		{
+			name:   "error daily count on success counts success only",
+			trx:    trx,
+			status: constants.TransactionStatusSuccess,
+			want: &models.LimitExceededError{
+				Code: constants.LimitExceededCode, Limit: constants.LimitDailyCount, KYCTier: constants.KYCTierBasic,
+				TransactionType: constants.TransactionTypeTopup, Max: 3, Current: 4,
+			},
+			mockFn: func() {
+				mockRepo.EXPECT().GetUserKYCTier(gomock.Any(), uint64(1)).Return(models.UserKYCTier{}, gorm.ErrRecordNotFound)
+				mockRepo.EXPECT().GetLimit(gomock.Any(), constants.KYCTierBasic, constants.TransactionTypeTopup).Return(limit, nil)
+				mockUsage.EXPECT().GetLimitUsage(gomock.Any(), uint64(1), constants.TransactionTypeTopup,
+					[]string{constants.TransactionStatusSuccess}, dayStart, monthStart).
+					Return(models.LimitUsage{DailyCount: 3, DailyAmount: 1_000_000, MonthlyCount: 3, MonthlyAmount: 1_000_000}, nil)
+			},
+		},
+		{
+			name:   "error daily amount",
+			trx:    trx,
+			status: constants.TransactionStatusPending,
+			want: &models.LimitExceededError{
+				Code: constants.LimitExceededCode, Limit: constants.LimitDailyAmount, KYCTier: constants.KYCTierBasic,
+				TransactionType: constants.TransactionTypeTopup, Max: 5_000_000, Current: 5_000_000.5,
+			},
+			mockFn: func() {
+				mockRepo.EXPECT().GetUserKYCTier(gomock.Any(), uint64(1)).Return(models.UserKYCTier{}, gorm.ErrRecordNotFound)
+				mockRepo.EXPECT().GetLimit(gomock.Any(), constants.KYCTierBasic, constants.TransactionTypeTopup).Return(limit, nil)
+				mockUsage.EXPECT().GetLimitUsage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(models.LimitUsage{DailyCount: 1, DailyAmount: 4_000_000.5, MonthlyCount: 1, MonthlyAmount: 4_000_000.5}, nil)
+			},
+		},
+		{
+			name:   "error monthly count",
+			trx:    trx,
+			status: constants.TransactionStatusPending,
+			want: &models.LimitExceededError{
+				Code: constants.LimitExceededCode, Limit: constants.LimitMonthlyCount, KYCTier: constants.KYCTierBasic,
+				TransactionType: constants.TransactionTypeTopup, Max: 10, Current: 11,
+			},
+			mockFn: func() {
+				mockRepo.EXPECT().GetUserKYCTier(gomock.Any(), uint64(1)).Return(models.UserKYCTier{}, gorm.ErrRecordNotFound)
+				mockRepo.EXPECT().GetLimit(gomock.Any(), constants.KYCTierBasic, constants.TransactionTypeTopup).Return(limit, nil)
+				mockUsage.EXPECT().GetLimitUsage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(models.LimitUsage{MonthlyCount: 10, MonthlyAmount: 10_000_000}, nil)
+			},
+		},
+		{
+			name:   "error monthly amount",
+			trx:    trx,
+			status: constants.TransactionStatusPending,
+			want: &models.LimitExceededError{
+				Code: constants.LimitExceededCode, Limit: constants.LimitMonthlyAmount, KYCTier: constants.KYCTierBasic,
+				TransactionType: constants.TransactionTypeTopup, Max: 20_000_000, Current: 21_000_000,
+			},
+			mockFn: func() {
+				mockRepo.EXPECT().GetUserKYCTier(gomock.Any(), uint64(1)).Return(models.UserKYCTier{}, gorm.ErrRecordNotFound)
+				mockRepo.EXPECT().GetLimit(gomock.Any(), constants.KYCTierBasic, constants.TransactionTypeTopup).Return(limit, nil)
+				mockUsage.EXPECT().GetLimitUsage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(models.LimitUsage{MonthlyCount: 5, MonthlyAmount: 20_000_000}, nil)
+			},
+		},
+		{
+			name:    "error get kyc tier",
+			trx:     trx,
+			status:  constants.TransactionStatusPending,
+			wantErr: assert.AnError,
+			mockFn: func() {
+				mockRepo.EXPECT().GetUserKYCTier(gomock.Any(), uint64(1)).Return(models.UserKYCTier{}, assert.AnError)
+			},
+		},
+		{
+			name:    "error get limit",
+			trx:     trx,
+			status:  constants.TransactionStatusPending,
+			wantErr: assert.AnError,
+			mockFn: func() {
+				mockRepo.EXPECT().GetUserKYCTier(gomock.Any(), uint64(1)).Return(models.UserKYCTier{}, gorm.ErrRecordNotFound)
+				mockRepo.EXPECT().GetLimit(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.TransactionLimit{}, assert.AnError)
+			},
+		},
+		{
+			name:    "error get usage",
+			trx:     trx,
+			status:  constants.TransactionStatusPending,
+			wantErr: assert.AnError,
+			mockFn: func() {
+				mockRepo.EXPECT().GetUserKYCTier(gomock.Any(), uint64(1)).Return(models.UserKYCTier{}, gorm.ErrRecordNotFound)
+				mockRepo.EXPECT().GetLimit(gomock.Any(), gomock.Any(), gomock.Any()).Return(limit, nil)
+				mockUsage.EXPECT().GetLimitUsage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(models.LimitUsage{}, assert.AnError)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			s := NewService(mockRepo, mockUsage, Config{Location: jakarta})
+			s.now = func() time.Time { return now }
+
+			err := s.Check(context.Background(), tt.trx, tt.status)
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.want != nil:
+				assert.ErrorIs(t, err, models.ErrLimitExceeded)
+				var got *models.LimitExceededError
+				assert.ErrorAs(t, err, &got)
+				assert.Equal(t, tt.want, got)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_service_SetLimit(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+
+	valid := models.TransactionLimit{KYCTier: constants.KYCTierVerified, TransactionType: constants.TransactionTypePurchase, MaxAmount: 10_000_000, DailyCount: 100}
+
+	tests := []struct {
+		name    string
+		limit   models.TransactionLimit
+		wantErr error
+		mockFn  func()
+	}{
+		{
+			name:  "success",
+			limit: valid,
+			mockFn: func() {
+				mockRepo.EXPECT().UpsertLimit(gomock.Any(), &valid).Return(nil)
+			},
+		},
+		{
+			name:    "error unknown tier",
+			limit:   models.TransactionLimit{KYCTier: "GOLD", TransactionType: constants.TransactionTypePurchase},
+			wantErr: models.ErrInvalidLimit,
+			mockFn:  func() {},
+		},
+		{
+			name:    "error unknown type",
+			limit:   models.TransactionLimit{KYCTier: constants.KYCTierBasic, TransactionType: "DEBIT"},
+			wantErr: models.ErrInvalidLimit,
+			mockFn:  func() {},
+		},
+		{
+			name:    "error negative limit",
+			limit:   models.TransactionLimit{KYCTier: constants.KYCTierBasic, TransactionType: constants.TransactionTypeTopup, MonthlyCount: -1},
+			wantErr: models.ErrInvalidLimit,
+			mockFn:  func() {},
+		},
+		{
+			name:    "error upsert",
+			limit:   valid,
+			wantErr: assert.AnError,
+			mockFn: func() {
+				mockRepo.EXPECT().UpsertLimit(gomock.Any(), gomock.Any()).Return(assert.AnError)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			s := NewService(mockRepo, nil, Config{})
+			got, err := s.SetLimit(context.Background(), tt.limit)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.limit, got)
+		})
+	}
+}
+
+func Test_service_UserKYCTier(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+	s := NewService(mockRepo, nil, Config{})
+
+	mockRepo.EXPECT().GetUserKYCTier(gomock.Any(), uint64(1)).Return(models.UserKYCTier{}, gorm.ErrRecordNotFound)
+	got, err := s.GetUserKYCTier(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, models.UserKYCTier{UserID: 1, KYCTier: constants.DefaultKYCTier}, got)
+
+	mockRepo.EXPECT().GetUserKYCTier(gomock.Any(), uint64(2)).Return(models.UserKYCTier{}, assert.AnError)
+	_, err = s.GetUserKYCTier(context.Background(), 2)
+	assert.ErrorIs(t, err, assert.AnError)
+
+	mockRepo.EXPECT().UpsertUserKYCTier(gomock.Any(), &models.UserKYCTier{UserID: 1, KYCTier: constants.KYCTierVerified}).Return(nil)
+	got, err = s.SetUserKYCTier(context.Background(), 1, constants.KYCTierVerified)
+	assert.NoError(t, err)
+	assert.Equal(t, constants.KYCTierVerified, got.KYCTier)
+
+	_, err = s.SetUserKYCTier(context.Background(), 1, "GOLD")
+	assert.ErrorIs(t, err, models.ErrInvalidLimit)
+
+	_, err = s.SetUserKYCTier(context.Background(), 0, constants.KYCTierBasic)
+	assert.ErrorIs(t, err, models.ErrInvalidLimit)
+}
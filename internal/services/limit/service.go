@@ -0,0 +1,46 @@
+package limit
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+	"time"
+)
+
+//go:generate mockgen -source=service.go -destination=service_mock_test.go -package=limit
+type repository interface {
+	GetLimit(ctx context.Context, kycTier string, transactionType string) (models.TransactionLimit, error)
+	ListLimits(ctx context.Context) ([]models.TransactionLimit, error)
+	UpsertLimit(ctx context.Context, limit *models.TransactionLimit) error
+	GetUserKYCTier(ctx context.Context, userID uint64) (models.UserKYCTier, error)
+	UpsertUserKYCTier(ctx context.Context, tier *models.UserKYCTier) error
+}
+
+// usageCounter adds up the transactions already counted against the limits.
+type usageCounter interface {
+	GetLimitUsage(ctx context.Context, userID uint64, transactionType string, statuses []string, dayStart time.Time, monthStart time.Time) (models.LimitUsage, error)
+}
+
+type Config struct {
+	// Location of the days and months the cumulative limits are counted over.
+	Location *time.Location
+}
+
+type service struct {
+	repository repository
+	usage      usageCounter
+	config     Config
+	now        func() time.Time
+}
+
+func NewService(repository repository, usage usageCounter, config Config) *service {
+	if config.Location == nil {
+		config.Location = time.UTC
+	}
+
+	return &service{
+		repository: repository,
+		usage:      usage,
+		config:     config,
+		now:        time.Now,
+	}
+}
@@ -0,0 +1,155 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=service_mock_test.go -package=limit
+//
+
+// Package limit is a generated GoMock package.
+package limit
+
+import (
+	context "context"
+	models "ewallet-transaction/internal/models"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// Mockrepository is a mock of repository interface.
+type Mockrepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockrepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockrepositoryMockRecorder is the mock recorder for Mockrepository.
+type MockrepositoryMockRecorder struct {
+	mock *Mockrepository
+}
+
+// NewMockrepository creates a new mock instance.
+func NewMockrepository(ctrl *gomock.Controller) *Mockrepository {
+	mock := &Mockrepository{ctrl: ctrl}
+	mock.recorder = &MockrepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockrepository) EXPECT() *MockrepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetLimit mocks base method.
+func (m *Mockrepository) GetLimit(ctx context.Context, kycTier, transactionType string) (models.TransactionLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLimit", ctx, kycTier, transactionType)
+	ret0, _ := ret[0].(models.TransactionLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLimit indicates an expected call of GetLimit.
+func (mr *MockrepositoryMockRecorder) GetLimit(ctx, kycTier, transactionType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLimit", reflect.TypeOf((*Mockrepository)(nil).GetLimit), ctx, kycTier, transactionType)
+}
+
+// GetUserKYCTier mocks base method.
+func (m *Mockrepository) GetUserKYCTier(ctx context.Context, userID uint64) (models.UserKYCTier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserKYCTier", ctx, userID)
+	ret0, _ := ret[0].(models.UserKYCTier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserKYCTier indicates an expected call of GetUserKYCTier.
+func (mr *MockrepositoryMockRecorder) GetUserKYCTier(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserKYCTier", reflect.TypeOf((*Mockrepository)(nil).GetUserKYCTier), ctx, userID)
+}
+
+// ListLimits mocks base method.
+func (m *Mockrepository) ListLimits(ctx context.Context) ([]models.TransactionLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLimits", ctx)
+	ret0, _ := ret[0].([]models.TransactionLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLimits indicates an expected call of ListLimits.
+func (mr *MockrepositoryMockRecorder) ListLimits(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLimits", reflect.TypeOf((*Mockrepository)(nil).ListLimits), ctx)
+}
+
+// UpsertLimit mocks base method.
+func (m *Mockrepository) UpsertLimit(ctx context.Context, limit *models.TransactionLimit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertLimit", ctx, limit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertLimit indicates an expected call of UpsertLimit.
+func (mr *MockrepositoryMockRecorder) UpsertLimit(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertLimit", reflect.TypeOf((*Mockrepository)(nil).UpsertLimit), ctx, limit)
+}
+
+// UpsertUserKYCTier mocks base method.
+func (m *Mockrepository) UpsertUserKYCTier(ctx context.Context, tier *models.UserKYCTier) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertUserKYCTier", ctx, tier)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertUserKYCTier indicates an expected call of UpsertUserKYCTier.
+func (mr *MockrepositoryMockRecorder) UpsertUserKYCTier(ctx, tier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUserKYCTier", reflect.TypeOf((*Mockrepository)(nil).UpsertUserKYCTier), ctx, tier)
+}
+
+// MockusageCounter is a mock of usageCounter interface.
+type MockusageCounter struct {
+	ctrl     *gomock.Controller
+	recorder *MockusageCounterMockRecorder
+	isgomock struct{}
+}
+
+// MockusageCounterMockRecorder is the mock recorder for MockusageCounter.
+type MockusageCounterMockRecorder struct {
+	mock *MockusageCounter
+}
+
+// NewMockusageCounter creates a new mock instance.
+func NewMockusageCounter(ctrl *gomock.Controller) *MockusageCounter {
+	mock := &MockusageCounter{ctrl: ctrl}
+	mock.recorder = &MockusageCounterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockusageCounter) EXPECT() *MockusageCounterMockRecorder {
+	return m.recorder
+}
+
+// GetLimitUsage mocks base method.
+func (m *MockusageCounter) GetLimitUsage(ctx context.Context, userID uint64, transactionType string, statuses []string, dayStart, monthStart time.Time) (models.LimitUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLimitUsage", ctx, userID, transactionType, statuses, dayStart, monthStart)
+	ret0, _ := ret[0].(models.LimitUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLimitUsage indicates an expected call of GetLimitUsage.
+func (mr *MockusageCounterMockRecorder) GetLimitUsage(ctx, userID, transactionType, statuses, dayStart, monthStart any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLimitUsage", reflect.TypeOf((*MockusageCounter)(nil).GetLimitUsage), ctx, userID, transactionType, statuses, dayStart, monthStart)
+}
@@ -0,0 +1,183 @@
+package notification
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Start runs the worker pool that drains the notification queue.
+func (s *service) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started || s.stopped {
+		return
+	}
+	s.started = true
+
+	for i := 0; i < s.config.Workers; i++ {
+		s.wg.Add(1)
+		go s.worker()
+	}
+}
+
+// Stop rejects new notifications and waits until the queued ones are sent
+// or the context is done.
+func (s *service) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return nil
+	}
+	s.stopped = true
+	close(s.queue)
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "failed to drain notification queue")
+	}
+}
+
+// Notify enqueues a notification for the given event without waiting for it to be sent.
+// Without a recipient the worker sends it to the stored contact of the user.
+func (s *service) Notify(ctx context.Context, event string, userID uint64, recipient string, placeHolder map[string]string) error {
+	templateName, ok := s.config.Templates[event]
+	if !ok || templateName == "" {
+		return fmt.Errorf("notification template is not configured for event %s", event)
+	}
+
+	if recipient == "" && userID == 0 {
+		return fmt.Errorf("notification recipient is empty for event %s", event)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.stopped {
+		return errors.New("notification service is stopped")
+	}
+
+	select {
+	case s.queue <- models.Notification{
+		Event:        event,
+		UserID:       userID,
+		Recipient:    recipient,
+		TemplateName: templateName,
+		PlaceHolder:  placeHolder,
+	}:
+		return nil
+	default:
+		return fmt.Errorf("notification queue is full, dropping event %s", event)
+	}
+}
+
+func (s *service) worker() {
+	defer s.wg.Done()
+
+	for notif := range s.queue {
+		notif, err := s.resolveRecipient(notif)
+		if err == nil {
+			err = s.send(notif)
+		}
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"event":     notif.Event,
+				"template":  notif.TemplateName,
+				"user_id":   notif.UserID,
+				"recipient": notif.Recipient,
+			}).Error("failed to send notification: ", err)
+		}
+	}
+}
+
+// resolveRecipient fills the recipient of a notification queued without one from the stored contact of the user.
+func (s *service) resolveRecipient(notif models.Notification) (models.Notification, error) {
+	if notif.Recipient != "" {
+		return notif, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.SendTimeout)
+	defer cancel()
+
+	contact, err := s.contacts.GetContact(ctx, notif.UserID)
+	if err != nil {
+		return notif, errors.Wrap(err, "failed to get contact")
+	}
+
+	notif.Recipient = contact.Email
+	if notif.PlaceHolder["full_name"] == "" {
+		placeHolder := make(map[string]string, len(notif.PlaceHolder)+1)
+		for key, val := range notif.PlaceHolder {
+			placeHolder[key] = val
+		}
+		placeHolder["full_name"] = contact.Fullname
+		notif.PlaceHolder = placeHolder
+	}
+
+	return notif, nil
+}
+
+// RecordContact stores the email of a validated token, so the notifications of the user can be sent
+// when they don't act with that token. An unchanged contact isn't written again by this instance.
+func (s *service) RecordContact(ctx context.Context, tokenData models.TokenData) error {
+	if tokenData.UserID == 0 || tokenData.Email == "" {
+		return nil
+	}
+
+	contact := models.UserContact{
+		UserID:   tokenData.UserID,
+		Email:    tokenData.Email,
+		Fullname: tokenData.Fullname,
+	}
+	if known, ok := s.known.Load(contact.UserID); ok && known == contact {
+		return nil
+	}
+
+	stored := contact
+	err := s.contacts.UpsertContact(ctx, &stored)
+	if err != nil {
+		return errors.Wrap(err, "failed to upsert contact")
+	}
+
+	s.known.Store(contact.UserID, contact)
+
+	return nil
+}
+
+func (s *service) send(notif models.Notification) error {
+	var err error
+
+	for attempt := 0; attempt <= s.config.MaxRetry; attempt++ {
+		if attempt > 0 {
+			backoff := s.config.RetryInterval * time.Duration(1<<(attempt-1))
+			logrus.WithFields(logrus.Fields{
+				"event":   notif.Event,
+				"attempt": attempt,
+			}).Warn("retrying notification: ", err)
+			time.Sleep(backoff)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), s.config.SendTimeout)
+		err = s.external.SendNotification(ctx, notif.Recipient, notif.TemplateName, notif.PlaceHolder)
+		cancel()
+		if err == nil {
+			return nil
+		}
+	}
+
+	return errors.Wrapf(err, "failed after %d retries", s.config.MaxRetry)
+}
@@ -0,0 +1,257 @@
+package notification
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func Test_service_Notify(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockExt := NewMockIExternal(ctrlMock)
+
+	placeHolder := map[string]string{
+		"full_name": "FULLNAME",
+		"reference": "REFERENCE",
+	}
+
+	type args struct {
+		ctx         context.Context
+		event       string
+		userID      uint64
+		recipient   string
+		placeHolder map[string]string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		queueSize int
+		stopped   bool
+		prefill   int
+		wantErr   bool
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx:         context.Background(),
+				event:       constants.NotificationEventTopupSuccess,
+				recipient:   "email@gmail.com",
+				placeHolder: placeHolder,
+			},
+			queueSize: 1,
+			wantErr:   false,
+		},
+		{
+			name: "success looked up by user id",
+			args: args{
+				ctx:         context.Background(),
+				event:       constants.NotificationEventPendingExpired,
+				userID:      3,
+				placeHolder: placeHolder,
+			},
+			queueSize: 1,
+			wantErr:   false,
+		},
+		{
+			name: "error unknown event",
+			args: args{
+				ctx:         context.Background(),
+				event:       "UNKNOWN",
+				recipient:   "email@gmail.com",
+				placeHolder: placeHolder,
+			},
+			queueSize: 1,
+			wantErr:   true,
+		},
+		{
+			name: "error empty recipient",
+			args: args{
+				ctx:         context.Background(),
+				event:       constants.NotificationEventTopupSuccess,
+				recipient:   "",
+				placeHolder: placeHolder,
+			},
+			queueSize: 1,
+			wantErr:   true,
+		},
+		{
+			name: "error queue full",
+			args: args{
+				ctx:         context.Background(),
+				event:       constants.NotificationEventTopupSuccess,
+				recipient:   "email@gmail.com",
+				placeHolder: placeHolder,
+			},
+			queueSize: 1,
+			prefill:   1,
+			wantErr:   true,
+		},
+		{
+			name: "error stopped",
+			args: args{
+				ctx:         context.Background(),
+				event:       constants.NotificationEventTopupSuccess,
+				recipient:   "email@gmail.com",
+				placeHolder: placeHolder,
+			},
+			queueSize: 1,
+			stopped:   true,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewService(mockExt, NewMockcontactStore(ctrlMock), Config{
+				Templates: constants.MapNotificationTemplate,
+				QueueSize: tt.queueSize,
+			})
+			for i := 0; i < tt.prefill; i++ {
+				s.queue <- models.Notification{}
+			}
+			if tt.stopped {
+				assert.NoError(t, s.Stop(context.Background()))
+			}
+
+			err := s.Notify(tt.args.ctx, tt.args.event, tt.args.userID, tt.args.recipient, tt.args.placeHolder)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("service.Notify() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				got := <-s.queue
+				assert.Equal(t, models.Notification{
+					Event:        tt.args.event,
+					UserID:       tt.args.userID,
+					Recipient:    tt.args.recipient,
+					TemplateName: constants.MapNotificationTemplate[tt.args.event],
+					PlaceHolder:  tt.args.placeHolder,
+				}, got)
+			}
+		})
+	}
+}
+
+func Test_service_send(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockExt := NewMockIExternal(ctrlMock)
+
+	notif := models.Notification{
+		Event:        constants.NotificationEventReversal,
+		Recipient:    "email@gmail.com",
+		TemplateName: "reversal",
+		PlaceHolder:  map[string]string{"reference": "REFERENCE"},
+	}
+
+	tests := []struct {
+		name    string
+		wantErr bool
+		mockFn  func()
+	}{
+		{
+			name:    "success",
+			wantErr: false,
+			mockFn: func() {
+				mockExt.EXPECT().SendNotification(gomock.Any(), notif.Recipient, notif.TemplateName, notif.PlaceHolder).Return(nil)
+			},
+		},
+		{
+			name:    "success after retry",
+			wantErr: false,
+			mockFn: func() {
+				gomock.InOrder(
+					mockExt.EXPECT().SendNotification(gomock.Any(), notif.Recipient, notif.TemplateName, notif.PlaceHolder).Return(assert.AnError),
+					mockExt.EXPECT().SendNotification(gomock.Any(), notif.Recipient, notif.TemplateName, notif.PlaceHolder).Return(nil),
+				)
+			},
+		},
+		{
+			name:    "error after max retry",
+			wantErr: true,
+			mockFn: func() {
+				mockExt.EXPECT().SendNotification(gomock.Any(), notif.Recipient, notif.TemplateName, notif.PlaceHolder).Return(assert.AnError).Times(3)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+			s := NewService(mockExt, NewMockcontactStore(ctrlMock), Config{
+				MaxRetry:      2,
+				RetryInterval: time.Millisecond,
+				SendTimeout:   time.Second,
+			})
+			if err := s.send(notif); (err != nil) != tt.wantErr {
+				t.Errorf("service.send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_service_StartStop(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockExt := NewMockIExternal(ctrlMock)
+	mockExt.EXPECT().SendNotification(gomock.Any(), "email@gmail.com", "refund_issued", gomock.Any()).Return(nil).Times(3)
+
+	// queued without a recipient, the worker sends them to the stored contact
+	mockContacts := NewMockcontactStore(ctrlMock)
+	mockContacts.EXPECT().GetContact(gomock.Any(), uint64(3)).Return(models.UserContact{UserID: 3, Email: "owner@gmail.com", Fullname: "OWNER"}, nil)
+	mockExt.EXPECT().SendNotification(gomock.Any(), "owner@gmail.com", "pending_expired", map[string]string{
+		"full_name": "OWNER",
+		"reference": "REFERENCE",
+	}).Return(nil)
+	mockContacts.EXPECT().GetContact(gomock.Any(), uint64(4)).Return(models.UserContact{}, gorm.ErrRecordNotFound)
+
+	s := NewService(mockExt, mockContacts, Config{
+		Templates:   constants.MapNotificationTemplate,
+		Workers:     2,
+		QueueSize:   10,
+		SendTimeout: time.Second,
+	})
+	s.Start()
+
+	for i := 0; i < 3; i++ {
+		err := s.Notify(context.Background(), constants.NotificationEventRefundIssued, 1, "email@gmail.com", nil)
+		assert.NoError(t, err)
+	}
+	err := s.Notify(context.Background(), constants.NotificationEventPendingExpired, 3, "", map[string]string{"full_name": "", "reference": "REFERENCE"})
+	assert.NoError(t, err)
+	err = s.Notify(context.Background(), constants.NotificationEventPendingExpired, 4, "", nil)
+	assert.NoError(t, err, "a user without contact is only logged by the worker")
+
+	assert.NoError(t, s.Stop(context.Background()))
+}
+
+func Test_service_RecordContact(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockContacts := NewMockcontactStore(ctrlMock)
+	s := NewService(NewMockIExternal(ctrlMock), mockContacts, Config{})
+	ctx := context.Background()
+	tokenData := models.TokenData{UserID: 1, Email: "email@gmail.com", Fullname: "FULLNAME", Token: "TOKEN"}
+
+	mockContacts.EXPECT().UpsertContact(gomock.Any(), &models.UserContact{UserID: 1, Email: "email@gmail.com", Fullname: "FULLNAME"}).Return(nil)
+	assert.NoError(t, s.RecordContact(ctx, tokenData))
+	assert.NoError(t, s.RecordContact(ctx, tokenData), "an unchanged contact isn't written again")
+
+	tokenData.Email = "new@gmail.com"
+	mockContacts.EXPECT().UpsertContact(gomock.Any(), &models.UserContact{UserID: 1, Email: "new@gmail.com", Fullname: "FULLNAME"}).Return(assert.AnError)
+	assert.ErrorIs(t, s.RecordContact(ctx, tokenData), assert.AnError)
+
+	mockContacts.EXPECT().UpsertContact(gomock.Any(), gomock.Any()).Return(nil)
+	assert.NoError(t, s.RecordContact(ctx, tokenData), "a failed write is tried again")
+
+	assert.NoError(t, s.RecordContact(ctx, models.TokenData{UserID: 2}), "a token without email has nothing to record")
+}
@@ -0,0 +1,51 @@
+package notification
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+	"sync"
+	"time"
+)
+
+//go:generate mockgen -source=service.go -destination=service_mock_test.go -package=notification
+type IExternal interface {
+	SendNotification(ctx context.Context, recipient string, templateName string, placeHolder map[string]string) error
+}
+
+type contactStore interface {
+	UpsertContact(ctx context.Context, contact *models.UserContact) error
+	GetContact(ctx context.Context, userID uint64) (models.UserContact, error)
+}
+
+type Config struct {
+	Templates     map[string]string
+	Workers       int
+	QueueSize     int
+	MaxRetry      int
+	RetryInterval time.Duration
+	SendTimeout   time.Duration
+}
+
+type service struct {
+	external IExternal
+	contacts contactStore
+	config   Config
+
+	// known are the contacts already stored by this instance, a token only writes a changed one
+	known sync.Map
+
+	mu      sync.RWMutex
+	queue   chan models.Notification
+	wg      sync.WaitGroup
+	started bool
+	stopped bool
+}
+
+func NewService(external IExternal, contacts contactStore, config Config) *service {
+	return &service{
+		external: external,
+		contacts: contacts,
+		config:   config,
+		queue:    make(chan models.Notification, config.QueueSize),
+	}
+}
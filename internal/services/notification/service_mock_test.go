@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=service_mock_test.go -package=notification
+//
+
+// Package notification is a generated GoMock package.
+package notification
+
+import (
+	context "context"
+	models "ewallet-transaction/internal/models"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIExternal is a mock of IExternal interface.
+type MockIExternal struct {
+	ctrl     *gomock.Controller
+	recorder *MockIExternalMockRecorder
+	isgomock struct{}
+}
+
+// MockIExternalMockRecorder is the mock recorder for MockIExternal.
+type MockIExternalMockRecorder struct {
+	mock *MockIExternal
+}
+
+// NewMockIExternal creates a new mock instance.
+func NewMockIExternal(ctrl *gomock.Controller) *MockIExternal {
+	mock := &MockIExternal{ctrl: ctrl}
+	mock.recorder = &MockIExternalMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIExternal) EXPECT() *MockIExternalMockRecorder {
+	return m.recorder
+}
+
+// SendNotification mocks base method.
+func (m *MockIExternal) SendNotification(ctx context.Context, recipient, templateName string, placeHolder map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendNotification", ctx, recipient, templateName, placeHolder)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendNotification indicates an expected call of SendNotification.
+func (mr *MockIExternalMockRecorder) SendNotification(ctx, recipient, templateName, placeHolder any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendNotification", reflect.TypeOf((*MockIExternal)(nil).SendNotification), ctx, recipient, templateName, placeHolder)
+}
+
+// MockcontactStore is a mock of contactStore interface.
+type MockcontactStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockcontactStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockcontactStoreMockRecorder is the mock recorder for MockcontactStore.
+type MockcontactStoreMockRecorder struct {
+	mock *MockcontactStore
+}
+
+// NewMockcontactStore creates a new mock instance.
+func NewMockcontactStore(ctrl *gomock.Controller) *MockcontactStore {
+	mock := &MockcontactStore{ctrl: ctrl}
+	mock.recorder = &MockcontactStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockcontactStore) EXPECT() *MockcontactStoreMockRecorder {
+	return m.recorder
+}
+
+// GetContact mocks base method.
+func (m *MockcontactStore) GetContact(ctx context.Context, userID uint64) (models.UserContact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContact", ctx, userID)
+	ret0, _ := ret[0].(models.UserContact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContact indicates an expected call of GetContact.
+func (mr *MockcontactStoreMockRecorder) GetContact(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContact", reflect.TypeOf((*MockcontactStore)(nil).GetContact), ctx, userID)
+}
+
+// UpsertContact mocks base method.
+func (m *MockcontactStore) UpsertContact(ctx context.Context, contact *models.UserContact) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertContact", ctx, contact)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertContact indicates an expected call of UpsertContact.
+func (mr *MockcontactStoreMockRecorder) UpsertContact(ctx, contact any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertContact", reflect.TypeOf((*MockcontactStore)(nil).UpsertContact), ctx, contact)
+}
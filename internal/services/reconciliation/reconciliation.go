@@ -0,0 +1,230 @@
+package reconciliation
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/external"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Reconcile compares the transactions created in [from, to) with the wallet movements of their references.
+// With repair it fixes the local status of the safe cases: the wallet moved exactly the expected amount
+// and the status change is a valid flow, anything that would move money is only reported.
+func (s *service) Reconcile(ctx context.Context, from time.Time, to time.Time, repair bool) (models.ReconciliationReport, error) {
+	report := models.ReconciliationReport{
+		From:       from,
+		To:         to,
+		Mismatches: []models.ReconciliationMismatch{},
+	}
+
+	trxs, err := s.repository.GetTransactionsByTimeRange(ctx, from, to)
+	if err != nil {
+		return report, errors.Wrap(err, "failed to get transactions")
+	}
+
+	for _, trx := range trxs {
+		mismatch, err := s.check(ctx, trx)
+		if err != nil {
+			return report, err
+		}
+
+		report.Checked++
+		if mismatch == nil {
+			continue
+		}
+
+		if repair && mismatch.RepairStatus != "" {
+			if err := s.repair(ctx, trx, mismatch.RepairStatus); err != nil {
+				logrus.WithField("reference", trx.Reference).Error("failed to repair transaction: ", err)
+			} else {
+				mismatch.Repaired = true
+				report.Repaired++
+			}
+		}
+
+		report.Mismatches = append(report.Mismatches, *mismatch)
+	}
+
+	return report, nil
+}
+
+// check returns the mismatch of trx or nil when the wallet agrees with its status.
+func (s *service) check(ctx context.Context, trx models.Transaction) (*models.ReconciliationMismatch, error) {
+	direction, reversalDirection := movementDirections(trx.TransactionType)
+
+	moved, err := s.movedAmount(ctx, trx.Reference, direction)
+	if err != nil {
+		return nil, err
+	}
+
+	var reversed int64
+	if trx.TransactionType != constants.TransactionTypeRefund {
+		reversed, err = s.movedAmount(ctx, constants.WalletReferenceReversedPrefix+trx.Reference, reversalDirection)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	expected := helpers.Cents(trx.Amount)
+	mismatch := &models.ReconciliationMismatch{
+		Reference:         trx.Reference,
+		TransactionType:   trx.TransactionType,
+		TransactionStatus: trx.TransactionStatus,
+		ExpectedAmount:    trx.Amount,
+		WalletAmount:      helpers.Amount(moved),
+	}
+
+	switch trx.TransactionStatus {
+	case constants.TransactionStatusPending, constants.TransactionStatusFailed, constants.TransactionStatusOnHold:
+		if moved == 0 && reversed == 0 {
+			return nil, nil
+		}
+
+		mismatch.Kind = constants.ReconciliationMismatchStatus
+		mismatch.Detail = "wallet moved money for a transaction that is not success"
+		if moved == expected && reversed == 0 {
+			mismatch.RepairStatus = constants.TransactionStatusSuccess
+		}
+	case constants.TransactionStatusSuccess:
+		switch {
+		case moved == 0:
+			mismatch.Kind = constants.ReconciliationMismatchMissing
+			mismatch.Detail = "wallet has no movement for a success transaction"
+		case moved != expected:
+			mismatch.Kind = constants.ReconciliationMismatchAmount
+			mismatch.Detail = "wallet moved a different amount"
+		case reversed != 0:
+			mismatch.Kind = constants.ReconciliationMismatchStatus
+			mismatch.Detail = "wallet reversed a transaction that is still success"
+			if reversed == expected {
+				mismatch.RepairStatus = constants.TransactionStatusReversed
+			}
+		default:
+			return nil, nil
+		}
+	case constants.TransactionStatusReversed:
+		switch {
+		case moved == 0 || reversed == 0:
+			mismatch.Kind = constants.ReconciliationMismatchMissing
+			mismatch.Detail = "wallet is missing the movement or the reversal of a reversed transaction"
+		case moved != expected || reversed != expected:
+			mismatch.Kind = constants.ReconciliationMismatchAmount
+			mismatch.Detail = fmt.Sprintf("wallet moved %.2f and reversed %.2f", float64(moved)/100, float64(reversed)/100)
+		default:
+			return nil, nil
+		}
+	default:
+		return nil, nil
+	}
+
+	return mismatch, nil
+}
+
+func (s *service) movedAmount(ctx context.Context, reference string, direction string) (int64, error) {
+	movements, err := s.wallet.GetWalletMovements(ctx, reference)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get wallet movements of %s", reference)
+	}
+
+	return sumMovements(movements, direction), nil
+}
+
+func (s *service) repair(ctx context.Context, trx models.Transaction, status string) error {
+	err := s.statuses.SyncStatus(ctx, trx, status, constants.StatusActorReconciliation, "repaired from wallet movements")
+	if err != nil {
+		return errors.Wrap(err, "failed to sync status transaction")
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"reference": trx.Reference,
+		"from":      trx.TransactionStatus,
+		"to":        status,
+	}).Info("repaired transaction status from wallet movements")
+
+	return nil
+}
+
+// Start runs the reconciliation every interval over the window that ends grace ago.
+func (s *service) Start() {
+	s.wg.Add(1)
+	go s.run()
+}
+
+// Stop waits for the running reconciliation to finish or the context to be done.
+func (s *service) Stop(ctx context.Context) error {
+	s.once.Do(func() {
+		close(s.stop)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "failed to stop reconciliation worker")
+	}
+}
+
+func (s *service) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+
+		to := time.Now().Add(-s.config.Grace)
+		report, err := s.Reconcile(context.Background(), to.Add(-s.config.Window), to, s.config.AutoRepair)
+		if err != nil {
+			logrus.Error("failed to reconcile transactions: ", err)
+			continue
+		}
+
+		entry := logrus.WithFields(logrus.Fields{
+			"checked":    report.Checked,
+			"mismatches": len(report.Mismatches),
+			"repaired":   report.Repaired,
+		})
+		if len(report.Mismatches) > 0 {
+			entry.Warn("reconciliation found mismatches")
+		} else {
+			entry.Info("reconciliation completed")
+		}
+	}
+}
+
+// movementDirections returns the wallet movement of a transaction type and of its reversal.
+func movementDirections(transactionType string) (string, string) {
+	if transactionType == constants.TransactionTypePurchase {
+		return constants.WalletMovementDebit, constants.WalletMovementCredit
+	}
+
+	return constants.WalletMovementCredit, constants.WalletMovementDebit
+}
+
+func sumMovements(movements []external.WalletMovement, direction string) int64 {
+	var total int64
+	for _, movement := range movements {
+		if movement.WalletTransactionType == direction {
+			total += helpers.Cents(movement.Amount)
+		}
+	}
+
+	return total
+}
@@ -0,0 +1,257 @@
+package reconciliation
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/external"
+	"ewallet-transaction/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func movement(reference string, direction string, amount float64) external.WalletMovement {
+	return external.WalletMovement{Reference: reference, WalletTransactionType: direction, Amount: amount}
+}
+
+func Test_service_Reconcile(t *testing.T) {
+	to := time.Now()
+	from := to.Add(-time.Hour)
+
+	trx := func(trxType string, status string) models.Transaction {
+		return models.Transaction{
+			UserID:            1,
+			Amount:            100000,
+			TransactionType:   trxType,
+			TransactionStatus: status,
+			Reference:         "REFERENCE",
+			AdditionalInfo:    "{}",
+		}
+	}
+
+	tests := []struct {
+		name           string
+		trx            models.Transaction
+		movements      []external.WalletMovement
+		reversals      []external.WalletMovement
+		repair         bool
+		wantMismatch   *models.ReconciliationMismatch
+		wantRepairedTo string
+		repairErr      error
+	}{
+		{
+			name:      "match success topup",
+			trx:       trx(constants.TransactionTypeTopup, constants.TransactionStatusSuccess),
+			movements: []external.WalletMovement{movement("REFERENCE", constants.WalletMovementCredit, 100000)},
+		},
+		{
+			name: "match pending purchase without movement",
+			trx:  trx(constants.TransactionTypePurchase, constants.TransactionStatusPending),
+		},
+		{
+			name:      "match reversed purchase",
+			trx:       trx(constants.TransactionTypePurchase, constants.TransactionStatusReversed),
+			movements: []external.WalletMovement{movement("REFERENCE", constants.WalletMovementDebit, 100000)},
+			reversals: []external.WalletMovement{movement("REVERSED-REFERENCE", constants.WalletMovementCredit, 100000)},
+		},
+		{
+			name: "missing success topup",
+			trx:  trx(constants.TransactionTypeTopup, constants.TransactionStatusSuccess),
+			wantMismatch: &models.ReconciliationMismatch{
+				Kind:   constants.ReconciliationMismatchMissing,
+				Detail: "wallet has no movement for a success transaction",
+			},
+		},
+		{
+			name:      "amount mismatch success purchase",
+			trx:       trx(constants.TransactionTypePurchase, constants.TransactionStatusSuccess),
+			movements: []external.WalletMovement{movement("REFERENCE", constants.WalletMovementDebit, 90000)},
+			repair:    true,
+			wantMismatch: &models.ReconciliationMismatch{
+				Kind:         constants.ReconciliationMismatchAmount,
+				WalletAmount: 90000,
+				Detail:       "wallet moved a different amount",
+			},
+		},
+		{
+			name:      "status mismatch pending topup is repaired to success",
+			trx:       trx(constants.TransactionTypeTopup, constants.TransactionStatusPending),
+			movements: []external.WalletMovement{movement("REFERENCE", constants.WalletMovementCredit, 100000)},
+			repair:    true,
+			wantMismatch: &models.ReconciliationMismatch{
+				Kind:         constants.ReconciliationMismatchStatus,
+				WalletAmount: 100000,
+				Detail:       "wallet moved money for a transaction that is not success",
+				RepairStatus: constants.TransactionStatusSuccess,
+				Repaired:     true,
+			},
+			wantRepairedTo: constants.TransactionStatusSuccess,
+		},
+		{
+			name:      "status mismatch success purchase reversed in wallet is repaired to reversed",
+			trx:       trx(constants.TransactionTypePurchase, constants.TransactionStatusSuccess),
+			movements: []external.WalletMovement{movement("REFERENCE", constants.WalletMovementDebit, 100000)},
+			reversals: []external.WalletMovement{movement("REVERSED-REFERENCE", constants.WalletMovementCredit, 100000)},
+			repair:    true,
+			wantMismatch: &models.ReconciliationMismatch{
+				Kind:         constants.ReconciliationMismatchStatus,
+				WalletAmount: 100000,
+				Detail:       "wallet reversed a transaction that is still success",
+				RepairStatus: constants.TransactionStatusReversed,
+				Repaired:     true,
+			},
+			wantRepairedTo: constants.TransactionStatusReversed,
+		},
+		{
+			name:      "status mismatch pending topup changed meanwhile is left unrepaired",
+			trx:       trx(constants.TransactionTypeTopup, constants.TransactionStatusPending),
+			movements: []external.WalletMovement{movement("REFERENCE", constants.WalletMovementCredit, 100000)},
+			repair:    true,
+			wantMismatch: &models.ReconciliationMismatch{
+				Kind:         constants.ReconciliationMismatchStatus,
+				WalletAmount: 100000,
+				Detail:       "wallet moved money for a transaction that is not success",
+				RepairStatus: constants.TransactionStatusSuccess,
+			},
+			wantRepairedTo: constants.TransactionStatusSuccess,
+			repairErr:      models.ErrStatusConflict,
+		},
+		{
+			name:      "status mismatch failed topup with partial movement is not repairable",
+			trx:       trx(constants.TransactionTypeTopup, constants.TransactionStatusFailed),
+			movements: []external.WalletMovement{movement("REFERENCE", constants.WalletMovementCredit, 50000)},
+			repair:    true,
+			wantMismatch: &models.ReconciliationMismatch{
+				Kind:         constants.ReconciliationMismatchStatus,
+				WalletAmount: 50000,
+				Detail:       "wallet moved money for a transaction that is not success",
+			},
+		},
+		{
+			name:      "status mismatch pending topup is only reported without repair",
+			trx:       trx(constants.TransactionTypeTopup, constants.TransactionStatusPending),
+			movements: []external.WalletMovement{movement("REFERENCE", constants.WalletMovementCredit, 100000)},
+			wantMismatch: &models.ReconciliationMismatch{
+				Kind:         constants.ReconciliationMismatchStatus,
+				WalletAmount: 100000,
+				Detail:       "wallet moved money for a transaction that is not success",
+				RepairStatus: constants.TransactionStatusSuccess,
+			},
+		},
+		{
+			name: "match purchase on hold without movement",
+			trx:  trx(constants.TransactionTypePurchase, constants.TransactionStatusOnHold),
+		},
+		{
+			name:      "status mismatch purchase on hold debited in wallet",
+			trx:       trx(constants.TransactionTypePurchase, constants.TransactionStatusOnHold),
+			movements: []external.WalletMovement{movement("REFERENCE", constants.WalletMovementDebit, 100000)},
+			wantMismatch: &models.ReconciliationMismatch{
+				Kind:         constants.ReconciliationMismatchStatus,
+				WalletAmount: 100000,
+				Detail:       "wallet moved money for a transaction that is not success",
+				RepairStatus: constants.TransactionStatusSuccess,
+			},
+		},
+		{
+			name:      "missing reversal of reversed topup",
+			trx:       trx(constants.TransactionTypeTopup, constants.TransactionStatusReversed),
+			movements: []external.WalletMovement{movement("REFERENCE", constants.WalletMovementCredit, 100000)},
+			wantMismatch: &models.ReconciliationMismatch{
+				Kind:         constants.ReconciliationMismatchMissing,
+				WalletAmount: 100000,
+				Detail:       "wallet is missing the movement or the reversal of a reversed transaction",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrlMock := gomock.NewController(t)
+			defer ctrlMock.Finish()
+
+			mockRepo := NewMockrepository(ctrlMock)
+			mockWallet := NewMockwallet(ctrlMock)
+			mockStatuses := NewMockstatusSyncer(ctrlMock)
+
+			mockRepo.EXPECT().GetTransactionsByTimeRange(gomock.Any(), from, to).Return([]models.Transaction{tt.trx}, nil)
+			mockWallet.EXPECT().GetWalletMovements(gomock.Any(), "REFERENCE").Return(tt.movements, nil)
+			mockWallet.EXPECT().GetWalletMovements(gomock.Any(), "REVERSED-REFERENCE").Return(tt.reversals, nil)
+
+			if tt.wantRepairedTo != "" {
+				mockStatuses.EXPECT().SyncStatus(gomock.Any(), tt.trx, tt.wantRepairedTo, constants.StatusActorReconciliation, "repaired from wallet movements").Return(tt.repairErr)
+			}
+
+			s := NewService(mockRepo, mockWallet, mockStatuses, Config{})
+			report, err := s.Reconcile(context.Background(), from, to, tt.repair)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, report.Checked)
+
+			if tt.wantMismatch == nil {
+				assert.Empty(t, report.Mismatches)
+				return
+			}
+
+			want := *tt.wantMismatch
+			want.Reference = tt.trx.Reference
+			want.TransactionType = tt.trx.TransactionType
+			want.TransactionStatus = tt.trx.TransactionStatus
+			want.ExpectedAmount = tt.trx.Amount
+			assert.Equal(t, []models.ReconciliationMismatch{want}, report.Mismatches)
+			if want.Repaired {
+				assert.Equal(t, 1, report.Repaired)
+			}
+		})
+	}
+}
+
+func Test_service_Reconcile_Error(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+	mockWallet := NewMockwallet(ctrlMock)
+	mockStatuses := NewMockstatusSyncer(ctrlMock)
+	s := NewService(mockRepo, mockWallet, mockStatuses, Config{})
+
+	mockRepo.EXPECT().GetTransactionsByTimeRange(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+	_, err := s.Reconcile(context.Background(), time.Now().Add(-time.Hour), time.Now(), false)
+	assert.ErrorIs(t, err, assert.AnError)
+
+	mockRepo.EXPECT().GetTransactionsByTimeRange(gomock.Any(), gomock.Any(), gomock.Any()).Return([]models.Transaction{{
+		Reference:         "REFUND-REFERENCE",
+		TransactionType:   constants.TransactionTypeRefund,
+		TransactionStatus: constants.TransactionStatusSuccess,
+	}}, nil)
+	mockWallet.EXPECT().GetWalletMovements(gomock.Any(), "REFUND-REFERENCE").Return(nil, assert.AnError)
+	_, err = s.Reconcile(context.Background(), time.Now().Add(-time.Hour), time.Now(), false)
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func Test_service_StartStop(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+	mockWallet := NewMockwallet(ctrlMock)
+	mockStatuses := NewMockstatusSyncer(ctrlMock)
+
+	ran := make(chan struct{}, 1)
+	mockRepo.EXPECT().GetTransactionsByTimeRange(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, from time.Time, to time.Time) ([]models.Transaction, error) {
+		assert.Equal(t, time.Hour, to.Sub(from))
+		select {
+		case ran <- struct{}{}:
+		default:
+		}
+		return nil, nil
+	}).MinTimes(1)
+
+	s := NewService(mockRepo, mockWallet, mockStatuses, Config{Interval: 10 * time.Millisecond, Window: time.Hour, Grace: time.Minute})
+	s.Start()
+	<-ran
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, s.Stop(ctx))
+}
@@ -0,0 +1,52 @@
+package reconciliation
+
+import (
+	"context"
+	"ewallet-transaction/external"
+	"ewallet-transaction/internal/models"
+	"sync"
+	"time"
+)
+
+//go:generate mockgen -source=service.go -destination=service_mock_test.go -package=reconciliation
+type repository interface {
+	GetTransactionsByTimeRange(ctx context.Context, from time.Time, to time.Time) ([]models.Transaction, error)
+}
+
+type wallet interface {
+	GetWalletMovements(ctx context.Context, reference string) ([]external.WalletMovement, error)
+}
+
+// statusSyncer saves a repaired status without moving money, with the history, ledger, event,
+// webhook and notification of any other status change.
+type statusSyncer interface {
+	SyncStatus(ctx context.Context, trx models.Transaction, status string, actor string, reason string) error
+}
+
+type Config struct {
+	Interval   time.Duration
+	Window     time.Duration
+	Grace      time.Duration
+	AutoRepair bool
+}
+
+type service struct {
+	repository repository
+	wallet     wallet
+	statuses   statusSyncer
+	config     Config
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+func NewService(repository repository, wallet wallet, statuses statusSyncer, config Config) *service {
+	return &service{
+		repository: repository,
+		wallet:     wallet,
+		statuses:   statuses,
+		config:     config,
+		stop:       make(chan struct{}),
+	}
+}
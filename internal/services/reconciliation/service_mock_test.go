@@ -0,0 +1,136 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=service_mock_test.go -package=reconciliation
+//
+
+// Package reconciliation is a generated GoMock package.
+package reconciliation
+
+import (
+	context "context"
+	external "ewallet-transaction/external"
+	models "ewallet-transaction/internal/models"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// Mockrepository is a mock of repository interface.
+type Mockrepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockrepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockrepositoryMockRecorder is the mock recorder for Mockrepository.
+type MockrepositoryMockRecorder struct {
+	mock *Mockrepository
+}
+
+// NewMockrepository creates a new mock instance.
+func NewMockrepository(ctrl *gomock.Controller) *Mockrepository {
+	mock := &Mockrepository{ctrl: ctrl}
+	mock.recorder = &MockrepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockrepository) EXPECT() *MockrepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetTransactionsByTimeRange mocks base method.
+func (m *Mockrepository) GetTransactionsByTimeRange(ctx context.Context, from, to time.Time) ([]models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionsByTimeRange", ctx, from, to)
+	ret0, _ := ret[0].([]models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionsByTimeRange indicates an expected call of GetTransactionsByTimeRange.
+func (mr *MockrepositoryMockRecorder) GetTransactionsByTimeRange(ctx, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByTimeRange", reflect.TypeOf((*Mockrepository)(nil).GetTransactionsByTimeRange), ctx, from, to)
+}
+
+// Mockwallet is a mock of wallet interface.
+type Mockwallet struct {
+	ctrl     *gomock.Controller
+	recorder *MockwalletMockRecorder
+	isgomock struct{}
+}
+
+// MockwalletMockRecorder is the mock recorder for Mockwallet.
+type MockwalletMockRecorder struct {
+	mock *Mockwallet
+}
+
+// NewMockwallet creates a new mock instance.
+func NewMockwallet(ctrl *gomock.Controller) *Mockwallet {
+	mock := &Mockwallet{ctrl: ctrl}
+	mock.recorder = &MockwalletMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockwallet) EXPECT() *MockwalletMockRecorder {
+	return m.recorder
+}
+
+// GetWalletMovements mocks base method.
+func (m *Mockwallet) GetWalletMovements(ctx context.Context, reference string) ([]external.WalletMovement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWalletMovements", ctx, reference)
+	ret0, _ := ret[0].([]external.WalletMovement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWalletMovements indicates an expected call of GetWalletMovements.
+func (mr *MockwalletMockRecorder) GetWalletMovements(ctx, reference any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWalletMovements", reflect.TypeOf((*Mockwallet)(nil).GetWalletMovements), ctx, reference)
+}
+
+// MockstatusSyncer is a mock of statusSyncer interface.
+type MockstatusSyncer struct {
+	ctrl     *gomock.Controller
+	recorder *MockstatusSyncerMockRecorder
+	isgomock struct{}
+}
+
+// MockstatusSyncerMockRecorder is the mock recorder for MockstatusSyncer.
+type MockstatusSyncerMockRecorder struct {
+	mock *MockstatusSyncer
+}
+
+// NewMockstatusSyncer creates a new mock instance.
+func NewMockstatusSyncer(ctrl *gomock.Controller) *MockstatusSyncer {
+	mock := &MockstatusSyncer{ctrl: ctrl}
+	mock.recorder = &MockstatusSyncerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockstatusSyncer) EXPECT() *MockstatusSyncerMockRecorder {
+	return m.recorder
+}
+
+// SyncStatus mocks base method.
+func (m *MockstatusSyncer) SyncStatus(ctx context.Context, trx models.Transaction, status, actor, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncStatus", ctx, trx, status, actor, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncStatus indicates an expected call of SyncStatus.
+func (mr *MockstatusSyncerMockRecorder) SyncStatus(ctx, trx, status, actor, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncStatus", reflect.TypeOf((*MockstatusSyncer)(nil).SyncStatus), ctx, trx, status, actor, reason)
+}
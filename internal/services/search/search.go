@@ -0,0 +1,56 @@
+package search
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// Search validates the filters and returns one page of the transactions matching the query,
+// best match first. Without a query it lists the filtered transactions, newest first.
+// The page defaults to the first one of DefaultSearchPageSize results.
+func (s *service) Search(ctx context.Context, req models.SearchRequest) (models.SearchResponse, error) {
+	req.Query = strings.TrimSpace(req.Query)
+	if req.Query != "" && utf8.RuneCountInString(req.Query) < constants.MinSearchQueryLength {
+		return models.SearchResponse{}, errors.Wrapf(models.ErrInvalidSearchRequest, "query must have at least %d characters", constants.MinSearchQueryLength)
+	}
+	if req.TransactionType != "" && !constants.MapTransactionType[req.TransactionType] {
+		return models.SearchResponse{}, errors.Wrapf(models.ErrInvalidSearchRequest, "unknown transaction type %q", req.TransactionType)
+	}
+	if req.TransactionStatus != "" && !constants.MapTransactionStatus[req.TransactionStatus] {
+		return models.SearchResponse{}, errors.Wrapf(models.ErrInvalidSearchRequest, "unknown transaction status %q", req.TransactionStatus)
+	}
+	if !req.From.IsZero() && !req.To.IsZero() && !req.From.Before(req.To) {
+		return models.SearchResponse{}, errors.Wrap(models.ErrInvalidSearchRequest, "from must be before to")
+	}
+
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = constants.DefaultSearchPageSize
+	}
+	if req.Page < 0 || req.PageSize < 0 || req.PageSize > constants.MaxSearchPageSize {
+		return models.SearchResponse{}, errors.Wrapf(models.ErrInvalidSearchRequest, "page must be positive and page_size at most %d", constants.MaxSearchPageSize)
+	}
+
+	items, total, err := s.backend.SearchTransactions(ctx, req)
+	if err != nil {
+		return models.SearchResponse{}, errors.Wrap(err, "failed to search transactions")
+	}
+
+	if items == nil {
+		items = []models.SearchResult{}
+	}
+
+	return models.SearchResponse{
+		Items:    items,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		Total:    total,
+	}, nil
+}
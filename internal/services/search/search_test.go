@@ -0,0 +1,132 @@
+package search
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_service_Search(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockBackend := NewMockbackend(ctrlMock)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	tests := []struct {
+		name    string
+		req     models.SearchRequest
+		want    models.SearchResponse
+		wantErr error
+		mockFn  func()
+	}{
+		{
+			name: "success default page",
+			req:  models.SearchRequest{Query: "  kopi  "},
+			want: models.SearchResponse{
+				Items:    []models.SearchResult{{Transaction: models.Transaction{ID: 1, Description: "kopi susu"}, Score: 1.5}},
+				Page:     1,
+				PageSize: constants.DefaultSearchPageSize,
+				Total:    1,
+			},
+			mockFn: func() {
+				mockBackend.EXPECT().SearchTransactions(gomock.Any(), models.SearchRequest{Query: "kopi", Page: 1, PageSize: constants.DefaultSearchPageSize}).
+					Return([]models.SearchResult{{Transaction: models.Transaction{ID: 1, Description: "kopi susu"}, Score: 1.5}}, int64(1), nil)
+			},
+		},
+		{
+			name: "success with filters and no result",
+			req: models.SearchRequest{
+				Query:             "REF-",
+				UserID:            1,
+				TransactionType:   constants.TransactionTypePurchase,
+				TransactionStatus: constants.TransactionStatusSuccess,
+				From:              from,
+				To:                to,
+				Page:              3,
+				PageSize:          10,
+			},
+			want: models.SearchResponse{Items: []models.SearchResult{}, Page: 3, PageSize: 10, Total: 21},
+			mockFn: func() {
+				mockBackend.EXPECT().SearchTransactions(gomock.Any(), models.SearchRequest{
+					Query:             "REF-",
+					UserID:            1,
+					TransactionType:   constants.TransactionTypePurchase,
+					TransactionStatus: constants.TransactionStatusSuccess,
+					From:              from,
+					To:                to,
+					Page:              3,
+					PageSize:          10,
+				}).Return(nil, int64(21), nil)
+			},
+		},
+		{
+			name: "success list without query",
+			req:  models.SearchRequest{Query: "  ", UserID: 1},
+			want: models.SearchResponse{Items: []models.SearchResult{}, Page: 1, PageSize: constants.DefaultSearchPageSize},
+			mockFn: func() {
+				mockBackend.EXPECT().SearchTransactions(gomock.Any(), models.SearchRequest{UserID: 1, Page: 1, PageSize: constants.DefaultSearchPageSize}).
+					Return(nil, int64(0), nil)
+			},
+		},
+		{
+			name:    "error query too short",
+			req:     models.SearchRequest{Query: " a "},
+			wantErr: models.ErrInvalidSearchRequest,
+			mockFn:  func() {},
+		},
+		{
+			name:    "error unknown type",
+			req:     models.SearchRequest{Query: "kopi", TransactionType: "DEBIT"},
+			wantErr: models.ErrInvalidSearchRequest,
+			mockFn:  func() {},
+		},
+		{
+			name:    "error unknown status",
+			req:     models.SearchRequest{Query: "kopi", TransactionStatus: "DONE"},
+			wantErr: models.ErrInvalidSearchRequest,
+			mockFn:  func() {},
+		},
+		{
+			name:    "error from after to",
+			req:     models.SearchRequest{Query: "kopi", From: to, To: from},
+			wantErr: models.ErrInvalidSearchRequest,
+			mockFn:  func() {},
+		},
+		{
+			name:    "error page size too large",
+			req:     models.SearchRequest{Query: "kopi", PageSize: constants.MaxSearchPageSize + 1},
+			wantErr: models.ErrInvalidSearchRequest,
+			mockFn:  func() {},
+		},
+		{
+			name:    "error backend",
+			req:     models.SearchRequest{Query: "kopi"},
+			wantErr: assert.AnError,
+			mockFn: func() {
+				mockBackend.EXPECT().SearchTransactions(gomock.Any(), gomock.Any()).Return(nil, int64(0), assert.AnError)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			s := NewService(mockBackend)
+			got, err := s.Search(context.Background(), tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package search
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+)
+
+//go:generate mockgen -source=service.go -destination=service_mock_test.go -package=search
+
+// backend runs the ranked query, the transaction repository implements it on the database
+// and a dedicated search engine can replace it without touching the service.
+type backend interface {
+	SearchTransactions(ctx context.Context, req models.SearchRequest) ([]models.SearchResult, int64, error)
+}
+
+type service struct {
+	backend backend
+}
+
+func NewService(backend backend) *service {
+	return &service{
+		backend: backend,
+	}
+}
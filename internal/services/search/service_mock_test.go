@@ -0,0 +1,58 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=service_mock_test.go -package=search
+//
+
+// Package search is a generated GoMock package.
+package search
+
+import (
+	context "context"
+	models "ewallet-transaction/internal/models"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// Mockbackend is a mock of backend interface.
+type Mockbackend struct {
+	ctrl     *gomock.Controller
+	recorder *MockbackendMockRecorder
+	isgomock struct{}
+}
+
+// MockbackendMockRecorder is the mock recorder for Mockbackend.
+type MockbackendMockRecorder struct {
+	mock *Mockbackend
+}
+
+// NewMockbackend creates a new mock instance.
+func NewMockbackend(ctrl *gomock.Controller) *Mockbackend {
+	mock := &Mockbackend{ctrl: ctrl}
+	mock.recorder = &MockbackendMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockbackend) EXPECT() *MockbackendMockRecorder {
+	return m.recorder
+}
+
+// SearchTransactions mocks base method.
+func (m *Mockbackend) SearchTransactions(ctx context.Context, req models.SearchRequest) ([]models.SearchResult, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTransactions", ctx, req)
+	ret0, _ := ret[0].([]models.SearchResult)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchTransactions indicates an expected call of SearchTransactions.
+func (mr *MockbackendMockRecorder) SearchTransactions(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTransactions", reflect.TypeOf((*Mockbackend)(nil).SearchTransactions), ctx, req)
+}
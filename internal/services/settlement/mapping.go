@@ -0,0 +1,179 @@
+package settlement
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultMappings are always available, a mappings file can add providers or override them.
+var DefaultMappings = map[string]models.SettlementMapping{
+	constants.SettlementDefaultProvider: {
+		Provider:  constants.SettlementDefaultProvider,
+		Delimiter: ",",
+		Header:    true,
+		Reference: "reference",
+		Amount:    "amount",
+	},
+}
+
+// LoadMappings returns the default mappings plus the ones of the json file at path, a json array of
+// SettlementMapping. An empty path only returns the defaults.
+func LoadMappings(path string) (map[string]models.SettlementMapping, error) {
+	mappings := map[string]models.SettlementMapping{}
+	for provider, mapping := range DefaultMappings {
+		mappings[provider] = mapping
+	}
+
+	if path == "" {
+		return mappings, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read settlement mappings file")
+	}
+
+	var fileMappings []models.SettlementMapping
+	if err := json.Unmarshal(content, &fileMappings); err != nil {
+		return nil, errors.Wrap(err, "failed to parse settlement mappings file")
+	}
+
+	for _, mapping := range fileMappings {
+		if mapping.Provider == "" || mapping.Reference == "" || mapping.Amount == "" {
+			return nil, fmt.Errorf("settlement mapping %q needs provider, reference and amount", mapping.Provider)
+		}
+		if mapping.Delimiter == "" {
+			mapping.Delimiter = ","
+		}
+		mappings[mapping.Provider] = mapping
+	}
+
+	return mappings, nil
+}
+
+// parse reads the settlement lines of a csv file. A line that can't be read is returned as unmatched
+// so one bad line doesn't reject the whole file, a file that doesn't fit the mapping is an error.
+func parse(r io.Reader, mapping models.SettlementMapping) ([]models.SettlementLine, []models.SettlementUnmatched, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if mapping.Delimiter != "" {
+		reader.Comma = []rune(mapping.Delimiter)[0]
+	}
+
+	var (
+		lines     []models.SettlementLine
+		unmatched []models.SettlementUnmatched
+		refIdx    = -1
+		amountIdx = -1
+		err       error
+	)
+
+	if !mapping.Header {
+		refIdx, err = strconv.Atoi(mapping.Reference)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "reference column must be an index without header")
+		}
+		amountIdx, err = strconv.Atoi(mapping.Amount)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "amount column must be an index without header")
+		}
+	}
+
+	for lineNo := 1; ; lineNo++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to read line %d", lineNo)
+		}
+
+		if mapping.Header && lineNo == 1 {
+			for i, column := range record {
+				switch strings.TrimSpace(column) {
+				case mapping.Reference:
+					refIdx = i
+				case mapping.Amount:
+					amountIdx = i
+				}
+			}
+			if refIdx < 0 || amountIdx < 0 {
+				return nil, nil, fmt.Errorf("header must have the %q and %q columns", mapping.Reference, mapping.Amount)
+			}
+			continue
+		}
+
+		if isBlank(record) {
+			continue
+		}
+
+		if refIdx >= len(record) || amountIdx >= len(record) {
+			unmatched = append(unmatched, models.SettlementUnmatched{
+				Line:   lineNo,
+				Reason: constants.SettlementUnmatchedInvalidLine,
+				Detail: fmt.Sprintf("line has %d columns", len(record)),
+			})
+			continue
+		}
+
+		reference := strings.TrimSpace(record[refIdx])
+		amount, err := parseAmount(record[amountIdx], mapping.DecimalComma)
+		if reference == "" || err != nil {
+			unmatched = append(unmatched, models.SettlementUnmatched{
+				Line:      lineNo,
+				Reference: reference,
+				Reason:    constants.SettlementUnmatchedInvalidLine,
+				Detail:    fmt.Sprintf("invalid reference %q or amount %q", reference, record[amountIdx]),
+			})
+			continue
+		}
+
+		lines = append(lines, models.SettlementLine{
+			Line:      lineNo,
+			Reference: reference,
+			Amount:    amount,
+		})
+	}
+
+	return lines, unmatched, nil
+}
+
+func parseAmount(raw string, decimalComma bool) (float64, error) {
+	raw = strings.TrimSpace(raw)
+	if decimalComma {
+		raw = strings.ReplaceAll(raw, ".", "")
+		raw = strings.ReplaceAll(raw, ",", ".")
+	} else {
+		raw = strings.ReplaceAll(raw, ",", "")
+	}
+
+	amount, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, err
+	}
+	if amount <= 0 {
+		return 0, fmt.Errorf("amount must be positive, got %s", raw)
+	}
+
+	return amount, nil
+}
+
+func isBlank(record []string) bool {
+	for _, column := range record {
+		if strings.TrimSpace(column) != "" {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,35 @@
+package settlement
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+)
+
+//go:generate mockgen -source=service.go -destination=service_mock_test.go -package=settlement
+type repository interface {
+	GetTransactionByReference(ctx context.Context, reference string, includeRefund bool) (models.Transaction, error)
+}
+
+type transactionUpdater interface {
+	UpdateStatusTransaction(ctx context.Context, tokenData models.TokenData, req *models.UpdateStatusTransaction) error
+}
+
+type Config struct {
+	Mappings map[string]models.SettlementMapping
+	// ServiceToken authorizes the wallet credit of a matched topup on behalf of its user.
+	ServiceToken string
+}
+
+type service struct {
+	repository  repository
+	transaction transactionUpdater
+	config      Config
+}
+
+func NewService(repository repository, transaction transactionUpdater, config Config) *service {
+	return &service{
+		repository:  repository,
+		transaction: transaction,
+		config:      config,
+	}
+}
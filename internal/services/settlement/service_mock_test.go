@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=service_mock_test.go -package=settlement
+//
+
+// Package settlement is a generated GoMock package.
+package settlement
+
+import (
+	context "context"
+	models "ewallet-transaction/internal/models"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// Mockrepository is a mock of repository interface.
+type Mockrepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockrepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockrepositoryMockRecorder is the mock recorder for Mockrepository.
+type MockrepositoryMockRecorder struct {
+	mock *Mockrepository
+}
+
+// NewMockrepository creates a new mock instance.
+func NewMockrepository(ctrl *gomock.Controller) *Mockrepository {
+	mock := &Mockrepository{ctrl: ctrl}
+	mock.recorder = &MockrepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockrepository) EXPECT() *MockrepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetTransactionByReference mocks base method.
+func (m *Mockrepository) GetTransactionByReference(ctx context.Context, reference string, includeRefund bool) (models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionByReference", ctx, reference, includeRefund)
+	ret0, _ := ret[0].(models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionByReference indicates an expected call of GetTransactionByReference.
+func (mr *MockrepositoryMockRecorder) GetTransactionByReference(ctx, reference, includeRefund any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionByReference", reflect.TypeOf((*Mockrepository)(nil).GetTransactionByReference), ctx, reference, includeRefund)
+}
+
+// MocktransactionUpdater is a mock of transactionUpdater interface.
+type MocktransactionUpdater struct {
+	ctrl     *gomock.Controller
+	recorder *MocktransactionUpdaterMockRecorder
+	isgomock struct{}
+}
+
+// MocktransactionUpdaterMockRecorder is the mock recorder for MocktransactionUpdater.
+type MocktransactionUpdaterMockRecorder struct {
+	mock *MocktransactionUpdater
+}
+
+// NewMocktransactionUpdater creates a new mock instance.
+func NewMocktransactionUpdater(ctrl *gomock.Controller) *MocktransactionUpdater {
+	mock := &MocktransactionUpdater{ctrl: ctrl}
+	mock.recorder = &MocktransactionUpdaterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocktransactionUpdater) EXPECT() *MocktransactionUpdaterMockRecorder {
+	return m.recorder
+}
+
+// UpdateStatusTransaction mocks base method.
+func (m *MocktransactionUpdater) UpdateStatusTransaction(ctx context.Context, tokenData models.TokenData, req *models.UpdateStatusTransaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatusTransaction", ctx, tokenData, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatusTransaction indicates an expected call of UpdateStatusTransaction.
+func (mr *MocktransactionUpdaterMockRecorder) UpdateStatusTransaction(ctx, tokenData, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatusTransaction", reflect.TypeOf((*MocktransactionUpdater)(nil).UpdateStatusTransaction), ctx, tokenData, req)
+}
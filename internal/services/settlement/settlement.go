@@ -0,0 +1,130 @@
+package settlement
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// Providers returns the providers that have a column mapping.
+func (s *service) Providers() []string {
+	providers := make([]string, 0, len(s.config.Mappings))
+	for provider := range s.config.Mappings {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+
+	return providers
+}
+
+// Import matches the lines of a provider settlement file to TOPUP transactions by reference and amount.
+// Matched PENDING topups are marked SUCCESS through the transaction service so the wallet is credited,
+// matched SUCCESS ones were already settled. Everything else ends up in the unmatched report.
+func (s *service) Import(ctx context.Context, provider string, r io.Reader) (models.SettlementReport, error) {
+	report := models.SettlementReport{
+		Provider:  provider,
+		Unmatched: []models.SettlementUnmatched{},
+	}
+
+	mapping, ok := s.config.Mappings[provider]
+	if !ok {
+		return report, errors.Wrapf(models.ErrInvalidSettlementFile, "no settlement mapping for provider %q", provider)
+	}
+
+	lines, invalid, err := parse(r, mapping)
+	if err != nil {
+		return report, errors.Wrapf(models.ErrInvalidSettlementFile, "failed to parse settlement file: %v", err)
+	}
+
+	report.Lines = len(lines) + len(invalid)
+	report.Unmatched = append(report.Unmatched, invalid...)
+
+	seen := map[string]bool{}
+	for _, line := range lines {
+		if seen[line.Reference] {
+			report.Unmatched = append(report.Unmatched, unmatched(line, constants.SettlementUnmatchedDuplicate, "reference already settled earlier in the file"))
+			continue
+		}
+		seen[line.Reference] = true
+
+		reason, detail, marked, err := s.match(ctx, line)
+		if err != nil {
+			return report, err
+		}
+
+		if reason != "" {
+			report.Unmatched = append(report.Unmatched, unmatched(line, reason, detail))
+			continue
+		}
+
+		report.Matched++
+		if marked {
+			report.MarkedSuccess++
+		}
+	}
+
+	sort.SliceStable(report.Unmatched, func(i, j int) bool {
+		return report.Unmatched[i].Line < report.Unmatched[j].Line
+	})
+
+	return report, nil
+}
+
+// match returns the unmatched reason of a line, or whether the line marked its topup SUCCESS.
+func (s *service) match(ctx context.Context, line models.SettlementLine) (string, string, bool, error) {
+	trx, err := s.repository.GetTransactionByReference(ctx, line.Reference, false)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return constants.SettlementUnmatchedNotFound, "no transaction with this reference", false, nil
+	}
+	if err != nil {
+		return "", "", false, errors.Wrapf(err, "failed to get transaction %s", line.Reference)
+	}
+
+	if trx.TransactionType != constants.TransactionTypeTopup {
+		return constants.SettlementUnmatchedNotFound, "transaction is a " + trx.TransactionType + ", not a topup", false, nil
+	}
+
+	if helpers.Cents(trx.Amount) != helpers.Cents(line.Amount) {
+		return constants.SettlementUnmatchedAmountMismatch, fmt.Sprintf("transaction amount is %.2f", trx.Amount), false, nil
+	}
+
+	switch trx.TransactionStatus {
+	case constants.TransactionStatusSuccess:
+		return "", "", false, nil
+	case constants.TransactionStatusPending:
+	default:
+		return constants.SettlementUnmatchedInvalidStatus, "transaction is " + trx.TransactionStatus, false, nil
+	}
+
+	err = s.transaction.UpdateStatusTransaction(ctx, models.TokenData{
+		UserID: trx.UserID,
+		Token:  s.config.ServiceToken,
+	}, &models.UpdateStatusTransaction{
+		Reference:         trx.Reference,
+		TransactionStatus: constants.TransactionStatusSuccess,
+		Actor:             constants.StatusActorSettlement,
+		Reason:            "settled by the provider",
+	})
+	if err != nil {
+		return constants.SettlementUnmatchedUpdateFailed, err.Error(), false, nil
+	}
+
+	return "", "", true, nil
+}
+
+func unmatched(line models.SettlementLine, reason string, detail string) models.SettlementUnmatched {
+	return models.SettlementUnmatched{
+		Line:      line.Line,
+		Reference: line.Reference,
+		Amount:    line.Amount,
+		Reason:    reason,
+		Detail:    detail,
+	}
+}
@@ -0,0 +1,196 @@
+package settlement
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func Test_parse(t *testing.T) {
+	tests := []struct {
+		name          string
+		mapping       models.SettlementMapping
+		file          string
+		wantLines     []models.SettlementLine
+		wantUnmatched []string
+		wantErr       bool
+	}{
+		{
+			name:    "success header",
+			mapping: DefaultMappings[constants.SettlementDefaultProvider],
+			file:    "date,amount,reference\n2025-01-01,\"100,000.50\",REF-1\n\n2025-01-01,abc,REF-2\n",
+			wantLines: []models.SettlementLine{
+				{Line: 2, Reference: "REF-1", Amount: 100000.50},
+			},
+			wantUnmatched: []string{constants.SettlementUnmatchedInvalidLine},
+		},
+		{
+			name:    "success index columns with decimal comma",
+			mapping: models.SettlementMapping{Delimiter: ";", Reference: "1", Amount: "0", DecimalComma: true},
+			file:    "1.000.000,25;REF-1\n-5;REF-2\n7\n",
+			wantLines: []models.SettlementLine{
+				{Line: 1, Reference: "REF-1", Amount: 1000000.25},
+			},
+			wantUnmatched: []string{constants.SettlementUnmatchedInvalidLine, constants.SettlementUnmatchedInvalidLine},
+		},
+		{
+			name:    "error header without columns",
+			mapping: DefaultMappings[constants.SettlementDefaultProvider],
+			file:    "date,total\n2025-01-01,100\n",
+			wantErr: true,
+		},
+		{
+			name:    "error column is not an index",
+			mapping: models.SettlementMapping{Reference: "reference", Amount: "1"},
+			file:    "REF-1,100\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, unmatched, err := parse(strings.NewReader(tt.file), tt.mapping)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.wantLines, lines)
+
+			var reasons []string
+			for _, u := range unmatched {
+				reasons = append(reasons, u.Reason)
+			}
+			assert.Equal(t, tt.wantUnmatched, reasons)
+		})
+	}
+}
+
+func TestLoadMappings(t *testing.T) {
+	mappings, err := LoadMappings("")
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultMappings, mappings)
+
+	path := filepath.Join(t.TempDir(), "mappings.json")
+	err = os.WriteFile(path, []byte(`[{"provider":"bank","header":true,"reference":"ref_no","amount":"nominal","decimal_comma":true}]`), 0o600)
+	assert.NoError(t, err)
+
+	mappings, err = LoadMappings(path)
+	assert.NoError(t, err)
+	assert.Contains(t, mappings, constants.SettlementDefaultProvider)
+	assert.Equal(t, models.SettlementMapping{
+		Provider:     "bank",
+		Delimiter:    ",",
+		Header:       true,
+		Reference:    "ref_no",
+		Amount:       "nominal",
+		DecimalComma: true,
+	}, mappings["bank"])
+
+	err = os.WriteFile(path, []byte(`[{"provider":"bank"}]`), 0o600)
+	assert.NoError(t, err)
+	_, err = LoadMappings(path)
+	assert.Error(t, err)
+
+	_, err = LoadMappings(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func Test_service_Import(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+	mockTrx := NewMocktransactionUpdater(ctrlMock)
+
+	topup := func(reference string, status string) models.Transaction {
+		return models.Transaction{
+			UserID:            7,
+			Amount:            100000,
+			TransactionType:   constants.TransactionTypeTopup,
+			TransactionStatus: status,
+			Reference:         reference,
+		}
+	}
+
+	file := strings.Join([]string{
+		"reference,amount",
+		"PENDING,100000",
+		"SUCCESS,100000.00",
+		"PENDING,100000",
+		"MISSING,100000",
+		"PURCHASE,100000",
+		"MISMATCH,99999.99",
+		"FAILED,100000",
+		"REJECTED,100000",
+		"BROKEN,",
+	}, "\n")
+
+	mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "PENDING", false).Return(topup("PENDING", constants.TransactionStatusPending), nil)
+	mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "SUCCESS", false).Return(topup("SUCCESS", constants.TransactionStatusSuccess), nil)
+	mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "MISSING", false).Return(models.Transaction{}, gorm.ErrRecordNotFound)
+	purchase := topup("PURCHASE", constants.TransactionStatusSuccess)
+	purchase.TransactionType = constants.TransactionTypePurchase
+	mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "PURCHASE", false).Return(purchase, nil)
+	mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "MISMATCH", false).Return(topup("MISMATCH", constants.TransactionStatusPending), nil)
+	mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "FAILED", false).Return(topup("FAILED", constants.TransactionStatusFailed), nil)
+	mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "REJECTED", false).Return(topup("REJECTED", constants.TransactionStatusPending), nil)
+
+	mockTrx.EXPECT().UpdateStatusTransaction(gomock.Any(), models.TokenData{UserID: 7, Token: "service-token"}, &models.UpdateStatusTransaction{
+		Reference:         "PENDING",
+		TransactionStatus: constants.TransactionStatusSuccess,
+		Actor:             constants.StatusActorSettlement,
+		Reason:            "settled by the provider",
+	}).Return(nil)
+	mockTrx.EXPECT().UpdateStatusTransaction(gomock.Any(), gomock.Any(), &models.UpdateStatusTransaction{
+		Reference:         "REJECTED",
+		TransactionStatus: constants.TransactionStatusSuccess,
+		Actor:             constants.StatusActorSettlement,
+		Reason:            "settled by the provider",
+	}).Return(assert.AnError)
+
+	s := NewService(mockRepo, mockTrx, Config{Mappings: DefaultMappings, ServiceToken: "service-token"})
+	report, err := s.Import(context.Background(), constants.SettlementDefaultProvider, strings.NewReader(file))
+	assert.NoError(t, err)
+	assert.Equal(t, 9, report.Lines)
+	assert.Equal(t, 2, report.Matched)
+	assert.Equal(t, 1, report.MarkedSuccess)
+
+	var reasons []string
+	for _, u := range report.Unmatched {
+		reasons = append(reasons, u.Reference+" "+u.Reason)
+	}
+	assert.Equal(t, []string{
+		"PENDING " + constants.SettlementUnmatchedDuplicate,
+		"MISSING " + constants.SettlementUnmatchedNotFound,
+		"PURCHASE " + constants.SettlementUnmatchedNotFound,
+		"MISMATCH " + constants.SettlementUnmatchedAmountMismatch,
+		"FAILED " + constants.SettlementUnmatchedInvalidStatus,
+		"REJECTED " + constants.SettlementUnmatchedUpdateFailed,
+		"BROKEN " + constants.SettlementUnmatchedInvalidLine,
+	}, reasons)
+}
+
+func Test_service_Import_Error(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+	mockTrx := NewMocktransactionUpdater(ctrlMock)
+	s := NewService(mockRepo, mockTrx, Config{Mappings: DefaultMappings})
+
+	_, err := s.Import(context.Background(), "unknown", strings.NewReader("reference,amount\n"))
+	assert.ErrorIs(t, err, models.ErrInvalidSettlementFile)
+
+	_, err = s.Import(context.Background(), constants.SettlementDefaultProvider, strings.NewReader("ref,total\n"))
+	assert.ErrorIs(t, err, models.ErrInvalidSettlementFile)
+
+	mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "REF", false).Return(models.Transaction{}, assert.AnError)
+	_, err = s.Import(context.Background(), constants.SettlementDefaultProvider, strings.NewReader("reference,amount\nREF,100\n"))
+	assert.ErrorIs(t, err, assert.AnError)
+}
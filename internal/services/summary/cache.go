@@ -0,0 +1,73 @@
+package summary
+
+import (
+	"ewallet-transaction/internal/models"
+	"sync"
+	"time"
+)
+
+type cacheEntry struct {
+	summary   models.TransactionSummary
+	expiresAt time.Time
+}
+
+// cache keeps computed summaries for a short ttl so the home screen doesn't aggregate on every open.
+// Once full, expired entries are swept and if none expired an arbitrary one is dropped.
+type cache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	size    int
+	entries map[string]cacheEntry
+}
+
+func newCache(ttl time.Duration, size int) *cache {
+	return &cache{
+		ttl:     ttl,
+		size:    size,
+		entries: map[string]cacheEntry{},
+	}
+}
+
+func (c *cache) get(key string, now time.Time) (models.TransactionSummary, bool) {
+	if c.ttl <= 0 {
+		return models.TransactionSummary{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || !now.Before(entry.expiresAt) {
+		return models.TransactionSummary{}, false
+	}
+
+	return entry.summary, true
+}
+
+func (c *cache) set(key string, summary models.TransactionSummary, now time.Time) {
+	if c.ttl <= 0 || c.size <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.size {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		for k := range c.entries {
+			if len(c.entries) < c.size {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+
+	c.entries[key] = cacheEntry{
+		summary:   summary,
+		expiresAt: now.Add(c.ttl),
+	}
+}
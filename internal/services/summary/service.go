@@ -0,0 +1,35 @@
+package summary
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+	"time"
+)
+
+//go:generate mockgen -source=service.go -destination=service_mock_test.go -package=summary
+type repository interface {
+	GetSummaryBuckets(ctx context.Context, userID uint64, bounds []time.Time) ([]models.SummaryBucket, error)
+	GetSummaryGroups(ctx context.Context, userID uint64, from time.Time, to time.Time, groupBy string) ([]models.SummaryGroup, error)
+}
+
+type Config struct {
+	DefaultTimezone string
+	CacheTTL        time.Duration
+	CacheSize       int
+}
+
+type service struct {
+	repository repository
+	config     Config
+	cache      *cache
+	now        func() time.Time
+}
+
+func NewService(repository repository, config Config) *service {
+	return &service{
+		repository: repository,
+		config:     config,
+		cache:      newCache(config.CacheTTL, config.CacheSize),
+		now:        time.Now,
+	}
+}
@@ -0,0 +1,73 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=service_mock_test.go -package=summary
+//
+
+// Package summary is a generated GoMock package.
+package summary
+
+import (
+	context "context"
+	models "ewallet-transaction/internal/models"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// Mockrepository is a mock of repository interface.
+type Mockrepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockrepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockrepositoryMockRecorder is the mock recorder for Mockrepository.
+type MockrepositoryMockRecorder struct {
+	mock *Mockrepository
+}
+
+// NewMockrepository creates a new mock instance.
+func NewMockrepository(ctrl *gomock.Controller) *Mockrepository {
+	mock := &Mockrepository{ctrl: ctrl}
+	mock.recorder = &MockrepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockrepository) EXPECT() *MockrepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetSummaryBuckets mocks base method.
+func (m *Mockrepository) GetSummaryBuckets(ctx context.Context, userID uint64, bounds []time.Time) ([]models.SummaryBucket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSummaryBuckets", ctx, userID, bounds)
+	ret0, _ := ret[0].([]models.SummaryBucket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSummaryBuckets indicates an expected call of GetSummaryBuckets.
+func (mr *MockrepositoryMockRecorder) GetSummaryBuckets(ctx, userID, bounds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSummaryBuckets", reflect.TypeOf((*Mockrepository)(nil).GetSummaryBuckets), ctx, userID, bounds)
+}
+
+// GetSummaryGroups mocks base method.
+func (m *Mockrepository) GetSummaryGroups(ctx context.Context, userID uint64, from, to time.Time, groupBy string) ([]models.SummaryGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSummaryGroups", ctx, userID, from, to, groupBy)
+	ret0, _ := ret[0].([]models.SummaryGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSummaryGroups indicates an expected call of GetSummaryGroups.
+func (mr *MockrepositoryMockRecorder) GetSummaryGroups(ctx, userID, from, to, groupBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSummaryGroups", reflect.TypeOf((*Mockrepository)(nil).GetSummaryGroups), ctx, userID, from, to, groupBy)
+}
@@ -0,0 +1,156 @@
+package summary
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var summaryTypes = []string{
+	constants.TransactionTypeTopup,
+	constants.TransactionTypePurchase,
+	constants.TransactionTypeRefund,
+}
+
+// GetSummary returns the totals per type, the net flow and a per-day series of the current
+// day, week (from monday) or month in the user's timezone. Only successful transactions count.
+// With a group the purchases of the period are also broken down by category, merchant or tag.
+func (s *service) GetSummary(ctx context.Context, userID uint64, req models.SummaryRequest) (models.TransactionSummary, error) {
+	if !constants.MapSummaryPeriod[req.Period] {
+		return models.TransactionSummary{}, errors.Wrapf(models.ErrInvalidSummaryRequest, "unknown period %q", req.Period)
+	}
+	if req.GroupBy != "" && !constants.MapSummaryGroupBy[req.GroupBy] {
+		return models.TransactionSummary{}, errors.Wrapf(models.ErrInvalidSummaryRequest, "unknown group %q", req.GroupBy)
+	}
+
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = s.config.DefaultTimezone
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return models.TransactionSummary{}, errors.Wrapf(models.ErrInvalidSummaryRequest, "unknown timezone %q", timezone)
+	}
+
+	now := s.now()
+	bounds := periodBounds(req.Period, now.In(loc))
+
+	key := fmt.Sprintf("%d|%s|%s|%d|%s", userID, req.Period, loc.String(), bounds[0].Unix(), req.GroupBy)
+	if summary, ok := s.cache.get(key, now); ok {
+		return summary, nil
+	}
+
+	buckets, err := s.repository.GetSummaryBuckets(ctx, userID, bounds)
+	if err != nil {
+		return models.TransactionSummary{}, errors.Wrap(err, "failed to aggregate transactions")
+	}
+
+	summary := buildSummary(req.Period, loc, bounds, buckets)
+
+	if req.GroupBy != "" {
+		groups, err := s.repository.GetSummaryGroups(ctx, userID, bounds[0], bounds[len(bounds)-1], req.GroupBy)
+		if err != nil {
+			return models.TransactionSummary{}, errors.Wrap(err, "failed to group purchases")
+		}
+		summary.GroupBy = req.GroupBy
+		summary.Groups = groups
+	}
+
+	s.cache.set(key, summary, now)
+
+	return summary, nil
+}
+
+// periodBounds returns the local midnights of every day of the period, plus the end of the period.
+func periodBounds(period string, now time.Time) []time.Time {
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	var end time.Time
+	switch period {
+	case constants.SummaryPeriodWeek:
+		start = start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+		end = start.AddDate(0, 0, 7)
+	case constants.SummaryPeriodMonth:
+		start = start.AddDate(0, 0, 1-start.Day())
+		end = start.AddDate(0, 1, 0)
+	default:
+		end = start.AddDate(0, 0, 1)
+	}
+
+	bounds := []time.Time{start}
+	for day := start.AddDate(0, 0, 1); !day.After(end); day = day.AddDate(0, 0, 1) {
+		bounds = append(bounds, day)
+	}
+
+	return bounds
+}
+
+func buildSummary(period string, loc *time.Location, bounds []time.Time, buckets []models.SummaryBucket) models.TransactionSummary {
+	type total struct {
+		count int
+		cents int64
+	}
+
+	totals := map[string]*total{}
+	for _, trxType := range summaryTypes {
+		totals[trxType] = &total{}
+	}
+
+	days := make([]models.SummaryDay, len(bounds)-1)
+	credits := make([]int64, len(days))
+	debits := make([]int64, len(days))
+	for i := range days {
+		days[i].Date = bounds[i].Format(constants.SummaryDateLayout)
+	}
+
+	for _, bucket := range buckets {
+		if bucket.Bucket < 0 || bucket.Bucket >= len(days) {
+			continue
+		}
+
+		value := helpers.Cents(bucket.Amount)
+		if t, ok := totals[bucket.TransactionType]; ok {
+			t.count += bucket.Count
+			t.cents += value
+		}
+
+		days[bucket.Bucket].Count += bucket.Count
+		if bucket.TransactionType == constants.TransactionTypePurchase {
+			debits[bucket.Bucket] += value
+		} else {
+			credits[bucket.Bucket] += value
+		}
+	}
+
+	summary := models.TransactionSummary{
+		Period:   period,
+		Timezone: loc.String(),
+		From:     bounds[0],
+		To:       bounds[len(bounds)-1],
+		Series:   days,
+	}
+
+	var net int64
+	for i := range days {
+		days[i].Credit = helpers.Amount(credits[i])
+		days[i].Debit = helpers.Amount(debits[i])
+		days[i].NetFlow = helpers.Amount(credits[i] - debits[i])
+		net += credits[i] - debits[i]
+	}
+	summary.NetFlow = helpers.Amount(net)
+
+	for _, trxType := range summaryTypes {
+		summary.Totals = append(summary.Totals, models.SummaryTotal{
+			TransactionType: trxType,
+			Count:           totals[trxType].count,
+			Amount:          helpers.Amount(totals[trxType].cents),
+		})
+	}
+
+	return summary
+}
@@ -0,0 +1,220 @@
+package summary
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_periodBounds(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	assert.NoError(t, err)
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		period    string
+		now       time.Time
+		wantStart time.Time
+		wantEnd   time.Time
+		wantDays  int
+	}{
+		{
+			name:      "day",
+			period:    constants.SummaryPeriodDay,
+			now:       time.Date(2025, 3, 12, 23, 30, 0, 0, jakarta),
+			wantStart: time.Date(2025, 3, 12, 0, 0, 0, 0, jakarta),
+			wantEnd:   time.Date(2025, 3, 13, 0, 0, 0, 0, jakarta),
+			wantDays:  1,
+		},
+		{
+			name:      "week starts on monday",
+			period:    constants.SummaryPeriodWeek,
+			now:       time.Date(2025, 3, 16, 10, 0, 0, 0, jakarta),
+			wantStart: time.Date(2025, 3, 10, 0, 0, 0, 0, jakarta),
+			wantEnd:   time.Date(2025, 3, 17, 0, 0, 0, 0, jakarta),
+			wantDays:  7,
+		},
+		{
+			name:      "month",
+			period:    constants.SummaryPeriodMonth,
+			now:       time.Date(2024, 2, 29, 10, 0, 0, 0, jakarta),
+			wantStart: time.Date(2024, 2, 1, 0, 0, 0, 0, jakarta),
+			wantEnd:   time.Date(2024, 3, 1, 0, 0, 0, 0, jakarta),
+			wantDays:  29,
+		},
+		{
+			name:      "days stay on local midnight across a dst change",
+			period:    constants.SummaryPeriodMonth,
+			now:       time.Date(2025, 3, 20, 10, 0, 0, 0, newYork),
+			wantStart: time.Date(2025, 3, 1, 0, 0, 0, 0, newYork),
+			wantEnd:   time.Date(2025, 4, 1, 0, 0, 0, 0, newYork),
+			wantDays:  31,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bounds := periodBounds(tt.period, tt.now)
+			assert.Len(t, bounds, tt.wantDays+1)
+			assert.True(t, tt.wantStart.Equal(bounds[0]))
+			assert.True(t, tt.wantEnd.Equal(bounds[len(bounds)-1]))
+			for _, bound := range bounds {
+				assert.Zero(t, bound.Hour(), bound.String())
+			}
+		})
+	}
+}
+
+func Test_service_GetSummary(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	assert.NoError(t, err)
+	now := time.Date(2025, 3, 12, 23, 30, 0, 0, jakarta)
+
+	s := NewService(mockRepo, Config{DefaultTimezone: "Asia/Jakarta", CacheTTL: time.Minute, CacheSize: 10})
+	s.now = func() time.Time { return now }
+
+	mockRepo.EXPECT().GetSummaryBuckets(gomock.Any(), uint64(1), gomock.Len(8)).Return([]models.SummaryBucket{
+		{Bucket: 0, TransactionType: constants.TransactionTypeTopup, Count: 2, Amount: 150000.10},
+		{Bucket: 0, TransactionType: constants.TransactionTypePurchase, Count: 1, Amount: 20000},
+		{Bucket: 2, TransactionType: constants.TransactionTypeRefund, Count: 1, Amount: 5000.20},
+		{Bucket: 2, TransactionType: constants.TransactionTypePurchase, Count: 3, Amount: 75000.30},
+	}, nil).Times(1)
+
+	want := models.TransactionSummary{
+		Period:   constants.SummaryPeriodWeek,
+		Timezone: "Asia/Jakarta",
+		From:     time.Date(2025, 3, 10, 0, 0, 0, 0, jakarta),
+		To:       time.Date(2025, 3, 17, 0, 0, 0, 0, jakarta),
+		Totals: []models.SummaryTotal{
+			{TransactionType: constants.TransactionTypeTopup, Count: 2, Amount: 150000.10},
+			{TransactionType: constants.TransactionTypePurchase, Count: 4, Amount: 95000.30},
+			{TransactionType: constants.TransactionTypeRefund, Count: 1, Amount: 5000.20},
+		},
+		NetFlow: 60000,
+		Series: []models.SummaryDay{
+			{Date: "2025-03-10", Count: 3, Credit: 150000.10, Debit: 20000, NetFlow: 130000.10},
+			{Date: "2025-03-11"},
+			{Date: "2025-03-12", Count: 4, Credit: 5000.20, Debit: 75000.30, NetFlow: -70000.10},
+			{Date: "2025-03-13"},
+			{Date: "2025-03-14"},
+			{Date: "2025-03-15"},
+			{Date: "2025-03-16"},
+		},
+	}
+
+	got, err := s.GetSummary(context.Background(), 1, models.SummaryRequest{Period: constants.SummaryPeriodWeek})
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	got, err = s.GetSummary(context.Background(), 1, models.SummaryRequest{Period: constants.SummaryPeriodWeek, Timezone: "Asia/Jakarta"})
+	assert.NoError(t, err)
+	assert.Equal(t, want, got, "served from the cache")
+
+	now = now.Add(2 * time.Minute)
+	mockRepo.EXPECT().GetSummaryBuckets(gomock.Any(), uint64(1), gomock.Any()).Return(nil, nil)
+	got, err = s.GetSummary(context.Background(), 1, models.SummaryRequest{Period: constants.SummaryPeriodWeek})
+	assert.NoError(t, err)
+	assert.Zero(t, got.NetFlow, "cache expired")
+	assert.Len(t, got.Totals, 3)
+}
+
+func Test_service_GetSummary_GroupBy(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	assert.NoError(t, err)
+	now := time.Date(2025, 3, 12, 23, 30, 0, 0, jakarta)
+
+	s := NewService(mockRepo, Config{DefaultTimezone: "Asia/Jakarta", CacheTTL: time.Minute, CacheSize: 10})
+	s.now = func() time.Time { return now }
+
+	groups := []models.SummaryGroup{
+		{Key: "FOOD_AND_DRINK", Name: "Makanan & Minuman", Count: 3, Amount: 75000.30},
+		{Key: "", Count: 1, Amount: 20000},
+	}
+	mockRepo.EXPECT().GetSummaryBuckets(gomock.Any(), uint64(1), gomock.Any()).Return(nil, nil).Times(2)
+	mockRepo.EXPECT().GetSummaryGroups(gomock.Any(), uint64(1), time.Date(2025, 3, 1, 0, 0, 0, 0, jakarta), time.Date(2025, 4, 1, 0, 0, 0, 0, jakarta), constants.SummaryGroupByCategory).Return(groups, nil).Times(1)
+
+	got, err := s.GetSummary(context.Background(), 1, models.SummaryRequest{Period: constants.SummaryPeriodMonth, GroupBy: constants.SummaryGroupByCategory})
+	assert.NoError(t, err)
+	assert.Equal(t, constants.SummaryGroupByCategory, got.GroupBy)
+	assert.Equal(t, groups, got.Groups)
+
+	got, err = s.GetSummary(context.Background(), 1, models.SummaryRequest{Period: constants.SummaryPeriodMonth, GroupBy: constants.SummaryGroupByCategory})
+	assert.NoError(t, err)
+	assert.Equal(t, groups, got.Groups, "served from the cache")
+
+	got, err = s.GetSummary(context.Background(), 1, models.SummaryRequest{Period: constants.SummaryPeriodMonth})
+	assert.NoError(t, err)
+	assert.Empty(t, got.Groups, "the group is part of the cache key")
+
+	_, err = s.GetSummary(context.Background(), 1, models.SummaryRequest{Period: constants.SummaryPeriodMonth, GroupBy: "country"})
+	assert.ErrorIs(t, err, models.ErrInvalidSummaryRequest)
+
+	mockRepo.EXPECT().GetSummaryBuckets(gomock.Any(), uint64(1), gomock.Any()).Return(nil, nil)
+	mockRepo.EXPECT().GetSummaryGroups(gomock.Any(), uint64(1), gomock.Any(), gomock.Any(), constants.SummaryGroupByTag).Return(nil, assert.AnError)
+	_, err = s.GetSummary(context.Background(), 1, models.SummaryRequest{Period: constants.SummaryPeriodMonth, GroupBy: constants.SummaryGroupByTag})
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func Test_service_GetSummary_Error(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+	s := NewService(mockRepo, Config{DefaultTimezone: "Asia/Jakarta"})
+
+	_, err := s.GetSummary(context.Background(), 1, models.SummaryRequest{Period: "year"})
+	assert.ErrorIs(t, err, models.ErrInvalidSummaryRequest)
+
+	_, err = s.GetSummary(context.Background(), 1, models.SummaryRequest{Period: constants.SummaryPeriodDay, Timezone: "Mars/Olympus"})
+	assert.ErrorIs(t, err, models.ErrInvalidSummaryRequest)
+
+	mockRepo.EXPECT().GetSummaryBuckets(gomock.Any(), uint64(1), gomock.Any()).Return(nil, assert.AnError).Times(2)
+	_, err = s.GetSummary(context.Background(), 1, models.SummaryRequest{Period: constants.SummaryPeriodDay})
+	assert.ErrorIs(t, err, assert.AnError)
+	_, err = s.GetSummary(context.Background(), 1, models.SummaryRequest{Period: constants.SummaryPeriodDay})
+	assert.ErrorIs(t, err, assert.AnError, "errors are not cached")
+}
+
+func Test_cache(t *testing.T) {
+	now := time.Now()
+	c := newCache(time.Minute, 2)
+
+	c.set("a", models.TransactionSummary{Period: "a"}, now)
+	c.set("b", models.TransactionSummary{Period: "b"}, now.Add(30*time.Second))
+
+	got, ok := c.get("a", now.Add(59*time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, "a", got.Period)
+
+	_, ok = c.get("a", now.Add(time.Minute))
+	assert.False(t, ok, "expired")
+
+	c.set("c", models.TransactionSummary{Period: "c"}, now.Add(time.Minute))
+	assert.Len(t, c.entries, 2, "the expired entry made room")
+	_, ok = c.get("b", now.Add(time.Minute))
+	assert.True(t, ok)
+
+	c.set("d", models.TransactionSummary{Period: "d"}, now.Add(time.Minute))
+	assert.Len(t, c.entries, 2, "never grows past its size")
+
+	disabled := newCache(0, 2)
+	disabled.set("a", models.TransactionSummary{}, now)
+	_, ok = disabled.get("a", now)
+	assert.False(t, ok)
+}
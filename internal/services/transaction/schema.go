@@ -0,0 +1,198 @@
+package transaction
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// defaultSchemas are the built-in additional_info schemas, one per transaction type.
+//
+//go:embed schemas/*.json
+var defaultSchemas embed.FS
+
+var unknownFieldPattern = regexp.MustCompile(`'([^']*)'`)
+
+// SchemaRegistry holds the json schemas additional_info is validated against. A schema is registered
+// for a transaction type and optionally a status, the status specific one wins when both exist.
+type SchemaRegistry struct {
+	mu      sync.RWMutex
+	schemas map[string]*jsonschema.Schema
+}
+
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{schemas: map[string]*jsonschema.Schema{}}
+}
+
+// LoadSchemas returns a registry with the built-in schemas plus the ones of dir, named TYPE.json
+// or TYPE.STATUS.json like PURCHASE.REVERSED.json. A file overrides the built-in schema of its name,
+// an empty dir only loads the built-in ones.
+func LoadSchemas(dir string) (*SchemaRegistry, error) {
+	r := NewSchemaRegistry()
+	if err := r.registerFS(defaultSchemas, "schemas"); err != nil {
+		return nil, err
+	}
+
+	if dir == "" {
+		return r, nil
+	}
+
+	if err := r.registerFS(os.DirFS(dir), "."); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *SchemaRegistry) registerFS(fsys fs.FS, dir string) error {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return errors.Wrap(err, "failed to list additional info schemas")
+	}
+
+	for _, file := range files {
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return errors.Wrap(err, "failed to read additional info schema")
+		}
+
+		name := strings.TrimSuffix(path.Base(file), ".json")
+		trxType, status, _ := strings.Cut(name, ".")
+		if err := r.Register(trxType, status, content); err != nil {
+			return errors.Wrapf(err, "invalid additional info schema %s", file)
+		}
+	}
+
+	return nil
+}
+
+// Register compiles schema and uses it for the additional_info of trxType, status may be empty
+// to cover every status without a schema of its own.
+func (r *SchemaRegistry) Register(trxType string, status string, schema []byte) error {
+	if !constants.MapTransactionType[trxType] {
+		return fmt.Errorf("unknown transaction type %q", trxType)
+	}
+	if status != "" && !constants.MapTransactionStatus[status] {
+		return fmt.Errorf("unknown transaction status %q", status)
+	}
+
+	url := "mem://additional-info/" + schemaKey(trxType, status) + ".json"
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(url, bytes.NewReader(schema)); err != nil {
+		return err
+	}
+	compiled, err := compiler.Compile(url)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schemas[schemaKey(trxType, status)] = compiled
+
+	return nil
+}
+
+// Validate checks that info is a json object matching the schema of the type and status,
+// empty info is validated as an empty object. Without any schema for the type every object passes.
+func (r *SchemaRegistry) Validate(trxType string, status string, info string) error {
+	var doc interface{} = map[string]interface{}{}
+	if info != "" {
+		if err := json.Unmarshal([]byte(info), &doc); err != nil {
+			doc = nil
+		}
+	}
+	if _, ok := doc.(map[string]interface{}); !ok {
+		return &models.AdditionalInfoError{Fields: []models.FieldError{
+			{Field: fieldName(""), Message: "must be a json object"},
+		}}
+	}
+
+	schema := r.lookup(trxType, status)
+	if schema == nil {
+		return nil
+	}
+
+	err := schema.Validate(doc)
+	var validationErr *jsonschema.ValidationError
+	if errors.As(err, &validationErr) {
+		fields := fieldErrors(validationErr)
+		sort.SliceStable(fields, func(i, j int) bool {
+			return fields[i].Field < fields[j].Field
+		})
+		return &models.AdditionalInfoError{Fields: fields}
+	}
+
+	return err
+}
+
+func (r *SchemaRegistry) lookup(trxType string, status string) *jsonschema.Schema {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if schema, ok := r.schemas[schemaKey(trxType, status)]; ok {
+		return schema
+	}
+
+	return r.schemas[schemaKey(trxType, "")]
+}
+
+func schemaKey(trxType string, status string) string {
+	if status == "" {
+		return trxType
+	}
+
+	return trxType + "." + status
+}
+
+// fieldErrors flattens the validation error to its leaves, one unknown property
+// becomes one error on that property rather than on the object holding it.
+func fieldErrors(err *jsonschema.ValidationError) []models.FieldError {
+	if len(err.Causes) > 0 {
+		var fields []models.FieldError
+		for _, cause := range err.Causes {
+			fields = append(fields, fieldErrors(cause)...)
+		}
+		return fields
+	}
+
+	field := fieldName(err.InstanceLocation)
+	if strings.HasSuffix(err.KeywordLocation, "/additionalProperties") {
+		var fields []models.FieldError
+		for _, match := range unknownFieldPattern.FindAllStringSubmatch(err.Message, -1) {
+			fields = append(fields, models.FieldError{Field: field + "." + match[1], Message: "unknown field"})
+		}
+		if len(fields) > 0 {
+			return fields
+		}
+	}
+
+	return []models.FieldError{{Field: field, Message: err.Message}}
+}
+
+// fieldName turns the json pointer of a value into a dotted path under additional_info.
+func fieldName(pointer string) string {
+	name := "additional_info"
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		name += "." + token
+	}
+
+	return name
+}
@@ -0,0 +1,120 @@
+package transaction
+
+import (
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaRegistry_Validate(t *testing.T) {
+	r, err := LoadSchemas("")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		trxType    string
+		status     string
+		info       string
+		wantFields []models.FieldError
+	}{
+		{
+			name:    "empty info",
+			trxType: constants.TransactionTypeTopup,
+			status:  constants.TransactionStatusPending,
+		},
+		{
+			name:    "valid purchase",
+			trxType: constants.TransactionTypePurchase,
+			status:  constants.TransactionStatusPending,
+			info:    `{"order_id":"ORDER-1","items":[{"name":"Kopi","quantity":2,"price":18000}]}`,
+		},
+		{
+			name:    "not an object",
+			trxType: constants.TransactionTypePurchase,
+			status:  constants.TransactionStatusPending,
+			info:    `["order"]`,
+			wantFields: []models.FieldError{
+				{Field: "additional_info", Message: "must be a json object"},
+			},
+		},
+		{
+			name:    "not json",
+			trxType: constants.TransactionTypeRefund,
+			status:  constants.TransactionStatusSuccess,
+			info:    `ADDINFO`,
+			wantFields: []models.FieldError{
+				{Field: "additional_info", Message: "must be a json object"},
+			},
+		},
+		{
+			name:    "unknown and invalid fields",
+			trxType: constants.TransactionTypePurchase,
+			status:  constants.TransactionStatusSuccess,
+			info:    `{"foo":1,"bar":2,"items":[{"name":"Kopi","quantity":0,"price":18000,"color":"red"}]}`,
+			wantFields: []models.FieldError{
+				{Field: "additional_info.bar", Message: "unknown field"},
+				{Field: "additional_info.foo", Message: "unknown field"},
+				{Field: "additional_info.items.0.color", Message: "unknown field"},
+				{Field: "additional_info.items.0.quantity", Message: "must be >= 1 but found 0"},
+			},
+		},
+		{
+			name:    "type without schema",
+			trxType: "CASHBACK",
+			status:  constants.TransactionStatusSuccess,
+			info:    `{"anything":true}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := r.Validate(tt.trxType, tt.status, tt.info)
+			if tt.wantFields == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var infoErr *models.AdditionalInfoError
+			if assert.ErrorAs(t, err, &infoErr) {
+				assert.ErrorIs(t, err, models.ErrInvalidAdditionalInfo)
+				assert.Equal(t, tt.wantFields, infoErr.Fields)
+			}
+		})
+	}
+}
+
+func TestLoadSchemas(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "PURCHASE.REVERSED.json"), []byte(`{
+		"type": "object",
+		"properties": {"reason": {"type": "string"}},
+		"required": ["reason"],
+		"additionalProperties": false
+	}`), 0o600))
+
+	r, err := LoadSchemas(dir)
+	assert.NoError(t, err)
+
+	assert.NoError(t, r.Validate(constants.TransactionTypePurchase, constants.TransactionStatusReversed, `{"reason":"salah order"}`))
+	assert.ErrorIs(t, r.Validate(constants.TransactionTypePurchase, constants.TransactionStatusReversed, `{"order_id":"ORDER-1"}`), models.ErrInvalidAdditionalInfo,
+		"the status schema wins over the type schema")
+	assert.NoError(t, r.Validate(constants.TransactionTypePurchase, constants.TransactionStatusSuccess, `{"order_id":"ORDER-1"}`),
+		"other statuses keep the type schema")
+
+	assert.NoError(t, r.Register(constants.TransactionTypeTopup, "", []byte(`{"type": "object"}`)))
+	assert.NoError(t, r.Validate(constants.TransactionTypeTopup, constants.TransactionStatusPending, `{"foo":1}`), "registered schemas replace the built-in one")
+
+	assert.Error(t, r.Register("CASHBACK", "", []byte(`{}`)))
+	assert.Error(t, r.Register(constants.TransactionTypeTopup, "DONE", []byte(`{}`)))
+	assert.Error(t, r.Register(constants.TransactionTypeTopup, "", []byte(`{"type": 1}`)))
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "ORDER.json"), []byte(`{}`), 0o600))
+	_, err = LoadSchemas(dir)
+	assert.Error(t, err)
+
+	_, err = LoadSchemas(filepath.Join(dir, "missing"))
+	assert.NoError(t, err, "a missing directory has no schema files")
+}
@@ -4,14 +4,21 @@ import (
 	"context"
 	"ewallet-transaction/external"
 	"ewallet-transaction/internal/models"
+	"time"
 )
 
 //go:generate mockgen -source=service.go -destination=service_mock_test.go -package=transaction
 type repository interface {
 	CreateTransaction(ctx context.Context, trx *models.Transaction) error
 	GetTransactionByReference(context.Context, string, bool) (models.Transaction, error)
-	UpdateStatusTransaction(ctx context.Context, reference string, status string, additionalInfo string) error
-	GetTransaction(ctx context.Context, userID uint64) ([]models.Transaction, error)
+	GetTransactionDetail(ctx context.Context, reference string) (models.Transaction, error)
+	UpdateStatusTransaction(ctx context.Context, reference string, currentStatus string, status string, additionalInfo string) error
+	LockUserLimits(ctx context.Context, userID uint64, fn func(ctx context.Context) error) error
+	GetTransaction(ctx context.Context, userID uint64, filter models.TransactionFilter) ([]models.Transaction, error)
+	ListCategories(ctx context.Context) ([]models.TransactionCategory, error)
+	GetCategory(ctx context.Context, code string) (models.TransactionCategory, error)
+	CreateStatusHistory(ctx context.Context, history *models.TransactionStatusHistory) error
+	GetPendingTransactions(ctx context.Context, before time.Time, limit int) ([]models.Transaction, error)
 }
 
 type IExternal interface {
@@ -21,14 +28,65 @@ type IExternal interface {
 	SendNotification(ctx context.Context, recipient string, templateName string, placeHolder map[string]string) error
 }
 
+type notifier interface {
+	Notify(ctx context.Context, event string, userID uint64, recipient string, placeHolder map[string]string) error
+}
+
+type publisher interface {
+	Publish(ctx context.Context, event models.TransactionEvent) error
+}
+
+type webhookDispatcher interface {
+	DispatchStatusChanged(ctx context.Context, trx models.Transaction, previousStatus string) error
+}
+
+type ledgerPoster interface {
+	PostSuccess(ctx context.Context, trx models.Transaction) error
+	PostReversal(ctx context.Context, trx models.Transaction) error
+	PostRefund(ctx context.Context, refund models.Transaction, originalReference string) error
+}
+
+type schemaValidator interface {
+	Validate(trxType string, status string, info string) error
+}
+
+type limiter interface {
+	Check(ctx context.Context, trx models.Transaction, status string) error
+}
+
+type screener interface {
+	Screen(ctx context.Context, trx models.Transaction) (models.FraudScreening, error)
+}
+
+type Config struct {
+	// MerchantUserIDs are the merchants a purchase may pay.
+	MerchantUserIDs map[uint64]bool
+}
+
 type service struct {
 	repository repository
 	external   IExternal
+	notifier   notifier
+	publisher  publisher
+	webhook    webhookDispatcher
+	ledger     ledgerPoster
+	schemas    schemaValidator
+	limits     limiter
+	fraud      screener
+	config     Config
 }
 
-func NewService(repository repository, external IExternal) *service {
+func NewService(repository repository, external IExternal, notifier notifier, publisher publisher, webhook webhookDispatcher, ledger ledgerPoster, schemas schemaValidator, limits limiter, fraud screener, config Config) *service {
 	return &service{
 		repository: repository,
 		external:   external,
+		notifier:   notifier,
+		publisher:  publisher,
+		webhook:    webhook,
+		ledger:     ledger,
+		schemas:    schemas,
+		limits:     limits,
+		fraud:      fraud,
+		config:     config,
 	}
 }
@@ -14,6 +14,7 @@ import (
 	external "ewallet-transaction/external"
 	models "ewallet-transaction/internal/models"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -42,6 +43,20 @@ func (m *Mockrepository) EXPECT() *MockrepositoryMockRecorder {
 	return m.recorder
 }
 
+// CreateStatusHistory mocks base method.
+func (m *Mockrepository) CreateStatusHistory(ctx context.Context, history *models.TransactionStatusHistory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStatusHistory", ctx, history)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateStatusHistory indicates an expected call of CreateStatusHistory.
+func (mr *MockrepositoryMockRecorder) CreateStatusHistory(ctx, history any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStatusHistory", reflect.TypeOf((*Mockrepository)(nil).CreateStatusHistory), ctx, history)
+}
+
 // CreateTransaction mocks base method.
 func (m *Mockrepository) CreateTransaction(ctx context.Context, trx *models.Transaction) error {
 	m.ctrl.T.Helper()
@@ -56,19 +71,49 @@ func (mr *MockrepositoryMockRecorder) CreateTransaction(ctx, trx any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransaction", reflect.TypeOf((*Mockrepository)(nil).CreateTransaction), ctx, trx)
 }
 
+// GetCategory mocks base method.
+func (m *Mockrepository) GetCategory(ctx context.Context, code string) (models.TransactionCategory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategory", ctx, code)
+	ret0, _ := ret[0].(models.TransactionCategory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategory indicates an expected call of GetCategory.
+func (mr *MockrepositoryMockRecorder) GetCategory(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategory", reflect.TypeOf((*Mockrepository)(nil).GetCategory), ctx, code)
+}
+
+// GetPendingTransactions mocks base method.
+func (m *Mockrepository) GetPendingTransactions(ctx context.Context, before time.Time, limit int) ([]models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingTransactions", ctx, before, limit)
+	ret0, _ := ret[0].([]models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingTransactions indicates an expected call of GetPendingTransactions.
+func (mr *MockrepositoryMockRecorder) GetPendingTransactions(ctx, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingTransactions", reflect.TypeOf((*Mockrepository)(nil).GetPendingTransactions), ctx, before, limit)
+}
+
 // GetTransaction mocks base method.
-func (m *Mockrepository) GetTransaction(ctx context.Context, userID uint64) ([]models.Transaction, error) {
+func (m *Mockrepository) GetTransaction(ctx context.Context, userID uint64, filter models.TransactionFilter) ([]models.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransaction", ctx, userID)
+	ret := m.ctrl.Call(m, "GetTransaction", ctx, userID, filter)
 	ret0, _ := ret[0].([]models.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTransaction indicates an expected call of GetTransaction.
-func (mr *MockrepositoryMockRecorder) GetTransaction(ctx, userID any) *gomock.Call {
+func (mr *MockrepositoryMockRecorder) GetTransaction(ctx, userID, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransaction", reflect.TypeOf((*Mockrepository)(nil).GetTransaction), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransaction", reflect.TypeOf((*Mockrepository)(nil).GetTransaction), ctx, userID, filter)
 }
 
 // GetTransactionByReference mocks base method.
@@ -86,18 +131,62 @@ func (mr *MockrepositoryMockRecorder) GetTransactionByReference(arg0, arg1, arg2
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionByReference", reflect.TypeOf((*Mockrepository)(nil).GetTransactionByReference), arg0, arg1, arg2)
 }
 
+// GetTransactionDetail mocks base method.
+func (m *Mockrepository) GetTransactionDetail(ctx context.Context, reference string) (models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionDetail", ctx, reference)
+	ret0, _ := ret[0].(models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionDetail indicates an expected call of GetTransactionDetail.
+func (mr *MockrepositoryMockRecorder) GetTransactionDetail(ctx, reference any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionDetail", reflect.TypeOf((*Mockrepository)(nil).GetTransactionDetail), ctx, reference)
+}
+
+// ListCategories mocks base method.
+func (m *Mockrepository) ListCategories(ctx context.Context) ([]models.TransactionCategory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCategories", ctx)
+	ret0, _ := ret[0].([]models.TransactionCategory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCategories indicates an expected call of ListCategories.
+func (mr *MockrepositoryMockRecorder) ListCategories(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCategories", reflect.TypeOf((*Mockrepository)(nil).ListCategories), ctx)
+}
+
+// LockUserLimits mocks base method.
+func (m *Mockrepository) LockUserLimits(ctx context.Context, userID uint64, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockUserLimits", ctx, userID, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockUserLimits indicates an expected call of LockUserLimits.
+func (mr *MockrepositoryMockRecorder) LockUserLimits(ctx, userID, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockUserLimits", reflect.TypeOf((*Mockrepository)(nil).LockUserLimits), ctx, userID, fn)
+}
+
 // UpdateStatusTransaction mocks base method.
-func (m *Mockrepository) UpdateStatusTransaction(ctx context.Context, reference, status, additionalInfo string) error {
+func (m *Mockrepository) UpdateStatusTransaction(ctx context.Context, reference, currentStatus, status, additionalInfo string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateStatusTransaction", ctx, reference, status, additionalInfo)
+	ret := m.ctrl.Call(m, "UpdateStatusTransaction", ctx, reference, currentStatus, status, additionalInfo)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateStatusTransaction indicates an expected call of UpdateStatusTransaction.
-func (mr *MockrepositoryMockRecorder) UpdateStatusTransaction(ctx, reference, status, additionalInfo any) *gomock.Call {
+func (mr *MockrepositoryMockRecorder) UpdateStatusTransaction(ctx, reference, currentStatus, status, additionalInfo any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatusTransaction", reflect.TypeOf((*Mockrepository)(nil).UpdateStatusTransaction), ctx, reference, status, additionalInfo)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatusTransaction", reflect.TypeOf((*Mockrepository)(nil).UpdateStatusTransaction), ctx, reference, currentStatus, status, additionalInfo)
 }
 
 // MockIExternal is a mock of IExternal interface.
@@ -182,3 +271,298 @@ func (mr *MockIExternalMockRecorder) ValidateToken(ctx, token any) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateToken", reflect.TypeOf((*MockIExternal)(nil).ValidateToken), ctx, token)
 }
+
+// Mocknotifier is a mock of notifier interface.
+type Mocknotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MocknotifierMockRecorder
+	isgomock struct{}
+}
+
+// MocknotifierMockRecorder is the mock recorder for Mocknotifier.
+type MocknotifierMockRecorder struct {
+	mock *Mocknotifier
+}
+
+// NewMocknotifier creates a new mock instance.
+func NewMocknotifier(ctrl *gomock.Controller) *Mocknotifier {
+	mock := &Mocknotifier{ctrl: ctrl}
+	mock.recorder = &MocknotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mocknotifier) EXPECT() *MocknotifierMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *Mocknotifier) Notify(ctx context.Context, event string, userID uint64, recipient string, placeHolder map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", ctx, event, userID, recipient, placeHolder)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MocknotifierMockRecorder) Notify(ctx, event, userID, recipient, placeHolder any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*Mocknotifier)(nil).Notify), ctx, event, userID, recipient, placeHolder)
+}
+
+// Mockpublisher is a mock of publisher interface.
+type Mockpublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockpublisherMockRecorder
+	isgomock struct{}
+}
+
+// MockpublisherMockRecorder is the mock recorder for Mockpublisher.
+type MockpublisherMockRecorder struct {
+	mock *Mockpublisher
+}
+
+// NewMockpublisher creates a new mock instance.
+func NewMockpublisher(ctrl *gomock.Controller) *Mockpublisher {
+	mock := &Mockpublisher{ctrl: ctrl}
+	mock.recorder = &MockpublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockpublisher) EXPECT() *MockpublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *Mockpublisher) Publish(ctx context.Context, event models.TransactionEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockpublisherMockRecorder) Publish(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*Mockpublisher)(nil).Publish), ctx, event)
+}
+
+// MockwebhookDispatcher is a mock of webhookDispatcher interface.
+type MockwebhookDispatcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockwebhookDispatcherMockRecorder
+	isgomock struct{}
+}
+
+// MockwebhookDispatcherMockRecorder is the mock recorder for MockwebhookDispatcher.
+type MockwebhookDispatcherMockRecorder struct {
+	mock *MockwebhookDispatcher
+}
+
+// NewMockwebhookDispatcher creates a new mock instance.
+func NewMockwebhookDispatcher(ctrl *gomock.Controller) *MockwebhookDispatcher {
+	mock := &MockwebhookDispatcher{ctrl: ctrl}
+	mock.recorder = &MockwebhookDispatcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockwebhookDispatcher) EXPECT() *MockwebhookDispatcherMockRecorder {
+	return m.recorder
+}
+
+// DispatchStatusChanged mocks base method.
+func (m *MockwebhookDispatcher) DispatchStatusChanged(ctx context.Context, trx models.Transaction, previousStatus string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DispatchStatusChanged", ctx, trx, previousStatus)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DispatchStatusChanged indicates an expected call of DispatchStatusChanged.
+func (mr *MockwebhookDispatcherMockRecorder) DispatchStatusChanged(ctx, trx, previousStatus any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DispatchStatusChanged", reflect.TypeOf((*MockwebhookDispatcher)(nil).DispatchStatusChanged), ctx, trx, previousStatus)
+}
+
+// MockledgerPoster is a mock of ledgerPoster interface.
+type MockledgerPoster struct {
+	ctrl     *gomock.Controller
+	recorder *MockledgerPosterMockRecorder
+	isgomock struct{}
+}
+
+// MockledgerPosterMockRecorder is the mock recorder for MockledgerPoster.
+type MockledgerPosterMockRecorder struct {
+	mock *MockledgerPoster
+}
+
+// NewMockledgerPoster creates a new mock instance.
+func NewMockledgerPoster(ctrl *gomock.Controller) *MockledgerPoster {
+	mock := &MockledgerPoster{ctrl: ctrl}
+	mock.recorder = &MockledgerPosterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockledgerPoster) EXPECT() *MockledgerPosterMockRecorder {
+	return m.recorder
+}
+
+// PostRefund mocks base method.
+func (m *MockledgerPoster) PostRefund(ctx context.Context, refund models.Transaction, originalReference string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostRefund", ctx, refund, originalReference)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostRefund indicates an expected call of PostRefund.
+func (mr *MockledgerPosterMockRecorder) PostRefund(ctx, refund, originalReference any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostRefund", reflect.TypeOf((*MockledgerPoster)(nil).PostRefund), ctx, refund, originalReference)
+}
+
+// PostReversal mocks base method.
+func (m *MockledgerPoster) PostReversal(ctx context.Context, trx models.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostReversal", ctx, trx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostReversal indicates an expected call of PostReversal.
+func (mr *MockledgerPosterMockRecorder) PostReversal(ctx, trx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostReversal", reflect.TypeOf((*MockledgerPoster)(nil).PostReversal), ctx, trx)
+}
+
+// PostSuccess mocks base method.
+func (m *MockledgerPoster) PostSuccess(ctx context.Context, trx models.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostSuccess", ctx, trx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostSuccess indicates an expected call of PostSuccess.
+func (mr *MockledgerPosterMockRecorder) PostSuccess(ctx, trx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostSuccess", reflect.TypeOf((*MockledgerPoster)(nil).PostSuccess), ctx, trx)
+}
+
+// MockschemaValidator is a mock of schemaValidator interface.
+type MockschemaValidator struct {
+	ctrl     *gomock.Controller
+	recorder *MockschemaValidatorMockRecorder
+	isgomock struct{}
+}
+
+// MockschemaValidatorMockRecorder is the mock recorder for MockschemaValidator.
+type MockschemaValidatorMockRecorder struct {
+	mock *MockschemaValidator
+}
+
+// NewMockschemaValidator creates a new mock instance.
+func NewMockschemaValidator(ctrl *gomock.Controller) *MockschemaValidator {
+	mock := &MockschemaValidator{ctrl: ctrl}
+	mock.recorder = &MockschemaValidatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockschemaValidator) EXPECT() *MockschemaValidatorMockRecorder {
+	return m.recorder
+}
+
+// Validate mocks base method.
+func (m *MockschemaValidator) Validate(trxType, status, info string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Validate", trxType, status, info)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Validate indicates an expected call of Validate.
+func (mr *MockschemaValidatorMockRecorder) Validate(trxType, status, info any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockschemaValidator)(nil).Validate), trxType, status, info)
+}
+
+// Mocklimiter is a mock of limiter interface.
+type Mocklimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MocklimiterMockRecorder
+	isgomock struct{}
+}
+
+// MocklimiterMockRecorder is the mock recorder for Mocklimiter.
+type MocklimiterMockRecorder struct {
+	mock *Mocklimiter
+}
+
+// NewMocklimiter creates a new mock instance.
+func NewMocklimiter(ctrl *gomock.Controller) *Mocklimiter {
+	mock := &Mocklimiter{ctrl: ctrl}
+	mock.recorder = &MocklimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mocklimiter) EXPECT() *MocklimiterMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *Mocklimiter) Check(ctx context.Context, trx models.Transaction, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, trx, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MocklimiterMockRecorder) Check(ctx, trx, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*Mocklimiter)(nil).Check), ctx, trx, status)
+}
+
+// Mockscreener is a mock of screener interface.
+type Mockscreener struct {
+	ctrl     *gomock.Controller
+	recorder *MockscreenerMockRecorder
+	isgomock struct{}
+}
+
+// MockscreenerMockRecorder is the mock recorder for Mockscreener.
+type MockscreenerMockRecorder struct {
+	mock *Mockscreener
+}
+
+// NewMockscreener creates a new mock instance.
+func NewMockscreener(ctrl *gomock.Controller) *Mockscreener {
+	mock := &Mockscreener{ctrl: ctrl}
+	mock.recorder = &MockscreenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockscreener) EXPECT() *MockscreenerMockRecorder {
+	return m.recorder
+}
+
+// Screen mocks base method.
+func (m *Mockscreener) Screen(ctx context.Context, trx models.Transaction) (models.FraudScreening, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Screen", ctx, trx)
+	ret0, _ := ret[0].(models.FraudScreening)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Screen indicates an expected call of Screen.
+func (mr *MockscreenerMockRecorder) Screen(ctx, trx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Screen", reflect.TypeOf((*Mockscreener)(nil).Screen), ctx, trx)
+}
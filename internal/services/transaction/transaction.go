@@ -8,9 +8,13 @@ import (
 	"ewallet-transaction/helpers"
 	"ewallet-transaction/internal/models"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
 func (s *service) CreateTransaction(ctx context.Context, req *models.Transaction) (models.CreateTransactionResponse, error) {
@@ -21,19 +25,60 @@ func (s *service) CreateTransaction(ctx context.Context, req *models.Transaction
 	req.TransactionStatus = constants.TransactionStatusPending
 	req.Reference = helpers.GenerateReference()
 
-	jsonAdditionalStatus := map[string]interface{}{}
-	if req.AdditionalInfo != "" {
-		err := json.Unmarshal([]byte(req.AdditionalInfo), &jsonAdditionalStatus)
+	err := s.schemas.Validate(req.TransactionType, req.TransactionStatus, req.AdditionalInfo)
+	if err != nil {
+		return resp, errors.Wrap(err, "failed to validate additional info")
+	}
+
+	// the merchant comes from the body, only a known merchant can be paid and notified
+	if req.MerchantID != 0 && !s.config.MerchantUserIDs[req.MerchantID] {
+		return resp, errors.Wrapf(models.ErrInvalidMerchant, "unknown merchant %d", req.MerchantID)
+	}
+
+	if req.Category != "" {
+		req.Category = strings.ToUpper(req.Category)
+		_, err := s.repository.GetCategory(ctx, req.Category)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return resp, errors.Wrapf(models.ErrInvalidCategory, "unknown category %q", req.Category)
+		}
 		if err != nil {
-			return resp, errors.Wrap(err, "failed to unmarshal current additional info")
+			return resp, errors.Wrap(err, "failed to get category")
 		}
 	}
 
-	err := s.repository.CreateTransaction(ctx, req)
+	// the usage is read and the transaction inserted under the limit lock of the user, a concurrent
+	// transaction is counted once this one is committed
+	err = s.repository.LockUserLimits(ctx, req.UserID, func(ctx context.Context) error {
+		err := s.limits.Check(ctx, *req, req.TransactionStatus)
+		if err != nil {
+			return errors.Wrap(err, "failed to check limits")
+		}
+
+		// the reference is unique per type, one clashing with an existing reference is generated again
+		for attempt := 1; ; attempt++ {
+			err = s.repository.CreateTransaction(ctx, req)
+			if !errors.Is(err, gorm.ErrDuplicatedKey) || attempt == constants.MaxReferenceAttempts {
+				break
+			}
+			req.Reference = helpers.GenerateReference()
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to create transaction")
+		}
+
+		return nil
+	})
 	if err != nil {
-		return resp, errors.Wrap(err, "failed to create transaction")
+		return resp, err
 	}
 
+	s.recordHistory(ctx, *req, "", actorUser(req.UserID), "")
+
+	s.publishEvent(ctx, models.TransactionEvent{
+		Type:        constants.EventTransactionCreated,
+		Transaction: *req,
+	})
+
 	resp.Reference = req.Reference
 	resp.TransactionStatus = req.TransactionStatus
 	return resp, nil
@@ -58,43 +103,41 @@ func (s *service) UpdateStatusTransaction(ctx context.Context, tokenData models.
 		return fmt.Errorf("transaction status flow invalid. request status = %s", req.TransactionStatus)
 	}
 
+	// a transaction parked by the screening only leaves ON_HOLD through an admin
+	if trx.TransactionStatus == constants.TransactionStatusOnHold && !req.Force {
+		return errors.Wrap(models.ErrTransactionOnHold, "transaction is waiting for a review")
+	}
+
+	err = s.schemas.Validate(trx.TransactionType, req.TransactionStatus, req.AdditionalInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to validate additional info")
+	}
+
+	// the debit of a purchase is screened, an admin forcing the status is the review
+	if trx.TransactionType == constants.TransactionTypePurchase && req.TransactionStatus == constants.TransactionStatusSuccess && !req.Force {
+		err = s.screen(ctx, tokenData, trx)
+		if err != nil {
+			return err
+		}
+	}
+
 	//request update balance to ewallet-wallet
 	reqUpdateBalance := external.UpdateBalance{
+		UserID:    trx.UserID,
 		Amount:    trx.Amount,
 		Reference: req.Reference,
 	}
 
 	if req.TransactionStatus == constants.TransactionStatusReversed {
-		reqUpdateBalance.Reference = "REVERSED-" + req.Reference
+		reqUpdateBalance.Reference = constants.WalletReferenceReversedPrefix + req.Reference
 
 		now := time.Now()
 		expiredReversalTime := trx.CreatedAt.Add(constants.MaximumReversalDuration)
-		if now.After(expiredReversalTime) {
+		if now.After(expiredReversalTime) && !req.Force {
 			return errors.New("reversal duration is already expired")
 		}
 	}
 
-	var errUpdateBalance error
-
-	switch trx.TransactionType {
-	case constants.TransactionTypeTopup:
-		if req.TransactionStatus == constants.TransactionStatusSuccess {
-			_, errUpdateBalance = s.external.CreditBalance(ctx, tokenData.Token, reqUpdateBalance)
-		} else if req.TransactionStatus == constants.TransactionStatusReversed {
-			_, errUpdateBalance = s.external.DebitBalance(ctx, tokenData.Token, reqUpdateBalance)
-		}
-	case constants.TransactionTypePurchase:
-		if req.TransactionStatus == constants.TransactionStatusSuccess {
-			_, errUpdateBalance = s.external.DebitBalance(ctx, tokenData.Token, reqUpdateBalance)
-		} else if req.TransactionStatus == constants.TransactionStatusReversed {
-			_, errUpdateBalance = s.external.CreditBalance(ctx, tokenData.Token, reqUpdateBalance)
-		}
-	}
-
-	if errUpdateBalance != nil {
-		return errors.Wrap(errUpdateBalance, "failed to update balance")
-	}
-
 	// Update additional info
 	var (
 		newAdditionalInfo     = map[string]interface{}{}
@@ -124,24 +167,219 @@ func (s *service) UpdateStatusTransaction(ctx context.Context, tokenData models.
 		return errors.Wrap(err, "failed to marshal merged additional info")
 	}
 
-	// Update status in DB
-	err = s.repository.UpdateStatusTransaction(ctx, req.Reference, req.TransactionStatus, string(byteAdditionalInfo))
+	// the status is claimed before the money moves, of two concurrent updates only the first
+	// finds the status it read and the other one fails with ErrStatusConflict
+	claim := func(ctx context.Context) error {
+		err := s.repository.UpdateStatusTransaction(ctx, req.Reference, trx.TransactionStatus, req.TransactionStatus, string(byteAdditionalInfo))
+		if err != nil {
+			return errors.Wrap(err, "failed to update status transaction")
+		}
+
+		return nil
+	}
+
+	// the limits may have been lowered or used up by other transactions since this one was created,
+	// a SUCCESS is claimed under the limit lock of the user like a new transaction is inserted
+	if req.TransactionStatus == constants.TransactionStatusSuccess {
+		err = s.repository.LockUserLimits(ctx, trx.UserID, func(ctx context.Context) error {
+			err := s.limits.Check(ctx, trx, req.TransactionStatus)
+			if err != nil {
+				return errors.Wrap(err, "failed to check limits")
+			}
+
+			return claim(ctx)
+		})
+	} else {
+		err = claim(ctx)
+	}
 	if err != nil {
-		return errors.Wrap(err, "failed to update status transaction")
+		return err
+	}
+
+	var errUpdateBalance error
+
+	switch trx.TransactionType {
+	case constants.TransactionTypeTopup:
+		if req.TransactionStatus == constants.TransactionStatusSuccess {
+			_, errUpdateBalance = s.external.CreditBalance(ctx, tokenData.Token, reqUpdateBalance)
+		} else if req.TransactionStatus == constants.TransactionStatusReversed {
+			_, errUpdateBalance = s.external.DebitBalance(ctx, tokenData.Token, reqUpdateBalance)
+		}
+	case constants.TransactionTypePurchase:
+		if req.TransactionStatus == constants.TransactionStatusSuccess {
+			_, errUpdateBalance = s.external.DebitBalance(ctx, tokenData.Token, reqUpdateBalance)
+		} else if req.TransactionStatus == constants.TransactionStatusReversed {
+			_, errUpdateBalance = s.external.CreditBalance(ctx, tokenData.Token, reqUpdateBalance)
+		}
 	}
 
+	// the wallet may have moved the money, what follows is written even if the client goes away,
+	// the repository still bounds each write with its timeout
+	ctx = context.WithoutCancel(ctx)
+
+	if errUpdateBalance != nil {
+		s.releaseStatus(ctx, trx, req.TransactionStatus)
+		return errors.Wrap(errUpdateBalance, "failed to update balance")
+	}
+
+	previousStatus := trx.TransactionStatus
 	trx.TransactionStatus = req.TransactionStatus
-	s.sendNotification(ctx, tokenData, trx)
+	trx.AdditionalInfo = string(byteAdditionalInfo)
+
+	actor := req.Actor
+	if actor == "" {
+		actor = actorUser(tokenData.UserID)
+	}
+	s.statusChanged(ctx, tokenData, trx, previousStatus, actor, req.Reason)
 
 	return nil
 }
 
+// SyncStatus moves trx to the status matching money the wallet already moved, the wallet is not called.
+// The status is claimed like in UpdateStatusTransaction and the change goes through the same history,
+// ledger, event, webhook and notification, under actor.
+func (s *service) SyncStatus(ctx context.Context, trx models.Transaction, status string, actor string, reason string) error {
+	err := s.repository.UpdateStatusTransaction(ctx, trx.Reference, trx.TransactionStatus, status, trx.AdditionalInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to update status transaction")
+	}
+
+	// the status is saved, what follows is written even if the caller goes away
+	ctx = context.WithoutCancel(ctx)
+
+	previousStatus := trx.TransactionStatus
+	trx.TransactionStatus = status
+	s.statusChanged(ctx, models.TokenData{}, trx, previousStatus, actor, reason)
+
+	return nil
+}
+
+// statusChanged runs what follows a saved status change, each step logs its own failure.
+func (s *service) statusChanged(ctx context.Context, tokenData models.TokenData, trx models.Transaction, previousStatus string, actor string, reason string) {
+	s.recordHistory(ctx, trx, previousStatus, actor, reason)
+
+	s.postLedger(ctx, trx)
+
+	s.publishEvent(ctx, models.TransactionEvent{
+		Type:           constants.EventTransactionStatusChanged,
+		PreviousStatus: previousStatus,
+		Transaction:    trx,
+	})
+	s.dispatchWebhook(ctx, trx, previousStatus)
+	s.sendNotification(ctx, tokenData, trx, actor)
+}
+
+// releaseStatus gives back the status claimed by an update the wallet refused. It only applies while the
+// claimed status is still current, a failure is logged and left to the reconciliation.
+func (s *service) releaseStatus(ctx context.Context, trx models.Transaction, claimedStatus string) {
+	err := s.repository.UpdateStatusTransaction(ctx, trx.Reference, claimedStatus, trx.TransactionStatus, trx.AdditionalInfo)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"reference": trx.Reference,
+			"status":    claimedStatus,
+		}).Error("failed to release the status of the transaction: ", err)
+	}
+}
+
+// screen runs the fraud rules before the debit. REVIEW parks the transaction ON_HOLD and DENY fails it,
+// both return an error so the wallet is not debited.
+func (s *service) screen(ctx context.Context, tokenData models.TokenData, trx models.Transaction) error {
+	screening, err := s.fraud.Screen(ctx, trx)
+	if err != nil {
+		return errors.Wrap(err, "failed to screen transaction")
+	}
+
+	var (
+		status  string
+		outcome error
+	)
+	switch screening.Decision {
+	case constants.FraudDecisionReview:
+		status, outcome = constants.TransactionStatusOnHold, models.ErrTransactionOnHold
+	case constants.FraudDecisionDeny:
+		status, outcome = constants.TransactionStatusFailed, models.ErrTransactionDenied
+	default:
+		return nil
+	}
+
+	err = s.repository.UpdateStatusTransaction(ctx, trx.Reference, trx.TransactionStatus, status, trx.AdditionalInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to update status transaction")
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"reference": trx.Reference,
+		"user_id":   trx.UserID,
+		"decision":  screening.Decision,
+	}).Warn("transaction flagged by the fraud screening: ", screening.Reason())
+
+	previousStatus := trx.TransactionStatus
+	trx.TransactionStatus = status
+	s.recordHistory(ctx, trx, previousStatus, constants.StatusActorFraud, screening.Reason())
+
+	s.publishEvent(ctx, models.TransactionEvent{
+		Type:           constants.EventTransactionStatusChanged,
+		PreviousStatus: previousStatus,
+		Transaction:    trx,
+	})
+	s.dispatchWebhook(ctx, trx, previousStatus)
+	s.sendNotification(ctx, tokenData, trx, constants.StatusActorFraud)
+
+	return errors.Wrap(outcome, screening.Reason())
+}
+
 func (s *service) GetTransactionDetail(ctx context.Context, reference string) (models.Transaction, error) {
-	return s.repository.GetTransactionByReference(ctx, reference, true)
+	return s.repository.GetTransactionDetail(ctx, reference)
+}
+
+func (s *service) GetTransaction(ctx context.Context, userID uint64, filter models.TransactionFilter) ([]models.Transaction, error) {
+	return s.repository.GetTransaction(ctx, userID, filter)
 }
 
-func (s *service) GetTransaction(ctx context.Context, userID uint64) ([]models.Transaction, error) {
-	return s.repository.GetTransaction(ctx, userID)
+func (s *service) ListCategories(ctx context.Context) ([]models.TransactionCategory, error) {
+	return s.repository.ListCategories(ctx)
+}
+
+// ExpirePending fails at most limit transactions left PENDING since before, oldest first.
+// FAILED moves no money so no user token is needed. A transaction that can't be expired,
+// e.g. because it was settled meanwhile, is reported and the others are still expired.
+func (s *service) ExpirePending(ctx context.Context, before time.Time, limit int) (models.ExpirePendingReport, error) {
+	report := models.ExpirePendingReport{
+		Before:  before,
+		Expired: []string{},
+		Failed:  []models.ExpirePendingFailure{},
+	}
+
+	trxs, err := s.repository.GetPendingTransactions(ctx, before, limit)
+	if err != nil {
+		return report, errors.Wrap(err, "failed to get pending transactions")
+	}
+
+	for _, trx := range trxs {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		report.Checked++
+		err := s.UpdateStatusTransaction(ctx, models.TokenData{UserID: trx.UserID}, &models.UpdateStatusTransaction{
+			Reference:         trx.Reference,
+			TransactionStatus: constants.TransactionStatusFailed,
+			Actor:             constants.StatusActorExpiry,
+			Reason:            "pending since " + trx.CreatedAt.Format(time.RFC3339),
+		})
+		if err != nil {
+			logrus.WithField("reference", trx.Reference).Error("failed to expire pending transaction: ", err)
+			report.Failed = append(report.Failed, models.ExpirePendingFailure{
+				Reference: trx.Reference,
+				Error:     err.Error(),
+			})
+			continue
+		}
+
+		report.Expired = append(report.Expired, trx.Reference)
+	}
+
+	return report, nil
 }
 
 func (s *service) RefundTransaction(ctx context.Context, tokenData models.TokenData, req *models.RefundTransaction) (models.CreateTransactionResponse, error) {
@@ -163,8 +401,14 @@ func (s *service) RefundTransaction(ctx context.Context, tokenData models.TokenD
 		return resp, errors.New("current transaction status is not purchase type")
 	}
 
+	err = s.schemas.Validate(constants.TransactionTypeRefund, constants.TransactionStatusSuccess, req.AdditionalInfo)
+	if err != nil {
+		return resp, errors.Wrap(err, "failed to validate additional info")
+	}
+
 	refundReference := "REFUND-" + req.Reference
 	reqCreditBalance := external.UpdateBalance{
+		UserID:    trx.UserID,
 		Reference: refundReference,
 		Amount:    trx.Amount,
 	}
@@ -174,6 +418,9 @@ func (s *service) RefundTransaction(ctx context.Context, tokenData models.TokenD
 		return resp, errors.Wrap(err, "failed to credit balance")
 	}
 
+	// the wallet was credited, the refund is recorded even if the client goes away
+	ctx = context.WithoutCancel(ctx)
+
 	transaction := models.Transaction{
 		UserID:            tokenData.UserID,
 		Amount:            trx.Amount,
@@ -182,6 +429,9 @@ func (s *service) RefundTransaction(ctx context.Context, tokenData models.TokenD
 		Reference:         refundReference,
 		Description:       req.Description,
 		AdditionalInfo:    req.AdditionalInfo,
+		MerchantID:        trx.MerchantID,
+		MerchantName:      trx.MerchantName,
+		Category:          trx.Category,
 	}
 
 	err = s.repository.CreateTransaction(ctx, &transaction)
@@ -189,21 +439,126 @@ func (s *service) RefundTransaction(ctx context.Context, tokenData models.TokenD
 		return resp, errors.Wrap(err, "failed to insert new transaction refund")
 	}
 
+	actor := req.Actor
+	if actor == "" {
+		actor = actorUser(tokenData.UserID)
+	}
+	s.recordHistory(ctx, transaction, "", actor, req.Reason)
+
+	err = s.ledger.PostRefund(ctx, transaction, req.Reference)
+	if err != nil {
+		logrus.WithField("reference", transaction.Reference).Error("failed to post refund to ledger: ", err)
+	}
+
+	s.publishEvent(ctx, models.TransactionEvent{
+		Type:              constants.EventTransactionRefunded,
+		OriginalReference: req.Reference,
+		Transaction:       transaction,
+	})
+	s.notify(ctx, constants.NotificationEventRefundIssued, tokenData, transaction.UserID, transaction)
+
 	resp.Reference = refundReference
 	resp.TransactionStatus = transaction.TransactionStatus
 
 	return resp, nil
 }
 
-func (s *service) sendNotification(ctx context.Context, tokenData models.TokenData, trx models.Transaction) {
-	if trx.TransactionType == constants.TransactionTypePurchase && trx.TransactionStatus == constants.TransactionStatusSuccess {
+// sendNotification tells the owner about the new status of the transaction, an expired one has its own event
+// whatever its type. The merchant of a purchase is told about the payment it received.
+func (s *service) sendNotification(ctx context.Context, tokenData models.TokenData, trx models.Transaction, actor string) {
+	event, ok := constants.MapNotificationEvent[trx.TransactionType][trx.TransactionStatus]
+	if actor == constants.StatusActorExpiry {
+		event, ok = constants.NotificationEventPendingExpired, true
+	}
+	if ok {
+		s.notify(ctx, event, tokenData, trx.UserID, trx)
+	}
 
-		s.external.SendNotification(ctx, tokenData.Email, "purchase_success", map[string]string{
-			"full_name":   tokenData.Fullname,
-			"description": trx.Description,
-			"reference":   trx.Reference,
-			"date":        trx.CreatedAt.Format("2006-01-02 15:04:05"),
-		})
+	// the merchant_id of older rows wasn't checked, only a configured merchant is told about the payment
+	if trx.TransactionType == constants.TransactionTypePurchase && trx.TransactionStatus == constants.TransactionStatusSuccess && s.config.MerchantUserIDs[trx.MerchantID] {
+		s.notify(ctx, constants.NotificationEventTransferReceived, tokenData, trx.MerchantID, trx)
+	}
+}
+
+// notify enqueues the notification of an event for the user userID. The token only has the email when
+// the user acts on their own transaction, for the others (the expiry, the settlement, the admins acting
+// on behalf of the owner, the merchant) the worker looks up the contact of the user.
+func (s *service) notify(ctx context.Context, event string, tokenData models.TokenData, userID uint64, trx models.Transaction) {
+	var recipient, fullName string
+	if tokenData.UserID == userID {
+		recipient, fullName = tokenData.Email, tokenData.Fullname
+	}
+
+	err := s.notifier.Notify(ctx, event, userID, recipient, map[string]string{
+		"full_name":   fullName,
+		"description": trx.Description,
+		"reference":   trx.Reference,
+		"amount":      strconv.FormatFloat(trx.Amount, 'f', 2, 64),
+		"date":        trx.CreatedAt.Format("2006-01-02 15:04:05"),
+	})
+	if err != nil {
+		logrus.WithField("reference", trx.Reference).Warn("failed to enqueue notification: ", err)
+	}
+}
 
+func (s *service) dispatchWebhook(ctx context.Context, trx models.Transaction, previousStatus string) {
+	if trx.TransactionType != constants.TransactionTypePurchase || trx.MerchantID == 0 {
+		return
+	}
+
+	err := s.webhook.DispatchStatusChanged(ctx, trx, previousStatus)
+	if err != nil {
+		logrus.WithField("reference", trx.Reference).Error("failed to dispatch merchant webhook: ", err)
+	}
+}
+
+// postLedger records the balance movement of a status change, FAILED moves no money.
+// A failed posting is logged rather than failing the request because the wallet was already updated,
+// posting is idempotent so it can be replayed from the transaction.
+func (s *service) postLedger(ctx context.Context, trx models.Transaction) {
+	var err error
+	switch trx.TransactionStatus {
+	case constants.TransactionStatusSuccess:
+		err = s.ledger.PostSuccess(ctx, trx)
+	case constants.TransactionStatusReversed:
+		err = s.ledger.PostReversal(ctx, trx)
+	}
+
+	if err != nil {
+		logrus.WithField("reference", trx.Reference).Error("failed to post ledger entry: ", err)
+	}
+}
+
+// recordHistory appends the new status of the transaction to its history, a failure is logged
+// like the ledger posting because the status itself is already saved.
+func (s *service) recordHistory(ctx context.Context, trx models.Transaction, from string, actor string, reason string) {
+	err := s.repository.CreateStatusHistory(ctx, &models.TransactionStatusHistory{
+		TransactionID: trx.ID,
+		Reference:     trx.Reference,
+		FromStatus:    from,
+		ToStatus:      trx.TransactionStatus,
+		Actor:         actor,
+		Reason:        reason,
+	})
+	if err != nil {
+		logrus.WithField("reference", trx.Reference).Error("failed to record status history: ", err)
+	}
+}
+
+func actorUser(userID uint64) string {
+	return constants.StatusActorUserPrefix + strconv.FormatUint(userID, 10)
+}
+
+func (s *service) publishEvent(ctx context.Context, event models.TransactionEvent) {
+	event.ID = helpers.GenerateEventID()
+	event.Version = constants.MapEventVersion[event.Type]
+	event.OccurredAt = time.Now()
+
+	err := s.publisher.Publish(ctx, event)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"event":     event.Type,
+			"reference": event.Transaction.Reference,
+		}).Error("failed to publish event: ", err)
 	}
 }
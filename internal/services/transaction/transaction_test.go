@@ -12,6 +12,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
 )
 
 func Test_service_CreateTransaction(t *testing.T) {
@@ -19,7 +20,16 @@ func Test_service_CreateTransaction(t *testing.T) {
 	defer ctrlMock.Finish()
 
 	mockRepo := NewMockrepository(ctrlMock)
+	expectLimitLock(mockRepo)
 	mockExt := NewMockIExternal(ctrlMock)
+	mockNotifier := NewMocknotifier(ctrlMock)
+	mockPublisher := NewMockpublisher(ctrlMock)
+	mockWebhook := NewMockwebhookDispatcher(ctrlMock)
+	mockLedger := NewMockledgerPoster(ctrlMock)
+	mockSchemas := NewMockschemaValidator(ctrlMock)
+	mockSchemas.EXPECT().Validate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockLimits := NewMocklimiter(ctrlMock)
+	mockLimits.EXPECT().Check(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 
 	type args struct {
 		ctx context.Context
@@ -47,6 +57,14 @@ func Test_service_CreateTransaction(t *testing.T) {
 			wantErr: false,
 			mockfn: func(args args) {
 				mockRepo.EXPECT().CreateTransaction(gomock.Any(), args.req).Return(nil)
+				mockRepo.EXPECT().CreateStatusHistory(gomock.Any(), gomock.Any()).Return(nil)
+
+				mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, event models.TransactionEvent) {
+					assert.Equal(t, constants.EventTransactionCreated, event.Type)
+					assert.Equal(t, 1, event.Version)
+					assert.NotEmpty(t, event.ID)
+					assert.Equal(t, *args.req, event.Transaction)
+				}).Return(nil)
 			},
 		},
 		{
@@ -67,6 +85,124 @@ func Test_service_CreateTransaction(t *testing.T) {
 				mockRepo.EXPECT().CreateTransaction(gomock.Any(), args.req).Return(assert.AnError)
 			},
 		},
+		{
+			name: "success after a duplicate reference",
+			args: args{
+				ctx: context.Background(),
+				req: &models.Transaction{
+					UserID:          1,
+					Amount:          100000,
+					TransactionType: constants.TransactionTypeTopup,
+					Description:     "DESCRIPTION",
+				},
+			},
+			wantErr: false,
+			mockfn: func(args args) {
+				var first string
+				gomock.InOrder(
+					mockRepo.EXPECT().CreateTransaction(gomock.Any(), args.req).Do(func(ctx context.Context, trx *models.Transaction) {
+						first = trx.Reference
+					}).Return(gorm.ErrDuplicatedKey),
+					mockRepo.EXPECT().CreateTransaction(gomock.Any(), args.req).Do(func(ctx context.Context, trx *models.Transaction) {
+						assert.NotEqual(t, first, trx.Reference)
+					}).Return(nil),
+				)
+				mockRepo.EXPECT().CreateStatusHistory(gomock.Any(), gomock.Any()).Return(nil)
+				mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "error duplicate reference on every attempt",
+			args: args{
+				ctx: context.Background(),
+				req: &models.Transaction{
+					UserID:          1,
+					Amount:          100000,
+					TransactionType: constants.TransactionTypeTopup,
+					Description:     "DESCRIPTION",
+				},
+			},
+			wantErr: true,
+			mockfn: func(args args) {
+				mockRepo.EXPECT().CreateTransaction(gomock.Any(), args.req).Return(gorm.ErrDuplicatedKey).Times(constants.MaxReferenceAttempts)
+			},
+		},
+		{
+			name: "success with category",
+			args: args{
+				ctx: context.Background(),
+				req: &models.Transaction{
+					UserID:          1,
+					Amount:          100000,
+					TransactionType: constants.TransactionTypePurchase,
+					Description:     "DESCRIPTION",
+					MerchantID:      9,
+					MerchantName:    "MERCHANT",
+					Category:        "food_and_drink",
+					Tags:            []string{"trip"},
+				},
+			},
+			wantErr: false,
+			mockfn: func(args args) {
+				mockRepo.EXPECT().GetCategory(gomock.Any(), "FOOD_AND_DRINK").Return(models.TransactionCategory{Code: "FOOD_AND_DRINK"}, nil)
+				mockRepo.EXPECT().CreateTransaction(gomock.Any(), args.req).Do(func(ctx context.Context, trx *models.Transaction) {
+					assert.Equal(t, "FOOD_AND_DRINK", trx.Category)
+					assert.Equal(t, []string{"trip"}, trx.Tags)
+				}).Return(nil)
+				mockRepo.EXPECT().CreateStatusHistory(gomock.Any(), gomock.Any()).Return(nil)
+				mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "error unknown merchant",
+			args: args{
+				ctx: context.Background(),
+				req: &models.Transaction{
+					UserID:          1,
+					Amount:          100000,
+					TransactionType: constants.TransactionTypePurchase,
+					Description:     "DESCRIPTION",
+					MerchantID:      10,
+					MerchantName:    "MERCHANT",
+				},
+			},
+			wantErr: true,
+			mockfn:  func(args args) {},
+		},
+		{
+			name: "error unknown category",
+			args: args{
+				ctx: context.Background(),
+				req: &models.Transaction{
+					UserID:          1,
+					Amount:          100000,
+					TransactionType: constants.TransactionTypePurchase,
+					Description:     "DESCRIPTION",
+					Category:        "UNKNOWN",
+				},
+			},
+			wantErr: true,
+			mockfn: func(args args) {
+				mockRepo.EXPECT().GetCategory(gomock.Any(), "UNKNOWN").Return(models.TransactionCategory{}, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name: "error get category",
+			args: args{
+				ctx: context.Background(),
+				req: &models.Transaction{
+					UserID:          1,
+					Amount:          100000,
+					TransactionType: constants.TransactionTypePurchase,
+					Description:     "DESCRIPTION",
+					Category:        "BILLS",
+				},
+			},
+			wantErr: true,
+			mockfn: func(args args) {
+				mockRepo.EXPECT().GetCategory(gomock.Any(), "BILLS").Return(models.TransactionCategory{}, assert.AnError)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -74,6 +210,13 @@ func Test_service_CreateTransaction(t *testing.T) {
 			s := &service{
 				repository: mockRepo,
 				external:   mockExt,
+				notifier:   mockNotifier,
+				publisher:  mockPublisher,
+				webhook:    mockWebhook,
+				ledger:     mockLedger,
+				schemas:    mockSchemas,
+				limits:     mockLimits,
+				config:     Config{MerchantUserIDs: map[uint64]bool{9: true}},
 			}
 			got, err := s.CreateTransaction(tt.args.ctx, tt.args.req)
 			if (err != nil) != tt.wantErr {
@@ -94,7 +237,18 @@ func Test_service_UpdateStatusTransaction(t *testing.T) {
 	defer ctrlMock.Finish()
 
 	mockRepo := NewMockrepository(ctrlMock)
+	expectLimitLock(mockRepo)
 	mockExt := NewMockIExternal(ctrlMock)
+	mockNotifier := NewMocknotifier(ctrlMock)
+	mockPublisher := NewMockpublisher(ctrlMock)
+	mockWebhook := NewMockwebhookDispatcher(ctrlMock)
+	mockLedger := NewMockledgerPoster(ctrlMock)
+	mockSchemas := NewMockschemaValidator(ctrlMock)
+	mockSchemas.EXPECT().Validate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockLimits := NewMocklimiter(ctrlMock)
+	mockLimits.EXPECT().Check(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFraud := NewMockscreener(ctrlMock)
+	mockFraud.EXPECT().Screen(gomock.Any(), gomock.Any()).Return(models.FraudScreening{Decision: constants.FraudDecisionAllow}, nil).AnyTimes()
 
 	now := time.Now()
 
@@ -142,6 +296,7 @@ func Test_service_UpdateStatusTransaction(t *testing.T) {
 				}, nil)
 
 				mockExt.EXPECT().CreditBalance(gomock.Any(), args.tokenData.Token, external.UpdateBalance{
+					UserID:    1,
 					Reference: args.req.Reference,
 					Amount:    100000,
 				}).Return(&external.UpdateBalanceResponse{
@@ -149,7 +304,21 @@ func Test_service_UpdateStatusTransaction(t *testing.T) {
 					Amount:  100000,
 				}, nil)
 
-				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, args.req.TransactionStatus, args.req.AdditionalInfo).Return(nil)
+				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, "PENDING", args.req.TransactionStatus, args.req.AdditionalInfo).Return(nil)
+
+				mockRepo.EXPECT().CreateStatusHistory(gomock.Any(), &models.TransactionStatusHistory{
+					TransactionID: 1,
+					Reference:     "REFERENCE",
+					FromStatus:    constants.TransactionStatusPending,
+					ToStatus:      constants.TransactionStatusSuccess,
+					Actor:         "user:1",
+				}).Return(nil)
+
+				mockLedger.EXPECT().PostSuccess(gomock.Any(), gomock.Any()).Return(nil)
+
+				mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+
+				mockNotifier.EXPECT().Notify(gomock.Any(), constants.NotificationEventTopupSuccess, args.tokenData.UserID, args.tokenData.Email, gomock.Any()).Return(nil)
 
 			},
 		},
@@ -186,6 +355,61 @@ func Test_service_UpdateStatusTransaction(t *testing.T) {
 				}, nil)
 
 				mockExt.EXPECT().DebitBalance(gomock.Any(), args.tokenData.Token, external.UpdateBalance{
+					UserID:    1,
+					Reference: args.req.Reference,
+					Amount:    100000,
+				}).Return(&external.UpdateBalanceResponse{
+					Message: constants.SuccessMessage,
+					Amount:  100000,
+				}, nil)
+
+				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, "PENDING", args.req.TransactionStatus, args.req.AdditionalInfo).Return(nil)
+
+				mockRepo.EXPECT().CreateStatusHistory(gomock.Any(), gomock.Any()).Return(nil)
+
+				mockLedger.EXPECT().PostSuccess(gomock.Any(), gomock.Any()).Return(nil)
+
+				mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+
+				mockNotifier.EXPECT().Notify(gomock.Any(), constants.NotificationEventPurchaseSuccess, args.tokenData.UserID, args.tokenData.Email, gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "success update status from pending to success for merchant purchase",
+			args: args{
+				ctx: context.Background(),
+				tokenData: models.TokenData{
+					UserID:   1,
+					Username: "USERNAME",
+					Fullname: "FULLNAME",
+					Token:    "TOKENDATA",
+					Email:    "email@gmail.com",
+				},
+				req: &models.UpdateStatusTransaction{
+					Reference:         "REFERENCE",
+					TransactionStatus: "SUCCESS",
+					AdditionalInfo:    "{\"purchase\":\"testing purchase\"}",
+				},
+			},
+			wantErr: false,
+			mockFn: func(args args) {
+				trx := models.Transaction{
+					ID:                1,
+					UserID:            1,
+					Amount:            100000,
+					TransactionType:   constants.TransactionTypePurchase,
+					TransactionStatus: "PENDING",
+					Reference:         "REFERENCE",
+					Description:       "DESCRIPTION",
+					AdditionalInfo:    "{\"purchase\":\"testing purchase\"}",
+					MerchantID:        99,
+					CreatedAt:         now,
+					UpdatedAt:         now,
+				}
+				mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), args.req.Reference, false).Return(trx, nil)
+
+				mockExt.EXPECT().DebitBalance(gomock.Any(), args.tokenData.Token, external.UpdateBalance{
+					UserID:    1,
 					Reference: args.req.Reference,
 					Amount:    100000,
 				}).Return(&external.UpdateBalanceResponse{
@@ -193,9 +417,19 @@ func Test_service_UpdateStatusTransaction(t *testing.T) {
 					Amount:  100000,
 				}, nil)
 
-				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, args.req.TransactionStatus, args.req.AdditionalInfo).Return(nil)
+				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, "PENDING", args.req.TransactionStatus, args.req.AdditionalInfo).Return(nil)
+
+				mockRepo.EXPECT().CreateStatusHistory(gomock.Any(), gomock.Any()).Return(nil)
+
+				mockLedger.EXPECT().PostSuccess(gomock.Any(), gomock.Any()).Return(assert.AnError)
 
-				mockExt.EXPECT().SendNotification(gomock.Any(), args.tokenData.Email, gomock.Any(), gomock.Any())
+				mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+
+				trx.TransactionStatus = constants.TransactionStatusSuccess
+				mockWebhook.EXPECT().DispatchStatusChanged(gomock.Any(), trx, constants.TransactionStatusPending).Return(assert.AnError)
+
+				mockNotifier.EXPECT().Notify(gomock.Any(), constants.NotificationEventPurchaseSuccess, args.tokenData.UserID, args.tokenData.Email, gomock.Any()).Return(nil)
+				mockNotifier.EXPECT().Notify(gomock.Any(), constants.NotificationEventTransferReceived, uint64(99), "", gomock.Any()).Return(nil)
 			},
 		},
 		{
@@ -230,7 +464,13 @@ func Test_service_UpdateStatusTransaction(t *testing.T) {
 					UpdatedAt:         now,
 				}, nil)
 
-				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, args.req.TransactionStatus, args.req.AdditionalInfo).Return(nil)
+				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, "PENDING", args.req.TransactionStatus, args.req.AdditionalInfo).Return(nil)
+
+				mockRepo.EXPECT().CreateStatusHistory(gomock.Any(), gomock.Any()).Return(nil)
+
+				mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+
+				mockNotifier.EXPECT().Notify(gomock.Any(), constants.NotificationEventTopupFailed, args.tokenData.UserID, args.tokenData.Email, gomock.Any()).Return(nil)
 			},
 		},
 		{
@@ -265,7 +505,11 @@ func Test_service_UpdateStatusTransaction(t *testing.T) {
 					UpdatedAt:         now,
 				}, nil)
 
-				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, args.req.TransactionStatus, args.req.AdditionalInfo).Return(nil)
+				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, "PENDING", args.req.TransactionStatus, args.req.AdditionalInfo).Return(nil)
+
+				mockRepo.EXPECT().CreateStatusHistory(gomock.Any(), gomock.Any()).Return(nil)
+
+				mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
 			},
 		},
 		{
@@ -301,6 +545,7 @@ func Test_service_UpdateStatusTransaction(t *testing.T) {
 				}, nil)
 
 				mockExt.EXPECT().DebitBalance(gomock.Any(), args.tokenData.Token, external.UpdateBalance{
+					UserID:    1,
 					Reference: fmt.Sprintf("REVERSED-%s", args.req.Reference),
 					Amount:    100000,
 				}).Return(&external.UpdateBalanceResponse{
@@ -308,7 +553,15 @@ func Test_service_UpdateStatusTransaction(t *testing.T) {
 					Amount:  100000,
 				}, nil)
 
-				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, args.req.TransactionStatus, args.req.AdditionalInfo).Return(nil)
+				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, "SUCCESS", args.req.TransactionStatus, args.req.AdditionalInfo).Return(nil)
+
+				mockRepo.EXPECT().CreateStatusHistory(gomock.Any(), gomock.Any()).Return(nil)
+
+				mockLedger.EXPECT().PostReversal(gomock.Any(), gomock.Any()).Return(nil)
+
+				mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+
+				mockNotifier.EXPECT().Notify(gomock.Any(), constants.NotificationEventReversal, args.tokenData.UserID, args.tokenData.Email, gomock.Any()).Return(nil)
 
 			},
 		},
@@ -345,6 +598,7 @@ func Test_service_UpdateStatusTransaction(t *testing.T) {
 				}, nil)
 
 				mockExt.EXPECT().CreditBalance(gomock.Any(), args.tokenData.Token, external.UpdateBalance{
+					UserID:    1,
 					Reference: fmt.Sprintf("REVERSED-%s", args.req.Reference),
 					Amount:    100000,
 				}).Return(&external.UpdateBalanceResponse{
@@ -352,7 +606,15 @@ func Test_service_UpdateStatusTransaction(t *testing.T) {
 					Amount:  100000,
 				}, nil)
 
-				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, args.req.TransactionStatus, args.req.AdditionalInfo).Return(nil)
+				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, "SUCCESS", args.req.TransactionStatus, args.req.AdditionalInfo).Return(nil)
+
+				mockRepo.EXPECT().CreateStatusHistory(gomock.Any(), gomock.Any()).Return(nil)
+
+				mockLedger.EXPECT().PostReversal(gomock.Any(), gomock.Any()).Return(nil)
+
+				mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+
+				mockNotifier.EXPECT().Notify(gomock.Any(), constants.NotificationEventReversal, args.tokenData.UserID, args.tokenData.Email, gomock.Any()).Return(nil)
 
 			},
 		},
@@ -389,6 +651,7 @@ func Test_service_UpdateStatusTransaction(t *testing.T) {
 				}, nil)
 
 				mockExt.EXPECT().CreditBalance(gomock.Any(), args.tokenData.Token, external.UpdateBalance{
+					UserID:    1,
 					Reference: args.req.Reference,
 					Amount:    100000,
 				}).Return(&external.UpdateBalanceResponse{
@@ -396,7 +659,15 @@ func Test_service_UpdateStatusTransaction(t *testing.T) {
 					Amount:  100000,
 				}, nil)
 
-				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, args.req.TransactionStatus, args.req.AdditionalInfo).Return(nil)
+				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, "FAILED", args.req.TransactionStatus, args.req.AdditionalInfo).Return(nil)
+
+				mockRepo.EXPECT().CreateStatusHistory(gomock.Any(), gomock.Any()).Return(nil)
+
+				mockLedger.EXPECT().PostSuccess(gomock.Any(), gomock.Any()).Return(nil)
+
+				mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+
+				mockNotifier.EXPECT().Notify(gomock.Any(), constants.NotificationEventTopupSuccess, args.tokenData.UserID, args.tokenData.Email, gomock.Any()).Return(nil)
 
 			},
 		},
@@ -631,6 +902,63 @@ func Test_service_UpdateStatusTransaction(t *testing.T) {
 				}, nil)
 			},
 		},
+		{
+			name: "success forced reversal after the time limit by an admin",
+			args: args{
+				ctx: context.Background(),
+				tokenData: models.TokenData{
+					UserID: 1,
+					Token:  "SERVICE_TOKEN",
+				},
+				req: &models.UpdateStatusTransaction{
+					Reference:         "REFERENCE",
+					TransactionStatus: "REVERSED",
+					Actor:             "admin:7",
+					Reason:            "chargeback",
+					Force:             true,
+				},
+			},
+			wantErr: false,
+			mockFn: func(args args) {
+				mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), args.req.Reference, false).Return(models.Transaction{
+					ID:                1,
+					UserID:            1,
+					Amount:            100000,
+					TransactionType:   constants.TransactionTypeTopup,
+					TransactionStatus: "SUCCESS",
+					Reference:         "REFERENCE",
+					Description:       "DESCRIPTION",
+					CreatedAt:         now.Add(time.Hour * -36),
+					UpdatedAt:         now,
+				}, nil)
+
+				mockExt.EXPECT().DebitBalance(gomock.Any(), "SERVICE_TOKEN", external.UpdateBalance{
+					UserID:    1,
+					Reference: fmt.Sprintf("REVERSED-%s", args.req.Reference),
+					Amount:    100000,
+				}).Return(&external.UpdateBalanceResponse{
+					Message: constants.SuccessMessage,
+					Amount:  100000,
+				}, nil)
+
+				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, "SUCCESS", args.req.TransactionStatus, "{}").Return(nil)
+
+				mockRepo.EXPECT().CreateStatusHistory(gomock.Any(), &models.TransactionStatusHistory{
+					TransactionID: 1,
+					Reference:     "REFERENCE",
+					FromStatus:    constants.TransactionStatusSuccess,
+					ToStatus:      constants.TransactionStatusReversed,
+					Actor:         "admin:7",
+					Reason:        "chargeback",
+				}).Return(nil)
+
+				mockLedger.EXPECT().PostReversal(gomock.Any(), gomock.Any()).Return(nil)
+
+				mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+
+				mockNotifier.EXPECT().Notify(gomock.Any(), constants.NotificationEventReversal, uint64(1), "", gomock.Any()).Return(assert.AnError)
+			},
+		},
 		{
 			name: "error credit balance for topup",
 			args: args{
@@ -663,13 +991,18 @@ func Test_service_UpdateStatusTransaction(t *testing.T) {
 					UpdatedAt:         now,
 				}, nil)
 
+				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, "PENDING", args.req.TransactionStatus, args.req.AdditionalInfo).Return(nil)
+
 				mockExt.EXPECT().CreditBalance(gomock.Any(), args.tokenData.Token, external.UpdateBalance{
+					UserID:    1,
 					Reference: args.req.Reference,
 					Amount:    100000,
 				}).Return(&external.UpdateBalanceResponse{
 					Message: constants.SuccessMessage,
 					Amount:  100000,
 				}, assert.AnError)
+
+				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, args.req.TransactionStatus, "PENDING", args.req.AdditionalInfo).Return(nil)
 			},
 		},
 		{
@@ -704,17 +1037,22 @@ func Test_service_UpdateStatusTransaction(t *testing.T) {
 					UpdatedAt:         now,
 				}, nil)
 
+				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, "SUCCESS", args.req.TransactionStatus, args.req.AdditionalInfo).Return(nil)
+
 				mockExt.EXPECT().DebitBalance(gomock.Any(), args.tokenData.Token, external.UpdateBalance{
+					UserID:    1,
 					Reference: fmt.Sprintf("REVERSED-%s", args.req.Reference),
 					Amount:    100000,
 				}).Return(&external.UpdateBalanceResponse{
 					Message: constants.SuccessMessage,
 					Amount:  100000,
 				}, assert.AnError)
+
+				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, args.req.TransactionStatus, "SUCCESS", args.req.AdditionalInfo).Return(nil)
 			},
 		},
 		{
-			name: "error debit balance for purchase",
+			name: "error update status for purchase",
 			args: args{
 				ctx: context.Background(),
 				tokenData: models.TokenData{
@@ -745,15 +1083,7 @@ func Test_service_UpdateStatusTransaction(t *testing.T) {
 					UpdatedAt:         now,
 				}, nil)
 
-				mockExt.EXPECT().DebitBalance(gomock.Any(), args.tokenData.Token, external.UpdateBalance{
-					Reference: args.req.Reference,
-					Amount:    100000,
-				}).Return(&external.UpdateBalanceResponse{
-					Message: constants.SuccessMessage,
-					Amount:  100000,
-				}, nil)
-
-				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, args.req.TransactionStatus, args.req.AdditionalInfo).Return(assert.AnError)
+				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, "PENDING", args.req.TransactionStatus, args.req.AdditionalInfo).Return(assert.AnError)
 			},
 		},
 		{
@@ -788,13 +1118,18 @@ func Test_service_UpdateStatusTransaction(t *testing.T) {
 					UpdatedAt:         now,
 				}, nil)
 
+				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, "SUCCESS", args.req.TransactionStatus, args.req.AdditionalInfo).Return(nil)
+
 				mockExt.EXPECT().CreditBalance(gomock.Any(), args.tokenData.Token, external.UpdateBalance{
+					UserID:    1,
 					Reference: fmt.Sprintf("REVERSED-%s", args.req.Reference),
 					Amount:    100000,
 				}).Return(&external.UpdateBalanceResponse{
 					Message: constants.SuccessMessage,
 					Amount:  100000,
 				}, assert.AnError)
+
+				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, args.req.TransactionStatus, "SUCCESS", args.req.AdditionalInfo).Return(nil)
 			},
 		},
 		{
@@ -829,16 +1164,38 @@ func Test_service_UpdateStatusTransaction(t *testing.T) {
 					UpdatedAt:         now,
 				}, nil)
 
-				mockExt.EXPECT().CreditBalance(gomock.Any(), args.tokenData.Token, external.UpdateBalance{
-					Reference: args.req.Reference,
-					Amount:    100000,
-				}).Return(&external.UpdateBalanceResponse{
-					Message: constants.SuccessMessage,
-					Amount:  100000,
-				}, nil)
+				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, "PENDING", args.req.TransactionStatus, args.req.AdditionalInfo).Return(assert.AnError)
 
-				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, args.req.TransactionStatus, args.req.AdditionalInfo).Return(assert.AnError)
+			},
+		},
+		{
+			name: "error status changed by a concurrent update",
+			args: args{
+				ctx: context.Background(),
+				tokenData: models.TokenData{
+					UserID: 1,
+					Token:  "TOKENDATA",
+				},
+				req: &models.UpdateStatusTransaction{
+					Reference:         "REFERENCE",
+					TransactionStatus: "SUCCESS",
+				},
+			},
+			wantErr: true,
+			mockFn: func(args args) {
+				mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), args.req.Reference, false).Return(models.Transaction{
+					ID:                1,
+					UserID:            1,
+					Amount:            100000,
+					TransactionType:   constants.TransactionTypeTopup,
+					TransactionStatus: "PENDING",
+					Reference:         "REFERENCE",
+					CreatedAt:         now,
+					UpdatedAt:         now,
+				}, nil)
 
+				// the other update already moved the money, this one must not credit again
+				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, "PENDING", args.req.TransactionStatus, "{}").Return(models.ErrStatusConflict)
 			},
 		},
 		{
@@ -874,6 +1231,7 @@ func Test_service_UpdateStatusTransaction(t *testing.T) {
 				}, nil)
 
 				mockExt.EXPECT().DebitBalance(gomock.Any(), args.tokenData.Token, external.UpdateBalance{
+					UserID:    1,
 					Reference: args.req.Reference,
 					Amount:    100000,
 				}).Return(&external.UpdateBalanceResponse{
@@ -881,9 +1239,15 @@ func Test_service_UpdateStatusTransaction(t *testing.T) {
 					Amount:  100000,
 				}, nil)
 
-				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, args.req.TransactionStatus, args.req.AdditionalInfo).Return(nil)
+				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), args.req.Reference, "PENDING", args.req.TransactionStatus, args.req.AdditionalInfo).Return(nil)
+
+				mockRepo.EXPECT().CreateStatusHistory(gomock.Any(), gomock.Any()).Return(nil)
 
-				mockExt.EXPECT().SendNotification(gomock.Any(), args.tokenData.Email, gomock.Any(), gomock.Any()).Return(assert.AnError)
+				mockLedger.EXPECT().PostSuccess(gomock.Any(), gomock.Any()).Return(nil)
+
+				mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+
+				mockNotifier.EXPECT().Notify(gomock.Any(), constants.NotificationEventPurchaseSuccess, args.tokenData.UserID, args.tokenData.Email, gomock.Any()).Return(assert.AnError)
 			},
 		},
 	}
@@ -893,6 +1257,14 @@ func Test_service_UpdateStatusTransaction(t *testing.T) {
 			s := &service{
 				repository: mockRepo,
 				external:   mockExt,
+				notifier:   mockNotifier,
+				publisher:  mockPublisher,
+				webhook:    mockWebhook,
+				ledger:     mockLedger,
+				schemas:    mockSchemas,
+				limits:     mockLimits,
+				fraud:      mockFraud,
+				config:     Config{MerchantUserIDs: map[uint64]bool{99: true}},
 			}
 			if err := s.UpdateStatusTransaction(tt.args.ctx, tt.args.tokenData, tt.args.req); (err != nil) != tt.wantErr {
 				t.Errorf("service.UpdateStatusTransaction() error = %v, wantErr %v", err, tt.wantErr)
@@ -901,12 +1273,458 @@ func Test_service_UpdateStatusTransaction(t *testing.T) {
 	}
 }
 
+func Test_service_WritesAfterWalletOutliveCancel(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+	expectLimitLock(mockRepo)
+	mockExt := NewMockIExternal(ctrlMock)
+	mockNotifier := NewMocknotifier(ctrlMock)
+	mockPublisher := NewMockpublisher(ctrlMock)
+	mockLedger := NewMockledgerPoster(ctrlMock)
+	mockSchemas := NewMockschemaValidator(ctrlMock)
+	mockLimits := NewMocklimiter(ctrlMock)
+	mockSchemas.EXPECT().Validate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockLimits.EXPECT().Check(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockLedger.EXPECT().PostSuccess(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockLedger.EXPECT().PostRefund(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockNotifier.EXPECT().Notify(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	s := &service{
+		repository: mockRepo,
+		external:   mockExt,
+		notifier:   mockNotifier,
+		publisher:  mockPublisher,
+		ledger:     mockLedger,
+		schemas:    mockSchemas,
+		limits:     mockLimits,
+	}
+
+	tokenData := models.TokenData{UserID: 1, Token: "TOKEN", Email: "email@gmail.com"}
+	notCanceled := func(ctx context.Context) {
+		assert.NoError(t, ctx.Err(), "the write runs after the client went away")
+	}
+
+	// the client goes away right after the wallet credited the topup
+	ctx, cancel := context.WithCancel(context.Background())
+	topup := models.Transaction{
+		ID:                1,
+		UserID:            1,
+		Amount:            100000,
+		TransactionType:   constants.TransactionTypeTopup,
+		TransactionStatus: constants.TransactionStatusPending,
+		Reference:         "REFERENCE",
+	}
+	mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "REFERENCE", false).Return(topup, nil)
+	mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), "REFERENCE", constants.TransactionStatusPending, constants.TransactionStatusSuccess, "{}").Return(nil)
+	mockExt.EXPECT().CreditBalance(gomock.Any(), "TOKEN", gomock.Any()).DoAndReturn(func(ctx context.Context, token string, req external.UpdateBalance) (*external.UpdateBalanceResponse, error) {
+		cancel()
+		return &external.UpdateBalanceResponse{Message: constants.SuccessMessage}, nil
+	})
+	mockRepo.EXPECT().CreateStatusHistory(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, history *models.TransactionStatusHistory) {
+		notCanceled(ctx)
+	}).Return(nil)
+	err := s.UpdateStatusTransaction(ctx, tokenData, &models.UpdateStatusTransaction{
+		Reference:         "REFERENCE",
+		TransactionStatus: constants.TransactionStatusSuccess,
+	})
+	assert.NoError(t, err)
+
+	// the wallet call is cut short by the client, the claimed status is still given back
+	ctx, cancel = context.WithCancel(context.Background())
+	mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "REFERENCE", false).Return(topup, nil)
+	mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), "REFERENCE", constants.TransactionStatusPending, constants.TransactionStatusSuccess, "{}").Return(nil)
+	mockExt.EXPECT().CreditBalance(gomock.Any(), "TOKEN", gomock.Any()).DoAndReturn(func(ctx context.Context, token string, req external.UpdateBalance) (*external.UpdateBalanceResponse, error) {
+		cancel()
+		return nil, context.Canceled
+	})
+	mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), "REFERENCE", constants.TransactionStatusSuccess, constants.TransactionStatusPending, "").Do(func(ctx context.Context, reference string, currentStatus string, status string, info string) {
+		notCanceled(ctx)
+	}).Return(nil)
+	err = s.UpdateStatusTransaction(ctx, tokenData, &models.UpdateStatusTransaction{
+		Reference:         "REFERENCE",
+		TransactionStatus: constants.TransactionStatusSuccess,
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// and right after the wallet credited the refund
+	ctx, cancel = context.WithCancel(context.Background())
+	purchase := models.Transaction{
+		ID:                2,
+		UserID:            1,
+		Amount:            50000,
+		TransactionType:   constants.TransactionTypePurchase,
+		TransactionStatus: constants.TransactionStatusSuccess,
+		Reference:         "PURCHASE",
+	}
+	mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "PURCHASE", false).Return(purchase, nil)
+	mockExt.EXPECT().CreditBalance(gomock.Any(), "TOKEN", gomock.Any()).DoAndReturn(func(ctx context.Context, token string, req external.UpdateBalance) (*external.UpdateBalanceResponse, error) {
+		cancel()
+		return &external.UpdateBalanceResponse{Message: constants.SuccessMessage}, nil
+	})
+	mockRepo.EXPECT().CreateTransaction(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, trx *models.Transaction) {
+		notCanceled(ctx)
+	}).Return(nil)
+	mockRepo.EXPECT().CreateStatusHistory(gomock.Any(), gomock.Any()).Return(nil)
+	resp, err := s.RefundTransaction(ctx, tokenData, &models.RefundTransaction{
+		Reference:   "PURCHASE",
+		Description: "DESCRIPTION",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "REFUND-PURCHASE", resp.Reference)
+}
+
+func Test_service_SyncStatus(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	now := time.Now()
+	mockRepo := NewMockrepository(ctrlMock)
+	mockExt := NewMockIExternal(ctrlMock)
+	mockNotifier := NewMocknotifier(ctrlMock)
+	mockPublisher := NewMockpublisher(ctrlMock)
+	mockWebhook := NewMockwebhookDispatcher(ctrlMock)
+	mockLedger := NewMockledgerPoster(ctrlMock)
+	s := &service{
+		repository: mockRepo,
+		external:   mockExt,
+		notifier:   mockNotifier,
+		publisher:  mockPublisher,
+		webhook:    mockWebhook,
+		ledger:     mockLedger,
+		config:     Config{MerchantUserIDs: map[uint64]bool{9: true}},
+	}
+
+	purchase := models.Transaction{
+		ID:                1,
+		UserID:            3,
+		Amount:            100000,
+		TransactionType:   constants.TransactionTypePurchase,
+		TransactionStatus: constants.TransactionStatusPending,
+		Reference:         "REFERENCE",
+		MerchantID:        9,
+		CreatedAt:         now,
+	}
+	synced := purchase
+	synced.TransactionStatus = constants.TransactionStatusSuccess
+
+	// the wallet already debited the purchase, only the side effects of the change follow
+	mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), "REFERENCE", constants.TransactionStatusPending, constants.TransactionStatusSuccess, "").Return(nil)
+	mockRepo.EXPECT().CreateStatusHistory(gomock.Any(), &models.TransactionStatusHistory{
+		TransactionID: 1,
+		Reference:     "REFERENCE",
+		FromStatus:    constants.TransactionStatusPending,
+		ToStatus:      constants.TransactionStatusSuccess,
+		Actor:         constants.StatusActorReconciliation,
+		Reason:        "REASON",
+	}).Return(nil)
+	mockLedger.EXPECT().PostSuccess(gomock.Any(), synced).Return(nil)
+	mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, event models.TransactionEvent) error {
+		assert.Equal(t, constants.EventTransactionStatusChanged, event.Type)
+		assert.Equal(t, constants.TransactionStatusPending, event.PreviousStatus)
+		assert.Equal(t, synced, event.Transaction)
+		return nil
+	})
+	mockWebhook.EXPECT().DispatchStatusChanged(gomock.Any(), synced, constants.TransactionStatusPending).Return(nil)
+	mockNotifier.EXPECT().Notify(gomock.Any(), constants.NotificationEventPurchaseSuccess, uint64(3), "", gomock.Any()).Return(nil)
+	mockNotifier.EXPECT().Notify(gomock.Any(), constants.NotificationEventTransferReceived, uint64(9), "", gomock.Any()).Return(nil)
+	err := s.SyncStatus(context.Background(), purchase, constants.TransactionStatusSuccess, constants.StatusActorReconciliation, "REASON")
+	assert.NoError(t, err)
+
+	// another update changed the status first, nothing follows
+	mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), "REFERENCE", constants.TransactionStatusPending, constants.TransactionStatusSuccess, "").Return(models.ErrStatusConflict)
+	err = s.SyncStatus(context.Background(), purchase, constants.TransactionStatusSuccess, constants.StatusActorReconciliation, "REASON")
+	assert.ErrorIs(t, err, models.ErrStatusConflict)
+}
+
+type limitLockKey struct{}
+
+// expectLimitLock runs the functions given to LockUserLimits in place, their context carries the locked user.
+func expectLimitLock(mockRepo *Mockrepository) {
+	mockRepo.EXPECT().LockUserLimits(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID uint64, fn func(ctx context.Context) error) error {
+		return fn(context.WithValue(ctx, limitLockKey{}, userID))
+	}).AnyTimes()
+}
+
+func Test_service_LimitExceeded(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+	mockExt := NewMockIExternal(ctrlMock)
+	mockSchemas := NewMockschemaValidator(ctrlMock)
+	mockSchemas.EXPECT().Validate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockLimits := NewMocklimiter(ctrlMock)
+	s := &service{
+		repository: mockRepo,
+		external:   mockExt,
+		schemas:    mockSchemas,
+		limits:     mockLimits,
+	}
+
+	// the limits are checked under the lock of the user, it is released on the rejection
+	mockRepo.EXPECT().LockUserLimits(gomock.Any(), uint64(1), gomock.Any()).DoAndReturn(func(ctx context.Context, userID uint64, fn func(ctx context.Context) error) error {
+		return fn(context.WithValue(ctx, limitLockKey{}, userID))
+	}).Times(2)
+	locked := func(ctx context.Context) {
+		assert.Equal(t, uint64(1), ctx.Value(limitLockKey{}), "the limits are checked outside the lock")
+	}
+
+	exceeded := &models.LimitExceededError{Code: constants.LimitExceededCode, Limit: constants.LimitDailyCount}
+
+	// a new transaction is rejected before it is created
+	req := &models.Transaction{UserID: 1, Amount: 100000, TransactionType: constants.TransactionTypeTopup}
+	mockLimits.EXPECT().Check(gomock.Any(), gomock.Any(), constants.TransactionStatusPending).Do(func(ctx context.Context, trx models.Transaction, status string) {
+		locked(ctx)
+		assert.Equal(t, uint64(1), trx.UserID)
+		assert.Equal(t, float64(100000), trx.Amount)
+	}).Return(exceeded)
+	got, err := s.CreateTransaction(context.Background(), req)
+	assert.ErrorIs(t, err, models.ErrLimitExceeded)
+	assert.Empty(t, got)
+
+	// a pending transaction is rejected before the wallet is updated
+	trx := models.Transaction{
+		ID:                1,
+		UserID:            1,
+		Amount:            100000,
+		TransactionType:   constants.TransactionTypeTopup,
+		TransactionStatus: constants.TransactionStatusPending,
+		Reference:         "REFERENCE",
+		CreatedAt:         time.Now(),
+	}
+	mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "REFERENCE", false).Return(trx, nil)
+	mockLimits.EXPECT().Check(gomock.Any(), trx, constants.TransactionStatusSuccess).Do(func(ctx context.Context, trx models.Transaction, status string) {
+		locked(ctx)
+	}).Return(exceeded)
+	err = s.UpdateStatusTransaction(context.Background(), models.TokenData{UserID: 1, Token: "TOKEN"}, &models.UpdateStatusTransaction{
+		Reference:         "REFERENCE",
+		TransactionStatus: constants.TransactionStatusSuccess,
+	})
+	var limitErr *models.LimitExceededError
+	assert.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, constants.LimitDailyCount, limitErr.Limit)
+
+	// other transitions aren't limited
+	mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "REFERENCE", false).Return(trx, nil)
+	mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), "REFERENCE", constants.TransactionStatusPending, constants.TransactionStatusFailed, "{}").Return(assert.AnError)
+	err = s.UpdateStatusTransaction(context.Background(), models.TokenData{UserID: 1, Token: "TOKEN"}, &models.UpdateStatusTransaction{
+		Reference:         "REFERENCE",
+		TransactionStatus: constants.TransactionStatusFailed,
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func Test_service_FraudScreening(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+	expectLimitLock(mockRepo)
+	mockExt := NewMockIExternal(ctrlMock)
+	mockNotifier := NewMocknotifier(ctrlMock)
+	mockPublisher := NewMockpublisher(ctrlMock)
+	mockWebhook := NewMockwebhookDispatcher(ctrlMock)
+	mockLedger := NewMockledgerPoster(ctrlMock)
+	mockSchemas := NewMockschemaValidator(ctrlMock)
+	mockSchemas.EXPECT().Validate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockLimits := NewMocklimiter(ctrlMock)
+	mockLimits.EXPECT().Check(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFraud := NewMockscreener(ctrlMock)
+
+	tokenData := models.TokenData{UserID: 3, Token: "TOKEN", Email: "email@gmail.com"}
+	purchase := models.Transaction{
+		ID:                1,
+		UserID:            3,
+		Amount:            100000,
+		TransactionType:   constants.TransactionTypePurchase,
+		TransactionStatus: constants.TransactionStatusPending,
+		Reference:         "REFERENCE",
+		AdditionalInfo:    `{"note":"coffee"}`,
+		MerchantID:        9,
+		DeviceID:          "DEVICE",
+		CreatedAt:         time.Now(),
+	}
+	onHold := purchase
+	onHold.TransactionStatus = constants.TransactionStatusOnHold
+
+	review := models.FraudScreening{Decision: constants.FraudDecisionReview, Hits: []models.FraudRuleHit{
+		{Rule: constants.FraudRuleNewDevice, Decision: constants.FraudDecisionReview, Detail: "device DEVICE is new to the user"},
+	}}
+	deny := models.FraudScreening{Decision: constants.FraudDecisionDeny, Hits: []models.FraudRuleHit{
+		{Rule: constants.FraudRuleVelocity, Decision: constants.FraudDecisionDeny, Detail: "10 transactions in the last 10m0s"},
+	}}
+
+	tests := []struct {
+		name      string
+		tokenData models.TokenData
+		req       *models.UpdateStatusTransaction
+		wantErr   error
+		mockFn    func()
+	}{
+		{
+			name:    "review parks the purchase on hold without debit",
+			req:     &models.UpdateStatusTransaction{Reference: "REFERENCE", TransactionStatus: constants.TransactionStatusSuccess},
+			wantErr: models.ErrTransactionOnHold,
+			mockFn: func() {
+				mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "REFERENCE", false).Return(purchase, nil)
+				mockFraud.EXPECT().Screen(gomock.Any(), purchase).Return(review, nil)
+				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), "REFERENCE", constants.TransactionStatusPending, constants.TransactionStatusOnHold, `{"note":"coffee"}`).Return(nil)
+				mockRepo.EXPECT().CreateStatusHistory(gomock.Any(), &models.TransactionStatusHistory{
+					TransactionID: 1,
+					Reference:     "REFERENCE",
+					FromStatus:    constants.TransactionStatusPending,
+					ToStatus:      constants.TransactionStatusOnHold,
+					Actor:         constants.StatusActorFraud,
+					Reason:        "NEW_DEVICE: device DEVICE is new to the user",
+				}).Return(nil)
+				mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, event models.TransactionEvent) {
+					assert.Equal(t, constants.TransactionStatusOnHold, event.Transaction.TransactionStatus)
+				}).Return(nil)
+				mockWebhook.EXPECT().DispatchStatusChanged(gomock.Any(), gomock.Any(), constants.TransactionStatusPending).Return(nil)
+			},
+		},
+		{
+			name:    "deny fails the purchase without debit",
+			req:     &models.UpdateStatusTransaction{Reference: "REFERENCE", TransactionStatus: constants.TransactionStatusSuccess},
+			wantErr: models.ErrTransactionDenied,
+			mockFn: func() {
+				mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "REFERENCE", false).Return(purchase, nil)
+				mockFraud.EXPECT().Screen(gomock.Any(), purchase).Return(deny, nil)
+				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), "REFERENCE", constants.TransactionStatusPending, constants.TransactionStatusFailed, `{"note":"coffee"}`).Return(nil)
+				mockRepo.EXPECT().CreateStatusHistory(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, history *models.TransactionStatusHistory) {
+					assert.Equal(t, constants.TransactionStatusFailed, history.ToStatus)
+					assert.Equal(t, constants.StatusActorFraud, history.Actor)
+				}).Return(nil)
+				mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+				mockWebhook.EXPECT().DispatchStatusChanged(gomock.Any(), gomock.Any(), constants.TransactionStatusPending).Return(nil)
+			},
+		},
+		{
+			name:    "error screening fails closed",
+			req:     &models.UpdateStatusTransaction{Reference: "REFERENCE", TransactionStatus: constants.TransactionStatusSuccess},
+			wantErr: assert.AnError,
+			mockFn: func() {
+				mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "REFERENCE", false).Return(purchase, nil)
+				mockFraud.EXPECT().Screen(gomock.Any(), purchase).Return(models.FraudScreening{}, assert.AnError)
+			},
+		},
+		{
+			name:    "error the user can't move a transaction on hold",
+			req:     &models.UpdateStatusTransaction{Reference: "REFERENCE", TransactionStatus: constants.TransactionStatusSuccess},
+			wantErr: models.ErrTransactionOnHold,
+			mockFn: func() {
+				mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "REFERENCE", false).Return(onHold, nil)
+			},
+		},
+		{
+			name: "success approved by an admin without screening",
+			req: &models.UpdateStatusTransaction{
+				Reference:         "REFERENCE",
+				TransactionStatus: constants.TransactionStatusSuccess,
+				Actor:             "admin:7",
+				Reason:            "customer confirmed",
+				Force:             true,
+			},
+			mockFn: func() {
+				mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "REFERENCE", false).Return(onHold, nil)
+				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), "REFERENCE", constants.TransactionStatusOnHold, constants.TransactionStatusSuccess, `{"note":"coffee"}`).Return(nil)
+				mockExt.EXPECT().DebitBalance(gomock.Any(), "TOKEN", external.UpdateBalance{UserID: 3, Reference: "REFERENCE", Amount: 100000}).
+					Return(&external.UpdateBalanceResponse{Amount: 100000}, nil)
+				mockRepo.EXPECT().CreateStatusHistory(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, history *models.TransactionStatusHistory) {
+					assert.Equal(t, constants.TransactionStatusOnHold, history.FromStatus)
+					assert.Equal(t, "admin:7", history.Actor)
+				}).Return(nil)
+				mockLedger.EXPECT().PostSuccess(gomock.Any(), gomock.Any()).Return(nil)
+				mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+				mockWebhook.EXPECT().DispatchStatusChanged(gomock.Any(), gomock.Any(), constants.TransactionStatusOnHold).Return(nil)
+				mockNotifier.EXPECT().Notify(gomock.Any(), constants.NotificationEventPurchaseSuccess, uint64(3), "email@gmail.com", gomock.Any()).Return(nil)
+				mockNotifier.EXPECT().Notify(gomock.Any(), constants.NotificationEventTransferReceived, uint64(9), "", gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:      "success approved by an admin on behalf of the user",
+			tokenData: models.TokenData{UserID: 3, Token: "SERVICE_TOKEN"},
+			req: &models.UpdateStatusTransaction{
+				Reference:         "REFERENCE",
+				TransactionStatus: constants.TransactionStatusSuccess,
+				Actor:             "admin:7",
+				Reason:            "customer confirmed",
+				Force:             true,
+			},
+			mockFn: func() {
+				mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "REFERENCE", false).Return(onHold, nil)
+				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), "REFERENCE", constants.TransactionStatusOnHold, constants.TransactionStatusSuccess, `{"note":"coffee"}`).Return(nil)
+				mockExt.EXPECT().DebitBalance(gomock.Any(), "SERVICE_TOKEN", external.UpdateBalance{UserID: 3, Reference: "REFERENCE", Amount: 100000}).
+					Return(&external.UpdateBalanceResponse{Amount: 100000}, nil)
+				mockRepo.EXPECT().CreateStatusHistory(gomock.Any(), gomock.Any()).Return(nil)
+				mockLedger.EXPECT().PostSuccess(gomock.Any(), gomock.Any()).Return(nil)
+				mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+				mockWebhook.EXPECT().DispatchStatusChanged(gomock.Any(), gomock.Any(), constants.TransactionStatusOnHold).Return(nil)
+				mockNotifier.EXPECT().Notify(gomock.Any(), constants.NotificationEventPurchaseSuccess, uint64(3), "", gomock.Any()).Return(nil)
+				mockNotifier.EXPECT().Notify(gomock.Any(), constants.NotificationEventTransferReceived, uint64(9), "", gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "success top up isn't screened",
+			req:  &models.UpdateStatusTransaction{Reference: "TOPUP", TransactionStatus: constants.TransactionStatusSuccess},
+			mockFn: func() {
+				topup := purchase
+				topup.Reference = "TOPUP"
+				topup.TransactionType = constants.TransactionTypeTopup
+				topup.MerchantID = 0
+				mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "TOPUP", false).Return(topup, nil)
+				mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), "TOPUP", constants.TransactionStatusPending, constants.TransactionStatusSuccess, gomock.Any()).Return(nil)
+				mockExt.EXPECT().CreditBalance(gomock.Any(), "TOKEN", gomock.Any()).Return(&external.UpdateBalanceResponse{}, nil)
+				mockRepo.EXPECT().CreateStatusHistory(gomock.Any(), gomock.Any()).Return(nil)
+				mockLedger.EXPECT().PostSuccess(gomock.Any(), gomock.Any()).Return(nil)
+				mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+				mockNotifier.EXPECT().Notify(gomock.Any(), constants.NotificationEventTopupSuccess, uint64(3), "email@gmail.com", gomock.Any()).Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+			s := &service{
+				repository: mockRepo,
+				external:   mockExt,
+				notifier:   mockNotifier,
+				publisher:  mockPublisher,
+				webhook:    mockWebhook,
+				ledger:     mockLedger,
+				schemas:    mockSchemas,
+				limits:     mockLimits,
+				fraud:      mockFraud,
+				config:     Config{MerchantUserIDs: map[uint64]bool{9: true}},
+			}
+			td := tokenData
+			if tt.tokenData.Token != "" {
+				td = tt.tokenData
+			}
+			err := s.UpdateStatusTransaction(context.Background(), td, tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
 func Test_service_GetTransactionDetail(t *testing.T) {
 	ctrlMock := gomock.NewController(t)
 	defer ctrlMock.Finish()
 
 	mockRepo := NewMockrepository(ctrlMock)
 	mockExt := NewMockIExternal(ctrlMock)
+	mockNotifier := NewMocknotifier(ctrlMock)
+	mockPublisher := NewMockpublisher(ctrlMock)
+	mockWebhook := NewMockwebhookDispatcher(ctrlMock)
+	mockLedger := NewMockledgerPoster(ctrlMock)
+	mockSchemas := NewMockschemaValidator(ctrlMock)
+	mockSchemas.EXPECT().Validate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 
 	now := time.Now()
 	transaction := models.Transaction{
@@ -942,7 +1760,7 @@ func Test_service_GetTransactionDetail(t *testing.T) {
 			want:    transaction,
 			wantErr: false,
 			mockfn: func(args args) {
-				mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), args.reference, true).Return(transaction, nil)
+				mockRepo.EXPECT().GetTransactionDetail(gomock.Any(), args.reference).Return(transaction, nil)
 			},
 		},
 		{
@@ -954,7 +1772,7 @@ func Test_service_GetTransactionDetail(t *testing.T) {
 			want:    transaction,
 			wantErr: true,
 			mockfn: func(args args) {
-				mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), args.reference, true).Return(transaction, assert.AnError)
+				mockRepo.EXPECT().GetTransactionDetail(gomock.Any(), args.reference).Return(transaction, assert.AnError)
 			},
 		},
 	}
@@ -964,6 +1782,11 @@ func Test_service_GetTransactionDetail(t *testing.T) {
 			s := &service{
 				repository: mockRepo,
 				external:   mockExt,
+				notifier:   mockNotifier,
+				publisher:  mockPublisher,
+				webhook:    mockWebhook,
+				ledger:     mockLedger,
+				schemas:    mockSchemas,
 			}
 			got, err := s.GetTransactionDetail(tt.args.ctx, tt.args.reference)
 			if (err != nil) != tt.wantErr {
@@ -983,6 +1806,12 @@ func Test_service_GetTransaction(t *testing.T) {
 
 	mockRepo := NewMockrepository(ctrlMock)
 	mockExt := NewMockIExternal(ctrlMock)
+	mockNotifier := NewMocknotifier(ctrlMock)
+	mockPublisher := NewMockpublisher(ctrlMock)
+	mockWebhook := NewMockwebhookDispatcher(ctrlMock)
+	mockLedger := NewMockledgerPoster(ctrlMock)
+	mockSchemas := NewMockschemaValidator(ctrlMock)
+	mockSchemas.EXPECT().Validate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	now := time.Now()
 
 	transactions := []models.Transaction{
@@ -1032,7 +1861,7 @@ func Test_service_GetTransaction(t *testing.T) {
 			want:    transactions,
 			wantErr: false,
 			mockfn: func(args args) {
-				mockRepo.EXPECT().GetTransaction(gomock.Any(), args.userID).Return(transactions, nil)
+				mockRepo.EXPECT().GetTransaction(gomock.Any(), args.userID, models.TransactionFilter{}).Return(transactions, nil)
 			},
 		},
 		{
@@ -1044,7 +1873,7 @@ func Test_service_GetTransaction(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 			mockfn: func(args args) {
-				mockRepo.EXPECT().GetTransaction(gomock.Any(), args.userID).Return(nil, assert.AnError)
+				mockRepo.EXPECT().GetTransaction(gomock.Any(), args.userID, models.TransactionFilter{}).Return(nil, assert.AnError)
 			},
 		},
 	}
@@ -1054,8 +1883,13 @@ func Test_service_GetTransaction(t *testing.T) {
 			s := &service{
 				repository: mockRepo,
 				external:   mockExt,
+				notifier:   mockNotifier,
+				publisher:  mockPublisher,
+				webhook:    mockWebhook,
+				ledger:     mockLedger,
+				schemas:    mockSchemas,
 			}
-			got, err := s.GetTransaction(tt.args.ctx, tt.args.userID)
+			got, err := s.GetTransaction(tt.args.ctx, tt.args.userID, models.TransactionFilter{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("service.GetTransaction() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -1073,6 +1907,12 @@ func Test_service_RefundTransaction(t *testing.T) {
 
 	mockRepo := NewMockrepository(ctrlMock)
 	mockExt := NewMockIExternal(ctrlMock)
+	mockNotifier := NewMocknotifier(ctrlMock)
+	mockPublisher := NewMockpublisher(ctrlMock)
+	mockWebhook := NewMockwebhookDispatcher(ctrlMock)
+	mockLedger := NewMockledgerPoster(ctrlMock)
+	mockSchemas := NewMockschemaValidator(ctrlMock)
+	mockSchemas.EXPECT().Validate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	now := time.Now()
 
 	type args struct {
@@ -1119,12 +1959,17 @@ func Test_service_RefundTransaction(t *testing.T) {
 					Reference:         "REFERENCE",
 					Description:       "DESCRIPTION",
 					AdditionalInfo:    "ADDINFO",
+					MerchantID:        9,
+					MerchantName:      "MERCHANT",
+					Category:          "FOOD_AND_DRINK",
+					Tags:              []string{"trip"},
 					CreatedAt:         now,
 					UpdatedAt:         now,
 				}
 				mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), args.req.Reference, false).Return(trx, nil)
 
 				mockExt.EXPECT().CreditBalance(gomock.Any(), args.tokenData.Token, external.UpdateBalance{
+					UserID:    1,
 					Reference: "REFUND-REFERENCE",
 					Amount:    200000,
 				}).Return(&external.UpdateBalanceResponse{
@@ -1140,10 +1985,75 @@ func Test_service_RefundTransaction(t *testing.T) {
 					Reference:         "REFUND-REFERENCE",
 					Description:       args.req.Description,
 					AdditionalInfo:    args.req.AdditionalInfo,
+					MerchantID:        9,
+					MerchantName:      "MERCHANT",
+					Category:          "FOOD_AND_DRINK",
 				}).Do(func(ctx context.Context, trx *models.Transaction) {
 					trx.TransactionStatus = constants.TransactionStatusReversed
 				}).Return(nil)
 
+				mockRepo.EXPECT().CreateStatusHistory(gomock.Any(), gomock.Any()).Return(assert.AnError)
+
+				mockLedger.EXPECT().PostRefund(gomock.Any(), gomock.Any(), args.req.Reference).Return(nil)
+
+				mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+
+				mockNotifier.EXPECT().Notify(gomock.Any(), constants.NotificationEventRefundIssued, args.tokenData.UserID, args.tokenData.Email, gomock.Any()).Return(nil)
+
+			},
+		},
+		{
+			name: "success on behalf of the user by an admin",
+			args: args{
+				ctx: context.Background(),
+				tokenData: models.TokenData{
+					UserID: 1,
+					Token:  "SERVICE_TOKEN",
+				},
+				req: &models.RefundTransaction{
+					Reference:   "REFERENCE",
+					Description: "chargeback",
+					Actor:       "admin:7",
+					Reason:      "chargeback",
+				},
+			},
+			want: models.CreateTransactionResponse{
+				Reference:         "REFUND-REFERENCE",
+				TransactionStatus: constants.TransactionStatusSuccess,
+			},
+			wantErr: false,
+			mockFn: func(args args) {
+				mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), args.req.Reference, false).Return(models.Transaction{
+					ID:                1,
+					UserID:            1,
+					Amount:            200000,
+					TransactionType:   constants.TransactionTypePurchase,
+					TransactionStatus: constants.TransactionStatusSuccess,
+					Reference:         "REFERENCE",
+					CreatedAt:         now,
+					UpdatedAt:         now,
+				}, nil)
+
+				mockExt.EXPECT().CreditBalance(gomock.Any(), "SERVICE_TOKEN", external.UpdateBalance{
+					UserID:    1,
+					Reference: "REFUND-REFERENCE",
+					Amount:    200000,
+				}).Return(&external.UpdateBalanceResponse{
+					Message: constants.SuccessMessage,
+					Amount:  200000,
+				}, nil)
+
+				mockRepo.EXPECT().CreateTransaction(gomock.Any(), gomock.Any()).Return(nil)
+
+				mockRepo.EXPECT().CreateStatusHistory(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, history *models.TransactionStatusHistory) {
+					assert.Equal(t, "admin:7", history.Actor)
+				}).Return(nil)
+
+				mockLedger.EXPECT().PostRefund(gomock.Any(), gomock.Any(), args.req.Reference).Return(nil)
+
+				mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+
+				mockNotifier.EXPECT().Notify(gomock.Any(), constants.NotificationEventRefundIssued, uint64(1), "", gomock.Any()).Return(nil)
 			},
 		},
 		{
@@ -1253,6 +2163,7 @@ func Test_service_RefundTransaction(t *testing.T) {
 				mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), args.req.Reference, false).Return(trx, nil)
 
 				mockExt.EXPECT().CreditBalance(gomock.Any(), args.tokenData.Token, external.UpdateBalance{
+					UserID:    1,
 					Reference: "REFUND-REFERENCE",
 					Amount:    200000,
 				}).Return(&external.UpdateBalanceResponse{
@@ -1297,6 +2208,7 @@ func Test_service_RefundTransaction(t *testing.T) {
 				mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), args.req.Reference, false).Return(trx, nil)
 
 				mockExt.EXPECT().CreditBalance(gomock.Any(), args.tokenData.Token, external.UpdateBalance{
+					UserID:    1,
 					Reference: "REFUND-REFERENCE",
 					Amount:    200000,
 				}).Return(&external.UpdateBalanceResponse{
@@ -1323,6 +2235,11 @@ func Test_service_RefundTransaction(t *testing.T) {
 			s := &service{
 				repository: mockRepo,
 				external:   mockExt,
+				notifier:   mockNotifier,
+				publisher:  mockPublisher,
+				webhook:    mockWebhook,
+				ledger:     mockLedger,
+				schemas:    mockSchemas,
 			}
 			got, err := s.RefundTransaction(tt.args.ctx, tt.args.tokenData, tt.args.req)
 			if (err != nil) != tt.wantErr {
@@ -1342,12 +2259,19 @@ func Test_service_sendNotification(t *testing.T) {
 
 	mockRepo := NewMockrepository(ctrlMock)
 	mockExt := NewMockIExternal(ctrlMock)
+	mockNotifier := NewMocknotifier(ctrlMock)
+	mockPublisher := NewMockpublisher(ctrlMock)
+	mockWebhook := NewMockwebhookDispatcher(ctrlMock)
+	mockLedger := NewMockledgerPoster(ctrlMock)
+	mockSchemas := NewMockschemaValidator(ctrlMock)
+	mockSchemas.EXPECT().Validate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	now := time.Now()
 
 	type args struct {
 		ctx       context.Context
 		tokenData models.TokenData
 		trx       models.Transaction
+		actor     string
 	}
 	tests := []struct {
 		name   string
@@ -1379,10 +2303,11 @@ func Test_service_sendNotification(t *testing.T) {
 				},
 			},
 			mockFn: func(args args) {
-				mockExt.EXPECT().SendNotification(gomock.Any(), args.tokenData.Email, "purchase_success", map[string]string{
+				mockNotifier.EXPECT().Notify(gomock.Any(), constants.NotificationEventPurchaseSuccess, args.tokenData.UserID, args.tokenData.Email, map[string]string{
 					"full_name":   args.tokenData.Fullname,
 					"description": args.trx.Description,
 					"reference":   args.trx.Reference,
+					"amount":      "200000.00",
 					"date":        args.trx.CreatedAt.Format("2006-01-02 15:04:05"),
 				}).Return(nil)
 			},
@@ -1412,14 +2337,93 @@ func Test_service_sendNotification(t *testing.T) {
 				},
 			},
 			mockFn: func(args args) {
-				mockExt.EXPECT().SendNotification(gomock.Any(), args.tokenData.Email, "purchase_success", map[string]string{
+				mockNotifier.EXPECT().Notify(gomock.Any(), constants.NotificationEventPurchaseSuccess, args.tokenData.UserID, args.tokenData.Email, map[string]string{
 					"full_name":   args.tokenData.Fullname,
 					"description": args.trx.Description,
 					"reference":   args.trx.Reference,
+					"amount":      "200000.00",
 					"date":        args.trx.CreatedAt.Format("2006-01-02 15:04:05"),
 				}).Return(assert.AnError)
 			},
 		},
+		{
+			name: "success on behalf of the owner is looked up by the worker",
+			args: args{
+				ctx:       context.Background(),
+				tokenData: models.TokenData{UserID: 1, Token: "SERVICE-TOKEN"},
+				trx: models.Transaction{
+					UserID:            1,
+					Amount:            50000,
+					TransactionType:   constants.TransactionTypeTopup,
+					TransactionStatus: constants.TransactionStatusFailed,
+					Reference:         "REFERENCE",
+					Description:       "DESCRIPTION",
+					CreatedAt:         now,
+				},
+				actor: constants.StatusActorExpiry,
+			},
+			mockFn: func(args args) {
+				mockNotifier.EXPECT().Notify(gomock.Any(), constants.NotificationEventPendingExpired, uint64(1), "", map[string]string{
+					"full_name":   "",
+					"description": args.trx.Description,
+					"reference":   args.trx.Reference,
+					"amount":      "50000.00",
+					"date":        args.trx.CreatedAt.Format("2006-01-02 15:04:05"),
+				}).Return(nil)
+			},
+		},
+		{
+			name: "success merchant receives the purchase",
+			args: args{
+				ctx: context.Background(),
+				tokenData: models.TokenData{
+					UserID:   1,
+					Fullname: "FULLNAME",
+					Email:    "user@gmail.com",
+				},
+				trx: models.Transaction{
+					UserID:            1,
+					Amount:            200000,
+					TransactionType:   constants.TransactionTypePurchase,
+					TransactionStatus: constants.TransactionStatusSuccess,
+					Reference:         "REFERENCE",
+					MerchantID:        9,
+					CreatedAt:         now,
+				},
+			},
+			mockFn: func(args args) {
+				mockNotifier.EXPECT().Notify(gomock.Any(), constants.NotificationEventPurchaseSuccess, uint64(1), "user@gmail.com", gomock.Any()).Return(nil)
+				mockNotifier.EXPECT().Notify(gomock.Any(), constants.NotificationEventTransferReceived, uint64(9), "", map[string]string{
+					"full_name":   "",
+					"description": "",
+					"reference":   args.trx.Reference,
+					"amount":      "200000.00",
+					"date":        args.trx.CreatedAt.Format("2006-01-02 15:04:05"),
+				}).Return(nil)
+			},
+		},
+		{
+			name: "success unknown merchant isn't notified",
+			args: args{
+				ctx: context.Background(),
+				tokenData: models.TokenData{
+					UserID: 1,
+					Email:  "user@gmail.com",
+				},
+				trx: models.Transaction{
+					UserID:            1,
+					Amount:            200000,
+					TransactionType:   constants.TransactionTypePurchase,
+					TransactionStatus: constants.TransactionStatusSuccess,
+					Reference:         "REFERENCE",
+					MerchantID:        10,
+					CreatedAt:         now,
+				},
+			},
+			mockFn: func(args args) {
+				mockNotifier.EXPECT().Notify(gomock.Any(), constants.NotificationEventPurchaseSuccess, uint64(1), "user@gmail.com", gomock.Any()).Return(nil)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1427,8 +2431,131 @@ func Test_service_sendNotification(t *testing.T) {
 			s := &service{
 				repository: mockRepo,
 				external:   mockExt,
+				notifier:   mockNotifier,
+				publisher:  mockPublisher,
+				webhook:    mockWebhook,
+				ledger:     mockLedger,
+				schemas:    mockSchemas,
+				config:     Config{MerchantUserIDs: map[uint64]bool{9: true}},
 			}
-			s.sendNotification(tt.args.ctx, tt.args.tokenData, tt.args.trx)
+			s.sendNotification(tt.args.ctx, tt.args.tokenData, tt.args.trx, tt.args.actor)
 		})
 	}
 }
+
+func Test_service_ValidateAdditionalInfo(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+	mockSchemas := NewMockschemaValidator(ctrlMock)
+	s := &service{
+		repository: mockRepo,
+		schemas:    mockSchemas,
+	}
+
+	infoErr := &models.AdditionalInfoError{Fields: []models.FieldError{{Field: "additional_info.foo", Message: "unknown field"}}}
+	tokenData := models.TokenData{UserID: 1, Token: "TOKEN"}
+
+	mockSchemas.EXPECT().Validate(constants.TransactionTypePurchase, constants.TransactionStatusPending, `{"foo":1}`).Return(infoErr)
+	_, err := s.CreateTransaction(context.Background(), &models.Transaction{
+		UserID:          1,
+		Amount:          100000,
+		TransactionType: constants.TransactionTypePurchase,
+		Description:     "DESCRIPTION",
+		AdditionalInfo:  `{"foo":1}`,
+	})
+	assert.ErrorIs(t, err, models.ErrInvalidAdditionalInfo)
+
+	mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "REFERENCE", false).Return(models.Transaction{
+		TransactionType:   constants.TransactionTypeTopup,
+		TransactionStatus: constants.TransactionStatusPending,
+		Reference:         "REFERENCE",
+	}, nil)
+	mockSchemas.EXPECT().Validate(constants.TransactionTypeTopup, constants.TransactionStatusSuccess, `{"foo":1}`).Return(infoErr)
+	err = s.UpdateStatusTransaction(context.Background(), tokenData, &models.UpdateStatusTransaction{
+		Reference:         "REFERENCE",
+		TransactionStatus: constants.TransactionStatusSuccess,
+		AdditionalInfo:    `{"foo":1}`,
+	})
+	assert.ErrorIs(t, err, models.ErrInvalidAdditionalInfo, "the wallet is not called")
+
+	mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "REFERENCE", false).Return(models.Transaction{
+		TransactionType:   constants.TransactionTypePurchase,
+		TransactionStatus: constants.TransactionStatusSuccess,
+		Reference:         "REFERENCE",
+	}, nil)
+	mockSchemas.EXPECT().Validate(constants.TransactionTypeRefund, constants.TransactionStatusSuccess, `{"foo":1}`).Return(infoErr)
+	_, err = s.RefundTransaction(context.Background(), tokenData, &models.RefundTransaction{
+		Reference:      "REFERENCE",
+		Description:    "DESCRIPTION",
+		AdditionalInfo: `{"foo":1}`,
+	})
+	var got *models.AdditionalInfoError
+	assert.ErrorAs(t, err, &got)
+	assert.Equal(t, infoErr.Fields, got.Fields)
+}
+
+func Test_service_ExpirePending(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+	mockExt := NewMockIExternal(ctrlMock)
+	mockNotifier := NewMocknotifier(ctrlMock)
+	mockPublisher := NewMockpublisher(ctrlMock)
+	mockSchemas := NewMockschemaValidator(ctrlMock)
+	mockSchemas.EXPECT().Validate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	s := &service{
+		repository: mockRepo,
+		external:   mockExt,
+		notifier:   mockNotifier,
+		publisher:  mockPublisher,
+		schemas:    mockSchemas,
+	}
+
+	before := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	expired := models.Transaction{
+		ID:                1,
+		UserID:            3,
+		Amount:            100000,
+		TransactionType:   constants.TransactionTypeTopup,
+		TransactionStatus: constants.TransactionStatusPending,
+		Reference:         "EXPIRED",
+		CreatedAt:         before.Add(-time.Hour),
+	}
+	settled := expired
+	settled.ID = 2
+	settled.Reference = "SETTLED"
+
+	mockRepo.EXPECT().GetPendingTransactions(gomock.Any(), before, 10).Return([]models.Transaction{expired, settled}, nil)
+
+	mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "EXPIRED", false).Return(expired, nil)
+	mockRepo.EXPECT().UpdateStatusTransaction(gomock.Any(), "EXPIRED", constants.TransactionStatusPending, constants.TransactionStatusFailed, "{}").Return(nil)
+	mockRepo.EXPECT().CreateStatusHistory(gomock.Any(), &models.TransactionStatusHistory{
+		TransactionID: 1,
+		Reference:     "EXPIRED",
+		FromStatus:    constants.TransactionStatusPending,
+		ToStatus:      constants.TransactionStatusFailed,
+		Actor:         constants.StatusActorExpiry,
+		Reason:        "pending since 2025-01-01T23:00:00Z",
+	}).Return(nil)
+	mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+	mockNotifier.EXPECT().Notify(gomock.Any(), constants.NotificationEventPendingExpired, uint64(3), "", gomock.Any()).Return(nil)
+
+	// settled after it was read, the status flow rejects the expiry
+	settled.TransactionStatus = constants.TransactionStatusSuccess
+	mockRepo.EXPECT().GetTransactionByReference(gomock.Any(), "SETTLED", false).Return(settled, nil)
+
+	report, err := s.ExpirePending(context.Background(), before, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.Checked)
+	assert.Equal(t, []string{"EXPIRED"}, report.Expired)
+	if assert.Len(t, report.Failed, 1) {
+		assert.Equal(t, "SETTLED", report.Failed[0].Reference)
+	}
+
+	mockRepo.EXPECT().GetPendingTransactions(gomock.Any(), before, 10).Return(nil, assert.AnError)
+	_, err = s.ExpirePending(context.Background(), before, 10)
+	assert.ErrorIs(t, err, assert.AnError)
+}
@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// ErrForbiddenAddress is returned for a webhook host that is not a public address, callbacks
+// must not reach the services next to this one or the metadata endpoints of the cloud.
+var ErrForbiddenAddress = errors.New("webhook address is not public")
+
+// sharedAddressSpace is the carrier-grade NAT range, some clouds serve their metadata from it.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+type resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+func publicAddress(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() || sharedAddressSpace.Contains(ip))
+}
+
+// checkHost rejects a host that is or resolves to a non public address.
+func (s *service) checkHost(ctx context.Context, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if !publicAddress(ip) {
+			return errors.Wrapf(ErrForbiddenAddress, "host %s", host)
+		}
+		return nil
+	}
+
+	addrs, err := s.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return errors.Wrapf(err, "failed to resolve host %s", host)
+	}
+
+	for _, addr := range addrs {
+		if !publicAddress(addr.IP) {
+			return errors.Wrapf(ErrForbiddenAddress, "host %s resolves to %s", host, addr.IP)
+		}
+	}
+
+	return nil
+}
+
+// NewHTTPClient returns the client the callbacks are sent with. The address is checked again when dialing
+// because the host may resolve differently than when the webhook was registered, redirects are dialed the same way.
+func NewHTTPClient() *http.Client {
+	dialer := &net.Dialer{
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !publicAddress(ip) {
+				return errors.Wrapf(ErrForbiddenAddress, "dial %s", address)
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// a proxy would be dialed instead of the merchant
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Transport: transport}
+}
@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"context"
+	"ewallet-transaction/internal/models"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+//go:generate mockgen -source=service.go -destination=service_mock_test.go -package=webhook
+type repository interface {
+	UpsertWebhook(ctx context.Context, webhook *models.MerchantWebhook) error
+	GetWebhookByMerchantID(ctx context.Context, merchantID uint64) (models.MerchantWebhook, error)
+	CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	GetDeliveryByID(ctx context.Context, id int) (models.WebhookDelivery, error)
+	GetDeliveriesByReference(ctx context.Context, merchantID uint64, reference string) ([]models.WebhookDelivery, error)
+	GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error)
+	ClaimDelivery(ctx context.Context, id int, now time.Time, leaseUntil time.Time) (bool, error)
+	UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+}
+
+type IHTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type Config struct {
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	PollInterval time.Duration
+	BatchSize    int
+	Timeout      time.Duration
+}
+
+type service struct {
+	repository repository
+	httpClient IHTTPClient
+	resolver   resolver
+	config     Config
+
+	wakeup chan struct{}
+	stop   chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+func NewService(repository repository, httpClient IHTTPClient, config Config) *service {
+	return &service{
+		repository: repository,
+		httpClient: httpClient,
+		resolver:   net.DefaultResolver,
+		config:     config,
+		wakeup:     make(chan struct{}, 1),
+		stop:       make(chan struct{}),
+	}
+}
@@ -0,0 +1,200 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=service_mock_test.go -package=webhook
+//
+
+// Package webhook is a generated GoMock package.
+package webhook
+
+import (
+	context "context"
+	models "ewallet-transaction/internal/models"
+	http "net/http"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// Mockrepository is a mock of repository interface.
+type Mockrepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockrepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockrepositoryMockRecorder is the mock recorder for Mockrepository.
+type MockrepositoryMockRecorder struct {
+	mock *Mockrepository
+}
+
+// NewMockrepository creates a new mock instance.
+func NewMockrepository(ctrl *gomock.Controller) *Mockrepository {
+	mock := &Mockrepository{ctrl: ctrl}
+	mock.recorder = &MockrepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockrepository) EXPECT() *MockrepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimDelivery mocks base method.
+func (m *Mockrepository) ClaimDelivery(ctx context.Context, id int, now, leaseUntil time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDelivery", ctx, id, now, leaseUntil)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDelivery indicates an expected call of ClaimDelivery.
+func (mr *MockrepositoryMockRecorder) ClaimDelivery(ctx, id, now, leaseUntil any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDelivery", reflect.TypeOf((*Mockrepository)(nil).ClaimDelivery), ctx, id, now, leaseUntil)
+}
+
+// CreateDelivery mocks base method.
+func (m *Mockrepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDelivery", ctx, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDelivery indicates an expected call of CreateDelivery.
+func (mr *MockrepositoryMockRecorder) CreateDelivery(ctx, delivery any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDelivery", reflect.TypeOf((*Mockrepository)(nil).CreateDelivery), ctx, delivery)
+}
+
+// GetDeliveriesByReference mocks base method.
+func (m *Mockrepository) GetDeliveriesByReference(ctx context.Context, merchantID uint64, reference string) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveriesByReference", ctx, merchantID, reference)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveriesByReference indicates an expected call of GetDeliveriesByReference.
+func (mr *MockrepositoryMockRecorder) GetDeliveriesByReference(ctx, merchantID, reference any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveriesByReference", reflect.TypeOf((*Mockrepository)(nil).GetDeliveriesByReference), ctx, merchantID, reference)
+}
+
+// GetDeliveryByID mocks base method.
+func (m *Mockrepository) GetDeliveryByID(ctx context.Context, id int) (models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveryByID", ctx, id)
+	ret0, _ := ret[0].(models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveryByID indicates an expected call of GetDeliveryByID.
+func (mr *MockrepositoryMockRecorder) GetDeliveryByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveryByID", reflect.TypeOf((*Mockrepository)(nil).GetDeliveryByID), ctx, id)
+}
+
+// GetDueDeliveries mocks base method.
+func (m *Mockrepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueDeliveries", ctx, now, limit)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueDeliveries indicates an expected call of GetDueDeliveries.
+func (mr *MockrepositoryMockRecorder) GetDueDeliveries(ctx, now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueDeliveries", reflect.TypeOf((*Mockrepository)(nil).GetDueDeliveries), ctx, now, limit)
+}
+
+// GetWebhookByMerchantID mocks base method.
+func (m *Mockrepository) GetWebhookByMerchantID(ctx context.Context, merchantID uint64) (models.MerchantWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookByMerchantID", ctx, merchantID)
+	ret0, _ := ret[0].(models.MerchantWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookByMerchantID indicates an expected call of GetWebhookByMerchantID.
+func (mr *MockrepositoryMockRecorder) GetWebhookByMerchantID(ctx, merchantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookByMerchantID", reflect.TypeOf((*Mockrepository)(nil).GetWebhookByMerchantID), ctx, merchantID)
+}
+
+// UpdateDelivery mocks base method.
+func (m *Mockrepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDelivery", ctx, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDelivery indicates an expected call of UpdateDelivery.
+func (mr *MockrepositoryMockRecorder) UpdateDelivery(ctx, delivery any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDelivery", reflect.TypeOf((*Mockrepository)(nil).UpdateDelivery), ctx, delivery)
+}
+
+// UpsertWebhook mocks base method.
+func (m *Mockrepository) UpsertWebhook(ctx context.Context, webhook *models.MerchantWebhook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWebhook", ctx, webhook)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWebhook indicates an expected call of UpsertWebhook.
+func (mr *MockrepositoryMockRecorder) UpsertWebhook(ctx, webhook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWebhook", reflect.TypeOf((*Mockrepository)(nil).UpsertWebhook), ctx, webhook)
+}
+
+// MockIHTTPClient is a mock of IHTTPClient interface.
+type MockIHTTPClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockIHTTPClientMockRecorder
+	isgomock struct{}
+}
+
+// MockIHTTPClientMockRecorder is the mock recorder for MockIHTTPClient.
+type MockIHTTPClientMockRecorder struct {
+	mock *MockIHTTPClient
+}
+
+// NewMockIHTTPClient creates a new mock instance.
+func NewMockIHTTPClient(ctrl *gomock.Controller) *MockIHTTPClient {
+	mock := &MockIHTTPClient{ctrl: ctrl}
+	mock.recorder = &MockIHTTPClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIHTTPClient) EXPECT() *MockIHTTPClientMockRecorder {
+	return m.recorder
+}
+
+// Do mocks base method.
+func (m *MockIHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Do", req)
+	ret0, _ := ret[0].(*http.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Do indicates an expected call of Do.
+func (mr *MockIHTTPClientMockRecorder) Do(req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockIHTTPClient)(nil).Do), req)
+}
@@ -0,0 +1,297 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+func (s *service) RegisterWebhook(ctx context.Context, merchantID uint64, req *models.RegisterWebhook) (models.RegisterWebhookResponse, error) {
+	var (
+		resp models.RegisterWebhookResponse
+	)
+
+	callbackURL, err := url.ParseRequestURI(req.URL)
+	if err != nil || (callbackURL.Scheme != "http" && callbackURL.Scheme != "https") || callbackURL.Host == "" {
+		return resp, fmt.Errorf("invalid webhook url %s", req.URL)
+	}
+
+	err = s.checkHost(ctx, callbackURL.Hostname())
+	if err != nil {
+		return resp, err
+	}
+
+	webhook := models.MerchantWebhook{
+		MerchantID: merchantID,
+		URL:        req.URL,
+		Secret:     helpers.GenerateSecret(),
+		IsActive:   true,
+	}
+
+	err = s.repository.UpsertWebhook(ctx, &webhook)
+	if err != nil {
+		return resp, errors.Wrap(err, "failed to save webhook")
+	}
+
+	resp.MerchantID = webhook.MerchantID
+	resp.URL = webhook.URL
+	resp.Secret = webhook.Secret
+
+	return resp, nil
+}
+
+func (s *service) GetDeliveries(ctx context.Context, merchantID uint64, reference string) ([]models.WebhookDelivery, error) {
+	return s.repository.GetDeliveriesByReference(ctx, merchantID, reference)
+}
+
+// Redeliver queues a fresh delivery with the same payload as an earlier one.
+func (s *service) Redeliver(ctx context.Context, merchantID uint64, deliveryID int) (models.WebhookDelivery, error) {
+	delivery, err := s.repository.GetDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return delivery, errors.Wrap(err, "failed to get webhook delivery")
+	}
+
+	if delivery.MerchantID != merchantID {
+		return models.WebhookDelivery{}, errors.New("webhook delivery does not belong to merchant")
+	}
+
+	webhook, err := s.repository.GetWebhookByMerchantID(ctx, merchantID)
+	if err != nil {
+		return models.WebhookDelivery{}, errors.Wrap(err, "failed to get merchant webhook")
+	}
+
+	redelivery := models.WebhookDelivery{
+		MerchantID:    delivery.MerchantID,
+		Reference:     delivery.Reference,
+		EventID:       delivery.EventID,
+		EventType:     delivery.EventType,
+		URL:           webhook.URL,
+		Payload:       delivery.Payload,
+		Status:        constants.WebhookDeliveryStatusPending,
+		NextAttemptAt: time.Now(),
+	}
+
+	err = s.repository.CreateDelivery(ctx, &redelivery)
+	if err != nil {
+		return models.WebhookDelivery{}, errors.Wrap(err, "failed to create webhook delivery")
+	}
+
+	s.notifyWorker()
+
+	return redelivery, nil
+}
+
+// DispatchStatusChanged queues a status change callback for the merchant of the transaction.
+// Transactions without merchant or merchants without an active webhook are skipped.
+func (s *service) DispatchStatusChanged(ctx context.Context, trx models.Transaction, previousStatus string) error {
+	if trx.MerchantID == 0 {
+		return nil
+	}
+
+	webhook, err := s.repository.GetWebhookByMerchantID(ctx, trx.MerchantID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return errors.Wrap(err, "failed to get merchant webhook")
+	}
+
+	if !webhook.IsActive {
+		return nil
+	}
+
+	payload := models.WebhookPayload{
+		EventID:           helpers.GenerateEventID(),
+		EventType:         constants.EventTransactionStatusChanged,
+		MerchantID:        trx.MerchantID,
+		Reference:         trx.Reference,
+		Amount:            trx.Amount,
+		TransactionStatus: trx.TransactionStatus,
+		PreviousStatus:    previousStatus,
+		OccurredAt:        time.Now(),
+	}
+
+	bytePayload, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal webhook payload")
+	}
+
+	delivery := models.WebhookDelivery{
+		MerchantID:    trx.MerchantID,
+		Reference:     trx.Reference,
+		EventID:       payload.EventID,
+		EventType:     payload.EventType,
+		URL:           webhook.URL,
+		Payload:       string(bytePayload),
+		Status:        constants.WebhookDeliveryStatusPending,
+		NextAttemptAt: payload.OccurredAt,
+	}
+
+	err = s.repository.CreateDelivery(ctx, &delivery)
+	if err != nil {
+		return errors.Wrap(err, "failed to create webhook delivery")
+	}
+
+	s.notifyWorker()
+
+	return nil
+}
+
+// Start runs the worker that sends due deliveries.
+func (s *service) Start() {
+	s.wg.Add(1)
+	go s.run()
+}
+
+// Stop waits for the in-flight deliveries to finish or the context to be done.
+func (s *service) Stop(ctx context.Context) error {
+	s.once.Do(func() {
+		close(s.stop)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "failed to stop webhook worker")
+	}
+}
+
+func (s *service) notifyWorker() {
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+func (s *service) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		case <-s.wakeup:
+		}
+
+		s.processDueDeliveries(context.Background())
+	}
+}
+
+func (s *service) processDueDeliveries(ctx context.Context) {
+	now := time.Now()
+	deliveries, err := s.repository.GetDueDeliveries(ctx, now, s.config.BatchSize)
+	if err != nil {
+		logrus.Error("failed to get due webhook deliveries: ", err)
+		return
+	}
+
+	for i := range deliveries {
+		claimed, err := s.repository.ClaimDelivery(ctx, deliveries[i].ID, now, now.Add(s.config.Timeout*2))
+		if err != nil {
+			logrus.WithField("delivery_id", deliveries[i].ID).Error("failed to claim webhook delivery: ", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		err = s.deliver(ctx, &deliveries[i])
+		if err != nil {
+			logrus.WithField("delivery_id", deliveries[i].ID).Error("failed to update webhook delivery: ", err)
+		}
+	}
+}
+
+// deliver sends one attempt of the delivery and stores the outcome,
+// scheduling the next attempt with exponential backoff when it fails.
+// A delivery whose merchant has no webhook anymore fails right away.
+func (s *service) deliver(ctx context.Context, delivery *models.WebhookDelivery) error {
+	delivery.Attempts++
+	delivery.LastResponseCode = 0
+
+	webhook, err := s.repository.GetWebhookByMerchantID(ctx, delivery.MerchantID)
+	if err != nil {
+		err = errors.Wrap(err, "failed to get merchant webhook")
+	} else {
+		delivery.LastResponseCode, err = s.send(ctx, webhook.Secret, delivery)
+	}
+
+	switch {
+	case err == nil:
+		delivery.Status = constants.WebhookDeliveryStatusSuccess
+		delivery.LastError = ""
+	case errors.Is(err, gorm.ErrRecordNotFound) || delivery.Attempts >= s.config.MaxAttempts:
+		delivery.Status = constants.WebhookDeliveryStatusFailed
+		delivery.LastError = err.Error()
+	default:
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = time.Now().Add(s.config.BaseBackoff * time.Duration(1<<(delivery.Attempts-1)))
+	}
+
+	return s.repository.UpdateDelivery(ctx, delivery)
+}
+
+func (s *service) send(ctx context.Context, secret string, delivery *models.WebhookDelivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create webhook request")
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set(constants.WebhookTimestampHeader, timestamp)
+	httpReq.Header.Set(constants.WebhookDeliveryHeader, strconv.Itoa(delivery.ID))
+	httpReq.Header.Set(constants.WebhookSignatureHeader, Sign(secret, timestamp, delivery.Payload))
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to send webhook")
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("got error response from merchant: %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature merchants use to verify a callback:
+// hex(HMAC-SHA256(secret, timestamp + "." + body)) prefixed with "sha256=".
+func Sign(secret string, timestamp string, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + payload))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,448 @@
+package webhook
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/internal/models"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+type fakeResolver map[string][]string
+
+func (r fakeResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	ips, ok := r[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	addrs := make([]net.IPAddr, 0, len(ips))
+	for _, ip := range ips {
+		addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip)})
+	}
+
+	return addrs, nil
+}
+
+func Test_service_RegisterWebhook(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+
+	type args struct {
+		ctx        context.Context
+		merchantID uint64
+		req        *models.RegisterWebhook
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+		mockFn  func(args args)
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx:        context.Background(),
+				merchantID: 1,
+				req:        &models.RegisterWebhook{URL: "https://merchant.test/callback"},
+			},
+			wantErr: false,
+			mockFn: func(args args) {
+				mockRepo.EXPECT().UpsertWebhook(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, webhook *models.MerchantWebhook) {
+					assert.Equal(t, args.merchantID, webhook.MerchantID)
+					assert.Equal(t, args.req.URL, webhook.URL)
+					assert.Len(t, webhook.Secret, 64)
+					assert.True(t, webhook.IsActive)
+				}).Return(nil)
+			},
+		},
+		{
+			name: "error invalid url",
+			args: args{
+				ctx:        context.Background(),
+				merchantID: 1,
+				req:        &models.RegisterWebhook{URL: "ftp://merchant.test/callback"},
+			},
+			wantErr: true,
+			mockFn:  func(args args) {},
+		},
+		{
+			name: "error loopback address",
+			args: args{
+				ctx:        context.Background(),
+				merchantID: 1,
+				req:        &models.RegisterWebhook{URL: "http://127.0.0.1:8080/callback"},
+			},
+			wantErr: true,
+			mockFn:  func(args args) {},
+		},
+		{
+			name: "error cloud metadata address",
+			args: args{
+				ctx:        context.Background(),
+				merchantID: 1,
+				req:        &models.RegisterWebhook{URL: "http://169.254.169.254/latest/meta-data"},
+			},
+			wantErr: true,
+			mockFn:  func(args args) {},
+		},
+		{
+			name: "error host resolves to a private address",
+			args: args{
+				ctx:        context.Background(),
+				merchantID: 1,
+				req:        &models.RegisterWebhook{URL: "https://internal.merchant.test/callback"},
+			},
+			wantErr: true,
+			mockFn:  func(args args) {},
+		},
+		{
+			name: "error host not resolved",
+			args: args{
+				ctx:        context.Background(),
+				merchantID: 1,
+				req:        &models.RegisterWebhook{URL: "https://unknown.merchant.test/callback"},
+			},
+			wantErr: true,
+			mockFn:  func(args args) {},
+		},
+		{
+			name: "error save webhook",
+			args: args{
+				ctx:        context.Background(),
+				merchantID: 1,
+				req:        &models.RegisterWebhook{URL: "https://merchant.test/callback"},
+			},
+			wantErr: true,
+			mockFn: func(args args) {
+				mockRepo.EXPECT().UpsertWebhook(gomock.Any(), gomock.Any()).Return(assert.AnError)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn(tt.args)
+			s := NewService(mockRepo, http.DefaultClient, Config{})
+			s.resolver = fakeResolver{
+				"merchant.test":          {"203.0.113.10"},
+				"internal.merchant.test": {"203.0.113.10", "10.0.0.5"},
+			}
+			got, err := s.RegisterWebhook(tt.args.ctx, tt.args.merchantID, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("service.RegisterWebhook() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				assert.Equal(t, tt.args.merchantID, got.MerchantID)
+				assert.NotEmpty(t, got.Secret)
+			} else {
+				assert.Empty(t, got)
+			}
+		})
+	}
+}
+
+func Test_service_DispatchStatusChanged(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+
+	trx := models.Transaction{
+		UserID:            1,
+		Amount:            100000,
+		TransactionType:   constants.TransactionTypePurchase,
+		TransactionStatus: constants.TransactionStatusSuccess,
+		Reference:         "REFERENCE",
+		MerchantID:        9,
+	}
+
+	tests := []struct {
+		name    string
+		trx     models.Transaction
+		wantErr bool
+		mockFn  func()
+	}{
+		{
+			name:    "success",
+			trx:     trx,
+			wantErr: false,
+			mockFn: func() {
+				mockRepo.EXPECT().GetWebhookByMerchantID(gomock.Any(), trx.MerchantID).Return(models.MerchantWebhook{
+					MerchantID: trx.MerchantID,
+					URL:        "https://merchant.test/callback",
+					IsActive:   true,
+				}, nil)
+				mockRepo.EXPECT().CreateDelivery(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, delivery *models.WebhookDelivery) {
+					assert.Equal(t, trx.Reference, delivery.Reference)
+					assert.Equal(t, "https://merchant.test/callback", delivery.URL)
+					assert.Equal(t, constants.WebhookDeliveryStatusPending, delivery.Status)
+					assert.Contains(t, delivery.Payload, `"previous_status":"PENDING"`)
+				}).Return(nil)
+			},
+		},
+		{
+			name: "success without merchant",
+			trx: models.Transaction{
+				Reference: "REFERENCE",
+			},
+			wantErr: false,
+			mockFn:  func() {},
+		},
+		{
+			name:    "success merchant without webhook",
+			trx:     trx,
+			wantErr: false,
+			mockFn: func() {
+				mockRepo.EXPECT().GetWebhookByMerchantID(gomock.Any(), trx.MerchantID).Return(models.MerchantWebhook{}, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name:    "error get webhook",
+			trx:     trx,
+			wantErr: true,
+			mockFn: func() {
+				mockRepo.EXPECT().GetWebhookByMerchantID(gomock.Any(), trx.MerchantID).Return(models.MerchantWebhook{}, assert.AnError)
+			},
+		},
+		{
+			name:    "error create delivery",
+			trx:     trx,
+			wantErr: true,
+			mockFn: func() {
+				mockRepo.EXPECT().GetWebhookByMerchantID(gomock.Any(), trx.MerchantID).Return(models.MerchantWebhook{
+					MerchantID: trx.MerchantID,
+					URL:        "https://merchant.test/callback",
+					IsActive:   true,
+				}, nil)
+				mockRepo.EXPECT().CreateDelivery(gomock.Any(), gomock.Any()).Return(assert.AnError)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+			s := NewService(mockRepo, http.DefaultClient, Config{})
+			if err := s.DispatchStatusChanged(context.Background(), tt.trx, constants.TransactionStatusPending); (err != nil) != tt.wantErr {
+				t.Errorf("service.DispatchStatusChanged() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_service_deliver(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+
+	var statusCode int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp := r.Header.Get(constants.WebhookTimestampHeader)
+		assert.Equal(t, Sign("SECRET", timestamp, string(body)), r.Header.Get(constants.WebhookSignatureHeader))
+		w.WriteHeader(statusCode)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		statusCode   int
+		webhookErr   error
+		attempts     int
+		wantStatus   string
+		wantAttempts int
+		wantRetry    bool
+	}{
+		{
+			name:         "success",
+			statusCode:   http.StatusOK,
+			attempts:     0,
+			wantStatus:   constants.WebhookDeliveryStatusSuccess,
+			wantAttempts: 1,
+		},
+		{
+			name:         "failed schedule retry",
+			statusCode:   http.StatusInternalServerError,
+			attempts:     0,
+			wantStatus:   constants.WebhookDeliveryStatusPending,
+			wantAttempts: 1,
+			wantRetry:    true,
+		},
+		{
+			name:         "failed max attempts",
+			statusCode:   http.StatusInternalServerError,
+			attempts:     2,
+			wantStatus:   constants.WebhookDeliveryStatusFailed,
+			wantAttempts: 3,
+		},
+		{
+			name:         "failed webhook removed",
+			webhookErr:   gorm.ErrRecordNotFound,
+			attempts:     0,
+			wantStatus:   constants.WebhookDeliveryStatusFailed,
+			wantAttempts: 1,
+		},
+		{
+			name:         "failed get webhook schedule retry",
+			webhookErr:   assert.AnError,
+			attempts:     0,
+			wantStatus:   constants.WebhookDeliveryStatusPending,
+			wantAttempts: 1,
+			wantRetry:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statusCode = tt.statusCode
+			now := time.Now()
+			delivery := &models.WebhookDelivery{
+				ID:            1,
+				MerchantID:    9,
+				Reference:     "REFERENCE",
+				URL:           server.URL,
+				Payload:       `{"reference":"REFERENCE"}`,
+				Status:        constants.WebhookDeliveryStatusPending,
+				Attempts:      tt.attempts,
+				NextAttemptAt: now,
+			}
+
+			mockRepo.EXPECT().GetWebhookByMerchantID(gomock.Any(), delivery.MerchantID).Return(models.MerchantWebhook{
+				MerchantID: delivery.MerchantID,
+				URL:        server.URL,
+				Secret:     "SECRET",
+				IsActive:   true,
+			}, tt.webhookErr)
+			mockRepo.EXPECT().UpdateDelivery(gomock.Any(), delivery).Return(nil)
+
+			s := NewService(mockRepo, server.Client(), Config{
+				MaxAttempts: 3,
+				BaseBackoff: time.Minute,
+				Timeout:     time.Second,
+			})
+			err := s.deliver(context.Background(), delivery)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, delivery.Status)
+			assert.Equal(t, tt.wantAttempts, delivery.Attempts)
+			assert.Equal(t, tt.statusCode, delivery.LastResponseCode)
+			assert.Equal(t, tt.wantRetry, delivery.NextAttemptAt.After(now))
+			assert.Equal(t, tt.wantStatus == constants.WebhookDeliveryStatusSuccess, delivery.LastError == "")
+		})
+	}
+}
+
+func Test_service_Redeliver(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockrepository(ctrlMock)
+
+	delivery := models.WebhookDelivery{
+		ID:         1,
+		MerchantID: 9,
+		Reference:  "REFERENCE",
+		EventID:    "EVENT",
+		EventType:  constants.EventTransactionStatusChanged,
+		URL:        "https://old.merchant.test/callback",
+		Payload:    "{}",
+		Status:     constants.WebhookDeliveryStatusFailed,
+		Attempts:   8,
+	}
+
+	type args struct {
+		merchantID uint64
+		deliveryID int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+		mockFn  func(args args)
+	}{
+		{
+			name:    "success",
+			args:    args{merchantID: 9, deliveryID: 1},
+			wantErr: false,
+			mockFn: func(args args) {
+				mockRepo.EXPECT().GetDeliveryByID(gomock.Any(), args.deliveryID).Return(delivery, nil)
+				mockRepo.EXPECT().GetWebhookByMerchantID(gomock.Any(), args.merchantID).Return(models.MerchantWebhook{
+					MerchantID: 9,
+					URL:        "https://merchant.test/callback",
+				}, nil)
+				mockRepo.EXPECT().CreateDelivery(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, redelivery *models.WebhookDelivery) {
+					assert.Equal(t, "https://merchant.test/callback", redelivery.URL)
+					assert.Equal(t, delivery.Payload, redelivery.Payload)
+					assert.Equal(t, constants.WebhookDeliveryStatusPending, redelivery.Status)
+					assert.Equal(t, 0, redelivery.Attempts)
+				}).Return(nil)
+			},
+		},
+		{
+			name:    "error other merchant",
+			args:    args{merchantID: 10, deliveryID: 1},
+			wantErr: true,
+			mockFn: func(args args) {
+				mockRepo.EXPECT().GetDeliveryByID(gomock.Any(), args.deliveryID).Return(delivery, nil)
+			},
+		},
+		{
+			name:    "error get delivery",
+			args:    args{merchantID: 9, deliveryID: 1},
+			wantErr: true,
+			mockFn: func(args args) {
+				mockRepo.EXPECT().GetDeliveryByID(gomock.Any(), args.deliveryID).Return(models.WebhookDelivery{}, assert.AnError)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn(tt.args)
+			s := NewService(mockRepo, http.DefaultClient, Config{})
+			got, err := s.Redeliver(context.Background(), tt.args.merchantID, tt.args.deliveryID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("service.Redeliver() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				assert.Empty(t, got)
+			}
+		})
+	}
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// the test server listens on loopback, the client refuses to dial it whatever the url says
+	_, err := NewHTTPClient().Get(server.URL)
+	assert.ErrorIs(t, err, ErrForbiddenAddress)
+
+	for ip, want := range map[string]bool{
+		"203.0.113.10":    true,
+		"2001:db8::1":     true,
+		"127.0.0.1":       false,
+		"10.1.2.3":        false,
+		"192.168.1.1":     false,
+		"169.254.169.254": false,
+		"100.100.100.200": false,
+		"0.0.0.0":         false,
+		"::1":             false,
+		"fd00:ec2::254":   false,
+		"::ffff:10.0.0.1": false,
+	} {
+		assert.Equal(t, want, publicAddress(net.ParseIP(ip)), ip)
+	}
+}
@@ -1,8 +1,12 @@
 package middleware
 
 import (
+	"crypto/subtle"
+	"ewallet-transaction/constants"
 	"ewallet-transaction/helpers"
 	"ewallet-transaction/internal/handler/transaction"
+	"ewallet-transaction/internal/interfaces"
+	"ewallet-transaction/internal/models"
 	"fmt"
 	"net/http"
 
@@ -11,6 +15,18 @@ import (
 
 type ExternalDependency struct {
 	External transaction.External
+	// SettlementAPIKey guards the settlement upload, it has no user token.
+	SettlementAPIKey string
+	// AdminUserIDs are the users allowed on the admin routes.
+	AdminUserIDs map[uint64]bool
+	// MerchantUserIDs are the users allowed on the merchant webhook routes.
+	MerchantUserIDs map[uint64]bool
+	// RateLimits are the limits of the route groups, kept in RateLimitStore.
+	RateLimitStore interfaces.IRateLimitStore
+	RateLimits     map[string]models.RateLimit
+	// Contacts keeps the email of the validated tokens, the notifications reach the users through it
+	// when a transaction is changed without their token.
+	Contacts interfaces.IContactRecorder
 }
 
 func (d *ExternalDependency) MiddlewareValidateToken(c *gin.Context) {
@@ -28,7 +44,13 @@ func (d *ExternalDependency) MiddlewareValidateToken(c *gin.Context) {
 		fmt.Println(err)
 		helpers.SendResponseHTTP(c, http.StatusUnauthorized, "unauthorized", nil)
 		c.Abort()
+		return
+	}
 
+	if d.Contacts != nil {
+		if err := d.Contacts.RecordContact(c.Request.Context(), tokenData); err != nil {
+			fmt.Println("failed to record contact, ", err)
+		}
 	}
 
 	tokenData.Token = auth
@@ -37,3 +59,45 @@ func (d *ExternalDependency) MiddlewareValidateToken(c *gin.Context) {
 
 	c.Next()
 }
+
+func (d *ExternalDependency) MiddlewareValidateSettlementKey(c *gin.Context) {
+	key := c.Request.Header.Get(constants.SettlementAPIKeyHeader)
+	if key == "" || d.SettlementAPIKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(d.SettlementAPIKey)) != 1 {
+		fmt.Println("invalid settlement key")
+		helpers.SendResponseHTTP(c, http.StatusUnauthorized, "unauthorized", nil)
+		c.Abort()
+		return
+	}
+
+	c.Next()
+}
+
+// MiddlewareValidateAdmin only lets admin users through, it runs after MiddlewareValidateToken.
+func (d *ExternalDependency) MiddlewareValidateAdmin(c *gin.Context) {
+	validateUser(c, d.AdminUserIDs, "user is not an admin")
+}
+
+// MiddlewareValidateMerchant only lets merchant users through, it runs after MiddlewareValidateToken.
+func (d *ExternalDependency) MiddlewareValidateMerchant(c *gin.Context) {
+	validateUser(c, d.MerchantUserIDs, "user is not a merchant")
+}
+
+func validateUser(c *gin.Context, allowed map[uint64]bool, denied string) {
+	token, ok := c.Get("token")
+	if !ok {
+		fmt.Println("failed to get token data")
+		helpers.SendResponseHTTP(c, http.StatusUnauthorized, "unauthorized", nil)
+		c.Abort()
+		return
+	}
+
+	tokenData, ok := token.(models.TokenData)
+	if !ok || !allowed[tokenData.UserID] {
+		fmt.Println(denied)
+		helpers.SendResponseHTTP(c, http.StatusForbidden, "forbidden", nil)
+		c.Abort()
+		return
+	}
+
+	c.Next()
+}
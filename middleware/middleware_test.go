@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"context"
+	"ewallet-transaction/constants"
 	models "ewallet-transaction/internal/models"
 	"net/http"
 	"net/http/httptest"
@@ -23,7 +25,9 @@ func TestExternalDependency_MiddlewareValidateToken(t *testing.T) {
 		name               string
 		wantErr            bool
 		mockFn             func()
+		contactErr         error
 		expectedStatusCode int
+		expectedContacts   []models.TokenData
 	}{
 		{
 			name:    "success",
@@ -37,6 +41,24 @@ func TestExternalDependency_MiddlewareValidateToken(t *testing.T) {
 				}, nil)
 			},
 			expectedStatusCode: http.StatusOK,
+			expectedContacts: []models.TokenData{
+				{UserID: 1, Username: "username", Fullname: "fullname", Email: "email@gmail.com"},
+			},
+		},
+		{
+			name:    "success contact not recorded",
+			wantErr: false,
+			mockFn: func() {
+				mockExt.EXPECT().ValidateToken(gomock.Any(), auth).Return(models.TokenData{
+					UserID: 1,
+					Email:  "email@gmail.com",
+				}, nil)
+			},
+			contactErr:         assert.AnError,
+			expectedStatusCode: http.StatusOK,
+			expectedContacts: []models.TokenData{
+				{UserID: 1, Email: "email@gmail.com"},
+			},
 		},
 		{
 			name:    "error",
@@ -52,8 +74,10 @@ func TestExternalDependency_MiddlewareValidateToken(t *testing.T) {
 			tt.mockFn()
 			api := gin.New()
 
+			contacts := &contactRecorder{err: tt.contactErr}
 			d := &ExternalDependency{
 				External: mockExt,
+				Contacts: contacts,
 			}
 
 			w := httptest.NewRecorder()
@@ -64,6 +88,157 @@ func TestExternalDependency_MiddlewareValidateToken(t *testing.T) {
 			assert.NoError(t, err)
 			req.Header.Set("Authorization", auth)
 
+			api.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedContacts, contacts.recorded)
+		})
+	}
+}
+
+type contactRecorder struct {
+	err      error
+	recorded []models.TokenData
+}
+
+func (r *contactRecorder) RecordContact(ctx context.Context, tokenData models.TokenData) error {
+	r.recorded = append(r.recorded, tokenData)
+	return r.err
+}
+
+func TestExternalDependency_MiddlewareValidateSettlementKey(t *testing.T) {
+	tests := []struct {
+		name               string
+		apiKey             string
+		header             string
+		expectedStatusCode int
+	}{
+		{
+			name:               "success",
+			apiKey:             "KEY",
+			header:             "KEY",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "error wrong key",
+			apiKey:             "KEY",
+			header:             "OTHER",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:               "error key not configured",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := gin.New()
+
+			d := &ExternalDependency{
+				SettlementAPIKey: tt.apiKey,
+			}
+
+			w := httptest.NewRecorder()
+			endPoint := "/validate-settlement-key"
+			api.POST(endPoint, d.MiddlewareValidateSettlementKey)
+
+			req, err := http.NewRequest(http.MethodPost, endPoint, nil)
+			assert.NoError(t, err)
+			req.Header.Set(constants.SettlementAPIKeyHeader, tt.header)
+
+			api.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+		})
+	}
+}
+
+func TestExternalDependency_MiddlewareValidateAdmin(t *testing.T) {
+	tests := []struct {
+		name               string
+		token              interface{}
+		expectedStatusCode int
+	}{
+		{
+			name:               "success",
+			token:              models.TokenData{UserID: 7},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "error not an admin",
+			token:              models.TokenData{UserID: 1},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "error no token",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := gin.New()
+
+			d := &ExternalDependency{
+				AdminUserIDs: map[uint64]bool{7: true},
+			}
+
+			w := httptest.NewRecorder()
+			endPoint := "/validate-admin"
+			api.GET(endPoint, func(c *gin.Context) {
+				if tt.token != nil {
+					c.Set("token", tt.token)
+				}
+				c.Next()
+			}, d.MiddlewareValidateAdmin)
+
+			req, err := http.NewRequest(http.MethodGet, endPoint, nil)
+			assert.NoError(t, err)
+
+			api.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+		})
+	}
+}
+
+func TestExternalDependency_MiddlewareValidateMerchant(t *testing.T) {
+	tests := []struct {
+		name               string
+		token              interface{}
+		expectedStatusCode int
+	}{
+		{
+			name:               "success",
+			token:              models.TokenData{UserID: 9},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "error not a merchant",
+			token:              models.TokenData{UserID: 1},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "error no token",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := gin.New()
+
+			d := &ExternalDependency{
+				MerchantUserIDs: map[uint64]bool{9: true},
+			}
+
+			w := httptest.NewRecorder()
+			endPoint := "/validate-merchant"
+			api.GET(endPoint, func(c *gin.Context) {
+				if tt.token != nil {
+					c.Set("token", tt.token)
+				}
+				c.Next()
+			}, d.MiddlewareValidateMerchant)
+
+			req, err := http.NewRequest(http.MethodGet, endPoint, nil)
+			assert.NoError(t, err)
+
 			api.ServeHTTP(w, req)
 			assert.Equal(t, tt.expectedStatusCode, w.Code)
 		})
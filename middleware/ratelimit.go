@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"ewallet-transaction/constants"
+	"ewallet-transaction/helpers"
+	"ewallet-transaction/internal/models"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MiddlewareRateLimit limits the routes of a group, it runs after MiddlewareValidateToken to limit
+// every user on its own, routes without a user token are limited per client ip. Ahead of
+// MiddlewareValidateToken it limits per client ip, a flood is throttled before the token reaches ums.
+// A group without a limit is not limited.
+func (d *ExternalDependency) MiddlewareRateLimit(group string) gin.HandlerFunc {
+	limit, ok := d.RateLimits[group]
+	if !ok || limit.Requests <= 0 || d.RateLimitStore == nil {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	return func(c *gin.Context) {
+		result, err := d.RateLimitStore.Take(c.Request.Context(), rateLimitKey(c, group), limit)
+		if err != nil {
+			// an unavailable store must not take the api down with it
+			fmt.Println("failed to take rate limit token: ", err)
+			c.Next()
+			return
+		}
+
+		c.Header(constants.RateLimitLimitHeader, strconv.Itoa(limit.Requests))
+		c.Header(constants.RateLimitRemainingHeader, strconv.Itoa(result.Remaining))
+		if !result.Allowed {
+			c.Header(constants.RetryAfterHeader, strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+			helpers.SendResponseHTTP(c, http.StatusTooManyRequests, constants.ErrTooManyRequests, nil)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func rateLimitKey(c *gin.Context, group string) string {
+	if token, ok := c.Get("token"); ok {
+		if tokenData, ok := token.(models.TokenData); ok {
+			return fmt.Sprintf("%s:user:%d", group, tokenData.UserID)
+		}
+	}
+
+	return fmt.Sprintf("%s:ip:%s", group, c.ClientIP())
+}
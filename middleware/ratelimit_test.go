@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"context"
+	"ewallet-transaction/constants"
+	"ewallet-transaction/external/ratelimit"
+	"ewallet-transaction/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, limit models.RateLimit) (models.RateLimitResult, error) {
+	return models.RateLimitResult{}, assert.AnError
+}
+
+func TestExternalDependency_MiddlewareRateLimit(t *testing.T) {
+	limits := map[string]models.RateLimit{
+		constants.RateLimitGroupTransaction: {Requests: 2, Period: time.Minute},
+		constants.RateLimitGroupExport:      {Requests: 0, Period: time.Minute},
+	}
+
+	type call struct {
+		token              interface{}
+		remoteAddr         string
+		expectedStatusCode int
+	}
+
+	tests := []struct {
+		name       string
+		group      string
+		dependency func() *ExternalDependency
+		calls      []call
+	}{
+		{
+			name:  "limited per user",
+			group: constants.RateLimitGroupTransaction,
+			calls: []call{
+				{token: models.TokenData{UserID: 1}, expectedStatusCode: http.StatusOK},
+				{token: models.TokenData{UserID: 1}, expectedStatusCode: http.StatusOK},
+				{token: models.TokenData{UserID: 1}, expectedStatusCode: http.StatusTooManyRequests},
+				{token: models.TokenData{UserID: 2}, expectedStatusCode: http.StatusOK},
+			},
+		},
+		{
+			name:  "limited per ip without a token",
+			group: constants.RateLimitGroupTransaction,
+			calls: []call{
+				{remoteAddr: "10.0.0.1:1000", expectedStatusCode: http.StatusOK},
+				{remoteAddr: "10.0.0.1:2000", expectedStatusCode: http.StatusOK},
+				{remoteAddr: "10.0.0.1:3000", expectedStatusCode: http.StatusTooManyRequests},
+				{remoteAddr: "10.0.0.2:1000", expectedStatusCode: http.StatusOK},
+			},
+		},
+		{
+			name:  "group without limit",
+			group: constants.RateLimitGroupExport,
+			calls: []call{
+				{token: models.TokenData{UserID: 1}, expectedStatusCode: http.StatusOK},
+				{token: models.TokenData{UserID: 1}, expectedStatusCode: http.StatusOK},
+				{token: models.TokenData{UserID: 1}, expectedStatusCode: http.StatusOK},
+			},
+		},
+		{
+			name:  "store error lets the request through",
+			group: constants.RateLimitGroupTransaction,
+			dependency: func() *ExternalDependency {
+				return &ExternalDependency{RateLimitStore: failingStore{}, RateLimits: limits}
+			},
+			calls: []call{
+				{token: models.TokenData{UserID: 1}, expectedStatusCode: http.StatusOK},
+				{token: models.TokenData{UserID: 1}, expectedStatusCode: http.StatusOK},
+				{token: models.TokenData{UserID: 1}, expectedStatusCode: http.StatusOK},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &ExternalDependency{
+				RateLimitStore: ratelimit.NewMemoryStore(),
+				RateLimits:     limits,
+			}
+			if tt.dependency != nil {
+				d = tt.dependency()
+			}
+
+			api := gin.New()
+			endPoint := "/rate-limit"
+			var token interface{}
+			api.GET(endPoint, func(c *gin.Context) {
+				if token != nil {
+					c.Set("token", token)
+				}
+				c.Next()
+			}, d.MiddlewareRateLimit(tt.group), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			for _, call := range tt.calls {
+				token = call.token
+
+				req, err := http.NewRequest(http.MethodGet, endPoint, nil)
+				assert.NoError(t, err)
+				req.RemoteAddr = call.remoteAddr
+
+				w := httptest.NewRecorder()
+				api.ServeHTTP(w, req)
+				assert.Equal(t, call.expectedStatusCode, w.Code)
+				if call.expectedStatusCode == http.StatusTooManyRequests {
+					assert.Equal(t, "30", w.Header().Get(constants.RetryAfterHeader))
+					assert.Equal(t, "0", w.Header().Get(constants.RateLimitRemainingHeader))
+					assert.Contains(t, w.Body.String(), constants.ErrTooManyRequests)
+				}
+			}
+		})
+	}
+}